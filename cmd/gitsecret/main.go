@@ -1,20 +1,74 @@
 package main
 
 import (
+	"errors"
 	"os"
 
+	"github.com/Drilmo/git-secret-scanner/internal/cli"
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+	"github.com/Drilmo/git-secret-scanner/internal/httpclient"
+	"github.com/Drilmo/git-secret-scanner/internal/plain"
 	"github.com/Drilmo/git-secret-scanner/internal/tui"
 	"github.com/charmbracelet/log"
+	"github.com/muesli/termenv"
 )
 
+// version is set at build time (-ldflags "-X main.version=...")
+var version = "dev"
+
 func main() {
 	// Configure logger
 	log.SetLevel(log.DebugLevel)
 	log.SetReportTimestamp(false)
 
+	cli.Version = version
+	// Remote configurations are fetched through the proxy and CA settings
+	config.RemoteClient = httpclient.New
+	args := cli.ParseGlobalFlags(os.Args[1:])
+	if plain.Enabled() {
+		log.SetColorProfile(termenv.Ascii)
+	}
+
+	err := run(args)
+
+	// Written even when the run failed: that is when it is needed
+	if path, bundleErr := debugbundle.Finish(err); bundleErr != nil {
+		log.Error("Cannot write the debug bundle", "err", bundleErr)
+	} else if path != "" {
+		log.Info("Debug bundle written", "path", path)
+	}
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	// Subcommands run without the TUI
+	if len(args) > 0 {
+		if cli.Script != "" {
+			err := errors.New("--script drives the TUI and cannot be used with a command")
+			log.Error("Command failed", "err", err)
+			return err
+		}
+		if err := cli.Run(args); err != nil {
+			log.Error("Command failed", "err", err)
+			return err
+		}
+		return nil
+	}
+
+	// Refuse to start with a configuration that breaks the signature policy
+	if err := cli.CheckPolicy(); err != nil {
+		log.Error("Configuration rejected", "err", err)
+		return err
+	}
+
 	// Run TUI
-	if err := tui.Run(); err != nil {
+	if err := tui.Run(cli.Script); err != nil {
 		log.Error("Application error", "err", err)
-		os.Exit(1)
+		return err
 	}
+	return nil
 }
@@ -8,12 +8,24 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/i18n"
+	"github.com/Drilmo/git-secret-scanner/internal/model"
 )
 
 // Analysis holds the complete analysis results
 type Analysis struct {
-	Stats   Stats    `json:"stats"`
-	Secrets []Secret `json:"secrets"`
+	Repository  string    `json:"repository,omitempty"` // From JSON results only
+	Stats       Stats     `json:"stats"`
+	Secrets     []Secret  `json:"secrets"`
+	Health      *Health   `json:"health,omitempty"`      // Set by ComputeHealth
+	Hotspots    []Hotspot `json:"hotspots,omitempty"`    // Set by ComputeHotspots
+	ValuesShown bool      `json:"valuesShown,omitempty"` // Raw values kept (AnalyzeOptions.ShowValues)
+	Excluded    int       `json:"excluded,omitempty"`    // Values left out by AnalyzeOptions.Skip
+	Locale      Locale    `json:"-"`                     // Formats of the exports (LookupLocale; default if unset)
+	Language    string    `json:"-"`                     // Text of the reports (config.Languages; English if unset)
 }
 
 // Stats holds global statistics
@@ -46,44 +58,50 @@ type TypeStat struct {
 
 // Secret represents an analyzed secret
 type Secret struct {
-	File             string        `json:"file"`
-	Key              string        `json:"key"`
-	Type             string        `json:"type"`
-	ChangeCount      int           `json:"changeCount"`
-	TotalOccurrences int           `json:"totalOccurrences"`
-	Authors          []string      `json:"authors"`
-	FirstSeen        string        `json:"firstSeen"`
-	LastSeen         string        `json:"lastSeen"`
-	History          []ValueEntry  `json:"history"`
+	File             string       `json:"file"`
+	Key              string       `json:"key"`
+	Type             string       `json:"type"`
+	Severity         string       `json:"severity,omitempty"` // Recorded by the scan
+	ChangeCount      int          `json:"changeCount"`
+	TotalOccurrences int          `json:"totalOccurrences"`
+	Authors          []string     `json:"authors"`
+	FirstSeen        string       `json:"firstSeen"`
+	LastSeen         string       `json:"lastSeen"`
+	History          []ValueEntry `json:"history"`
 }
 
 // ValueEntry represents a value in the history
 type ValueEntry struct {
-	Value       string   `json:"value"`
-	MaskedValue string   `json:"maskedValue"`
-	Occurrences int      `json:"occurrences"`
-	Authors     []string `json:"authors"`
-	FirstSeen   string   `json:"firstSeen"`
-	LastSeen    string   `json:"lastSeen"`
-}
-
-// StreamEntry represents a single entry from JSONL
-type StreamEntry struct {
-	File        string `json:"file"`
-	Key         string `json:"key"`
-	Value       string `json:"value"`
-	MaskedValue string `json:"maskedValue"`
-	Type        string `json:"type"`
-	Commit      string `json:"commit"`
-	Author      string `json:"author"`
-	Date        string `json:"date"`
-}
+	Value       string     `json:"value"`
+	MaskedValue string     `json:"maskedValue"`
+	Occurrences int        `json:"occurrences"`
+	Authors     []string   `json:"authors"`
+	FirstSeen   string     `json:"firstSeen"`
+	LastSeen    string     `json:"lastSeen"`
+	JWT         *JWTClaims `json:"jwt,omitempty"`
+
+	// Location of the latest occurrence
+	Line    int          `json:"line,omitempty"`
+	Offset  int          `json:"offset,omitempty"` // Characters before the value in its line
+	Context *CodeContext `json:"context,omitempty"`
+}
+
+// The scan result formats are declared in the model package, shared with
+// the scanner that writes them
+type (
+	CodeContext = model.CodeContext
+	JWTClaims   = model.JWTClaims
+	StreamEntry = model.StreamEntry
+)
 
 // AnalyzeOptions holds analysis options
 type AnalyzeOptions struct {
-	ShowValues bool
+	ShowValues bool // Keep raw values for the exports; otherwise only masked values remain
 	MaxSecrets int
 	OnProgress func(lines int)
+	// Skip leaves values out of the analysis, such as those triaged as
+	// false positives or accepted risks (triage.Store.Dismissed)
+	Skip func(file, key, value string) bool
 }
 
 // Analyzer performs analysis on scan results
@@ -94,36 +112,13 @@ func New() *Analyzer {
 	return &Analyzer{}
 }
 
-// ScanResult represents the structure of a JSON scan result file
-type ScanResult struct {
-	Repository   string       `json:"repository"`
-	Branch       string       `json:"branch"`
-	SecretsFound int          `json:"secretsFound"`
-	TotalValues  int          `json:"totalValues"`
-	Secrets      []ScanSecret `json:"secrets"`
-	ScanDate     string       `json:"scanDate"`
-}
-
-// ScanSecret represents a secret from the scan result
-type ScanSecret struct {
-	File             string           `json:"file"`
-	Key              string           `json:"key"`
-	Type             string           `json:"type"`
-	ChangeCount      int              `json:"changeCount"`
-	TotalOccurrences int              `json:"totalOccurrences"`
-	Authors          []string         `json:"authors"`
-	History          []ScanValueEntry `json:"history"`
-}
-
-// ScanValueEntry represents a value entry from the scan result
-type ScanValueEntry struct {
-	Value       string   `json:"value"`
-	MaskedValue string   `json:"maskedValue"`
-	Commits     []string `json:"commits"`
-	Authors     []string `json:"authors"`
-	FirstSeen   string   `json:"firstSeen"`
-	LastSeen    string   `json:"lastSeen"`
-}
+// ScanResult is a JSON scan result file, ScanSecret and ScanValueEntry
+// its secrets and their values
+type (
+	ScanResult     = model.ScanResult
+	ScanSecret     = model.Secret
+	ScanValueEntry = model.SecretValue
+)
 
 // AnalyzeJSON analyzes a JSON scan result file
 func (a *Analyzer) AnalyzeJSON(inputPath string, opts AnalyzeOptions) (*Analysis, error) {
@@ -153,23 +148,20 @@ func (a *Analyzer) AnalyzeJSON(inputPath string, opts AnalyzeOptions) (*Analysis
 	typeCounts := make(map[string]int)
 
 	secrets := make([]Secret, 0, len(scanResult.Secrets))
+	excluded := 0
 	for _, s := range scanResult.Secrets {
-		// Count file
-		fileCounts[s.File]++
-
-		// Count type
-		typeCounts[s.Type]++
-
-		// Count authors
-		for _, author := range s.Authors {
-			authorCounts[author]++
-		}
-
 		// Build history
 		history := make([]ValueEntry, 0, len(s.History))
 		firstSeen := ""
 		lastSeen := ""
+		changes, occurrences := s.ChangeCount, s.TotalOccurrences
 		for _, h := range s.History {
+			if opts.Skip != nil && opts.Skip(s.File, s.Key, h.Value) {
+				excluded++
+				changes--
+				occurrences -= len(h.Commits)
+				continue
+			}
 			history = append(history, ValueEntry{
 				Value:       h.Value,
 				MaskedValue: h.MaskedValue,
@@ -177,6 +169,10 @@ func (a *Analyzer) AnalyzeJSON(inputPath string, opts AnalyzeOptions) (*Analysis
 				Authors:     h.Authors,
 				FirstSeen:   h.FirstSeen,
 				LastSeen:    h.LastSeen,
+				JWT:         h.JWT,
+				Line:        h.Line,
+				Offset:      h.Offset,
+				Context:     h.Context,
 			})
 			if firstSeen == "" || compareDates(h.FirstSeen, firstSeen) < 0 {
 				firstSeen = h.FirstSeen
@@ -186,14 +182,31 @@ func (a *Analyzer) AnalyzeJSON(inputPath string, opts AnalyzeOptions) (*Analysis
 			}
 		}
 
+		// Every value left out: the secret is not reported
+		if len(history) == 0 && len(s.History) > 0 {
+			continue
+		}
+
+		// Count file
+		fileCounts[s.File]++
+
+		// Count type
+		typeCounts[s.Type]++
+
+		// Count authors
+		for _, author := range s.Authors {
+			authorCounts[author]++
+		}
+
 		stats.UniqueValues += len(history)
 
 		secrets = append(secrets, Secret{
 			File:             s.File,
 			Key:              s.Key,
 			Type:             s.Type,
-			ChangeCount:      s.ChangeCount,
-			TotalOccurrences: s.TotalOccurrences,
+			Severity:         s.Severity,
+			ChangeCount:      changes,
+			TotalOccurrences: occurrences,
 			Authors:          s.Authors,
 			FirstSeen:        firstSeen,
 			LastSeen:         lastSeen,
@@ -201,20 +214,24 @@ func (a *Analyzer) AnalyzeJSON(inputPath string, opts AnalyzeOptions) (*Analysis
 		})
 	}
 
+	if excluded > 0 {
+		stats.UniqueSecrets = len(secrets)
+		stats.TotalEntries -= excluded
+	}
+
 	// Sort and limit stats
 	stats.TopAuthors = sortMapToStats(authorCounts, 10)
 	stats.TopFiles = sortMapToFileStats(fileCounts, 10)
 	stats.TypeBreakdown = sortMapToTypeStats(typeCounts)
 
-	// Sort secrets by change count
-	sort.Slice(secrets, func(i, j int) bool {
-		return secrets[i].ChangeCount > secrets[j].ChangeCount
-	})
+	sortSecrets(secrets)
 
-	return &Analysis{
-		Stats:   stats,
-		Secrets: secrets,
-	}, nil
+	return applyValuePolicy(&Analysis{
+		Repository: scanResult.Repository,
+		Stats:      stats,
+		Secrets:    secrets,
+		Excluded:   excluded,
+	}, opts), nil
 }
 
 // AnalyzeJSONL analyzes a JSONL file
@@ -236,6 +253,7 @@ func (a *Analyzer) AnalyzeJSONL(inputPath string, opts AnalyzeOptions) (*Analysi
 	scanner := bufio.NewScanner(file)
 	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
 	lineCount := 0
+	excluded := make(map[string]bool) // Values left out, by file, key and value
 
 	for scanner.Scan() {
 		lineCount++
@@ -244,7 +262,11 @@ func (a *Analyzer) AnalyzeJSONL(inputPath string, opts AnalyzeOptions) (*Analysi
 		}
 
 		var entry StreamEntry
-		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Interrupted {
+			continue
+		}
+		if opts.Skip != nil && opts.Skip(entry.File, entry.Key, entry.Value) {
+			excluded[entry.File+"|"+entry.Key+"|"+entry.Value] = true
 			continue
 		}
 
@@ -257,6 +279,7 @@ func (a *Analyzer) AnalyzeJSONL(inputPath string, opts AnalyzeOptions) (*Analysi
 				file:      entry.File,
 				key:       entry.Key,
 				secretType: entry.Type,
+				severity:  entry.Severity,
 				values:    make(map[string]*valueData),
 				authors:   make(map[string]bool),
 				firstSeen: entry.Date,
@@ -278,7 +301,16 @@ func (a *Analyzer) AnalyzeJSONL(inputPath string, opts AnalyzeOptions) (*Analysi
 
 		vd := secret.values[entry.Value]
 		vd.count++
+		if entry.Severity != "" && config.SeverityRank(entry.Severity) < config.SeverityRank(secret.severity) {
+			secret.severity = entry.Severity
+		}
+		if entry.JWT != nil {
+			vd.jwt = entry.JWT
+		}
 		vd.authors[entry.Author] = true
+		if entry.Line > 0 && (vd.line == 0 || compareDates(entry.Date, vd.lastSeen) >= 0) {
+			vd.line, vd.offset, vd.context = entry.Line, entry.Offset, entry.Context
+		}
 
 		// Update dates
 		if compareDates(entry.Date, vd.firstSeen) < 0 {
@@ -304,13 +336,35 @@ func (a *Analyzer) AnalyzeJSONL(inputPath string, opts AnalyzeOptions) (*Analysi
 	}
 
 	// Build result
-	return a.buildAnalysis(secretsIndex, stats), nil
+	analysis := a.buildAnalysis(secretsIndex, stats)
+	analysis.Excluded = len(excluded)
+	return applyValuePolicy(analysis, opts), nil
+}
+
+// applyValuePolicy drops the raw values unless the options keep them, so
+// that no export can write them by mistake
+func applyValuePolicy(analysis *Analysis, opts AnalyzeOptions) *Analysis {
+	analysis.ValuesShown = opts.ShowValues
+	if opts.ShowValues {
+		return analysis
+	}
+	for i := range analysis.Secrets {
+		for j := range analysis.Secrets[i].History {
+			h := &analysis.Secrets[i].History[j]
+			if h.MaskedValue == "" && h.Value != "" {
+				h.MaskedValue = maskSecret(h.Value)
+			}
+			h.Value = ""
+		}
+	}
+	return analysis
 }
 
 type secretData struct {
 	file       string
 	key        string
 	secretType string
+	severity   string // Most severe recorded
 	values     map[string]*valueData
 	authors    map[string]bool
 	firstSeen  string
@@ -322,6 +376,10 @@ type valueData struct {
 	authors   map[string]bool
 	firstSeen string
 	lastSeen  string
+	jwt       *JWTClaims
+	line      int // Location of the latest occurrence
+	offset    int
+	context   *CodeContext
 }
 
 type statsData struct {
@@ -350,6 +408,10 @@ func (a *Analyzer) buildAnalysis(index map[string]*secretData, stats *statsData)
 				Authors:     authors,
 				FirstSeen:   vd.firstSeen,
 				LastSeen:    vd.lastSeen,
+				JWT:         vd.jwt,
+				Line:        vd.line,
+				Offset:      vd.offset,
+				Context:     vd.context,
 			})
 		}
 
@@ -372,6 +434,7 @@ func (a *Analyzer) buildAnalysis(index map[string]*secretData, stats *statsData)
 			File:             data.file,
 			Key:              data.key,
 			Type:             data.secretType,
+			Severity:         data.severity,
 			ChangeCount:      len(history),
 			TotalOccurrences: totalOccurrences,
 			Authors:          authors,
@@ -381,10 +444,7 @@ func (a *Analyzer) buildAnalysis(index map[string]*secretData, stats *statsData)
 		})
 	}
 
-	// Sort by change count
-	sort.Slice(secrets, func(i, j int) bool {
-		return secrets[i].ChangeCount > secrets[j].ChangeCount
-	})
+	sortSecrets(secrets)
 
 	// Build stats
 	topAuthors := sortMapToStats(stats.authors, 10)
@@ -409,6 +469,17 @@ func (a *Analyzer) buildAnalysis(index map[string]*secretData, stats *statsData)
 	}
 }
 
+// sortSecrets orders secrets by severity, then by change count
+func sortSecrets(secrets []Secret) {
+	sort.SliceStable(secrets, func(i, j int) bool {
+		ri, rj := config.SeverityRank(secrets[i].Severity), config.SeverityRank(secrets[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		return secrets[i].ChangeCount > secrets[j].ChangeCount
+	})
+}
+
 func maskSecret(value string) string {
 	if len(value) <= 4 {
 		return "****"
@@ -501,23 +572,36 @@ func sortMapToTypeStats(m map[string]int) []TypeStat {
 	return result
 }
 
-// GenerateReport generates a text report
+// GenerateReport generates a text report, in the language of the analysis
 func GenerateReport(analysis *Analysis, showValues bool, maxSecrets int) string {
 	var sb strings.Builder
+	p := i18n.For(analysis.Language)
+	// Rows of the secret boxes, padded to their width whatever the language
+	row := func(text string) {
+		sb.WriteString(fmt.Sprintf("│ %-76s │\n", truncate(text, 76)))
+	}
+	stat := func(label string, value string) {
+		sb.WriteString(fmt.Sprintf("  %-22s %s\n", p.T(label), value))
+	}
 
+	title := p.T("SECRET ANALYSIS REPORT")
 	sb.WriteString(strings.Repeat("═", 80) + "\n")
-	sb.WriteString("                     RAPPORT D'ANALYSE DES SECRETS\n")
+	sb.WriteString(strings.Repeat(" ", max((80-utf8.RuneCountInString(title))/2, 0)) + title + "\n")
 	sb.WriteString(strings.Repeat("═", 80) + "\n\n")
 
 	// Global stats
-	sb.WriteString("STATISTIQUES GLOBALES\n")
+	sb.WriteString(p.T("GLOBAL STATISTICS") + "\n")
 	sb.WriteString(strings.Repeat("─", 40) + "\n")
-	sb.WriteString(fmt.Sprintf("  Entrées analysées:     %d\n", analysis.Stats.TotalEntries))
-	sb.WriteString(fmt.Sprintf("  Secrets uniques:       %d\n", analysis.Stats.UniqueSecrets))
-	sb.WriteString(fmt.Sprintf("  Valeurs différentes:   %d\n\n", analysis.Stats.UniqueValues))
+	stat("Entries analyzed:", fmt.Sprint(analysis.Stats.TotalEntries))
+	stat("Unique secrets:", fmt.Sprint(analysis.Stats.UniqueSecrets))
+	stat("Distinct values:", fmt.Sprint(analysis.Stats.UniqueValues))
+	if h := analysis.Health; h != nil {
+		stat("Health score:", fmt.Sprintf("%d/100 (%s)", h.Score, h.Grade))
+	}
+	sb.WriteString("\n")
 
 	// Top authors
-	sb.WriteString("TOP AUTEURS (qui modifie le plus de secrets)\n")
+	sb.WriteString(p.T("TOP AUTHORS (who changes the most secrets)") + "\n")
 	sb.WriteString(strings.Repeat("─", 40) + "\n")
 	for _, stat := range analysis.Stats.TopAuthors {
 		bar := strings.Repeat("█", min(stat.Count*50/max(analysis.Stats.TotalEntries, 1), 30))
@@ -526,7 +610,7 @@ func GenerateReport(analysis *Analysis, showValues bool, maxSecrets int) string
 	sb.WriteString("\n")
 
 	// Top files
-	sb.WriteString("TOP FICHIERS (les plus impactés)\n")
+	sb.WriteString(p.T("TOP FILES (most affected)") + "\n")
 	sb.WriteString(strings.Repeat("─", 40) + "\n")
 	for _, stat := range analysis.Stats.TopFiles {
 		file := stat.File
@@ -537,8 +621,22 @@ func GenerateReport(analysis *Analysis, showValues bool, maxSecrets int) string
 	}
 	sb.WriteString("\n")
 
+	// Hotspots
+	if len(analysis.Hotspots) > 0 {
+		sb.WriteString(p.T("HOTSPOT FILES (secrets per 1000 lines)") + "\n")
+		sb.WriteString(strings.Repeat("─", 40) + "\n")
+		for _, h := range analysis.Hotspots {
+			density := p.T("deleted")
+			if h.Lines > 0 {
+				density = p.Sprintf("%.1f/KLOC (%d lines)", h.PerKLOC, h.Lines)
+			}
+			sb.WriteString(fmt.Sprintf("  %-50s %3d secrets  %s\n", truncate(h.File, 50), h.Secrets, density))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Types
-	sb.WriteString("TYPES DE SECRETS\n")
+	sb.WriteString(p.T("SECRET TYPES") + "\n")
 	sb.WriteString(strings.Repeat("─", 40) + "\n")
 	for _, stat := range analysis.Stats.TypeBreakdown {
 		sb.WriteString(fmt.Sprintf("  %-20s %d\n", stat.Type, stat.Count))
@@ -547,7 +645,7 @@ func GenerateReport(analysis *Analysis, showValues bool, maxSecrets int) string
 
 	// Secrets details
 	sb.WriteString(strings.Repeat("═", 80) + "\n")
-	sb.WriteString("SECRETS TRIÉS PAR FRÉQUENCE DE CHANGEMENT\n")
+	sb.WriteString(p.T("SECRETS SORTED BY CHANGE FREQUENCY") + "\n")
 	sb.WriteString(strings.Repeat("═", 80) + "\n\n")
 
 	displayed := analysis.Secrets
@@ -557,40 +655,74 @@ func GenerateReport(analysis *Analysis, showValues bool, maxSecrets int) string
 
 	for _, secret := range displayed {
 		sb.WriteString(fmt.Sprintf("┌%s┐\n", strings.Repeat("─", 78)))
-		sb.WriteString(fmt.Sprintf("│ %-76s │\n", truncate(secret.File, 76)))
-		sb.WriteString(fmt.Sprintf("│ Clé: %-71s │\n", truncate(secret.Key, 71)))
+		row(secret.File)
+		row(p.Sprintf("Key: %s", secret.Key))
 		sb.WriteString(fmt.Sprintf("├%s┤\n", strings.Repeat("─", 78)))
-		sb.WriteString(fmt.Sprintf("│ Type: %-15s Changements: %-5d Occurrences: %-10d │\n",
+		row(p.Sprintf("Type: %-15s Changes: %-5d Occurrences: %d",
 			secret.Type, secret.ChangeCount, secret.TotalOccurrences))
-		sb.WriteString(fmt.Sprintf("│ Auteurs: %-67s │\n", truncate(strings.Join(secret.Authors, ", "), 67)))
-		sb.WriteString(fmt.Sprintf("│ Période: %s → %-53s │\n", secret.FirstSeen[:10], secret.LastSeen[:10]))
+		if secret.Severity != "" {
+			row(p.Sprintf("Severity: %s", strings.ToUpper(secret.Severity)))
+		}
+		row(p.Sprintf("Authors: %s", strings.Join(secret.Authors, ", ")))
+		row(p.Sprintf("Period: %s → %s", secret.FirstSeen[:10], secret.LastSeen[:10]))
 		sb.WriteString(fmt.Sprintf("├%s┤\n", strings.Repeat("─", 78)))
-		sb.WriteString(fmt.Sprintf("│ %-76s │\n", "Historique des valeurs:"))
+		row(p.T("Value history:"))
 
 		for _, h := range secret.History {
 			val := h.MaskedValue
-			if showValues {
+			if showValues && analysis.ValuesShown {
 				val = h.Value
 			}
 			authors := strings.Join(h.Authors, ", ")
-			sb.WriteString(fmt.Sprintf("│   • %-40s (%dx par %s)%s │\n",
-				truncate(val, 40), h.Occurrences, truncate(authors, 20), strings.Repeat(" ", 5)))
+			row(fmt.Sprintf("  • %-40s %s", truncate(val, 40), p.Sprintf("(%dx by %s)", h.Occurrences, truncate(authors, 20))))
+			if h.JWT != nil {
+				row("    " + jwtSummary(p, h.JWT, time.Now()))
+			}
+			if h.Line > 0 && h.Offset > 0 {
+				row("    " + p.Sprintf("line %d, offset %d", h.Line, h.Offset))
+			} else if h.Line > 0 {
+				row("    " + p.Sprintf("line %d", h.Line))
+			}
+			if h.Context != nil {
+				for i, line := range h.Context.Lines {
+					sb.WriteString(fmt.Sprintf("│     %5d│ %-65s │\n", h.Context.Start+i, truncate(line, 65)))
+				}
+			}
 		}
 		sb.WriteString(fmt.Sprintf("└%s┘\n\n", strings.Repeat("─", 78)))
 	}
 
 	if len(analysis.Secrets) > maxSecrets && maxSecrets > 0 {
-		sb.WriteString(fmt.Sprintf("... et %d autres secrets\n", len(analysis.Secrets)-maxSecrets))
+		sb.WriteString(p.Sprintf("... and %d more secrets", len(analysis.Secrets)-maxSecrets) + "\n")
 	}
 
 	return sb.String()
 }
 
+// jwtSummary describes a JWT for the report: issuer and expiry, evaluated at now
+func jwtSummary(p i18n.Printer, c *JWTClaims, now time.Time) string {
+	parts := []string{"JWT " + c.Algorithm}
+	if c.Issuer != "" {
+		parts = append(parts, p.Sprintf("issuer %s", c.Issuer))
+	}
+	switch {
+	case c.ExpiresAt == "":
+		parts = append(parts, p.T("no expiry (revoke it)"))
+	case c.ExpiredAt(now):
+		parts = append(parts, p.Sprintf("expired on %s", c.ExpiresAt[:10]))
+	default:
+		parts = append(parts, p.Sprintf("VALID until %s (revoke it)", c.ExpiresAt[:10]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// truncate shortens s to maxLen characters, "..." marking the cut
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	return string(runes[:maxLen-3]) + "..."
 }
 
 // ExportCSV exports the analysis results to a CSV file
@@ -600,6 +732,8 @@ func ExportCSV(analysis *Analysis, outputPath string) error {
 		return err
 	}
 	defer file.Close()
+	locale := analysis.locale()
+	sep := locale.Separator
 
 	// Write BOM for Excel compatibility
 	file.WriteString("\xEF\xBB\xBF")
@@ -609,6 +743,7 @@ func ExportCSV(analysis *Analysis, outputPath string) error {
 		"File",
 		"Key",
 		"Type",
+		"Severity",
 		"ChangeCount",
 		"TotalOccurrences",
 		"Authors",
@@ -618,7 +753,7 @@ func ExportCSV(analysis *Analysis, outputPath string) error {
 		"DaysActive",
 		"Values",
 	}
-	file.WriteString(strings.Join(header, ";") + "\n")
+	file.WriteString(strings.Join(header, sep) + "\n")
 
 	// Write data rows
 	for _, secret := range analysis.Secrets {
@@ -632,71 +767,112 @@ func ExportCSV(analysis *Analysis, outputPath string) error {
 			}
 		}
 
-		// Collect masked values
+		// Collect masked values, raw ones if the analysis kept them
 		var values []string
 		for _, h := range secret.History {
-			values = append(values, h.MaskedValue)
+			if analysis.ValuesShown {
+				values = append(values, h.Value)
+			} else {
+				values = append(values, h.MaskedValue)
+			}
 		}
 
 		row := []string{
 			escapeCSV(secret.File),
 			escapeCSV(secret.Key),
 			escapeCSV(secret.Type),
+			secret.Severity,
 			fmt.Sprintf("%d", secret.ChangeCount),
 			fmt.Sprintf("%d", secret.TotalOccurrences),
 			escapeCSV(strings.Join(secret.Authors, ", ")),
 			fmt.Sprintf("%d", len(secret.Authors)),
-			formatDate(secret.FirstSeen),
-			formatDate(secret.LastSeen),
+			locale.Date(secret.FirstSeen),
+			locale.Date(secret.LastSeen),
 			fmt.Sprintf("%d", daysActive),
 			escapeCSV(strings.Join(values, " | ")),
 		}
-		file.WriteString(strings.Join(row, ";") + "\n")
+		file.WriteString(strings.Join(row, sep) + "\n")
 	}
 
 	return nil
 }
 
-// ExportStatsCSV exports summary statistics to a separate CSV file
+// ExportStatsCSV exports summary statistics to a separate CSV file, its
+// section headers and labels in the language of the analysis
 func ExportStatsCSV(analysis *Analysis, outputPath string) error {
 	file, err := os.Create(outputPath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
+	locale := analysis.locale()
+	row := func(fields ...any) {
+		cells := make([]string, len(fields))
+		for i, f := range fields {
+			switch f := f.(type) {
+			case string:
+				cells[i] = escapeCSV(f)
+			case float64:
+				cells[i] = locale.Float(f, 1)
+			default:
+				cells[i] = fmt.Sprint(f)
+			}
+		}
+		file.WriteString(strings.Join(cells, locale.Separator) + "\n")
+	}
+	p := i18n.For(analysis.Language)
+	section := func(name string) {
+		file.WriteString("=== " + p.T(name) + " ===\n")
+	}
 
 	// Write BOM for Excel compatibility
 	file.WriteString("\xEF\xBB\xBF")
 
 	// Summary stats
-	file.WriteString("=== SUMMARY ===\n")
-	file.WriteString("Metric;Value\n")
-	file.WriteString(fmt.Sprintf("Total Entries;%d\n", analysis.Stats.TotalEntries))
-	file.WriteString(fmt.Sprintf("Unique Secrets;%d\n", analysis.Stats.UniqueSecrets))
-	file.WriteString(fmt.Sprintf("Unique Values;%d\n", analysis.Stats.UniqueValues))
+	section("SUMMARY")
+	row(p.T("Metric"), p.T("Value"))
+	row(p.T("Total Entries"), analysis.Stats.TotalEntries)
+	row(p.T("Unique Secrets"), analysis.Stats.UniqueSecrets)
+	row(p.T("Unique Values"), analysis.Stats.UniqueValues)
+	if h := analysis.Health; h != nil {
+		row(p.T("Health Score"), h.Score)
+		row(p.T("Health Grade"), h.Grade)
+		row(p.T("Active Secrets"), h.ActiveSecrets)
+		row(p.T("Trend"), p.T(h.Direction))
+	}
 	file.WriteString("\n")
 
 	// Authors breakdown
-	file.WriteString("=== AUTHORS ===\n")
-	file.WriteString("Author;Count\n")
+	section("AUTHORS")
+	row(p.T("Author"), p.T("Count"))
 	for _, a := range analysis.Stats.TopAuthors {
-		file.WriteString(fmt.Sprintf("%s;%d\n", escapeCSV(a.Author), a.Count))
+		row(a.Author, a.Count)
 	}
 	file.WriteString("\n")
 
 	// Files breakdown
-	file.WriteString("=== FILES ===\n")
-	file.WriteString("File;Count\n")
+	section("FILES")
+	row(p.T("File"), p.T("Count"))
 	for _, f := range analysis.Stats.TopFiles {
-		file.WriteString(fmt.Sprintf("%s;%d\n", escapeCSV(f.File), f.Count))
+		row(f.File, f.Count)
 	}
 	file.WriteString("\n")
 
+	// Hotspot files
+	if len(analysis.Hotspots) > 0 {
+		section("HOTSPOTS")
+		row(p.T("File"), p.T("Secrets"), p.T("Values"), p.T("Lines"), p.T("PerKLOC"))
+		for _, h := range analysis.Hotspots {
+			row(h.File, h.Secrets, h.Values, h.Lines, h.PerKLOC)
+		}
+		file.WriteString("\n")
+	}
+
 	// Types breakdown
-	file.WriteString("=== SECRET TYPES ===\n")
-	file.WriteString("Type;Count\n")
+	section("SECRET TYPES")
+	row(p.T("Type"), p.T("Count"))
 	for _, t := range analysis.Stats.TypeBreakdown {
-		file.WriteString(fmt.Sprintf("%s;%d\n", escapeCSV(t.Type), t.Count))
+		row(t.Type, t.Count)
 	}
 
 	return nil
@@ -714,17 +890,6 @@ func escapeCSV(s string) string {
 	return s
 }
 
-func formatDate(dateStr string) string {
-	if dateStr == "" {
-		return ""
-	}
-	t, err := time.Parse(time.RFC3339, dateStr)
-	if err != nil {
-		return dateStr
-	}
-	return t.Format("2006-01-02")
-}
-
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzeSkip(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "secrets.json")
+	content := `{
+  "repository": "/repo",
+  "secretsFound": 2,
+  "totalValues": 3,
+  "secrets": [
+    {"file": "a.conf", "key": "password", "type": "password", "changeCount": 2, "totalOccurrences": 3,
+     "history": [{"value": "changeme", "commits": ["abc"]}, {"value": "s3cr3t-value", "commits": ["def", "ghi"]}]},
+    {"file": "test.conf", "key": "token", "type": "token", "changeCount": 1, "totalOccurrences": 1,
+     "history": [{"value": "dummy-token", "commits": ["abc"]}]}
+  ]
+}`
+	jsonlPath := filepath.Join(dir, "secrets.jsonl")
+	stream := `{"file":"a.conf","key":"password","value":"changeme","type":"password","commit":"abc"}
+{"file":"a.conf","key":"password","value":"s3cr3t-value","type":"password","commit":"def"}
+{"file":"test.conf","key":"token","value":"dummy-token","type":"token","commit":"abc"}
+{"file":"test.conf","key":"token","value":"dummy-token","type":"token","commit":"ghi"}
+`
+	if err := os.WriteFile(jsonPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jsonlPath, []byte(stream), 0644); err != nil {
+		t.Fatal(err)
+	}
+	skip := func(file, key, value string) bool { return value == "changeme" || file == "test.conf" }
+
+	a := New()
+	occurrences := map[string]int{jsonPath: 2, jsonlPath: 1}
+	for path, analyze := range map[string]func(string, AnalyzeOptions) (*Analysis, error){
+		jsonPath:  a.AnalyzeJSON,
+		jsonlPath: a.AnalyzeJSONL,
+	} {
+		result, err := analyze(path, AnalyzeOptions{Skip: skip})
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if result.Excluded != 2 || len(result.Secrets) != 1 || result.Stats.UniqueSecrets != 1 {
+			t.Fatalf("%s: excluded %d, secrets %+v, want a.conf alone", path, result.Excluded, result.Secrets)
+		}
+		// The stream lists one commit of the value kept, the JSON two
+		if secret := result.Secrets[0]; secret.ChangeCount != 1 || secret.TotalOccurrences != occurrences[path] {
+			t.Errorf("%s: %d changes, %d occurrences, want those of the value kept", path, secret.ChangeCount, secret.TotalOccurrences)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// AnonymizeSaltEnv names the variable holding the pseudonym salt
+const AnonymizeSaltEnv = config.AuthorSaltEnv
+
+// Pseudonym returns the stable pseudonym of an author (see config.Pseudonym)
+func Pseudonym(author, salt string) string {
+	return config.Pseudonym(author, salt)
+}
+
+// Anonymize replaces the authors of an analysis with pseudonyms and strips
+// every value, masked ones included, so the report can be shared outside
+func Anonymize(analysis *Analysis, salt string) {
+	if analysis.Repository != "" {
+		analysis.Repository = filepath.Base(analysis.Repository)
+	}
+	for i := range analysis.Stats.TopAuthors {
+		analysis.Stats.TopAuthors[i].Author = Pseudonym(analysis.Stats.TopAuthors[i].Author, salt)
+	}
+	for i := range analysis.Secrets {
+		secret := &analysis.Secrets[i]
+		pseudonymize(secret.Authors, salt)
+		for j := range secret.History {
+			secret.History[j].Value = ""
+			secret.History[j].MaskedValue = ""
+			if jwt := secret.History[j].JWT; jwt != nil {
+				jwt.Subject = ""
+			}
+			pseudonymize(secret.History[j].Authors, salt)
+		}
+	}
+}
+
+func pseudonymize(authors []string, salt string) {
+	for i, author := range authors {
+		authors[i] = Pseudonym(author, salt)
+	}
+}
+
+// AnonymizeResults writes an anonymized copy of a scan result file (JSON or
+// JSONL): "author" and "authors" fields get pseudonyms, "value" and
+// "maskedValue" fields are removed and the repository path is reduced to
+// its last element. Other fields are kept as they are.
+func AnonymizeResults(inputPath, outputPath, salt string) (int, error) {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return 0, err
+	}
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	if !strings.HasSuffix(inputPath, ".jsonl") {
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return 0, fmt.Errorf("invalid JSON results: %w", err)
+		}
+		count := anonymizeNode(doc, salt)
+		encoded, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return 0, err
+		}
+		_, err = out.Write(encoded)
+		return count, err
+	}
+
+	count := 0
+	w := bufio.NewWriter(out)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var entry any
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			continue
+		}
+		count += anonymizeNode(entry, salt)
+		encoded, err := json.Marshal(entry)
+		if err != nil {
+			return count, err
+		}
+		w.Write(encoded)
+		w.WriteByte('\n')
+	}
+	return count, w.Flush()
+}
+
+// anonymizeNode rewrites a decoded JSON document in place and returns the
+// number of values removed
+func anonymizeNode(node any, salt string) int {
+	removed := 0
+	switch n := node.(type) {
+	case map[string]any:
+		for key, child := range n {
+			switch key {
+			case "value", "maskedValue", "sub": // JWT subjects often name a user
+				delete(n, key)
+				if key == "value" {
+					removed++
+				}
+			case "repository":
+				// A local path often names its owner (/home/alice/...)
+				if path, ok := child.(string); ok {
+					n[key] = filepath.Base(path)
+				}
+			case "author":
+				if name, ok := child.(string); ok {
+					n[key] = Pseudonym(name, salt)
+				}
+			case "authors":
+				if names, ok := child.([]any); ok {
+					for i, name := range names {
+						if s, ok := name.(string); ok {
+							names[i] = Pseudonym(s, salt)
+						}
+					}
+				}
+			default:
+				removed += anonymizeNode(child, salt)
+			}
+		}
+	case []any:
+		for _, child := range n {
+			removed += anonymizeNode(child, salt)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,36 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnonymizeResultsJSONL(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "secrets.jsonl")
+	os.WriteFile(input, []byte(
+		`{"file":"app.conf","key":"db_password","value":"Sup3rS3cret!","maskedValue":"Su********t!","type":"password","commit":"abc","author":"Alice","date":"2024-01-01","status":"removed-in-history"}`+"\n"+
+			`{"file":"app.conf","key":"api_key","value":"abcd1234efgh","maskedValue":"ab********gh","type":"api_key","commit":"def","author":"alice ","date":"2024-01-02"}`+"\n"), 0644)
+	output := filepath.Join(dir, "out.jsonl")
+
+	removed, err := AnonymizeResults(input, output, "s3")
+	if err != nil || removed != 2 {
+		t.Fatalf("AnonymizeResults = %d, %v; want 2 values removed", removed, err)
+	}
+	data, _ := os.ReadFile(output)
+	text := string(data)
+	for _, leak := range []string{"Sup3rS3cret!", "Su**", "Alice", "alice", "abcd1234efgh"} {
+		if strings.Contains(text, leak) {
+			t.Errorf("Anonymized output still contains %q:\n%s", leak, text)
+		}
+	}
+	pseudonym := Pseudonym("Alice", "s3")
+	if strings.Count(text, pseudonym) != 2 || !strings.Contains(text, "removed-in-history") {
+		t.Errorf("Expected the stable pseudonym %s twice and other fields kept:\n%s", pseudonym, text)
+	}
+	if Pseudonym("Alice", "other") == pseudonym {
+		t.Error("Expected the salt to change pseudonyms")
+	}
+}
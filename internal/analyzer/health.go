@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"math"
+	"time"
+)
+
+// Health is a 0-100 "secret hygiene" score (100 = no secrets found) with the
+// penalty of each component, so teams can track a single number over time
+type Health struct {
+	Score    int    `json:"score"`
+	Grade    string `json:"grade"`    // A (>= 90) to F (< 40)
+	Density  int    `json:"density"`  // Penalty for the number of distinct values exposed (max 20)
+	Severity int    `json:"severity"` // Penalty for the severity mix (max 40)
+	Active   int    `json:"active"`   // Penalty for secrets still in place (max 25)
+	Trend    int    `json:"trend"`    // Penalty for a rising number of new values (max 15)
+
+	ActiveSecrets  int    `json:"activeSecrets"`
+	RecentValues   int    `json:"recentValues"`   // Values first seen in the last 90 days
+	PreviousValues int    `json:"previousValues"` // Values first seen in the 90 days before
+	Direction      string `json:"direction"`      // improving, stable or worsening
+}
+
+// severityWeights is how much one secret of each severity weighs in the score
+var severityWeights = map[string]float64{
+	"critical": 10,
+	"high":     5,
+	"medium":   2,
+	"low":      1,
+}
+
+// trendWindow is the period compared against the one before it (one quarter)
+const trendWindow = 90 * 24 * time.Hour
+
+// ComputeHealth scores an analysis. severityOf maps a secret type to its
+// severity (config.SeverityForType); now is the reference date for the trend.
+func ComputeHealth(analysis *Analysis, severityOf func(secretType string) string, now time.Time) *Health {
+	h := &Health{Direction: "stable"}
+
+	// Saturating penalties: the first findings cost the most
+	values := float64(analysis.Stats.UniqueValues)
+	h.Density = int(math.Round(20 * values / (values + 25)))
+
+	var weight float64
+	for _, s := range analysis.Secrets {
+		weight += severityWeights[severityOf(s.Type)]
+	}
+	h.Severity = int(math.Round(40 * weight / (weight + 50)))
+
+	// A secret is active when its latest value is still in the working tree.
+	// History-only results carry no working tree data: count secrets changed
+	// within the last year instead.
+	hasCurrent := false
+	for _, s := range analysis.Secrets {
+		for _, v := range s.History {
+			if containsAuthor(v.Authors, "current") {
+				hasCurrent = true
+			}
+		}
+	}
+	for _, s := range analysis.Secrets {
+		if len(s.History) == 0 {
+			continue
+		}
+		if hasCurrent {
+			if containsAuthor(s.History[len(s.History)-1].Authors, "current") {
+				h.ActiveSecrets++
+			}
+		} else if last, err := time.Parse(time.RFC3339, s.LastSeen); err == nil && now.Sub(last) < 4*trendWindow {
+			h.ActiveSecrets++
+		}
+	}
+	if len(analysis.Secrets) > 0 {
+		h.Active = int(math.Round(25 * float64(h.ActiveSecrets) / float64(len(analysis.Secrets))))
+	}
+
+	// Trend: values introduced this quarter compared with the previous one
+	for _, s := range analysis.Secrets {
+		for _, v := range s.History {
+			first, err := time.Parse(time.RFC3339, v.FirstSeen)
+			if err != nil {
+				continue
+			}
+			switch age := now.Sub(first); {
+			case age < trendWindow:
+				h.RecentValues++
+			case age < 2*trendWindow:
+				h.PreviousValues++
+			}
+		}
+	}
+	switch {
+	case h.RecentValues > h.PreviousValues:
+		h.Direction = "worsening"
+		h.Trend = int(math.Round(15 * float64(h.RecentValues-h.PreviousValues) / float64(h.RecentValues)))
+	case h.RecentValues < h.PreviousValues:
+		h.Direction = "improving"
+	}
+
+	h.Score = max(0, 100-h.Density-h.Severity-h.Active-h.Trend)
+	switch {
+	case h.Score >= 90:
+		h.Grade = "A"
+	case h.Score >= 75:
+		h.Grade = "B"
+	case h.Score >= 60:
+		h.Grade = "C"
+	case h.Score >= 40:
+		h.Grade = "D"
+	default:
+		h.Grade = "F"
+	}
+	return h
+}
+
+func containsAuthor(authors []string, author string) bool {
+	for _, a := range authors {
+		if a == author {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeHealth(t *testing.T) {
+	severityOf := func(secretType string) string {
+		if secretType == "api_key" {
+			return "critical"
+		}
+		return "medium"
+	}
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	if h := ComputeHealth(&Analysis{}, severityOf, now); h.Score != 100 || h.Grade != "A" {
+		t.Fatalf("clean repository scored %d (%s), want 100 (A)", h.Score, h.Grade)
+	}
+
+	analysis := &Analysis{
+		Stats: Stats{UniqueSecrets: 2, UniqueValues: 3},
+		Secrets: []Secret{
+			{Type: "api_key", LastSeen: "2024-06-20T00:00:00Z", History: []ValueEntry{
+				{FirstSeen: "2023-12-01T00:00:00Z", Authors: []string{"Alice"}},
+				{FirstSeen: "2024-06-20T00:00:00Z", Authors: []string{"Alice", "current"}},
+			}},
+			{Type: "password", LastSeen: "2024-06-01T00:00:00Z", History: []ValueEntry{
+				{FirstSeen: "2024-06-01T00:00:00Z", Authors: []string{"Bob"}},
+			}},
+		},
+	}
+	h := ComputeHealth(analysis, severityOf, now)
+	if h.ActiveSecrets != 1 {
+		t.Errorf("active = %d, want 1 (only the value still in the working tree)", h.ActiveSecrets)
+	}
+	if h.RecentValues != 2 || h.PreviousValues != 0 || h.Direction != "worsening" {
+		t.Errorf("trend = %d/%d %s, want 2/0 worsening", h.RecentValues, h.PreviousValues, h.Direction)
+	}
+	if want := 100 - h.Density - h.Severity - h.Active - h.Trend; h.Score != want || h.Score >= 100 {
+		t.Errorf("score = %d, want %d", h.Score, want)
+	}
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Hotspot is a file that accumulates credentials, ranked by density so the
+// config files most worth refactoring (to a vault, to env vars) come first
+type Hotspot struct {
+	File    string  `json:"file"`
+	Secrets int     `json:"secrets"` // Distinct keys holding a secret
+	Values  int     `json:"values"`  // Distinct values over the history
+	Lines   int     `json:"lines"`   // In the working tree; 0 if the file is gone
+	PerKLOC float64 `json:"perKloc"` // Secrets per 1000 lines; 0 if Lines is unknown
+}
+
+// ComputeHotspots ranks the files of an analysis by secrets per KLOC. Line
+// counts are read from the working tree of repoPath; files no longer there
+// (or an empty repoPath) are ranked after, by number of secrets.
+func ComputeHotspots(analysis *Analysis, repoPath string, limit int) []Hotspot {
+	index := make(map[string]*Hotspot)
+	var files []string
+	for _, s := range analysis.Secrets {
+		h, ok := index[s.File]
+		if !ok {
+			h = &Hotspot{File: s.File}
+			index[s.File] = h
+			files = append(files, s.File)
+		}
+		h.Secrets++
+		h.Values += len(s.History)
+	}
+
+	hotspots := make([]Hotspot, 0, len(files))
+	for _, file := range files {
+		h := index[file]
+		if repoPath != "" {
+			h.Lines = countLines(filepath.Join(repoPath, filepath.FromSlash(file)))
+		}
+		if h.Lines > 0 {
+			h.PerKLOC = float64(h.Secrets) * 1000 / float64(h.Lines)
+		}
+		hotspots = append(hotspots, *h)
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		a, b := hotspots[i], hotspots[j]
+		if a.PerKLOC != b.PerKLOC {
+			return a.PerKLOC > b.PerKLOC
+		}
+		if a.Secrets != b.Secrets {
+			return a.Secrets > b.Secrets
+		}
+		return a.File < b.File
+	})
+	if limit > 0 && len(hotspots) > limit {
+		hotspots = hotspots[:limit]
+	}
+	return hotspots
+}
+
+// countLines returns the number of lines of a file, 0 if it cannot be read
+func countLines(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return 0
+	}
+	lines := bytes.Count(data, []byte("\n"))
+	if data[len(data)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
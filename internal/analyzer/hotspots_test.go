@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComputeHotspots(t *testing.T) {
+	repo := t.TempDir()
+	os.WriteFile(filepath.Join(repo, "app.env"), []byte("A=1\nB=2\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "settings.yml"), []byte(strings.Repeat("x: y\n", 100)), 0644)
+
+	analysis := &Analysis{Secrets: []Secret{
+		{File: "settings.yml", Key: "a", History: make([]ValueEntry, 1)},
+		{File: "settings.yml", Key: "b", History: make([]ValueEntry, 1)},
+		{File: "settings.yml", Key: "c", History: make([]ValueEntry, 3)},
+		{File: "old.conf", Key: "a", History: make([]ValueEntry, 1)},
+		{File: "app.env", Key: "A", History: make([]ValueEntry, 2)},
+	}}
+
+	hotspots := ComputeHotspots(analysis, repo, 0)
+	if len(hotspots) != 3 {
+		t.Fatalf("got %d hotspots, want 3", len(hotspots))
+	}
+	if h := hotspots[0]; h.File != "app.env" || h.Lines != 2 || h.PerKLOC != 500 {
+		t.Errorf("densest file first, got %+v", h)
+	}
+	if h := hotspots[1]; h.File != "settings.yml" || h.Secrets != 3 || h.Values != 5 || h.PerKLOC != 30 {
+		t.Errorf("got %+v", h)
+	}
+	if h := hotspots[2]; h.File != "old.conf" || h.Lines != 0 {
+		t.Errorf("files no longer in the tree come last, got %+v", h)
+	}
+
+	if top := ComputeHotspots(analysis, "", 1); len(top) != 1 || top[0].File != "settings.yml" {
+		t.Errorf("without a working tree, rank by secrets, got %+v", top)
+	}
+}
@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	_ "embed"
+	"html/template"
+	"os"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/i18n"
+)
+
+// htmlBar is one bar of a chart in the HTML report
+type htmlBar struct {
+	Label   string
+	Count   int
+	Percent int // Width relative to the largest bar
+}
+
+// htmlChart is a titled bar chart
+type htmlChart struct {
+	Title  string
+	NoData string // Shown without bars
+	Bars   []htmlBar
+}
+
+// htmlReport is the data rendered by the HTML template
+type htmlReport struct {
+	Text      i18n.Printer // Language of the report
+	Generated string
+	Locale    Locale
+	RawValues bool // Analysis.ValuesShown
+	Stats     Stats
+	Health    *Health
+	Hotspots  []Hotspot
+	Authors   []htmlBar
+	Files     []htmlBar
+	Types     []htmlBar
+	Secrets   []Secret
+}
+
+// ExportHTML writes a self-contained HTML report (no external assets) with
+// sortable tables, author/file/type charts and the masked value history of
+// every secret, in the language of the analysis. Raw values are only
+// written when the analysis kept them (AnalyzeOptions.ShowValues), under a
+// warning.
+func ExportHTML(analysis *Analysis, outputPath string) error {
+	report := htmlReport{
+		Text:      i18n.For(analysis.Language),
+		Generated: analysis.locale().Time(time.Now()),
+		Locale:    analysis.locale(),
+		RawValues: analysis.ValuesShown,
+		Stats:     analysis.Stats,
+		Health:    analysis.Health,
+		Hotspots:  analysis.Hotspots,
+		Secrets:   analysis.Secrets,
+	}
+
+	for _, a := range analysis.Stats.TopAuthors {
+		report.Authors = append(report.Authors, htmlBar{Label: a.Author, Count: a.Count})
+	}
+	for _, f := range analysis.Stats.TopFiles {
+		report.Files = append(report.Files, htmlBar{Label: f.File, Count: f.Count})
+	}
+	for _, t := range analysis.Stats.TypeBreakdown {
+		report.Types = append(report.Types, htmlBar{Label: t.Type, Count: t.Count})
+	}
+	scaleBars(report.Authors)
+	scaleBars(report.Files)
+	scaleBars(report.Types)
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return htmlTemplate.Execute(file, report)
+}
+
+// scaleBars sets each bar width relative to the largest count
+func scaleBars(bars []htmlBar) {
+	largest := 1
+	for _, b := range bars {
+		largest = max(largest, b.Count)
+	}
+	for i := range bars {
+		bars[i].Percent = bars[i].Count * 100 / largest
+	}
+}
+
+// reportTemplate is the HTML report, embedded so the binary is self-contained
+//
+//go:embed report.html
+var reportTemplate string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"add":  func(a, b int) int { return a + b },
+	"rank": config.SeverityRank,
+	"chart": func(title, noData string, bars []htmlBar) htmlChart {
+		return htmlChart{Title: title, NoData: noData, Bars: bars}
+	},
+}).Parse(reportTemplate))
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportHTMLMasksValues(t *testing.T) {
+	analysis := &Analysis{
+		Stats: Stats{TotalEntries: 1, UniqueSecrets: 1, UniqueValues: 1,
+			TopAuthors: []AuthorStat{{Author: "Alice <script>", Count: 1}}},
+		Secrets: []Secret{{
+			File: "app.conf", Key: "db_password", Type: "password", ChangeCount: 1,
+			FirstSeen: "2024-01-15T10:30:00Z", LastSeen: "2024-01-15T10:30:00Z",
+			History: []ValueEntry{{Value: "SuperSecret!", MaskedValue: "Su********t!", Occurrences: 1}},
+		}},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := ExportHTML(analysis, path); err != nil {
+		t.Fatalf("ExportHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	if strings.Contains(html, "SuperSecret!") {
+		t.Error("raw value leaked into the report")
+	}
+	if !strings.Contains(html, "Su********t!") || !strings.Contains(html, "2024-01-15") {
+		t.Error("masked history missing from the report")
+	}
+	if strings.Contains(html, "Alice <script>") {
+		t.Error("author name not escaped")
+	}
+}
+
+func TestShowValuesReachesExportsOnlyWhenAsked(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "secrets.json")
+	results := `{"secrets": [{"file": "app.conf", "key": "db_password", "type": "password", "changeCount": 1,
+		"history": [{"value": "SuperSecret!", "maskedValue": "Su********t!", "commits": ["abc"],
+		"firstSeen": "2024-01-15T10:30:00Z", "lastSeen": "2024-01-15T10:30:00Z"}]}]}`
+	if err := os.WriteFile(input, []byte(results), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, show := range []bool{false, true} {
+		analysis, err := New().AnalyzeJSON(input, AnalyzeOptions{ShowValues: show})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := analysis.Secrets[0].History[0].Value != ""; got != show {
+			t.Errorf("ShowValues=%v: raw value kept = %v", show, got)
+		}
+		for _, export := range []func(*Analysis, string) error{ExportCSV, ExportHTML} {
+			path := filepath.Join(dir, "report")
+			if err := export(analysis, path); err != nil {
+				t.Fatal(err)
+			}
+			data, _ := os.ReadFile(path)
+			if strings.Contains(string(data), "SuperSecret!") != show {
+				t.Errorf("ShowValues=%v: raw value in the export = %v", show, !show)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// Locale formats the numbers and dates of the CSV and HTML exports, so a
+// spreadsheet opens them with the conventions of its own locale
+type Locale struct {
+	Name       string
+	Separator  string // Between CSV fields: Excel reads ; where the decimal mark is a comma
+	Decimal    string
+	DateLayout string
+	TimeLayout string // Date and time
+}
+
+// locales holds the settings.locale values (config.ReportLocales)
+var locales = map[string]Locale{
+	config.LocaleISO:  {Name: config.LocaleISO, Separator: ";", Decimal: ".", DateLayout: "2006-01-02", TimeLayout: "2006-01-02 15:04"},
+	config.LocaleEN:   {Name: config.LocaleEN, Separator: ",", Decimal: ".", DateLayout: "01/02/2006", TimeLayout: "01/02/2006 3:04 PM"},
+	config.LocaleENGB: {Name: config.LocaleENGB, Separator: ",", Decimal: ".", DateLayout: "02/01/2006", TimeLayout: "02/01/2006 15:04"},
+	config.LocaleFR:   {Name: config.LocaleFR, Separator: ";", Decimal: ",", DateLayout: "02/01/2006", TimeLayout: "02/01/2006 15:04"},
+	config.LocaleDE:   {Name: config.LocaleDE, Separator: ";", Decimal: ",", DateLayout: "02.01.2006", TimeLayout: "02.01.2006 15:04"},
+}
+
+// LookupLocale returns a report locale by name ("" for the default)
+func LookupLocale(name string) (Locale, error) {
+	if name == "" {
+		name = config.LocaleISO
+	}
+	locale, ok := locales[name]
+	if !ok {
+		return Locale{}, fmt.Errorf("unknown locale %q (%s)", name, strings.Join(config.ReportLocales, ", "))
+	}
+	return locale, nil
+}
+
+// locale returns the locale of the exports, the default when none is set
+func (a *Analysis) locale() Locale {
+	if a.Locale.Name == "" {
+		return locales[config.LocaleISO]
+	}
+	return a.Locale
+}
+
+// Date writes an RFC 3339 date of the results ("" stays empty, an
+// unparsable date is kept as is)
+func (l Locale) Date(date string) string {
+	if date == "" {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return date
+	}
+	return t.Format(l.DateLayout)
+}
+
+// Time writes a date and time
+func (l Locale) Time(t time.Time) string {
+	return t.Format(l.TimeLayout)
+}
+
+// Float writes a number with the given decimals
+func (l Locale) Float(f float64, decimals int) string {
+	return strings.Replace(strconv.FormatFloat(f, 'f', decimals, 64), ".", l.Decimal, 1)
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportLocale(t *testing.T) {
+	analysis := &Analysis{
+		Secrets: []Secret{{File: "app.conf", Key: "db_password", Type: "password",
+			FirstSeen: "2024-01-15T10:30:00Z", LastSeen: "2024-03-02T08:00:00Z"}},
+		Hotspots: []Hotspot{{File: "app.conf", Secrets: 1, Values: 1, Lines: 200, PerKLOC: 5}},
+	}
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		locale, row, date, kloc string
+	}{
+		{"", "app.conf;db_password;password", "2024-01-15", "app.conf;1;1;200;5.0"},
+		{"en", "app.conf,db_password,password", "01/15/2024", "app.conf,1,1,200,5.0"},
+		{"fr", "app.conf;db_password;password", "15/01/2024", "app.conf;1;1;200;5,0"},
+		{"de", "app.conf;db_password;password", "15.01.2024", "app.conf;1;1;200;5,0"},
+	} {
+		locale, err := LookupLocale(tc.locale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		analysis.Locale = locale
+		csv, stats, html := filepath.Join(dir, "a.csv"), filepath.Join(dir, "s.csv"), filepath.Join(dir, "r.html")
+		if err := ExportCSV(analysis, csv); err != nil {
+			t.Fatal(err)
+		}
+		if err := ExportStatsCSV(analysis, stats); err != nil {
+			t.Fatal(err)
+		}
+		if err := ExportHTML(analysis, html); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(csv)
+		if !strings.Contains(string(data), tc.row) || !strings.Contains(string(data), tc.date) {
+			t.Errorf("locale %q: CSV = %q, want %q and %q", tc.locale, data, tc.row, tc.date)
+		}
+		if data, _ := os.ReadFile(stats); !strings.Contains(string(data), tc.kloc) {
+			t.Errorf("locale %q: statistics = %q, want %q", tc.locale, data, tc.kloc)
+		}
+		if data, _ := os.ReadFile(html); !strings.Contains(string(data), ">"+tc.date+"<") {
+			t.Errorf("locale %q: HTML dates not written as %s", tc.locale, tc.date)
+		}
+	}
+
+	if _, err := LookupLocale("xx"); err == nil {
+		t.Error("unknown locale accepted")
+	}
+}
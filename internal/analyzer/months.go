@@ -0,0 +1,44 @@
+package analyzer
+
+import "time"
+
+// MonthStat counts the values that first appeared in a month
+type MonthStat struct {
+	Month string `json:"month"` // 2006-01
+	Count int    `json:"count"`
+}
+
+// ValuesByMonth counts the distinct values of an analysis by the month they
+// were first seen, from the earliest month to the latest, the months
+// without any included (none without dates)
+func ValuesByMonth(analysis *Analysis) []MonthStat {
+	counts := make(map[time.Time]int)
+	var first, last time.Time
+	for _, s := range analysis.Secrets {
+		for _, h := range s.History {
+			if len(h.FirstSeen) < 7 {
+				continue
+			}
+			month, err := time.Parse("2006-01", h.FirstSeen[:7])
+			if err != nil {
+				continue
+			}
+			counts[month]++
+			if first.IsZero() || month.Before(first) {
+				first = month
+			}
+			if month.After(last) {
+				last = month
+			}
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	var months []MonthStat
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		months = append(months, MonthStat{Month: month.Format("2006-01"), Count: counts[month]})
+	}
+	return months
+}
@@ -0,0 +1,21 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValuesByMonth(t *testing.T) {
+	analysis := &Analysis{Secrets: []Secret{
+		{History: []ValueEntry{{FirstSeen: "2024-11-03T10:00:00Z"}, {FirstSeen: "2025-02-01T00:00:00Z"}}},
+		{History: []ValueEntry{{FirstSeen: "2024-11-30T23:59:59+02:00"}, {FirstSeen: ""}}},
+	}}
+
+	want := []MonthStat{{"2024-11", 2}, {"2024-12", 0}, {"2025-01", 0}, {"2025-02", 1}}
+	if got := ValuesByMonth(analysis); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := ValuesByMonth(&Analysis{}); got != nil {
+		t.Errorf("without dates, got %v", got)
+	}
+}
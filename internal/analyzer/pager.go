@@ -0,0 +1,181 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+)
+
+// ResultPager gives paged access to a JSON or JSONL result file without
+// loading it into memory. Opening the pager indexes the byte offset of every
+// secret (JSON) or entry (JSONL); pages are then read on demand.
+type ResultPager struct {
+	Path       string
+	Repository string // From the JSON header (empty for JSONL)
+	Branch     string // From the JSON header (empty for JSONL)
+	IsJSONL    bool
+
+	offsets []int64 // Start offset of each record
+	ends    []int64 // End offset of each record
+}
+
+// OpenResults indexes a result file for paged display
+func OpenResults(path string) (*ResultPager, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	p := &ResultPager{Path: path, IsJSONL: strings.HasSuffix(path, ".jsonl")}
+	if p.IsJSONL {
+		err = p.indexJSONL(file)
+	} else {
+		err = p.indexJSON(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Len returns the number of records in the file
+func (p *ResultPager) Len() int {
+	return len(p.offsets)
+}
+
+// PageCount returns the number of pages for the given page size
+func (p *ResultPager) PageCount(pageSize int) int {
+	if pageSize <= 0 || p.Len() == 0 {
+		return 1
+	}
+	return (p.Len() + pageSize - 1) / pageSize
+}
+
+// Page reads one page of records (page is zero-based). JSONL entries are
+// returned as single-value secrets so both formats display the same way.
+func (p *ResultPager) Page(page, pageSize int) ([]ScanSecret, error) {
+	start := page * pageSize
+	if start < 0 || start >= p.Len() {
+		return nil, nil
+	}
+	end := start + pageSize
+	if end > p.Len() {
+		end = p.Len()
+	}
+
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rows := make([]ScanSecret, 0, end-start)
+	for i := start; i < end; i++ {
+		buf := make([]byte, p.ends[i]-p.offsets[i])
+		if _, err := file.ReadAt(buf, p.offsets[i]); err != nil && err != io.EOF {
+			return nil, err
+		}
+		// JSON records may carry the separating comma and whitespace
+		buf = bytes.TrimLeft(buf, ", \t\r\n")
+
+		if p.IsJSONL {
+			var entry StreamEntry
+			if err := json.Unmarshal(buf, &entry); err != nil {
+				continue
+			}
+			rows = append(rows, entrySecret(entry))
+			continue
+		}
+
+		var secret ScanSecret
+		if err := json.Unmarshal(buf, &secret); err != nil {
+			return nil, fmt.Errorf("invalid secret at offset %d: %w", p.offsets[i], err)
+		}
+		rows = append(rows, secret)
+	}
+	return rows, nil
+}
+
+func (p *ResultPager) indexJSONL(file *os.File) error {
+	reader := bufio.NewReaderSize(file, 64*1024)
+	var offset int64
+	for {
+		// A line longer than the buffer is read whole: its chunks would
+		// overwrite each other
+		line, err := reader.ReadBytes('\n')
+		length := int64(len(line))
+		// The marker of an interrupted scan is no entry
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 && string(trimmed) != model.InterruptedMarker {
+			p.offsets = append(p.offsets, offset)
+			p.ends = append(p.ends, offset+length)
+		}
+		offset += length
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *ResultPager) indexJSON(file *os.File) error {
+	dec := json.NewDecoder(bufio.NewReaderSize(file, 64*1024))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("invalid JSON format: expected an object")
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("invalid JSON format: %w", err)
+		}
+		key, _ := tok.(string)
+
+		switch key {
+		case "repository":
+			dec.Decode(&p.Repository)
+		case "branch":
+			dec.Decode(&p.Branch)
+		case "secrets":
+			if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+				// "secrets": null
+				continue
+			}
+			for dec.More() {
+				start := dec.InputOffset()
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return fmt.Errorf("invalid JSON format: %w", err)
+				}
+				p.offsets = append(p.offsets, start)
+				p.ends = append(p.ends, dec.InputOffset())
+			}
+			dec.Token() // closing ]
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("invalid JSON format: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// entrySecret wraps a JSONL entry as a single-value secret, its value
+// masked
+func entrySecret(entry StreamEntry) ScanSecret {
+	secret := model.EntrySecret(entry)
+	if secret.History[0].MaskedValue == "" {
+		secret.History[0].MaskedValue = maskSecret(entry.Value)
+	}
+	return secret
+}
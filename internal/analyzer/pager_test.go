@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResultPagerJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	content := `{
+  "repository": "/repo",
+  "branch": "--all",
+  "secretsFound": 3,
+  "secrets": [
+    {"file": "a.conf", "key": "password", "type": "password", "changeCount": 1, "history": [{"value": "one1"}]},
+    {"file": "b.conf", "key": "token", "type": "token", "changeCount": 2, "history": []},
+    {"file": "c.conf", "key": "api_key", "type": "api_key", "changeCount": 1, "history": []}
+  ],
+  "scanDate": "2024-01-01T00:00:00Z"
+}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := OpenResults(path)
+	if err != nil {
+		t.Fatalf("Failed to open results: %v", err)
+	}
+	if p.Len() != 3 || p.Repository != "/repo" || p.PageCount(2) != 2 {
+		t.Fatalf("Unexpected index: len=%d repo=%q pages=%d", p.Len(), p.Repository, p.PageCount(2))
+	}
+
+	rows, err := p.Page(1, 2)
+	if err != nil {
+		t.Fatalf("Failed to read page: %v", err)
+	}
+	if len(rows) != 1 || rows[0].File != "c.conf" {
+		t.Errorf("Expected last page to hold c.conf, got %+v", rows)
+	}
+}
+
+func TestResultPagerJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.jsonl")
+	content := `{"file":"a.conf","key":"password","value":"one1","type":"password","commit":"abc","author":"Alice"}
+
+{"file":"b.conf","key":"token","value":"two2","type":"token","commit":"def","author":"Bob"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := OpenResults(path)
+	if err != nil {
+		t.Fatalf("Failed to open results: %v", err)
+	}
+	if p.Len() != 2 {
+		t.Fatalf("Expected 2 entries (blank lines skipped), got %d", p.Len())
+	}
+
+	rows, err := p.Page(0, 10)
+	if err != nil {
+		t.Fatalf("Failed to read page: %v", err)
+	}
+	if len(rows) != 2 || rows[1].Authors[0] != "Bob" || rows[1].History[0].MaskedValue != "****" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+}
+
+func TestResultPagerJSONLLongLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.jsonl")
+	// Lines longer than the read buffer: an entry, and a blank line that is
+	// none however its chunks are read
+	long := `{"file":"a.min.js","key":"token","value":"` + strings.Repeat("x", 200*1024) + `","type":"token"}`
+	content := long + "\n" + strings.Repeat(" ", 200*1024) + "\n" + `{"file":"b.conf","key":"token","value":"two2","type":"token"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := OpenResults(path)
+	if err != nil {
+		t.Fatalf("Failed to open results: %v", err)
+	}
+	if p.Len() != 2 {
+		t.Fatalf("Expected 2 entries, got %d", p.Len())
+	}
+	rows, err := p.Page(0, 10)
+	if err != nil {
+		t.Fatalf("Failed to read page: %v", err)
+	}
+	if rows[0].File != "a.min.js" || rows[1].File != "b.conf" {
+		t.Errorf("Unexpected rows: %s, %s", rows[0].File, rows[1].File)
+	}
+}
@@ -0,0 +1,77 @@
+// Package auditlog records sensitive operations, such as reports written
+// with raw secret values, in an append-only JSON Lines file.
+package auditlog
+
+import (
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// PathEnv overrides the location of the audit log
+const PathEnv = "GITSECRET_AUDIT_LOG"
+
+// Recorded actions
+const (
+	ActionRawValuesReport = "raw-values-report" // Report written with raw values
+	ActionRawValueShown   = "raw-value-shown"   // Values of a secret revealed in the TUI detail view
+	ActionRawValuesReview = "raw-values-review" // Triage session of audit-findings showing raw values
+)
+
+// Entry is a line of the audit log
+type Entry struct {
+	Time   string `json:"time"`
+	User   string `json:"user"` // user@host
+	Action string `json:"action"`
+	Input  string `json:"input,omitempty"`
+	Output string `json:"output,omitempty"`
+	Values int    `json:"values,omitempty"` // Raw values concerned
+}
+
+// Path returns the audit log file: $GITSECRET_AUDIT_LOG, else audit.log in
+// the user configuration directory
+func Path() string {
+	if path := os.Getenv(PathEnv); path != "" {
+		return path
+	}
+	return filepath.Join(config.UserConfigDir(), "audit.log")
+}
+
+// Record appends an entry, stamped with the time and the user. Callers
+// refuse the operation when it fails.
+func Record(e Entry) error {
+	e.Time = time.Now().UTC().Format(time.RFC3339)
+	e.User = operator()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// operator identifies who ran the operation (user@host)
+func operator() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, _ := os.Hostname()
+	return name + "@" + host
+}
@@ -0,0 +1,39 @@
+package auditlog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAppendsEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.log")
+	t.Setenv(PathEnv, path)
+
+	for _, output := range []string{"a.csv", "b.html"} {
+		if err := Record(Entry{Action: ActionRawValuesReport, Input: "secrets.json", Output: output, Values: 3}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d entries, want 2:\n%s", len(lines), data)
+	}
+	var e Entry
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Output != "b.html" || e.Time == "" || !strings.Contains(e.User, "@") {
+		t.Errorf("entry = %+v", e)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("audit log mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,109 @@
+package cleaner
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	scannerPkg "github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// anchorSeparator matches what lies between a key and its value: quotes,
+// spaces and the assignment ("key = ", "key: ", "\"key\": \"", "key => ").
+// It is written for every backend: Python, Java, Go and sed regexes.
+const anchorSeparator = `["']?[ \t]*[:=]>?[ \t]*["']?`
+
+// unanchoredTypes are finding types whose key is not written before the value
+var unanchoredTypes = map[string]bool{
+	config.URLCredentialType: true,
+	scannerPkg.PEMKeyword:    true,
+}
+
+// anchorable reports whether an occurrence reads "key = value" in its file.
+// JWTs are found by shape, often after a scheme ("Bearer eyJ..."), and
+// Terraform outputs and credential files name values apart from the line.
+func anchorable(e SecretEntry) bool {
+	return !unanchoredTypes[e.Type] &&
+		!strings.HasPrefix(e.Value, "eyJ") &&
+		config.CredentialFileKind(e.File) == "" &&
+		!config.IsTerraformFile(e.File)
+}
+
+// anchorValues splits the values to clean into the ones replaced after their
+// keys, with the keys they go by, and the ones replaced wherever they appear:
+// values with no occurrence in the scan entries, or with one where the key
+// is not written before the value
+func anchorValues(secrets []string, entries []SecretEntry) (keys map[string][]string, bare []string) {
+	wanted := make(map[string]bool, len(secrets))
+	for _, v := range secrets {
+		wanted[v] = true
+	}
+
+	keys = make(map[string][]string)
+	blocked := make(map[string]bool)
+	for _, e := range entries {
+		if !wanted[e.Value] {
+			continue
+		}
+		if !anchorable(e) {
+			blocked[e.Value] = true
+			continue
+		}
+		if !containsString(keys[e.Value], e.Key) {
+			keys[e.Value] = append(keys[e.Value], e.Key)
+		}
+	}
+
+	for _, v := range secrets {
+		if blocked[v] || len(keys[v]) == 0 {
+			delete(keys, v)
+			bare = append(bare, v)
+		}
+	}
+	return keys, bare
+}
+
+// groupAnchoredPatterns builds the key-anchored rules (at most 100 values
+// each, sharing their replacement text): group 1 holds the key and
+// separator, kept by the replacement. A key of the batch may match another
+// value of the batch, which is still a secret after a secret key.
+func groupAnchoredPatterns(keys map[string][]string, r replacer) []rule {
+	values := make([]string, 0, len(keys))
+	for v := range keys {
+		values = append(values, v)
+	}
+	values = byLength(values)
+
+	var rules []rule
+	for _, batch := range r.batches(values) {
+		var batchKeys []string
+		escaped := make([]string, len(batch))
+		for j, v := range batch {
+			escaped[j] = valuePattern(v)
+			for _, k := range keys[v] {
+				if !containsString(batchKeys, k) {
+					batchKeys = append(batchKeys, k)
+				}
+			}
+		}
+		batchKeys = byLength(batchKeys)
+		for j, k := range batchKeys {
+			batchKeys[j] = regexp.QuoteMeta(k)
+		}
+
+		rules = append(rules, rule{
+			pattern: "((" + strings.Join(batchKeys, "|") + ")" + anchorSeparator + ")(" + strings.Join(escaped, "|") + ")",
+			text:    r.text(batch[0]),
+		})
+	}
+	return rules
+}
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
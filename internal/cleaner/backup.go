@@ -0,0 +1,43 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// A backup branch only keeps the branch checked out, and the rewrite moves
+// it with the others when it covers every ref. CleanOptions.Bundle writes
+// every ref to a git bundle instead: a single file outside the repository
+// that neither the rewrite nor the gc touches.
+
+// createBundle writes every ref of a repository to a new git bundle and
+// returns its absolute path. An existing file is not overwritten: it may be
+// the backup of an earlier clean.
+func createBundle(repoPath, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(abs); err == nil {
+		return "", fmt.Errorf("%s already exists", abs)
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "bundle", "create", "--quiet", abs, "--all")
+	cmd.Dir = repoPath
+	done := debugbundle.Track(cmd)
+	out, err := cmd.CombinedOutput()
+	done(err)
+	if err != nil {
+		os.Remove(abs)
+		return "", fmt.Errorf("git bundle create: %s", strings.TrimSpace(string(out)))
+	}
+	return abs, nil
+}
@@ -0,0 +1,30 @@
+package cleaner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCleanBacksUpToBundle(t *testing.T) {
+	repo := newTestRepo(t, map[string]string{"app.env": "API_TOKEN=tok-123456789\n"})
+	bundle := filepath.Join(t.TempDir(), "backups", "repo.bundle")
+	opts := CleanOptions{Tool: ToolNative, Source: "history", NoBackup: true, Bundle: bundle}
+
+	result, err := New().Clean(repo, []string{"tok-123456789"}, opts)
+	if err != nil || !result.Success || result.BackupBundle != bundle {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+	// The bundle holds the history before the rewrite
+	restored := filepath.Join(t.TempDir(), "restored")
+	gitTest(t, filepath.Dir(restored), "clone", "-q", bundle, restored)
+	if got := gitTest(t, restored, "show", "HEAD:app.env"); got != "API_TOKEN=tok-123456789\n" {
+		t.Errorf("bundle app.env = %q", got)
+	}
+
+	// An earlier backup is never overwritten
+	result, err = New().Clean(repo, []string{"tok-123456789"}, opts)
+	if err != nil || result.Success || !strings.Contains(result.Message, "already exists") {
+		t.Errorf("second Clean = %+v, %v", result, err)
+	}
+}
@@ -2,27 +2,45 @@ package cleaner
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 
-	scannerPkg "github.com/Drilmo/git-secret-scanner/internal/scanner"
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+	"github.com/Drilmo/git-secret-scanner/internal/model"
 )
 
 // CleanOptions holds cleaning options
 type CleanOptions struct {
-	Tool       string          // auto, filter-repo, bfg, filter-branch
-	Source     string          // current, history, both
-	FilePaths  map[string]bool // Files to clean (only these files will be modified for current files)
-	DryRun     bool
-	Force      bool
-	NoBackup   bool
-	OnProgress func(step, total int, message string)
+	Tool        string          // auto, filter-repo, bfg, native, filter-branch
+	Source      string          // current, history, both
+	FilePaths   map[string]bool // Files to clean (only these files will be modified for current files)
+	DryRun      bool
+	Force       bool
+	NoBackup    bool
+	Anchored    bool          // Replace values after their keys ("key = value") where the scan saw them so
+	Entries     []SecretEntry // Occurrences of the values, giving their keys (Anchored)
+	Paths       PathFilter    // Files the rewrite is limited to (all if empty)
+	MaxFileSize int64         // Larger current files are left unchanged (config.DefaultMaxFileSizeKB if 0, no limit if negative)
+	Replacement string        // Text written instead of the values, with ${KEY}, ${TYPE}, ${ENV:NAME} (DefaultReplacement if empty)
+	DeleteFiles []string      // Globs of the files removed whole ("*.pem", "id_rsa"; names match in any directory)
+	Mirror      bool          // Rewrite a temporary mirror clone, verified, instead of the repository (see PushMirror)
+	Credentials *Credentials  // Given to git to clone the mirror when the credential helpers have none (nil for none)
+	Bundle      string        // Git bundle of every ref written before the rewrite ("" for none; never overwritten)
+	OnProgress  func(step, total int, message string)
+	Output      io.Writer // Output of the rewrite tools and git gc (os.Stdout and os.Stderr if nil)
+
+	// Interrupt stops the clean once done: the rewrite tool is interrupted
+	// and the result tells what was left behind (nil: runs to the end)
+	Interrupt context.Context
 }
 
 // CleanResult holds cleaning results
@@ -30,13 +48,30 @@ type CleanResult struct {
 	Tool           string
 	Source         string
 	SecretsRemoved int
+	AnchoredValues int // Values replaced only after their keys
 	PatternsUsed   int
 	FilesModified  int // Number of current files modified
 	Success        bool
 	Message        string
 	BackupBranch   string
+	BackupBundle   string // Absolute path of the bundle written before the rewrite (CleanOptions.Bundle)
 	DryRun         bool
-	PreviewSecrets []string // First few secrets (masked) for preview
+	PreviewSecrets []string          // First few secrets (masked) for preview
+	Left           []LeftSecret      // Secrets deliberately kept (differential cleaning)
+	Filter         string            // Load filter the secrets passed (LoadFilter.String)
+	Filtered       int               // Occurrences the load filter left out
+	Risky          []RiskyValue      // Replacements that may damage unrelated data
+	TooLarge       []string          // Current files over the size limit, left unchanged
+	Interrupted    bool              // Stopped through CleanOptions.Interrupt
+	Replacement    string            // Text written instead of the values (${KEY} and ${TYPE} per value)
+	DeletedFiles   []string          // Paths of the history and index removed whole (CleanOptions.DeleteFiles)
+	Mirror         string            // Bare clone holding the rewritten history (CleanOptions.Mirror)
+	MirrorRefs     map[string]string // Branches and tags of the mirror when cloned, the leases of PushMirror
+	Remaining      []string          // Paths of the mirror still holding a value or a deleted file
+	Verified       bool              // The rewritten history was rescanned (by the caller, see Survivors)
+	Survivors      []Survivor        // Cleaned values the rescan still found
+	VerifyError    string            // Why the rescan did not complete
+	Auth           *AuthError        // The origin refused to be cloned without credentials
 }
 
 // Cleaner performs git history cleaning
@@ -69,13 +104,19 @@ func GetAvailableTools() map[string]bool {
 	return map[string]bool{
 		"filter-repo":   HasFilterRepo(),
 		"bfg":           HasBFG(),
+		ToolNative:      true, // Built in
 		"filter-branch": true, // Always available
 	}
 }
 
 // Clean performs the cleaning operation
 func (c *Cleaner) Clean(repoPath string, secrets []string, opts CleanOptions) (*CleanResult, error) {
-	if len(secrets) == 0 {
+	// Every tool replaces text line by line; scans report the whole values
+	cleaned := secrets
+	secrets = splitMultiline(secrets)
+	debugbundle.Redact(cleaned...)
+	debugbundle.Redact(secrets...)
+	if len(secrets) == 0 && len(opts.DeleteFiles) == 0 {
 		return &CleanResult{
 			Success: true,
 			Message: "No secrets to clean",
@@ -95,8 +136,27 @@ func (c *Cleaner) Clean(repoPath string, secrets []string, opts CleanOptions) (*
 		tool = selectBestTool()
 	}
 
-	// Group secrets into patterns
-	patterns := groupSecretsIntoPatterns(secrets)
+	// Group secrets into patterns: anchored on their keys where possible,
+	// the rest replaced wherever they appear
+	var keys map[string][]string
+	bare := secrets
+	if opts.Anchored {
+		keys, bare = anchorValues(secrets, opts.Entries)
+	}
+	replace, err := newReplacer(opts.Replacement, secrets, opts.Entries)
+	if err != nil {
+		return nil, err
+	}
+	patterns := groupSecretsIntoPatterns(bare, replace)
+	anchored := groupAnchoredPatterns(keys, replace)
+
+	// Files removed whole, wherever they appear in the history
+	var deleted []string
+	if len(opts.DeleteFiles) > 0 {
+		if deleted, err = listDeletedFiles(repoPath, opts.DeleteFiles); err != nil {
+			return nil, err
+		}
+	}
 
 	// For dry run, prepare preview and return early
 	if opts.DryRun {
@@ -118,38 +178,119 @@ func (c *Cleaner) Clean(repoPath string, secrets []string, opts CleanOptions) (*
 			msg = fmt.Sprintf("[DRY-RUN] Would clean %d secrets in current files + git history using %s", len(secrets), tool)
 		}
 
+		if !opts.Paths.Empty() {
+			msg += fmt.Sprintf(" (files: %s)", opts.Paths)
+		}
+		if len(opts.DeleteFiles) > 0 {
+			msg += fmt.Sprintf(", and delete %d files", len(deleted))
+		}
+		if opts.Mirror && source != "current" {
+			msg += ", in a mirror clone"
+		}
+		if opts.Bundle != "" && source != "current" {
+			msg += fmt.Sprintf(", after a backup to %s", opts.Bundle)
+		}
+
 		return &CleanResult{
 			Tool:           tool,
 			Source:         source,
 			SecretsRemoved: len(secrets),
-			PatternsUsed:   len(patterns),
+			AnchoredValues: len(keys),
+			PatternsUsed:   len(patterns) + len(anchored),
 			Success:        true,
 			Message:        msg,
 			DryRun:         true,
 			PreviewSecrets: preview,
+			Risky:          CheckValues(bare),
+			Replacement:    replace.String(),
+			DeletedFiles:   deleted,
 		}, nil
 	}
 
-	// Create backup unless disabled (only for history cleaning)
+	steps := newCleanSteps(opts, source)
+
+	// The mirror is cloned first: nothing is changed when the origin cannot
+	// be reached
+	historyPath := repoPath
+	var mirrorRefs map[string]string
+	if (source == "history" || source == "both") && opts.Mirror {
+		if tool == "filter-branch" {
+			return &CleanResult{
+				Success: false,
+				Source:  source,
+				Message: "git-filter-branch needs a working tree: choose another tool to clean a mirror clone",
+			}, nil
+		}
+		steps.next("Cloning a mirror of the repository...")
+		done := debugbundle.Step("clone a mirror")
+		historyPath, mirrorRefs, err = cloneMirror(repoPath, opts.Credentials)
+		done(err)
+		if err != nil {
+			var auth *AuthError
+			errors.As(err, &auth)
+			return &CleanResult{
+				Success: false,
+				Source:  source,
+				Auth:    auth,
+				Message: fmt.Sprintf("Failed to clone a mirror, nothing was cleaned: %v", err),
+			}, nil
+		}
+	}
+	// dropMirror removes the mirror of a clean stopped before the rewrite
+	dropMirror := func() {
+		if historyPath != repoPath {
+			RemoveMirror(historyPath)
+		}
+	}
+
+	// Create backup unless disabled (only for history cleaning; a mirror
+	// clean leaves the repository as it is)
 	var backupBranch string
-	if !opts.NoBackup && (source == "history" || source == "both") {
+	if !opts.NoBackup && !opts.Mirror && (source == "history" || source == "both") {
 		backupBranch = fmt.Sprintf("backup-before-clean-%d", os.Getpid())
 		cmd := exec.Command("git", "branch", backupBranch)
 		cmd.Dir = repoPath
-		cmd.Run()
+		done := debugbundle.Track(cmd)
+		done(cmd.Run())
+	}
+
+	// The bundle is required when asked for: nothing is cleaned without it
+	var bundle string
+	if opts.Bundle != "" && (source == "history" || source == "both") {
+		steps.next("Backing up to " + opts.Bundle + "...")
+		done := debugbundle.Step("back up to a bundle")
+		bundle, err = createBundle(repoPath, opts.Bundle)
+		done(err)
+		if err != nil {
+			dropMirror()
+			return &CleanResult{
+				Success:      false,
+				Source:       source,
+				BackupBranch: backupBranch,
+				Message:      fmt.Sprintf("Failed to back up to a bundle, nothing was cleaned: %v", err),
+			}, nil
+		}
 	}
 
 	var result *CleanResult
-	var err error
 	var filesModified int
+	var tooLarge []string
 
 	// Clean current files if needed
 	if source == "current" || source == "both" {
-		if opts.OnProgress != nil {
-			opts.OnProgress(1, 3, "Cleaning current files...")
+		steps.next("Cleaning current files...")
+		done := debugbundle.Step("clean current files")
+		maxFileSize := opts.MaxFileSize
+		if maxFileSize == 0 {
+			maxFileSize = config.DefaultMaxFileSizeKB * 1024
+		}
+		filesModified, tooLarge, err = c.cleanCurrentFiles(repoPath, bare, anchored, replace, opts.Paths.filter(opts.FilePaths), maxFileSize)
+		if err == nil && len(opts.DeleteFiles) > 0 {
+			err = deleteCurrentFiles(repoPath, opts.DeleteFiles)
 		}
-		filesModified, err = c.cleanCurrentFiles(repoPath, secrets, opts.FilePaths)
+		done(err)
 		if err != nil {
+			dropMirror()
 			return &CleanResult{
 				Success: false,
 				Source:  source,
@@ -159,40 +300,87 @@ func (c *Cleaner) Clean(repoPath string, secrets []string, opts CleanOptions) (*
 	}
 
 	// Clean git history if needed
-	if source == "history" || source == "both" {
-		if opts.OnProgress != nil {
-			step := 1
-			if source == "both" {
-				step = 2
-			}
-			opts.OnProgress(step, 3, fmt.Sprintf("Cleaning git history using %s with %d patterns", tool, len(patterns)))
+	if (source == "history" || source == "both") && opts.interrupted() {
+		msg := "Interrupted before the history was rewritten"
+		if source == "both" {
+			msg = fmt.Sprintf("Interrupted after cleaning %d current files: the history was not rewritten", filesModified)
 		}
+		dropMirror()
+		return &CleanResult{
+			Success:       false,
+			Interrupted:   true,
+			Source:        source,
+			FilesModified: filesModified,
+			BackupBranch:  backupBranch,
+			BackupBundle:  bundle,
+			Message:       msg,
+		}, nil
+	}
+	if source == "history" || source == "both" {
+		steps.next(fmt.Sprintf("Cleaning git history using %s with %d patterns", tool, len(patterns)+len(anchored)))
 
+		done := debugbundle.Step("clean history with " + tool)
 		switch tool {
 		case "filter-repo":
-			result, err = c.cleanWithFilterRepo(repoPath, patterns, opts)
+			result, err = c.cleanWithFilterRepo(historyPath, patterns, anchored, opts)
 		case "bfg":
-			result, err = c.cleanWithBFG(repoPath, secrets, opts)
+			result, err = c.cleanWithBFG(historyPath, bare, anchored, replace, opts)
+		case ToolNative:
+			result, err = c.cleanWithNative(historyPath, bare, anchored, replace, opts)
 		default:
-			result, err = c.cleanWithFilterBranch(repoPath, patterns, opts)
+			result, err = c.cleanWithFilterBranch(historyPath, patterns, anchored, opts)
+		}
+		if err == nil && !result.Success {
+			if opts.interrupted() {
+				result.Interrupted = true
+				result.Message = interruptedMessage(tool, backupBranch, bundle)
+				if opts.Mirror {
+					result.Message = fmt.Sprintf("Interrupted while %s rewrote the mirror clone: the repository is unchanged", tool)
+				}
+			}
+			done(errors.New(result.Message))
+		} else {
+			done(err)
 		}
 
+		if opts.Mirror && (err != nil || !result.Success) {
+			RemoveMirror(historyPath)
+		}
 		if err != nil {
 			return nil, err
 		}
 
 		// Run git gc after history rewrite
 		if result.Success {
-			if opts.OnProgress != nil {
-				opts.OnProgress(3, 3, "Running git gc...")
+			steps.next("Running git gc...")
+			if hasGit() {
+				cmd := exec.Command("git", "reflog", "expire", "--expire=now", "--all")
+				cmd.Dir = historyPath
+				done := debugbundle.Track(cmd)
+				done(cmd.Run())
+
+				cmd = exec.Command("git", "gc", "--prune=now", "--aggressive")
+				cmd.Dir = historyPath
+				cmd.Stderr = opts.stderr()
+				done = debugbundle.Track(cmd)
+				done(cmd.Run())
+			} else {
+				done := debugbundle.Step("purge the old objects")
+				done(purgeObjects(historyPath))
 			}
-			cmd := exec.Command("git", "reflog", "expire", "--expire=now", "--all")
-			cmd.Dir = repoPath
-			cmd.Run()
+		}
 
-			cmd = exec.Command("git", "gc", "--prune=now", "--aggressive")
-			cmd.Dir = repoPath
-			cmd.Run()
+		// The mirror is only pushed once nothing is left to clean in it
+		if result.Success && opts.Mirror {
+			steps.next("Verifying the mirror...")
+			done := debugbundle.Step("verify the mirror")
+			result.Remaining, err = verifyRewrite(historyPath, bare, anchored, replace, opts)
+			done(err)
+			if err != nil {
+				RemoveMirror(historyPath)
+				return nil, fmt.Errorf("verifying the mirror clone: %w", err)
+			}
+			result.Mirror, result.MirrorRefs = historyPath, mirrorRefs
 		}
 	} else {
 		// Current files only - create simple success result
@@ -205,10 +393,16 @@ func (c *Cleaner) Clean(repoPath string, secrets []string, opts CleanOptions) (*
 	result.Tool = tool
 	result.Source = source
 	result.SecretsRemoved = len(secrets)
-	result.PatternsUsed = len(patterns)
+	result.AnchoredValues = len(keys)
+	result.PatternsUsed = len(patterns) + len(anchored)
 	result.FilesModified = filesModified
+	result.TooLarge = tooLarge
 	result.BackupBranch = backupBranch
+	result.BackupBundle = bundle
 	result.DryRun = false
+	result.Risky = CheckValues(bare)
+	result.Replacement = replace.String()
+	result.DeletedFiles = deleted
 
 	// Update message based on source
 	if result.Success {
@@ -220,6 +414,22 @@ func (c *Cleaner) Clean(repoPath string, secrets []string, opts CleanOptions) (*
 		default:
 			result.Message = fmt.Sprintf("Successfully cleaned %d secrets in %d files + git history using %s", len(secrets), filesModified, tool)
 		}
+		if len(opts.DeleteFiles) > 0 {
+			result.Message += fmt.Sprintf(", %d files deleted", len(deleted))
+		}
+		if result.Mirror != "" {
+			result.Message += fmt.Sprintf(", in the mirror clone %s", result.Mirror)
+			if len(result.Remaining) > 0 {
+				result.Message += fmt.Sprintf(" (verification failed: %d files still hold values)", len(result.Remaining))
+			}
+		}
+		if !opts.Paths.Empty() {
+			// The values stay in the other files: they are not recorded as
+			// cleaned, which would flag them as reintroduced
+			result.Message += fmt.Sprintf(" (files: %s)", opts.Paths)
+		} else if err := recordCleaned(repoPath, cleaned); err != nil {
+			result.Message += fmt.Sprintf(" (cleaned values not recorded in %s: %v)", config.BaselineFile, err)
+		}
 	}
 
 	return result, nil
@@ -250,50 +460,65 @@ func selectBestTool() string {
 	if HasBFG() {
 		return "bfg"
 	}
-	return "filter-branch"
+	return ToolNative
 }
 
-// Group secrets into regex patterns (max 100 per pattern)
-func groupSecretsIntoPatterns(secrets []string) []string {
-	// Sort by length (longest first)
-	sorted := make([]string, len(secrets))
-	copy(sorted, secrets)
-	sort.Slice(sorted, func(i, j int) bool {
-		return len(sorted[i]) > len(sorted[j])
-	})
-
-	var patterns []string
-	batchSize := 100
-
-	for i := 0; i < len(sorted); i += batchSize {
-		end := i + batchSize
-		if end > len(sorted) {
-			end = len(sorted)
+// splitMultiline replaces multi-line values (private key blocks) by their
+// lines. The BEGIN/END markers and short lines are kept: they identify
+// nothing and are shared with other keys.
+func splitMultiline(secrets []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, secret := range secrets {
+		lines := []string{secret}
+		if strings.Contains(secret, "\n") {
+			lines = nil
+			for _, line := range strings.Split(secret, "\n") {
+				line = strings.TrimSpace(line)
+				if len(line) >= 16 && !strings.HasPrefix(line, "-----") && !strings.Contains(line, ":") {
+					lines = append(lines, line)
+				}
+			}
+		}
+		for _, line := range lines {
+			if !seen[line] {
+				seen[line] = true
+				out = append(out, line)
+			}
 		}
+	}
+	return out
+}
 
-		batch := sorted[i:end]
+// Group secrets into regex patterns (max 100 per pattern, sharing their
+// replacement text)
+func groupSecretsIntoPatterns(secrets []string, r replacer) []rule {
+	// Longest first, so the alternation prefers the longer values
+	var rules []rule
+	for _, batch := range r.batches(byLength(secrets)) {
 		escaped := make([]string, len(batch))
 		for j, s := range batch {
-			escaped[j] = regexp.QuoteMeta(s)
+			escaped[j] = valuePattern(s)
 		}
-
-		pattern := "(" + strings.Join(escaped, "|") + ")"
-		patterns = append(patterns, pattern)
+		rules = append(rules, rule{pattern: "(" + strings.Join(escaped, "|") + ")", text: r.text(batch[0])})
 	}
-
-	return patterns
+	return rules
 }
 
-// cleanCurrentFiles replaces secrets in current files without rewriting git history
-// Only files listed in allowedFiles will be modified (if nil, no files are modified)
-func (c *Cleaner) cleanCurrentFiles(repoPath string, secrets []string, allowedFiles map[string]bool) (int, error) {
+// cleanCurrentFiles replaces secrets, then the key-anchored patterns, in current files without rewriting git history
+// Only files listed in allowedFiles will be modified (if nil, no files are modified); files over maxFileSize
+// (no limit if negative) are left unchanged and returned
+func (c *Cleaner) cleanCurrentFiles(repoPath string, secrets []string, anchored []rule, r replacer, allowedFiles map[string]bool, maxFileSize int64) (int, []string, error) {
 	filesModified := 0
+	var tooLarge []string
 
 	// If no allowed files specified, don't modify anything
 	if allowedFiles == nil || len(allowedFiles) == 0 {
-		return 0, nil
+		return 0, nil, nil
 	}
 
+	cleaner := newContentCleaner(secrets, anchored, r)
+
 	// Only process files that are in the allowed list
 	for filePath := range allowedFiles {
 		// Build full path
@@ -310,8 +535,9 @@ func (c *Cleaner) cleanCurrentFiles(repoPath string, secrets []string, allowedFi
 			continue
 		}
 
-		// Skip large files (> 1MB)
-		if info.Size() > 1024*1024 {
+		// Skip large files
+		if maxFileSize >= 0 && info.Size() > maxFileSize {
+			tooLarge = append(tooLarge, filePath)
 			continue
 		}
 
@@ -322,14 +548,7 @@ func (c *Cleaner) cleanCurrentFiles(repoPath string, secrets []string, allowedFi
 		}
 
 		// Check if file contains any secrets and replace them
-		modified := false
-		contentStr := string(content)
-		for _, secret := range secrets {
-			if strings.Contains(contentStr, secret) {
-				contentStr = strings.ReplaceAll(contentStr, secret, "***REMOVED***")
-				modified = true
-			}
-		}
+		contentStr, modified := cleaner.clean(string(content))
 
 		// Write back if modified
 		if modified {
@@ -340,10 +559,11 @@ func (c *Cleaner) cleanCurrentFiles(repoPath string, secrets []string, allowedFi
 		}
 	}
 
-	return filesModified, nil
+	sort.Strings(tooLarge)
+	return filesModified, tooLarge, nil
 }
 
-func (c *Cleaner) cleanWithFilterRepo(repoPath string, patterns []string, opts CleanOptions) (*CleanResult, error) {
+func (c *Cleaner) cleanWithFilterRepo(repoPath string, patterns, anchored []rule, opts CleanOptions) (*CleanResult, error) {
 	// Create replacements file
 	replacementsFile := fmt.Sprintf("/tmp/replacements-%d.txt", os.Getpid())
 	f, err := os.Create(replacementsFile)
@@ -351,23 +571,45 @@ func (c *Cleaner) cleanWithFilterRepo(repoPath string, patterns []string, opts C
 		return nil, err
 	}
 
-	for _, pattern := range patterns {
-		f.WriteString(fmt.Sprintf("regex:%s==>***REMOVED***\n", pattern))
+	for _, rule := range patterns {
+		f.WriteString(fmt.Sprintf("regex:%s==>%s\n", rule.pattern, pythonReplacement(rule.text)))
+	}
+	for _, rule := range anchored {
+		f.WriteString(fmt.Sprintf("regex:%s==>\\1%s\n", rule.pattern, pythonReplacement(rule.text)))
 	}
 	f.Close()
 	defer os.Remove(replacementsFile)
 
-	args := []string{"filter-repo", "--replace-text", replacementsFile}
+	args := []string{"filter-repo"}
+	switch {
+	case len(patterns)+len(anchored) == 0:
+		// Only files to delete
+	case !opts.Paths.Empty():
+		// --replace-text rewrites every blob: the callback only rewrites
+		// the selected files (git-filter-repo 2.45 or later)
+		args = append(args, "--file-info-callback", opts.Paths.filterRepoCallback(patterns, anchored))
+	default:
+		args = append(args, "--replace-text", replacementsFile)
+	}
+	if len(opts.DeleteFiles) > 0 {
+		for _, glob := range deletionPathspecs(opts.DeleteFiles) {
+			args = append(args, "--path-glob", glob)
+		}
+		args = append(args, "--invert-paths")
+	}
 	if opts.Force {
 		args = append(args, "--force")
 	}
 
-	cmd := exec.Command("git", args...)
+	cmd := opts.command("git", args...)
 	cmd.Dir = repoPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = opts.stdout()
+	cmd.Stderr = opts.stderr()
+	done := debugbundle.Track(cmd)
 
-	if err := cmd.Run(); err != nil {
+	err = cmd.Run()
+	done(err)
+	if err != nil {
 		return &CleanResult{
 			Success: false,
 			Message: fmt.Sprintf("git-filter-repo failed: %v", err),
@@ -380,7 +622,7 @@ func (c *Cleaner) cleanWithFilterRepo(repoPath string, patterns []string, opts C
 	}, nil
 }
 
-func (c *Cleaner) cleanWithBFG(repoPath string, secrets []string, opts CleanOptions) (*CleanResult, error) {
+func (c *Cleaner) cleanWithBFG(repoPath string, secrets []string, anchored []rule, r replacer, opts CleanOptions) (*CleanResult, error) {
 	// Create replacements file
 	replacementsFile := fmt.Sprintf("/tmp/bfg-replacements-%d.txt", os.Getpid())
 	f, err := os.Create(replacementsFile)
@@ -389,16 +631,39 @@ func (c *Cleaner) cleanWithBFG(repoPath string, secrets []string, opts CleanOpti
 	}
 
 	for _, secret := range secrets {
-		f.WriteString(secret + "\n")
+		if len(secret) < MinBareLength {
+			f.WriteString("regex:" + valuePattern(secret) + "==>" + javaReplacement(r.text(secret)) + "\n")
+			continue
+		}
+		f.WriteString(secret + "==>" + javaReplacement(r.text(secret)) + "\n")
+	}
+	for _, rule := range anchored {
+		f.WriteString("regex:" + rule.pattern + "==>$1" + javaReplacement(rule.text) + "\n")
 	}
 	f.Close()
 	defer os.Remove(replacementsFile)
 
-	cmd := exec.Command("bfg", "--replace-text", replacementsFile, repoPath)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	filter, err := opts.Paths.bfgArgs()
+	if err != nil {
+		return &CleanResult{Success: false, Message: err.Error()}, nil
+	}
+	deletion, err := bfgDeleteArgs(opts.DeleteFiles)
+	if err != nil {
+		return &CleanResult{Success: false, Message: err.Error()}, nil
+	}
+	var args []string
+	if len(secrets)+len(anchored) > 0 {
+		args = append([]string{"--replace-text", replacementsFile}, filter...)
+	}
+	args = append(args, deletion...)
+	cmd := opts.command("bfg", append(args, repoPath)...)
+	cmd.Stdout = opts.stdout()
+	cmd.Stderr = opts.stderr()
+	done := debugbundle.Track(cmd)
 
-	if err := cmd.Run(); err != nil {
+	err = cmd.Run()
+	done(err)
+	if err != nil {
 		return &CleanResult{
 			Success: false,
 			Message: fmt.Sprintf("BFG failed: %v", err),
@@ -411,22 +676,47 @@ func (c *Cleaner) cleanWithBFG(repoPath string, secrets []string, opts CleanOpti
 	}, nil
 }
 
-func (c *Cleaner) cleanWithFilterBranch(repoPath string, patterns []string, opts CleanOptions) (*CleanResult, error) {
+func (c *Cleaner) cleanWithFilterBranch(repoPath string, patterns, anchored []rule, opts CleanOptions) (*CleanResult, error) {
 	// Build sed command
-	sedParts := make([]string, len(patterns))
-	for i, pattern := range patterns {
-		sedParts[i] = fmt.Sprintf("s/%s/***REMOVED***/g", pattern)
+	sedParts := make([]string, 0, len(patterns)+len(anchored))
+	for _, rule := range patterns {
+		sedParts = append(sedParts, fmt.Sprintf("s/%s/%s/g", rule.pattern, sedReplacement(rule.text)))
+	}
+	for _, rule := range anchored {
+		sedParts = append(sedParts, fmt.Sprintf(`s/%s/\1%s/g`, rule.pattern, sedReplacement(rule.text)))
 	}
-	sedCommand := strings.Join(sedParts, "; ")
+	// The command is single-quoted for the shell
+	sedCommand := strings.ReplaceAll(strings.Join(sedParts, "; "), "'", `'\''`)
 
-	filterCommand := fmt.Sprintf(`git ls-files -z | xargs -0 sed -i '' '%s' 2>/dev/null || true`, sedCommand)
+	var pathspecs string
+	for _, spec := range opts.Paths.pathspecs() {
+		pathspecs += " '" + strings.ReplaceAll(spec, "'", `'\''`) + "'"
+	}
+	if pathspecs != "" {
+		pathspecs = " --" + pathspecs
+	}
 
-	cmd := exec.Command("git", "filter-branch", "-f", "--tree-filter", filterCommand, "--", "--all")
+	args := []string{"filter-branch", "-f"}
+	if len(sedParts) > 0 {
+		args = append(args, "--tree-filter", fmt.Sprintf(`git ls-files -z%s | xargs -0 sed -i '' '%s' 2>/dev/null || true`, pathspecs, sedCommand))
+	}
+	if len(opts.DeleteFiles) > 0 {
+		var deleted string
+		for _, spec := range deletionPathspecs(opts.DeleteFiles) {
+			deleted += " '" + strings.ReplaceAll(spec, "'", `'\''`) + "'"
+		}
+		args = append(args, "--index-filter", "git rm -r -q --cached --ignore-unmatch --"+deleted)
+	}
+
+	cmd := opts.command("git", append(args, "--", "--all")...)
 	cmd.Dir = repoPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = opts.stdout()
+	cmd.Stderr = opts.stderr()
+	done := debugbundle.Track(cmd)
 
-	if err := cmd.Run(); err != nil {
+	err := cmd.Run()
+	done(err)
+	if err != nil {
 		return &CleanResult{
 			Success: false,
 			Message: fmt.Sprintf("git-filter-branch failed: %v", err),
@@ -442,13 +732,27 @@ func (c *Cleaner) cleanWithFilterBranch(repoPath string, patterns []string, opts
 // LoadSecretsResult holds secrets and detected source
 type LoadSecretsResult struct {
 	Secrets   []string
-	FilePaths []string          // List of file paths containing secrets
-	FileMap   map[string]bool   // Map of file paths for quick lookup
-	Source    string            // "current", "history", or "both"
+	FilePaths []string        // List of file paths containing secrets
+	FileMap   map[string]bool // Map of file paths for quick lookup
+	Source    string          // "current", "history", or "both"
+	Entries   []SecretEntry   // Each file/key/value occurrence, for triage lookups
+	Filtered  int             // Occurrences left out by the LoadFilter
+}
+
+// SecretEntry is one occurrence of a secret value in the scan results
+type SecretEntry struct {
+	File  string
+	Key   string
+	Value string
+	Type  string
 }
 
-// LoadSecretsFromJSONL loads secrets from a JSONL file and detects source
-func LoadSecretsFromJSONL(path string) (*LoadSecretsResult, error) {
+// LoadSecretsFromJSONL loads the secrets of a JSONL file that pass filter,
+// and detects source
+func LoadSecretsFromJSONL(path string, filter LoadFilter) (*LoadSecretsResult, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -457,18 +761,31 @@ func LoadSecretsFromJSONL(path string) (*LoadSecretsResult, error) {
 
 	values := make(map[string]bool)
 	filePaths := make(map[string]bool)
+	seen := make(map[SecretEntry]bool)
+	var entries []SecretEntry
+	filtered := 0
 	hasCurrent := false
 	hasHistory := false
 	fileScanner := bufio.NewScanner(file)
 
 	for fileScanner.Scan() {
-		var entry scannerPkg.StreamEntry
+		var entry model.StreamEntry
 		if err := json.Unmarshal(fileScanner.Bytes(), &entry); err != nil {
 			continue
 		}
 		if entry.Value != "" && !strings.Contains(entry.Value, "REMOVED") {
+			if !filter.keep(entry) {
+				filtered++
+				continue
+			}
 			values[entry.Value] = true
 
+			occurrence := SecretEntry{File: entry.File, Key: entry.Key, Value: entry.Value, Type: entry.Type}
+			if !seen[occurrence] {
+				seen[occurrence] = true
+				entries = append(entries, occurrence)
+			}
+
 			// Track file path for current files
 			if entry.File != "" {
 				filePaths[entry.File] = true
@@ -506,17 +823,23 @@ func LoadSecretsFromJSONL(path string) (*LoadSecretsResult, error) {
 		FilePaths: paths,
 		FileMap:   filePaths,
 		Source:    source,
+		Entries:   entries,
+		Filtered:  filtered,
 	}, nil
 }
 
-// LoadSecretsFromJSON loads secrets from a JSON scan result file and detects source
-func LoadSecretsFromJSON(path string) (*LoadSecretsResult, error) {
+// LoadSecretsFromJSON loads the secrets of a JSON scan result file that pass
+// filter, and detects source
+func LoadSecretsFromJSON(path string, filter LoadFilter) (*LoadSecretsResult, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var result scannerPkg.ScanResult
+	var result model.ScanResult
 	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, err
 	}
@@ -525,14 +848,25 @@ func LoadSecretsFromJSON(path string) (*LoadSecretsResult, error) {
 	hasCurrent := false
 	hasHistory := false
 	filePaths := make(map[string]bool)
+	values := make(map[string]bool)
+	var entries []SecretEntry
+	filtered := 0
 
 	for _, secret := range result.Secrets {
-		// Track file path
-		if secret.File != "" {
-			filePaths[secret.File] = true
-		}
-
-		for _, h := range secret.History {
+		occurrences := secret.Entries()
+		for i, h := range secret.History {
+			if !filter.keep(occurrences[i]) {
+				filtered++
+				continue
+			}
+			// Track file path
+			if secret.File != "" {
+				filePaths[secret.File] = true
+			}
+			if h.Value != "" && !strings.Contains(h.Value, "REMOVED") {
+				values[h.Value] = true
+				entries = append(entries, SecretEntry{File: secret.File, Key: secret.Key, Value: h.Value, Type: secret.Type})
+			}
 			for _, commit := range h.Commits {
 				if commit == "current" {
 					hasCurrent = true
@@ -557,9 +891,11 @@ func LoadSecretsFromJSON(path string) (*LoadSecretsResult, error) {
 	}
 
 	return &LoadSecretsResult{
-		Secrets:   scannerPkg.GetAllValues(&result),
+		Secrets:   sortedValues(values),
 		FilePaths: paths,
 		FileMap:   filePaths,
 		Source:    source,
+		Entries:   entries,
+		Filtered:  filtered,
 	}, nil
 }
@@ -0,0 +1,109 @@
+package cleaner
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// Globs of CleanOptions.DeleteFiles follow BFG and .gitignore: a glob
+// without "/" matches the file name in any directory ("id_rsa", "*.pem"),
+// one with "/" the whole path ("backups/*.sql").
+
+// deletedFile reports whether a path is deleted by one of the globs
+func deletedFile(globs []string, file string) bool {
+	file = strings.ReplaceAll(file, "\\", "/")
+	for _, glob := range globs {
+		target := file
+		if !strings.Contains(glob, "/") {
+			target = path.Base(file)
+		}
+		if globRegexp(glob).MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// deletionPathspecs are the globs as git pathspecs and git-filter-repo
+// --path-glob patterns, where "*" also matches "/": names are looked for
+// at the top and under every directory
+func deletionPathspecs(globs []string) []string {
+	var specs []string
+	for _, glob := range globs {
+		specs = append(specs, glob)
+		if !strings.Contains(glob, "/") {
+			specs = append(specs, "*/"+glob)
+		}
+	}
+	return specs
+}
+
+// bfgDeleteArgs is the --delete-files option of BFG, which matches file
+// names only
+func bfgDeleteArgs(globs []string) ([]string, error) {
+	if len(globs) == 0 {
+		return nil, nil
+	}
+	for _, glob := range globs {
+		if strings.Contains(glob, "/") {
+			return nil, fmt.Errorf("BFG only deletes files by name, not %q: use git-filter-repo", glob)
+		}
+	}
+	glob := globs[0]
+	if len(globs) > 1 {
+		glob = "{" + strings.Join(globs, ",") + "}"
+	}
+	return []string{"--delete-files", glob}, nil
+}
+
+// listDeletedFiles lists the paths of the history and of the index that the
+// globs delete
+func listDeletedFiles(repoPath string, globs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, args := range [][]string{
+		{"log", "--all", "--format=", "--name-only", "-z"},
+		{"ls-files", "-z"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		done := debugbundle.Track(cmd)
+		out, err := cmd.Output()
+		done(err)
+		if err != nil {
+			return nil, fmt.Errorf("listing the files to delete: %v", err)
+		}
+		for _, file := range bytes.Split(out, []byte{0}) {
+			name := strings.TrimSpace(string(file))
+			if name != "" && deletedFile(globs, name) {
+				seen[name] = true
+			}
+		}
+	}
+
+	files := make([]string, 0, len(seen))
+	for file := range seen {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// deleteCurrentFiles removes the tracked files the globs delete from the
+// working tree and the index, for the user to commit
+func deleteCurrentFiles(repoPath string, globs []string) error {
+	cmd := exec.Command("git", append([]string{"rm", "-r", "-q", "--ignore-unmatch", "--"}, deletionPathspecs(globs)...)...)
+	cmd.Dir = repoPath
+	done := debugbundle.Track(cmd)
+	out, err := cmd.CombinedOutput()
+	done(err)
+	if err != nil {
+		return fmt.Errorf("git rm: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
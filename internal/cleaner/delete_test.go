@@ -0,0 +1,34 @@
+package cleaner
+
+import "testing"
+
+func TestDeletedFile(t *testing.T) {
+	globs := []string{"*.pem", "id_rsa", "backups/*.sql"}
+
+	cases := map[string]bool{
+		"server.pem":           true,
+		"deploy/certs/key.pem": true,
+		"id_rsa":               true,
+		"home/.ssh/id_rsa":     true,
+		"home/.ssh/id_rsa.pub": false,
+		"backups/dump.sql":     true,
+		"backups/old/dump.sql": true,
+		"db/backups/dump.sql":  false,
+		"schema.sql":           false,
+	}
+	for path, want := range cases {
+		if got := deletedFile(globs, path); got != want {
+			t.Errorf("deletedFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if specs := deletionPathspecs(globs); len(specs) != 5 || specs[1] != "*/*.pem" || specs[4] != "backups/*.sql" {
+		t.Errorf("deletionPathspecs = %v", specs)
+	}
+	if _, err := bfgDeleteArgs(globs); err == nil {
+		t.Error("bfgDeleteArgs accepted a pattern with a directory")
+	}
+	if args, _ := bfgDeleteArgs(globs[:2]); len(args) != 2 || args[1] != "{*.pem,id_rsa}" {
+		t.Errorf("bfgDeleteArgs = %v", args)
+	}
+}
@@ -0,0 +1,99 @@
+package cleaner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+	scannerPkg "github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// LoadFilter restricts the findings loaded from a results file, so a cleanup
+// can target part of them without editing the file. A value kept is still
+// replaced wherever the rewrite finds it (see CleanOptions.Paths to limit
+// the files rewritten).
+type LoadFilter struct {
+	Types       []string       // Finding types kept, e.g. password (all if empty)
+	Files       PathFilter     // Files whose findings are kept
+	MinLength   int            // Shorter values are left out
+	MinSeverity string         // Lowest severity kept: critical, high, medium or low ("" for all)
+	Config      *config.Config // Severities of results that do not record them (nil: built-in)
+}
+
+// Empty reports whether the filter keeps every finding
+func (f LoadFilter) Empty() bool {
+	return len(f.Types) == 0 && f.Files.Empty() && f.MinLength <= 0 && f.MinSeverity == ""
+}
+
+// Validate rejects an unknown severity
+func (f LoadFilter) Validate() error {
+	if f.MinSeverity != "" && config.SeverityRank(f.MinSeverity) == len(config.Severities) {
+		return fmt.Errorf("invalid severity %q (critical, high, medium or low)", f.MinSeverity)
+	}
+	return nil
+}
+
+// String describes the filter for messages ("" if empty)
+func (f LoadFilter) String() string {
+	var parts []string
+	if len(f.Types) > 0 {
+		parts = append(parts, "types "+strings.Join(f.Types, ", "))
+	}
+	if files := f.Files.String(); files != "" {
+		parts = append(parts, "files "+files)
+	}
+	if f.MinLength > 0 {
+		parts = append(parts, fmt.Sprintf("values of %d+ characters", f.MinLength))
+	}
+	if f.MinSeverity != "" {
+		parts = append(parts, "severity "+f.MinSeverity+" and above")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keep reports whether a finding passes the filter
+func (f LoadFilter) keep(entry model.StreamEntry) bool {
+	if len(f.Types) > 0 && !containsFold(f.Types, entry.Type) {
+		return false
+	}
+	if !f.Files.Match(entry.File) || len(entry.Value) < f.MinLength {
+		return false
+	}
+	if f.MinSeverity != "" {
+		cfg := f.Config
+		if cfg == nil {
+			cfg = config.DefaultConfig()
+		}
+		severity := scannerPkg.EntrySeverity(cfg, entry)
+		if config.SeverityRank(severity) > config.SeverityRank(f.MinSeverity) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(list []string, item string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedValues lists the values of a set, longest first for cleaning
+func sortedValues(values map[string]bool) []string {
+	list := make([]string, 0, len(values))
+	for v := range values {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if len(list[i]) != len(list[j]) {
+			return len(list[i]) > len(list[j])
+		}
+		return list[i] < list[j]
+	})
+	return list
+}
@@ -0,0 +1,59 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadFilter(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "secrets.jsonl")
+	if err := os.WriteFile(jsonl, []byte(`{"file":"config/app.conf","key":"db_password","value":"Sup3rS3cret!","type":"password","severity":"high","commit":"abc"}
+{"file":"config/app.conf","key":"api_key","value":"abcd1234efgh","type":"api_key","severity":"medium","commit":"abc"}
+{"file":"test/fixture.conf","key":"password","value":"fixture-pass","type":"password","severity":"high","commit":"abc"}
+{"file":"config/app.conf","key":"pin","value":"1234","type":"password","severity":"high","commit":"current"}
+{"interrupted":true}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(dir, "secrets.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"secrets": [
+  {"file": "config/app.conf", "key": "db_password", "type": "password", "severity": "high",
+   "history": [{"value": "Sup3rS3cret!", "commits": ["abc"]}, {"value": "1234", "commits": ["current"]}]},
+  {"file": "config/app.conf", "key": "api_key", "type": "api_key", "severity": "medium",
+   "history": [{"value": "abcd1234efgh", "commits": ["abc"]}]},
+  {"file": "test/fixture.conf", "key": "password", "type": "password", "severity": "high",
+   "history": [{"value": "fixture-pass", "commits": ["abc"]}]}
+], "scanDate": "2024-01-01T00:00:00Z"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := LoadFilter{Types: []string{"PASSWORD"}, Files: PathFilter{Include: []string{"config/*"}}, MinLength: 6, MinSeverity: "high"}
+	for path, load := range map[string]func(string, LoadFilter) (*LoadSecretsResult, error){
+		jsonl:    LoadSecretsFromJSONL,
+		jsonPath: LoadSecretsFromJSON,
+	} {
+		loaded, err := load(path, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(loaded.Secrets, []string{"Sup3rS3cret!"}) || loaded.Filtered != 3 ||
+			len(loaded.FilePaths) != 1 || loaded.Source != "history" {
+			t.Errorf("%s: loaded %+v, want only the long config password (3 left out)", filepath.Base(path), loaded)
+		}
+
+		all, err := load(path, LoadFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all.Secrets) != 4 || all.Filtered != 0 {
+			t.Errorf("%s: unfiltered load got %v", filepath.Base(path), all.Secrets)
+		}
+	}
+
+	if _, err := LoadSecretsFromJSONL(jsonl, LoadFilter{MinSeverity: "urgent"}); err == nil {
+		t.Error("unknown severity accepted")
+	}
+}
@@ -0,0 +1,48 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// interruptGrace is how long an interrupted rewrite tool may take to exit
+// before it is killed
+const interruptGrace = 5 * time.Second
+
+// interrupted reports whether the clean was stopped through opts.Interrupt
+func (o CleanOptions) interrupted() bool {
+	return o.Interrupt != nil && o.Interrupt.Err() != nil
+}
+
+// command prepares a rewrite tool process that receives an interrupt signal
+// when the clean is interrupted, and is killed if it does not exit in time
+func (o CleanOptions) command(name string, args ...string) *exec.Cmd {
+	if o.Interrupt == nil {
+		return exec.Command(name, args...)
+	}
+	cmd := exec.CommandContext(o.Interrupt, name, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = interruptGrace
+	return cmd
+}
+
+// interruptedMessage tells what a clean stopped while rewriting the history
+// leaves behind
+func interruptedMessage(tool, backupBranch, bundle string) string {
+	if tool == ToolNative {
+		// Refs are only updated once the whole history is rewritten
+		return "Interrupted before the native engine updated any ref: the repository is unchanged"
+	}
+	msg := fmt.Sprintf("Interrupted while %s rewrote the history: it may be partly rewritten", tool)
+	if bundle != "" {
+		return msg + fmt.Sprintf(", restore it from the bundle %s", bundle)
+	}
+	if backupBranch != "" {
+		return msg + fmt.Sprintf(", restore it from the branch %s", backupBranch)
+	}
+	return msg + " (no backup branch was made)"
+}
@@ -0,0 +1,289 @@
+package cleaner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// With CleanOptions.Mirror, the history is rewritten in a bare mirror clone
+// in a temporary directory: the repository is left as it is until the
+// verified mirror is pushed with PushMirror. The mirror is cloned from the
+// origin of the repository when it has one, so the branches pushed back are
+// the ones of the remote, not local branches that may be behind it.
+
+// cloneMirror copies the origin of a repository (the repository itself
+// without one) to a new temporary bare mirror, returning it with its
+// branches and tags. The copy goes through the git transport (--no-local),
+// so it is freshly packed as git-filter-repo expects and shares no object
+// file with the repository. Credentials are needed when no helper or agent
+// has them (an AuthError otherwise).
+func cloneMirror(repoPath string, creds *Credentials) (string, map[string]string, error) {
+	dir, err := os.MkdirTemp("", "gitsecret-mirror-")
+	if err != nil {
+		return "", nil, err
+	}
+	abs, err := filepath.Abs(repoPath)
+	if err != nil {
+		abs = repoPath
+	}
+	source := MirrorRemote(repoPath)
+	if source == "" {
+		source = abs
+	}
+	mirror := filepath.Join(dir, "repo.git")
+	// A relative origin is relative to the repository
+	cmd := remoteCommand(abs, creds, "clone", "--mirror", "--no-local", "--quiet", source, mirror)
+	done := debugbundle.Track(cmd)
+	out, err := cmd.CombinedOutput()
+	done(err)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, remoteError("git clone --mirror "+source, source, out)
+	}
+	// The push runs in the mirror: it keeps the SSH command of the repository
+	if ssh, err := gitOutput(abs, "config", "--local", "core.sshCommand"); err == nil {
+		gitOutput(mirror, "config", "core.sshCommand", strings.TrimSpace(string(ssh)))
+	}
+	refs, err := mirrorRefs(mirror)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	return mirror, refs, nil
+}
+
+// mirrorRefs returns the branches and tags of a mirror with the object each
+// points at
+func mirrorRefs(mirror string) (map[string]string, error) {
+	out, err := gitOutput(mirror, "for-each-ref", "--format=%(refname) %(objectname)", "refs/heads", "refs/tags")
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref: %w", err)
+	}
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if ref, hash, ok := strings.Cut(line, " "); ok {
+			refs[ref] = hash
+		}
+	}
+	return refs, nil
+}
+
+// RemoveMirror deletes a mirror clone of CleanResult.Mirror with its
+// temporary directory
+func RemoveMirror(mirror string) error {
+	return os.RemoveAll(filepath.Dir(mirror))
+}
+
+// MirrorRemote returns the URL of the origin remote of a repository, where
+// its mirror is cloned from and pushed ("" when it has none)
+func MirrorRemote(repoPath string) string {
+	out, err := gitOutput(repoPath, "remote", "get-url", "origin")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// PushMirror pushes the rewritten branches and tags of a mirror to a remote,
+// each leased on the object it pointed at when cloned (CleanResult.MirrorRefs):
+// a ref pushed to since the clone is refused instead of being rewound. Refs
+// the mirror was not cloned with (remote-tracking branches, notes) are not
+// pushed. Git does not prompt: credentials are needed when no helper or
+// agent has them (an AuthError otherwise).
+func PushMirror(mirror, remote string, leases map[string]string, creds *Credentials) error {
+	refs, err := mirrorRefs(mirror)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(refs))
+	for ref := range refs {
+		if _, ok := leases[ref]; ok {
+			names = append(names, ref)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no branch or tag to push")
+	}
+	sort.Strings(names)
+
+	cmd := remoteCommand(mirror, creds, append([]string{"push", "--porcelain"}, pushArgs(remote, names, leases)...)...)
+	done := debugbundle.Track(cmd)
+	out, err := cmd.CombinedOutput()
+	done(err)
+	if err != nil {
+		if strings.Contains(string(out), "stale info") {
+			return fmt.Errorf("%s changed since the mirror was cloned: clean again from the new history", remote)
+		}
+		return remoteError("git push", remote, out)
+	}
+	return nil
+}
+
+// pushArgs returns the arguments of the push of refs to remote, each
+// leased on its object in leases
+func pushArgs(remote string, refs []string, leases map[string]string) []string {
+	args := []string{remote}
+	for _, ref := range refs {
+		args = append(args, fmt.Sprintf("--force-with-lease=%s:%s", ref, leases[ref]))
+	}
+	for _, ref := range refs {
+		args = append(args, ref+":"+ref)
+	}
+	return args
+}
+
+// PushCommand returns the command pushing a mirror by hand as PushMirror
+// does, to the remote given ("" for a placeholder to replace)
+func PushCommand(mirror, remote string, leases map[string]string) string {
+	refs := make([]string, 0, len(leases))
+	for ref := range leases {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	if remote == "" {
+		remote = "<remote>"
+	} else {
+		remote = shellQuote(remote)
+	}
+	return "git -C " + shellQuote(mirror) + " push " + strings.Join(pushArgs(remote, refs, leases), " ")
+}
+
+// shellQuote quotes s for a shell when it holds more than path characters
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@%+=:,./_-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// historyCheck walks a rewritten history for what the clean should have
+// removed
+type historyCheck struct {
+	repo      *git.Repository
+	content   *contentCleaner
+	paths     PathFilter
+	deleted   []string
+	trees     map[treeKey]bool
+	blobs     map[plumbing.Hash]bool // Text blobs found clean
+	remaining map[string]bool
+}
+
+// verifyRewrite lists the paths of a rewritten history where a value would
+// still be replaced, or a file still deleted. Binary files are skipped, as
+// in the rewrite.
+func verifyRewrite(repoPath string, secrets []string, anchored []rule, r replacer, opts CleanOptions) ([]string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	check := &historyCheck{
+		repo:      repo,
+		content:   newContentCleaner(secrets, anchored, r),
+		paths:     opts.Paths,
+		deleted:   opts.DeleteFiles,
+		trees:     make(map[treeKey]bool),
+		blobs:     make(map[plumbing.Hash]bool),
+		remaining: make(map[string]bool),
+	}
+
+	commits, err := repo.Log(&git.LogOptions{All: true})
+	if err != nil {
+		if err == plumbing.ErrReferenceNotFound {
+			return nil, nil // Empty repository
+		}
+		return nil, err
+	}
+	err = commits.ForEach(func(commit *object.Commit) error {
+		return check.tree(commit.TreeHash, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make([]string, 0, len(check.remaining))
+	for file := range check.remaining {
+		remaining = append(remaining, file)
+	}
+	sort.Strings(remaining)
+	return remaining, nil
+}
+
+// tree checks the files of a tree not checked yet
+func (h *historyCheck) tree(hash plumbing.Hash, dir string) error {
+	key := treeKey{dir: dir, hash: hash}
+	if h.trees[key] {
+		return nil
+	}
+	h.trees[key] = true
+
+	tree, err := object.GetTree(h.repo.Storer, hash)
+	if err != nil {
+		return err
+	}
+	for _, entry := range tree.Entries {
+		file := path.Join(dir, entry.Name)
+		switch entry.Mode {
+		case filemode.Dir:
+			if err := h.tree(entry.Hash, file); err != nil {
+				return err
+			}
+		case filemode.Submodule, filemode.Symlink:
+		default:
+			if len(h.deleted) > 0 && deletedFile(h.deleted, file) {
+				h.remaining[file] = true
+				continue
+			}
+			if !h.paths.Match(file) {
+				continue
+			}
+			clean, err := h.blob(entry.Hash)
+			if err != nil {
+				return err
+			}
+			if !clean {
+				h.remaining[file] = true
+			}
+		}
+	}
+	return nil
+}
+
+// blob reports whether a blob holds no value to replace
+func (h *historyCheck) blob(hash plumbing.Hash) (bool, error) {
+	if clean, ok := h.blobs[hash]; ok {
+		return clean, nil
+	}
+	blob, err := h.repo.BlobObject(hash)
+	if err != nil {
+		return false, err
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return false, err
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return false, err
+	}
+	clean := true
+	if bytes.IndexByte(data[:min(len(data), binarySniffLength)], 0) < 0 {
+		_, changed := h.content.clean(string(data))
+		clean = !changed
+	}
+	h.blobs[hash] = clean
+	return clean, nil
+}
@@ -0,0 +1,159 @@
+package cleaner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMirrorCleanLeavesRepository(t *testing.T) {
+	repo := newTestRepo(t, map[string]string{
+		"app.env": "API_TOKEN=tok-123456789\n",
+		"id_rsa":  "key\n",
+	})
+	origin := t.TempDir()
+	gitTest(t, origin, "init", "-q", "--bare")
+	gitTest(t, repo, "remote", "add", "origin", origin)
+	gitTest(t, repo, "push", "-q", "origin", "HEAD")
+	branch := strings.TrimSpace(gitTest(t, repo, "rev-parse", "--abbrev-ref", "HEAD"))
+
+	opts := CleanOptions{Tool: ToolNative, Source: "history", Mirror: true, DeleteFiles: []string{"id_rsa"}}
+	secrets := []string{"tok-123456789"}
+
+	// The repository still holds everything the clean removes
+	remaining, err := verifyRewrite(repo, secrets, nil, replacer{}, opts)
+	if err != nil || strings.Join(remaining, ",") != "app.env,id_rsa" {
+		t.Fatalf("verifyRewrite(repository) = %v, %v", remaining, err)
+	}
+
+	result, err := New().Clean(repo, secrets, opts)
+	if err != nil || !result.Success || result.Mirror == "" {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+	defer RemoveMirror(result.Mirror)
+	if len(result.Remaining) > 0 || result.BackupBranch != "" {
+		t.Errorf("Remaining = %v, BackupBranch = %q", result.Remaining, result.BackupBranch)
+	}
+	if got := gitTest(t, repo, "show", "HEAD:app.env"); got != "API_TOKEN=tok-123456789\n" {
+		t.Errorf("the repository was rewritten: %q", got)
+	}
+
+	if remote := MirrorRemote(repo); remote != origin {
+		t.Fatalf("MirrorRemote = %q, want %q", remote, origin)
+	}
+	if err := PushMirror(result.Mirror, origin, result.MirrorRefs, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitTest(t, origin, "ls-tree", "-r", "--name-only", branch); got != "app.env\n" {
+		t.Errorf("origin files = %q", got)
+	}
+	if got := gitTest(t, origin, "show", branch+":app.env"); got != "API_TOKEN=***REMOVED***\n" {
+		t.Errorf("origin app.env = %q", got)
+	}
+}
+
+// newTestOrigin gives a repository an origin, pushed to, and a second clone
+// of it for the commits of another user
+func newTestOrigin(t *testing.T, repo string) (origin, other, branch string) {
+	t.Helper()
+	origin = t.TempDir()
+	gitTest(t, origin, "init", "-q", "--bare")
+	gitTest(t, repo, "remote", "add", "origin", origin)
+	gitTest(t, repo, "push", "-q", "origin", "HEAD")
+	branch = strings.TrimSpace(gitTest(t, repo, "rev-parse", "--abbrev-ref", "HEAD"))
+	gitTest(t, origin, "symbolic-ref", "HEAD", "refs/heads/"+branch)
+
+	other = filepath.Join(t.TempDir(), "other")
+	gitTest(t, "", "clone", "-q", origin, other)
+	return origin, other, branch
+}
+
+// commitTest commits a file in a clone and pushes it
+func commitTest(t *testing.T, clone, name, content string, push ...string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(clone, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitTest(t, clone, "add", name)
+	gitTest(t, clone, "commit", "-q", "-m", "add "+name)
+	gitTest(t, clone, append([]string{"push", "-q", "origin"}, push...)...)
+}
+
+func TestMirrorCleanLocalBranchBehind(t *testing.T) {
+	repo := newTestRepo(t, map[string]string{"app.env": "API_TOKEN=tok-123456789\n"})
+	origin, other, branch := newTestOrigin(t, repo)
+	// The local branch is behind origin, which has a branch of its own
+	commitTest(t, other, "other.env", "TOKEN=tok-123456789\n", "HEAD")
+	gitTest(t, other, "checkout", "-q", "-b", "feature")
+	commitTest(t, other, "feature.env", "KEY=tok-123456789\n", "feature")
+
+	result, err := New().Clean(repo, []string{"tok-123456789"}, CleanOptions{Tool: ToolNative, Source: "history", Mirror: true})
+	if err != nil || !result.Success || len(result.Remaining) > 0 {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+	defer RemoveMirror(result.Mirror)
+	if err := PushMirror(result.Mirror, origin, result.MirrorRefs, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The commit of the other user is kept, cleaned, as is the other branch
+	if got := gitTest(t, origin, "rev-list", "--count", branch); got != "2\n" {
+		t.Errorf("%s has %q commits, want 2", branch, got)
+	}
+	for ref, file := range map[string]string{branch: "other.env", "feature": "feature.env"} {
+		if got := gitTest(t, origin, "show", ref+":"+file); strings.Contains(got, "tok-123456789") {
+			t.Errorf("%s:%s = %q", ref, file, got)
+		}
+	}
+}
+
+func TestPushMirrorRefusesMovedRef(t *testing.T) {
+	repo := newTestRepo(t, map[string]string{"app.env": "API_TOKEN=tok-123456789\n"})
+	origin, other, branch := newTestOrigin(t, repo)
+
+	result, err := New().Clean(repo, []string{"tok-123456789"}, CleanOptions{Tool: ToolNative, Source: "history", Mirror: true})
+	if err != nil || !result.Success {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+	defer RemoveMirror(result.Mirror)
+
+	// Pushed after the mirror was cloned: the push must not drop it
+	commitTest(t, other, "late.txt", "late\n", "HEAD")
+	tip := gitTest(t, origin, "rev-parse", branch)
+	if err := PushMirror(result.Mirror, origin, result.MirrorRefs, nil); err == nil {
+		t.Fatal("PushMirror over a moved branch succeeded")
+	}
+	if got := gitTest(t, origin, "rev-parse", branch); got != tip {
+		t.Errorf("%s moved to %s, want %s", branch, got, tip)
+	}
+}
+
+func TestPushCommandLeasesEachRef(t *testing.T) {
+	repo := newTestRepo(t, map[string]string{"app.env": "API_TOKEN=tok-123456789\n"})
+	origin, other, branch := newTestOrigin(t, repo)
+
+	result, err := New().Clean(repo, []string{"tok-123456789"}, CleanOptions{Tool: ToolNative, Source: "history", Mirror: true})
+	if err != nil || !result.Success {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+	defer RemoveMirror(result.Mirror)
+	command := PushCommand(result.Mirror, origin, result.MirrorRefs)
+	if want := "--force-with-lease=refs/heads/" + branch + ":" + result.MirrorRefs["refs/heads/"+branch]; !strings.Contains(command, want) {
+		t.Fatalf("PushCommand = %q, want %s", command, want)
+	}
+
+	// Run by hand after a push to origin, it is refused like PushMirror
+	commitTest(t, other, "late.txt", "late\n", "HEAD")
+	if out, err := exec.Command("sh", "-c", command).CombinedOutput(); err == nil {
+		t.Fatalf("%s over a moved branch succeeded: %s", command, out)
+	}
+	gitTest(t, other, "push", "-q", "--force", "origin", result.MirrorRefs["refs/heads/"+branch]+":refs/heads/"+branch)
+	if out, err := exec.Command("sh", "-c", command).CombinedOutput(); err != nil {
+		t.Fatalf("%s: %v\n%s", command, err, out)
+	}
+	if got := gitTest(t, origin, "show", branch+":app.env"); got != "API_TOKEN=***REMOVED***\n" {
+		t.Errorf("origin app.env = %q", got)
+	}
+}
@@ -0,0 +1,413 @@
+package cleaner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// ToolNative is the built-in history rewrite: go-git writes every commit
+// reachable from the refs again with cleaned blobs, so cleaning needs
+// neither git-filter-repo, BFG, Java nor the git binary
+const ToolNative = "native"
+
+// errNativeInterrupted stops the native rewrite before any ref is updated
+var errNativeInterrupted = errors.New("interrupted")
+
+// binarySniffLength is how much of a blob is searched for a NUL byte, as
+// git does, to leave binary files unchanged
+const binarySniffLength = 8000
+
+// nativeRewrite holds the objects already rewritten, by original hash
+type nativeRewrite struct {
+	repo    *git.Repository
+	content *contentCleaner
+	paths   PathFilter
+	deleted []string // CleanOptions.DeleteFiles
+	opts    CleanOptions
+
+	// Trees depend on their directory when files are selected or deleted
+	byPath  bool
+	blobs   map[blobKey]plumbing.Hash
+	trees   map[treeKey]plumbing.Hash
+	commits map[plumbing.Hash]plumbing.Hash
+
+	rewritten int // Commits written again
+	cleaned   int // Blobs whose values were replaced
+}
+
+type blobKey struct {
+	hash     plumbing.Hash
+	selected bool // The file passes CleanOptions.Paths
+}
+
+type treeKey struct {
+	dir  string
+	hash plumbing.Hash
+}
+
+func (c *Cleaner) cleanWithNative(repoPath string, secrets []string, anchored []rule, r replacer, opts CleanOptions) (*CleanResult, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return &CleanResult{Success: false, Message: fmt.Sprintf("native rewrite: %v", err)}, nil
+	}
+	w := &nativeRewrite{
+		repo:    repo,
+		content: newContentCleaner(secrets, anchored, r),
+		paths:   opts.Paths,
+		deleted: opts.DeleteFiles,
+		opts:    opts,
+		byPath:  !opts.Paths.Empty() || len(opts.DeleteFiles) > 0,
+		blobs:   make(map[blobKey]plumbing.Hash),
+		trees:   make(map[treeKey]plumbing.Hash),
+		commits: make(map[plumbing.Hash]plumbing.Hash),
+	}
+
+	done := debugbundle.Step("native rewrite of the refs")
+	updated, err := w.rewriteRefs()
+	done(err)
+	if errors.Is(err, errNativeInterrupted) {
+		return &CleanResult{Success: false, Message: "Interrupted before any ref was updated: the repository is unchanged"}, nil
+	}
+	if err != nil {
+		return &CleanResult{Success: false, Message: fmt.Sprintf("native rewrite failed: %v", err)}, nil
+	}
+	fmt.Fprintf(opts.stderr(), "\rProcessed %d commits: %d rewritten, %d blobs cleaned\n", len(w.commits), w.rewritten, w.cleaned)
+
+	// The index follows the new HEAD; the working tree is left as it is
+	if updated[plumbing.HEAD] {
+		if wt, err := repo.Worktree(); err == nil {
+			if head, err := repo.Head(); err == nil {
+				wt.Reset(&git.ResetOptions{Commit: head.Hash(), Mode: git.MixedReset})
+			}
+		}
+	}
+
+	return &CleanResult{
+		Success: true,
+		Message: fmt.Sprintf("Successfully cleaned with the native engine (%d commits rewritten, %d blobs cleaned)", w.rewritten, w.cleaned),
+	}, nil
+}
+
+// rewriteRefs rewrites the history of every ref, then points the refs at
+// the new commits. Nothing is updated until every ref is rewritten. The
+// result tells which refs moved (plumbing.HEAD when the checked-out one did).
+func (w *nativeRewrite) rewriteRefs() (map[plumbing.ReferenceName]bool, error) {
+	refs, err := w.repo.References()
+	if err != nil {
+		return nil, err
+	}
+	var names []plumbing.ReferenceName
+	targets := make(map[plumbing.ReferenceName]plumbing.Hash)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || !strings.HasPrefix(ref.Name().String(), "refs/") {
+			return nil
+		}
+		target, err := w.rewriteObject(ref.Hash())
+		if err != nil {
+			return err
+		}
+		if target != ref.Hash() {
+			names = append(names, ref.Name())
+			targets[ref.Name()] = target
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	head, _ := w.repo.Storer.Reference(plumbing.HEAD)
+	updated := make(map[plumbing.ReferenceName]bool, len(names)+1)
+	for _, name := range names {
+		if err := w.repo.Storer.SetReference(plumbing.NewHashReference(name, targets[name])); err != nil {
+			return updated, err
+		}
+		updated[name] = true
+		if head != nil && head.Type() == plumbing.SymbolicReference && head.Target() == name {
+			updated[plumbing.HEAD] = true
+		}
+	}
+	return updated, nil
+}
+
+// rewriteObject rewrites what a ref points at: commits and annotated tags,
+// other objects are kept
+func (w *nativeRewrite) rewriteObject(hash plumbing.Hash) (plumbing.Hash, error) {
+	obj, err := w.repo.Storer.EncodedObject(plumbing.AnyObject, hash)
+	if err != nil {
+		return hash, err
+	}
+	switch obj.Type() {
+	case plumbing.CommitObject:
+		return w.rewriteHistory(hash)
+	case plumbing.TagObject:
+		return w.rewriteTag(hash)
+	}
+	return hash, nil
+}
+
+// rewriteTag writes an annotated tag again on its rewritten target. The
+// signature of a changed tag is dropped: it no longer matches.
+func (w *nativeRewrite) rewriteTag(hash plumbing.Hash) (plumbing.Hash, error) {
+	tag, err := w.repo.TagObject(hash)
+	if err != nil {
+		return hash, err
+	}
+	target, err := w.rewriteObject(tag.Target)
+	if err != nil || target == tag.Target {
+		return hash, err
+	}
+	rewritten := &object.Tag{
+		Name:       tag.Name,
+		Tagger:     tag.Tagger,
+		Message:    tag.Message,
+		TargetType: tag.TargetType,
+		Target:     target,
+	}
+	return w.store(rewritten)
+}
+
+// rewriteHistory rewrites a commit after its ancestors, parents first
+// without recursion: histories can be hundreds of thousands deep
+func (w *nativeRewrite) rewriteHistory(tip plumbing.Hash) (plumbing.Hash, error) {
+	stack := []plumbing.Hash{tip}
+	pending := make(map[plumbing.Hash]*object.Commit)
+	for len(stack) > 0 {
+		hash := stack[len(stack)-1]
+		if _, done := w.commits[hash]; done {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		commit, ok := pending[hash]
+		if !ok {
+			var err error
+			if commit, err = w.repo.CommitObject(hash); err != nil {
+				return tip, err
+			}
+			pending[hash] = commit
+		}
+
+		waiting := false
+		for _, parent := range commit.ParentHashes {
+			if _, done := w.commits[parent]; !done {
+				stack = append(stack, parent)
+				waiting = true
+			}
+		}
+		if waiting {
+			continue
+		}
+
+		if w.opts.interrupted() {
+			return tip, errNativeInterrupted
+		}
+		rewritten, err := w.rewriteCommit(commit)
+		if err != nil {
+			return tip, err
+		}
+		w.commits[hash] = rewritten
+		if n := len(w.commits); n%1000 == 0 {
+			fmt.Fprintf(w.opts.stderr(), "\rProcessed %d commits", n)
+		}
+		delete(pending, hash)
+		stack = stack[:len(stack)-1]
+	}
+	return w.commits[tip], nil
+}
+
+// rewriteCommit writes a commit again on its cleaned tree and rewritten
+// parents; an unchanged commit keeps its hash (and signature)
+func (w *nativeRewrite) rewriteCommit(commit *object.Commit) (plumbing.Hash, error) {
+	tree, err := w.rewriteTree(commit.TreeHash, "")
+	if err != nil {
+		return commit.Hash, err
+	}
+	changed := tree != commit.TreeHash
+	parents := make([]plumbing.Hash, len(commit.ParentHashes))
+	for i, parent := range commit.ParentHashes {
+		parents[i] = w.commits[parent]
+		changed = changed || parents[i] != parent
+	}
+	if !changed {
+		return commit.Hash, nil
+	}
+
+	w.rewritten++
+	return w.store(&object.Commit{
+		Author:       commit.Author,
+		Committer:    commit.Committer,
+		MergeTag:     commit.MergeTag,
+		Message:      commit.Message,
+		TreeHash:     tree,
+		ParentHashes: parents,
+		Encoding:     commit.Encoding,
+	})
+}
+
+// rewriteTree writes a tree again with its cleaned blobs, without the
+// deleted files and the directories left empty
+func (w *nativeRewrite) rewriteTree(hash plumbing.Hash, dir string) (plumbing.Hash, error) {
+	key := treeKey{hash: hash}
+	if w.byPath {
+		key.dir = dir
+	}
+	if rewritten, ok := w.trees[key]; ok {
+		return rewritten, nil
+	}
+
+	tree, err := object.GetTree(w.repo.Storer, hash)
+	if err != nil {
+		return hash, err
+	}
+	changed := false
+	entries := make([]object.TreeEntry, 0, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		file := path.Join(dir, entry.Name)
+		switch entry.Mode {
+		case filemode.Dir:
+			rewritten, err := w.rewriteTree(entry.Hash, file)
+			if err != nil {
+				return hash, err
+			}
+			if rewritten == plumbing.ZeroHash {
+				changed = true
+				continue
+			}
+			changed = changed || rewritten != entry.Hash
+			entry.Hash = rewritten
+		case filemode.Submodule, filemode.Symlink:
+		default:
+			if len(w.deleted) > 0 && deletedFile(w.deleted, file) {
+				changed = true
+				continue
+			}
+			rewritten, err := w.rewriteBlob(entry.Hash, file)
+			if err != nil {
+				return hash, err
+			}
+			changed = changed || rewritten != entry.Hash
+			entry.Hash = rewritten
+		}
+		entries = append(entries, entry)
+	}
+
+	rewritten := hash
+	switch {
+	case len(entries) == 0 && len(tree.Entries) > 0 && dir != "":
+		rewritten = plumbing.ZeroHash // Emptied: dropped from its parent
+	case changed:
+		if rewritten, err = w.store(&object.Tree{Entries: entries}); err != nil {
+			return hash, err
+		}
+	}
+	w.trees[key] = rewritten
+	return rewritten, nil
+}
+
+// rewriteBlob replaces the values in a text blob of a selected file
+func (w *nativeRewrite) rewriteBlob(hash plumbing.Hash, file string) (plumbing.Hash, error) {
+	key := blobKey{hash: hash, selected: w.paths.Match(file)}
+	if rewritten, ok := w.blobs[key]; ok {
+		return rewritten, nil
+	}
+	rewritten := hash
+	if key.selected {
+		blob, err := w.repo.BlobObject(hash)
+		if err != nil {
+			return hash, err
+		}
+		reader, err := blob.Reader()
+		if err != nil {
+			return hash, err
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return hash, err
+		}
+		if bytes.IndexByte(data[:min(len(data), binarySniffLength)], 0) < 0 {
+			if cleaned, changed := w.content.clean(string(data)); changed {
+				if rewritten, err = w.storeBlob([]byte(cleaned)); err != nil {
+					return hash, err
+				}
+				w.cleaned++
+			}
+		}
+	}
+	w.blobs[key] = rewritten
+	return rewritten, nil
+}
+
+// encoder is an object go-git writes in its storage format
+type encoder interface {
+	Encode(plumbing.EncodedObject) error
+}
+
+// store writes a tree, commit or tag to the repository
+func (w *nativeRewrite) store(o encoder) (plumbing.Hash, error) {
+	obj := w.repo.Storer.NewEncodedObject()
+	if err := o.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return w.repo.Storer.SetEncodedObject(obj)
+}
+
+// storeBlob writes a blob to the repository
+func (w *nativeRewrite) storeBlob(data []byte) (plumbing.Hash, error) {
+	obj := w.repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	obj.SetSize(int64(len(data)))
+	writer, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return plumbing.ZeroHash, err
+	}
+	if err := writer.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return w.repo.Storer.SetEncodedObject(obj)
+}
+
+// hasGit reports whether the git binary is installed
+func hasGit() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// purgeObjects drops the objects of the old history with go-git, when git
+// is not installed to expire the reflogs and gc: the reflogs are removed,
+// unreachable loose objects deleted and the reachable ones repacked
+func purgeObjects(repoPath string) error {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return err
+	}
+	gitDir := filepath.Join(repoPath, ".git")
+	if _, err := os.Stat(gitDir); err != nil {
+		gitDir = repoPath // Bare repository
+	}
+	if err := os.RemoveAll(filepath.Join(gitDir, "logs")); err != nil {
+		return err
+	}
+	if err := repo.Prune(git.PruneOptions{Handler: repo.DeleteObject}); err != nil {
+		return err
+	}
+	return repo.RepackObjects(&git.RepackConfig{})
+}
@@ -0,0 +1,195 @@
+package cleaner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PathFilter limits the rewrite to some files. Patterns are git pathspec
+// globs: "*" also matches "/", so "*.env" is every .env file of the tree and
+// "fixtures/*" everything under fixtures.
+type PathFilter struct {
+	Include []string // Only rewrite matching files (every file if empty)
+	Exclude []string // Never rewrite matching files
+}
+
+// ParsePatterns splits a list of globs separated by commas or spaces
+func ParsePatterns(list string) []string {
+	return strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// Empty reports whether the filter lets every file through
+func (f PathFilter) Empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Match reports whether a file is rewritten
+func (f PathFilter) Match(path string) bool {
+	path = strings.ReplaceAll(path, "\\", "/")
+	for _, pattern := range f.Exclude {
+		if globRegexp(pattern).MatchString(path) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, pattern := range f.Include {
+		if globRegexp(pattern).MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the filter for messages ("" if empty)
+func (f PathFilter) String() string {
+	var parts []string
+	if len(f.Include) > 0 {
+		parts = append(parts, "only "+strings.Join(f.Include, ", "))
+	}
+	if len(f.Exclude) > 0 {
+		parts = append(parts, "except "+strings.Join(f.Exclude, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// filter keeps the files of a set the filter lets through
+func (f PathFilter) filter(files map[string]bool) map[string]bool {
+	if f.Empty() {
+		return files
+	}
+	kept := make(map[string]bool, len(files))
+	for file := range files {
+		if f.Match(file) {
+			kept[file] = true
+		}
+	}
+	return kept
+}
+
+// globPattern translates a glob to an anchored regex that Go and Python
+// read alike
+func globPattern(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '[':
+			if end := strings.IndexByte(glob[i+1:], ']'); end > 0 {
+				class := glob[i+1 : i+1+end]
+				if class[0] == '!' {
+					class = "^" + class[1:]
+				}
+				sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+				i += end + 1
+				continue
+			}
+			sb.WriteString(`\[`)
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// globRegexp compiles a glob, or a regex matching nothing if it is invalid
+func globRegexp(glob string) *regexp.Regexp {
+	re, err := regexp.Compile(globPattern(glob))
+	if err != nil {
+		return regexp.MustCompile(`^\b\B$`)
+	}
+	return re
+}
+
+// pathspecs is the filter as git pathspecs, for `git ls-files`
+func (f PathFilter) pathspecs() []string {
+	specs := append([]string{}, f.Include...)
+	for _, pattern := range f.Exclude {
+		specs = append(specs, ":(exclude)"+pattern)
+	}
+	return specs
+}
+
+// bfgArgs is the filter as BFG options. BFG matches file names (one glob
+// each way), so patterns with a directory are refused.
+func (f PathFilter) bfgArgs() ([]string, error) {
+	var args []string
+	for _, option := range []struct {
+		flag     string
+		patterns []string
+	}{{"--filesmatching", f.Include}, {"--filesexcluding", f.Exclude}} {
+		if len(option.patterns) == 0 {
+			continue
+		}
+		for _, pattern := range option.patterns {
+			if strings.Contains(pattern, "/") {
+				return nil, fmt.Errorf("BFG only matches file names, not %q: use git-filter-repo", pattern)
+			}
+		}
+		glob := option.patterns[0]
+		if len(option.patterns) > 1 {
+			glob = "{" + strings.Join(option.patterns, ",") + "}"
+		}
+		args = append(args, option.flag, glob)
+	}
+	return args, nil
+}
+
+// fileInfoCallback is the body of a git-filter-repo --file-info-callback
+// applying the replacements to the files the filter lets through. The
+// specification is embedded as base64 JSON, so no value needs quoting, and
+// compiled once.
+const fileInfoCallback = `g = globals()
+if '_gitsecret' not in g:
+    import base64, json, re
+    spec = json.loads(base64.b64decode(b'%s'))
+    g['_gitsecret'] = (
+        [re.compile(p) for p in spec['include']],
+        [re.compile(p) for p in spec['exclude']],
+        [(re.compile(p.encode()), r.encode()) for p, r in spec['replace']],
+    )
+include, exclude, replace = g['_gitsecret']
+name = filename.decode('utf-8', 'replace')
+if mode in (b'120000', b'160000') or any(p.match(name) for p in exclude) or (include and not any(p.match(name) for p in include)):
+    return (filename, mode, blob_id)
+contents = value.get_contents_by_identifier(blob_id)
+cleaned = contents
+for p, r in replace:
+    cleaned = p.sub(r, cleaned)
+if cleaned == contents:
+    return (filename, mode, blob_id)
+return (filename, mode, value.insert_file_with_contents(cleaned))
+`
+
+// filterRepoCallback builds the --file-info-callback replacing the bare
+// patterns, then the key-anchored ones, in the files the filter lets through
+func (f PathFilter) filterRepoCallback(patterns, anchored []rule) string {
+	spec := struct {
+		Include []string    `json:"include"`
+		Exclude []string    `json:"exclude"`
+		Replace [][2]string `json:"replace"`
+	}{Include: []string{}, Exclude: []string{}, Replace: [][2]string{}}
+	for _, pattern := range f.Include {
+		spec.Include = append(spec.Include, globPattern(pattern))
+	}
+	for _, pattern := range f.Exclude {
+		spec.Exclude = append(spec.Exclude, globPattern(pattern))
+	}
+	for _, rule := range patterns {
+		spec.Replace = append(spec.Replace, [2]string{rule.pattern, pythonReplacement(rule.text)})
+	}
+	for _, rule := range anchored {
+		spec.Replace = append(spec.Replace, [2]string{rule.pattern, `\1` + pythonReplacement(rule.text)})
+	}
+	data, _ := json.Marshal(spec)
+	return fmt.Sprintf(fileInfoCallback, base64.StdEncoding.EncodeToString(data))
+}
@@ -0,0 +1,29 @@
+package cleaner
+
+import "testing"
+
+func TestPathFilterMatch(t *testing.T) {
+	f := PathFilter{Include: []string{"*.env", "config/*.yaml"}, Exclude: []string{"*fixtures*"}}
+
+	cases := map[string]bool{
+		".env":                     true,
+		"deploy/prod.env":          true,
+		"config/app.yaml":          true,
+		"config/nested/app.yaml":   true,
+		"app.yaml":                 false,
+		"test/fixtures/sample.env": false,
+		"src/main.go":              false,
+	}
+	for path, want := range cases {
+		if got := f.Match(path); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if specs := f.pathspecs(); len(specs) != 3 || specs[2] != ":(exclude)*fixtures*" {
+		t.Errorf("pathspecs = %v", specs)
+	}
+	if _, err := f.bfgArgs(); err == nil {
+		t.Error("bfgArgs accepted a pattern with a directory")
+	}
+}
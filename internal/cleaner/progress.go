@@ -0,0 +1,56 @@
+package cleaner
+
+import (
+	"io"
+	"os"
+)
+
+// stdout is where the rewrite tools write their output: CleanOptions.Output,
+// else the terminal
+func (o CleanOptions) stdout() io.Writer {
+	if o.Output != nil {
+		return o.Output
+	}
+	return os.Stdout
+}
+
+// stderr is where the rewrite tools write their errors and progress
+func (o CleanOptions) stderr() io.Writer {
+	if o.Output != nil {
+		return o.Output
+	}
+	return os.Stderr
+}
+
+// cleanSteps numbers the steps of a clean for CleanOptions.OnProgress
+type cleanSteps struct {
+	done, total int
+	report      func(step, total int, message string)
+}
+
+// newCleanSteps counts the steps a clean of source goes through
+func newCleanSteps(opts CleanOptions, source string) *cleanSteps {
+	history := source == "history" || source == "both"
+	s := &cleanSteps{report: opts.OnProgress}
+	for _, planned := range []bool{
+		history && opts.Mirror,                  // Mirror clone
+		history && opts.Bundle != "",            // Backup bundle
+		source == "current" || source == "both", // Current files
+		history,                                 // Rewrite
+		history,                                 // Reflog expire and gc
+		history && opts.Mirror,                  // Mirror verification
+	} {
+		if planned {
+			s.total++
+		}
+	}
+	return s
+}
+
+// next reports the start of the next step
+func (s *cleanSteps) next(message string) {
+	s.done++
+	if s.report != nil {
+		s.report(s.done, s.total, message)
+	}
+}
@@ -0,0 +1,40 @@
+package cleaner
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCleanReportsStepsAndOutput(t *testing.T) {
+	repo := newTestRepo(t, map[string]string{"app.env": "API_TOKEN=tok-123456789\n"})
+	bundle := t.TempDir() + "/backup.bundle"
+
+	var steps []string
+	var output bytes.Buffer
+	result, err := New().Clean(repo, []string{"tok-123456789"}, CleanOptions{
+		Tool:   ToolNative,
+		Source: "history",
+		Bundle: bundle,
+		OnProgress: func(step, total int, message string) {
+			steps = append(steps, fmt.Sprintf("%d/%d %s", step, total, message))
+		},
+		Output: &output,
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+
+	want := []string{
+		"1/3 Backing up to " + bundle + "...",
+		"2/3 Cleaning git history using native with 1 patterns",
+		"3/3 Running git gc...",
+	}
+	if strings.Join(steps, "\n") != strings.Join(want, "\n") {
+		t.Errorf("steps = %q, want %q", steps, want)
+	}
+	if !strings.Contains(output.String(), "1 rewritten") {
+		t.Errorf("output = %q", output.String())
+	}
+}
@@ -0,0 +1,21 @@
+package cleaner
+
+import (
+	"path/filepath"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+)
+
+// recordCleaned adds the hashes of cleaned values to the baseline of the
+// repository, so later scans flag them as reintroduced if they come back
+func recordCleaned(repoPath string, secrets []string) error {
+	store, err := triage.Load(filepath.Join(repoPath, config.BaselineFile))
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		store.MarkCleaned(secret)
+	}
+	return store.Save()
+}
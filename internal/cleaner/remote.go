@@ -0,0 +1,134 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Git runs the clone and push of a mirror without a terminal: a prompt for
+// a password, a key passphrase or an unknown host key would be written over
+// the TUI and wait forever. Credential helpers and the SSH agent still
+// answer; when they cannot, git fails and the failure is an AuthError.
+
+// Credentials are a username and password (or token) for an HTTPS remote,
+// given to git through a credential helper of their own
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Environment variables the credential helper of Credentials reads: the
+// values stay out of the command line, which any local user can list
+const (
+	usernameEnv = "GITSECRET_GIT_USERNAME"
+	passwordEnv = "GITSECRET_GIT_PASSWORD"
+)
+
+// credentialHelper answers git with the Credentials of the environment
+const credentialHelper = `!f() { test "$1" = get || exit 0; echo "username=$` + usernameEnv + `"; echo "password=$` + passwordEnv + `"; }; f`
+
+// AuthError is a remote operation refused for want of credentials, or
+// one that would have needed a prompt
+type AuthError struct {
+	Remote string
+	SSH    bool   // The remote is reached over SSH: only the agent can answer
+	Output string // What git said
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("%s: authentication needed: %s", e.Remote, e.Output)
+}
+
+// Help tells how to give git the credentials it asked for
+func (e *AuthError) Help() []string {
+	if strings.Contains(e.Output, "Host key verification failed") {
+		return []string{
+			"The host key of the remote is not known yet. Connect once from a terminal",
+			"to check and accept it: ssh -T " + sshHost(e.Remote),
+		}
+	}
+	if e.SSH {
+		return []string{
+			"No key of the SSH agent was accepted, or the key needs its passphrase.",
+			"Load it into the agent, then try again: ssh-add ~/.ssh/id_ed25519",
+		}
+	}
+	return []string{
+		"No credential helper gave a username and password for this remote.",
+		"Enter them (a personal access token as password) to try again, or store",
+		"them once in a helper: git config --global credential.helper store",
+		"(or manager, osxkeychain, libsecret), then git fetch from a terminal.",
+	}
+}
+
+// authFailure matches what git and ssh print when a prompt was needed
+var authFailure = regexp.MustCompile(`(?i)terminal prompts disabled|could not read (username|password)|authentication failed|invalid username or password|permission denied \(|host key verification failed|returned error: 40[13]`)
+
+// sshRemote matches the remotes git reaches over SSH: ssh:// URLs and the
+// scp-like user@host:path
+var sshRemote = regexp.MustCompile(`^(ssh://|git\+ssh://|[^/:@]+@[^/:]+:)`)
+
+// isSSHRemote reports whether git reaches a remote over SSH
+func isSSHRemote(remote string) bool {
+	return sshRemote.MatchString(remote)
+}
+
+// sshHost returns the user@host of an SSH remote
+func sshHost(remote string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(remote, "git+ssh://"), "ssh://")
+	if i := strings.IndexAny(host, ":/"); i >= 0 {
+		host = host[:i]
+	}
+	return host
+}
+
+// remoteCommand prepares a git command run in dir, reaching a remote
+// without prompting, answered by creds when given. The SSH command set by
+// the user is kept, in batch mode.
+func remoteCommand(dir string, creds *Credentials, args ...string) *exec.Cmd {
+	if creds != nil {
+		// An empty helper first drops the configured ones, which gave nothing
+		args = append([]string{"-c", "credential.helper=", "-c", "credential.helper=" + credentialHelper}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_SSH_COMMAND="+sshCommand(dir)+" -o BatchMode=yes",
+		"GCM_INTERACTIVE=never",
+	)
+	if creds != nil {
+		cmd.Env = append(cmd.Env, usernameEnv+"="+creds.Username, passwordEnv+"="+creds.Password)
+	}
+	return cmd
+}
+
+// sshCommand returns the SSH command git would run in dir, in its order of
+// precedence: GIT_SSH_COMMAND, core.sshCommand, GIT_SSH (a program, without
+// arguments), else ssh. Setting GIT_SSH_COMMAND overrides the others, so the
+// batch mode is added to the one in force.
+func sshCommand(dir string) string {
+	if ssh := os.Getenv("GIT_SSH_COMMAND"); ssh != "" {
+		return ssh
+	}
+	if out, err := gitOutput(dir, "config", "core.sshCommand"); err == nil && strings.TrimSpace(string(out)) != "" {
+		return strings.TrimSpace(string(out))
+	}
+	if ssh := os.Getenv("GIT_SSH"); ssh != "" {
+		return shellQuote(ssh)
+	}
+	return "ssh"
+}
+
+// remoteError turns the failure of a remote command into an AuthError when
+// git needed credentials, else an error naming the command
+func remoteError(command, remote string, out []byte) error {
+	output := strings.TrimSpace(string(out))
+	if authFailure.MatchString(output) {
+		return &AuthError{Remote: remote, SSH: isSSHRemote(remote), Output: output}
+	}
+	return fmt.Errorf("%s: %s", command, output)
+}
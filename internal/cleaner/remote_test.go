@@ -0,0 +1,104 @@
+package cleaner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRemoteCommandCredentials(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	cmd := remoteCommand("", &Credentials{Username: "alice", Password: "tok-123 456"}, "credential", "fill")
+	cmd.Stdin = strings.NewReader("protocol=https\nhost=example.org\n\n")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git credential fill: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "username=alice\n") || !strings.Contains(string(out), "password=tok-123 456\n") {
+		t.Errorf("credential fill = %q", out)
+	}
+	for _, arg := range cmd.Args {
+		if strings.Contains(arg, "tok-123") {
+			t.Errorf("the password is on the command line: %q", cmd.Args)
+		}
+	}
+}
+
+func TestMirrorCloneNeverPrompts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as ssh")
+	}
+	repo := newTestRepo(t, map[string]string{"app.env": "API_TOKEN=tok-123456789\n"})
+	gitTest(t, repo, "remote", "add", "origin", "git@example.org:team/app.git")
+
+	// An ssh that would ask for a passphrase without batch mode
+	ssh := filepath.Join(t.TempDir(), "ssh")
+	script := "#!/bin/sh\ncase \"$*\" in *BatchMode=yes*) echo 'Permission denied (publickey).' >&2; exit 255;; esac\nread passphrase\n"
+	if err := os.WriteFile(ssh, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_SSH_COMMAND", ssh)
+
+	result, err := New().Clean(repo, []string{"tok-123456789"}, CleanOptions{Tool: ToolNative, Source: "history", Mirror: true})
+	if err != nil || result.Success || result.Auth == nil || !result.Auth.SSH {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+	if help := strings.Join(result.Auth.Help(), " "); !strings.Contains(help, "ssh-add") {
+		t.Errorf("Help = %q", help)
+	}
+	if got := gitTest(t, repo, "show", "HEAD:app.env"); got != "API_TOKEN=tok-123456789\n" {
+		t.Errorf("the repository was changed: %q", got)
+	}
+}
+
+func TestMirrorCloneKeepsConfiguredSSHCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as ssh")
+	}
+	repo := newTestRepo(t, map[string]string{"app.env": "API_TOKEN=tok-123456789\n"})
+	gitTest(t, repo, "remote", "add", "origin", "git@example.org:team/app.git")
+
+	// The wrapper of core.sshCommand, with its own options, records its call
+	dir := t.TempDir()
+	ssh := filepath.Join(dir, "ssh-wrapper")
+	script := "#!/bin/sh\necho \"$*\" > \"$0.args\"\necho 'Permission denied (publickey).' >&2\nexit 255\n"
+	if err := os.WriteFile(ssh, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitTest(t, repo, "config", "core.sshCommand", ssh+" -p 2222")
+	t.Setenv("GIT_SSH_COMMAND", "")
+	os.Unsetenv("GIT_SSH_COMMAND")
+
+	result, err := New().Clean(repo, []string{"tok-123456789"}, CleanOptions{Tool: ToolNative, Source: "history", Mirror: true})
+	if err != nil || result.Auth == nil {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+	args, err := os.ReadFile(ssh + ".args")
+	if err != nil {
+		t.Fatalf("core.sshCommand was not run: %v", err)
+	}
+	if got := string(args); !strings.Contains(got, "-p 2222") || !strings.Contains(got, "BatchMode=yes") {
+		t.Errorf("ssh arguments = %q, want the configured ones in batch mode", got)
+	}
+}
+
+func TestRemoteError(t *testing.T) {
+	outputs := map[string]bool{
+		"fatal: could not read Username for 'https://example.org': terminal prompts disabled": true,
+		"remote: Invalid username or password.\nfatal: Authentication failed":                 true,
+		"Host key verification failed.\nfatal: Could not read from remote repository.":        true,
+		"fatal: repository 'https://example.org/none.git/' not found":                         false,
+	}
+	for output, auth := range outputs {
+		var authErr *AuthError
+		if got := errors.As(remoteError("git push", "https://example.org/app.git", []byte(output)), &authErr); got != auth {
+			t.Errorf("remoteError(%q) is an AuthError: %v, want %v", output, got, auth)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultReplacement is the text written instead of the values when
+// CleanOptions.Replacement is empty
+const DefaultReplacement = "***REMOVED***"
+
+// replacementVariable matches the ${...} variables of a replacement text
+var replacementVariable = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// ValidateReplacement checks a replacement text: one line, and only the
+// ${KEY}, ${TYPE} and ${ENV:NAME} variables
+func ValidateReplacement(text string) error {
+	if strings.ContainsAny(text, "\r\n") {
+		return fmt.Errorf("replacement text must be a single line")
+	}
+	if strings.Contains(text, "==>") {
+		return fmt.Errorf("replacement text cannot contain \"==>\"")
+	}
+	for _, match := range replacementVariable.FindAllStringSubmatch(text, -1) {
+		switch name := match[1]; {
+		case name == "KEY" || name == "TYPE":
+		case strings.HasPrefix(name, "ENV:") && len(name) > len("ENV:"):
+		default:
+			return fmt.Errorf("unknown variable %s in replacement text (use ${KEY}, ${TYPE} or ${ENV:NAME})", match[0])
+		}
+	}
+	return nil
+}
+
+// replacer gives the text replacing each value. The zero replacer writes
+// DefaultReplacement everywhere.
+type replacer struct {
+	template string            // Replacement with ${ENV:NAME} expanded
+	texts    map[string]string // Text of each value, when the template names its key or type
+}
+
+// newReplacer expands the environment variables of a replacement text and,
+// when it names ${KEY} or ${TYPE}, resolves them for each value from its
+// first occurrence by file then key (SECRET and secret without occurrence)
+func newReplacer(text string, secrets []string, entries []SecretEntry) (replacer, error) {
+	if text == "" {
+		return replacer{}, nil
+	}
+	if err := ValidateReplacement(text); err != nil {
+		return replacer{}, err
+	}
+
+	var missing []string
+	template := replacementVariable.ReplaceAllStringFunc(text, func(variable string) string {
+		name, ok := strings.CutPrefix(variable[2:len(variable)-1], "ENV:")
+		if !ok {
+			return variable
+		}
+		value, set := os.LookupEnv(name)
+		if !set {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return replacer{}, fmt.Errorf("replacement text: environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	if strings.ContainsAny(template, "\r\n") {
+		return replacer{}, fmt.Errorf("replacement text must be a single line once the environment is expanded")
+	}
+
+	r := replacer{template: template}
+	if !strings.Contains(template, "${KEY}") && !strings.Contains(template, "${TYPE}") {
+		return r, nil
+	}
+	first := make(map[string]SecretEntry, len(secrets))
+	for _, e := range entries {
+		if f, ok := first[e.Value]; !ok || e.File < f.File || (e.File == f.File && e.Key < f.Key) {
+			first[e.Value] = e
+		}
+	}
+	r.texts = make(map[string]string, len(secrets))
+	for _, v := range secrets {
+		key, typ := "SECRET", "secret"
+		if e, ok := first[v]; ok {
+			key, typ = e.Key, e.Type
+		}
+		r.texts[v] = strings.NewReplacer("${KEY}", key, "${TYPE}", typ).Replace(template)
+	}
+	return r, nil
+}
+
+// text is the replacement of a value
+func (r replacer) text(value string) string {
+	if text, ok := r.texts[value]; ok {
+		return text
+	}
+	return r.String()
+}
+
+// String is the replacement text, ${KEY} and ${TYPE} unexpanded
+func (r replacer) String() string {
+	if r.template == "" {
+		return DefaultReplacement
+	}
+	return r.template
+}
+
+// rule is a regex of values sharing their replacement text
+type rule struct {
+	pattern string
+	text    string // Literal text, escaped by each backend
+}
+
+// batches splits values sorted longest first into runs of at most 100
+// values sharing their replacement text, keeping the order so a value is
+// still replaced before the shorter values it contains
+func (r replacer) batches(sorted []string) [][]string {
+	var batches [][]string
+	for i, v := range sorted {
+		if i == 0 || len(batches[len(batches)-1]) == 100 || r.text(v) != r.text(sorted[i-1]) {
+			batches = append(batches, nil)
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], v)
+	}
+	return batches
+}
+
+// goReplacement escapes a text for Go's Regexp.ReplaceAllString
+func goReplacement(text string) string {
+	return strings.ReplaceAll(text, "$", "$$")
+}
+
+// pythonReplacement escapes a text for Python's re.sub (git-filter-repo)
+func pythonReplacement(text string) string {
+	return strings.ReplaceAll(text, `\`, `\\`)
+}
+
+// javaReplacement escapes a text for Java's Matcher.replaceAll (BFG)
+func javaReplacement(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "$", `\$`).Replace(text)
+}
+
+// sedReplacement escapes a text for the replacement of a sed s/// command
+func sedReplacement(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "/", `\/`, "&", `\&`).Replace(text)
+}
+
+// contentCleaner replaces the values in the content of a file, as the
+// backends do: bare values longest first (short ones as whole words), then
+// the key-anchored rules
+type contentCleaner struct {
+	secrets  []string
+	short    map[string]*regexp.Regexp
+	keyed    []*regexp.Regexp
+	anchored []rule
+	r        replacer
+}
+
+func newContentCleaner(secrets []string, anchored []rule, r replacer) *contentCleaner {
+	c := &contentCleaner{secrets: byLength(secrets), short: make(map[string]*regexp.Regexp), anchored: anchored, r: r}
+	for _, secret := range c.secrets {
+		if len(secret) < MinBareLength {
+			c.short[secret] = regexp.MustCompile(valuePattern(secret))
+		}
+	}
+	c.keyed = make([]*regexp.Regexp, len(anchored))
+	for i, rule := range anchored {
+		c.keyed[i] = regexp.MustCompile(rule.pattern)
+	}
+	return c
+}
+
+// clean returns the content with the values replaced, and whether it changed
+func (c *contentCleaner) clean(content string) (string, bool) {
+	modified := false
+	for _, secret := range c.secrets {
+		if !strings.Contains(content, secret) {
+			continue
+		}
+		var replaced string
+		if re, ok := c.short[secret]; ok {
+			replaced = re.ReplaceAllLiteralString(content, c.r.text(secret))
+		} else {
+			replaced = strings.ReplaceAll(content, secret, c.r.text(secret))
+		}
+		if replaced != content {
+			content = replaced
+			modified = true
+		}
+	}
+	for i, re := range c.keyed {
+		if replaced := re.ReplaceAllString(content, "${1}"+goReplacement(c.anchored[i].text)); replaced != content {
+			content = replaced
+			modified = true
+		}
+	}
+	return content, modified
+}
@@ -0,0 +1,64 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplacementTemplate(t *testing.T) {
+	t.Setenv("GITSECRET_TEST_TICKET", "SEC-42")
+	entries := []SecretEntry{
+		{File: "b.env", Key: "DB_PASSWORD", Value: "pg-secret-value", Type: "password"},
+		{File: "a.env", Key: "DATABASE_PASSWORD", Value: "pg-secret-value", Type: "password"},
+		{File: "a.env", Key: "API_TOKEN", Value: "tok-123456789", Type: "token"},
+	}
+	secrets := []string{"pg-secret-value", "tok-123456789", "unlisted-value"}
+
+	r, err := newReplacer("<${KEY}:${ENV:GITSECRET_TEST_TICKET}>", secrets, entries)
+	if err != nil {
+		t.Fatalf("newReplacer: %v", err)
+	}
+	for value, want := range map[string]string{
+		"pg-secret-value": "<DATABASE_PASSWORD:SEC-42>",
+		"tok-123456789":   "<API_TOKEN:SEC-42>",
+		"unlisted-value":  "<SECRET:SEC-42>",
+	} {
+		if got := r.text(value); got != want {
+			t.Errorf("text(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	repo := t.TempDir()
+	path := filepath.Join(repo, "a.env")
+	if err := os.WriteFile(path, []byte("API_TOKEN=tok-123456789\nDATABASE_PASSWORD=pg-secret-value\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	keys, bare := anchorValues(secrets[:1], entries)
+	if _, _, err := New().cleanCurrentFiles(repo, append(bare, "tok-123456789"), groupAnchoredPatterns(keys, r), r, map[string]bool{"a.env": true}, -1); err != nil {
+		t.Fatalf("cleanCurrentFiles: %v", err)
+	}
+	want := "API_TOKEN=<API_TOKEN:SEC-42>\nDATABASE_PASSWORD=<DATABASE_PASSWORD:SEC-42>\n"
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Errorf("cleaned file:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestReplacementErrors(t *testing.T) {
+	for _, text := range []string{"${NAME}", "a\nb", "x==>y", "${ENV:GITSECRET_TEST_UNSET}"} {
+		if _, err := newReplacer(text, []string{"value"}, nil); err == nil {
+			t.Errorf("%q: no error", text)
+		}
+	}
+	if r, err := newReplacer("", nil, nil); err != nil || r.text("value") != DefaultReplacement {
+		t.Errorf("empty replacement: %q, %v", r.text("value"), err)
+	}
+}
+
+func TestBatchesKeepLengthOrder(t *testing.T) {
+	r := replacer{template: "${KEY}", texts: map[string]string{"aaaa": "A", "bbb": "B", "cc": "A"}}
+	batches := r.batches([]string{"aaaa", "bbb", "cc"})
+	if len(batches) != 3 || batches[0][0] != "aaaa" || batches[1][0] != "bbb" || batches[2][0] != "cc" {
+		t.Errorf("batches = %v, want one per run of text", batches)
+	}
+}
@@ -0,0 +1,79 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Backup is what a repository can be restored to after a clean: the bundle
+// of CleanOptions.Bundle, else the backup branch
+type Backup struct {
+	Bundle string // Every branch and tag before the clean
+	Branch string // The branch checked out before the clean
+}
+
+// Backup returns the backup a clean made (zero if none)
+func (r *CleanResult) Backup() Backup {
+	return Backup{Bundle: r.BackupBundle, Branch: r.BackupBranch}
+}
+
+// Empty reports whether there is nothing to restore from
+func (b Backup) Empty() bool {
+	return b.Bundle == "" && b.Branch == ""
+}
+
+// String names the backup restored from: the bundle when there is one
+func (b Backup) String() string {
+	if b.Bundle != "" {
+		return "bundle " + b.Bundle
+	}
+	return "branch " + b.Branch
+}
+
+// Restore resets a repository to its backup. From a bundle, every branch,
+// tag and remote-tracking branch it holds is reset (refs created since are
+// kept); from a backup branch, the branch checked out. The working tree
+// and the index of a non-bare repository are then reset to HEAD: changes
+// not committed are lost.
+func Restore(repoPath string, backup Backup) error {
+	switch {
+	case backup.Bundle != "":
+		if _, err := os.Stat(backup.Bundle); err != nil {
+			return fmt.Errorf("bundle %s: %w", backup.Bundle, err)
+		}
+		if _, err := gitOutput(repoPath, "bundle", "verify", "--quiet", backup.Bundle); err != nil {
+			return fmt.Errorf("%s is not a valid bundle of this repository: %w", backup.Bundle, err)
+		}
+		// --update-head-ok: the branch checked out is reset like the others
+		if _, err := gitOutput(repoPath, "fetch", "--quiet", "--force", "--update-head-ok", backup.Bundle,
+			"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*", "refs/remotes/*:refs/remotes/*"); err != nil {
+			return err
+		}
+		return resetWorktree(repoPath, "HEAD")
+	case backup.Branch != "":
+		if _, err := gitOutput(repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+backup.Branch); err != nil {
+			return fmt.Errorf("backup branch %s not found", backup.Branch)
+		}
+		return resetWorktree(repoPath, backup.Branch)
+	}
+	return fmt.Errorf("no backup to restore from")
+}
+
+// resetWorktree resets the branch checked out, its index and working tree
+// to a commit. A bare repository has none: only a branch target is moved.
+func resetWorktree(repoPath, commit string) error {
+	out, err := gitOutput(repoPath, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(out)) == "true" {
+		if commit == "HEAD" {
+			return nil
+		}
+		_, err := gitOutput(repoPath, "update-ref", "HEAD", commit)
+		return err
+	}
+	_, err = gitOutput(repoPath, "reset", "--quiet", "--hard", commit)
+	return err
+}
@@ -0,0 +1,48 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestoreFromBundle(t *testing.T) {
+	repo := newTestRepo(t, map[string]string{"app.env": "API_TOKEN=tok-123456789\n"})
+	gitTest(t, repo, "tag", "v1")
+	bundle := filepath.Join(t.TempDir(), "repo.bundle")
+
+	result, err := New().Clean(repo, []string{"tok-123456789"}, CleanOptions{Tool: ToolNative, Bundle: bundle})
+	if err != nil || !result.Success {
+		t.Fatalf("Clean = %+v, %v", result, err)
+	}
+	if got := gitTest(t, repo, "show", "v1:app.env"); got != "API_TOKEN=***REMOVED***\n" {
+		t.Fatalf("v1:app.env = %q after the clean", got)
+	}
+
+	if err := Restore(repo, result.Backup()); err != nil {
+		t.Fatal(err)
+	}
+	for _, rev := range []string{"HEAD", "v1"} {
+		if got := gitTest(t, repo, "show", rev+":app.env"); got != "API_TOKEN=tok-123456789\n" {
+			t.Errorf("%s:app.env = %q after the restore", rev, got)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(repo, "app.env")); string(data) != "API_TOKEN=tok-123456789\n" {
+		t.Errorf("working tree app.env = %q after the restore", data)
+	}
+}
+
+func TestRestoreFromBranch(t *testing.T) {
+	repo := newTestRepo(t, map[string]string{"app.env": "A=1\n"}, map[string]string{"app.env": "A=2\n"})
+	gitTest(t, repo, "branch", "backup-before-clean-1", "HEAD~1")
+
+	if err := Restore(repo, Backup{Branch: "backup-before-clean-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repo, "app.env")); string(data) != "A=1\n" {
+		t.Errorf("app.env = %q after the restore", data)
+	}
+	if err := Restore(repo, Backup{Branch: "missing"}); err == nil {
+		t.Error("restored from a missing branch")
+	}
+}
@@ -0,0 +1,99 @@
+package cleaner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MinBareLength is the shortest value replaced wherever it appears. Shorter
+// values are only replaced as whole words, so "1234" does not clobber
+// "order-12345" or a port number in another file.
+const MinBareLength = 8
+
+// RiskyValue is a value whose replacement may damage unrelated data
+type RiskyValue struct {
+	MaskedValue string
+	Reason      string
+}
+
+// Reasons reported for risky replacements
+const (
+	RiskShort     = "short value, replaced as a whole word only"
+	RiskNumeric   = "numeric value, common in unrelated data"
+	RiskSubstring = "part of another secret value"
+)
+
+// CheckValues lists the values whose replacement may hit more than the
+// secret: short or numeric values, and values contained in other values
+func CheckValues(secrets []string) []RiskyValue {
+	var risky []RiskyValue
+	for _, v := range secrets {
+		var reasons []string
+		if isNumeric(v) {
+			reasons = append(reasons, RiskNumeric)
+		}
+		if len(v) < MinBareLength {
+			reasons = append(reasons, RiskShort)
+		}
+		for _, other := range secrets {
+			if other != v && strings.Contains(other, v) {
+				reasons = append(reasons, RiskSubstring)
+				break
+			}
+		}
+		if len(reasons) > 0 {
+			risky = append(risky, RiskyValue{MaskedValue: maskSecret(v), Reason: strings.Join(reasons, "; ")})
+		}
+	}
+	sort.SliceStable(risky, func(i, j int) bool { return risky[i].MaskedValue < risky[j].MaskedValue })
+	return risky
+}
+
+// isNumeric reports whether a value only holds digits (and separators)
+func isNumeric(v string) bool {
+	digits := 0
+	for _, c := range v {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '.' || c == '-' || c == '_' || c == ' ':
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// valuePattern is the regex replacing a value: the value itself, anchored on
+// word boundaries when it is shorter than MinBareLength. A boundary is only
+// added next to a word character, where it means something.
+func valuePattern(v string) string {
+	pattern := regexp.QuoteMeta(v)
+	if len(v) >= MinBareLength {
+		return pattern
+	}
+	if isWordChar(v[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordChar(v[len(v)-1]) {
+		pattern += `\b`
+	}
+	return pattern
+}
+
+// isWordChar reports whether c is a regex word character
+func isWordChar(c byte) bool {
+	return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// byLength sorts values longest first, so a value is replaced before the
+// shorter values it contains
+func byLength(secrets []string) []string {
+	sorted := make([]string, len(secrets))
+	copy(sorted, secrets)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,44 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckValuesFlagsRiskyReplacements(t *testing.T) {
+	risky := CheckValues([]string{"4242", "hunter2", "longtoken-hunter2-suffix", "Sup3rS3cret!"})
+
+	reasons := make(map[string]string)
+	for _, r := range risky {
+		reasons[r.MaskedValue] = r.Reason
+	}
+	if len(reasons) != 2 {
+		t.Fatalf("risky = %+v, want the numeric and the short contained value", risky)
+	}
+	if r := reasons[maskSecret("4242")]; !strings.Contains(r, RiskNumeric) || !strings.Contains(r, RiskShort) {
+		t.Errorf("4242: %q", r)
+	}
+	if r := reasons[maskSecret("hunter2")]; !strings.Contains(r, RiskSubstring) {
+		t.Errorf("hunter2: %q", r)
+	}
+}
+
+func TestCleanCurrentFilesAnchorsShortValues(t *testing.T) {
+	repo := t.TempDir()
+	path := filepath.Join(repo, "app.conf")
+	content := "pin=4242\norder=order-142420\nport=14242\ntoken=Sup3rS3cret!\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := New().cleanCurrentFiles(repo, []string{"4242", "Sup3rS3cret!"}, nil, replacer{}, map[string]bool{"app.conf": true}, -1); err != nil {
+		t.Fatalf("cleanCurrentFiles: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "pin=***REMOVED***\norder=order-142420\nport=14242\ntoken=***REMOVED***\n"
+	if string(data) != want {
+		t.Errorf("cleaned file:\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,123 @@
+package cleaner
+
+import (
+	"sort"
+
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+)
+
+// LeftSecret is a secret value deliberately kept by differential cleaning
+type LeftSecret struct {
+	File        string
+	Key         string
+	MaskedValue string
+	Reason      string
+}
+
+// Reasons reported for values kept by differential cleaning (all of them)
+// and by FilterDismissed (false positives and accepted risks)
+const (
+	ReasonNotRotated    = "confirmed but not rotated yet"
+	ReasonAccepted      = "risk accepted"
+	ReasonUnreviewed    = "not reviewed"
+	ReasonFalsePositive = "false positive"
+)
+
+// FilterRotated keeps only the values that are safe to redact: every occurrence
+// of the value must be triaged as rotated or false positive, and at least one
+// as rotated. Redacting a value that is still in use would break whatever
+// depends on it until rotation happens, so those are returned as left instead.
+func FilterRotated(loaded *LoadSecretsResult, store *triage.Store) ([]string, []LeftSecret) {
+	rotated := make(map[string]bool)
+	blocked := make(map[string]bool)
+	var left []LeftSecret
+
+	for _, e := range loaded.Entries {
+		reason := ""
+		switch store.Status(e.File, e.Key, e.Value) {
+		case triage.StatusRotated:
+			rotated[e.Value] = true
+		case triage.StatusFalsePositive:
+			// Not a secret: neither a reason to clean nor to keep
+		case triage.StatusConfirmed:
+			reason = ReasonNotRotated
+		case triage.StatusAccepted:
+			reason = ReasonAccepted
+		default:
+			reason = ReasonUnreviewed
+		}
+		if reason != "" {
+			blocked[e.Value] = true
+			left = append(left, LeftSecret{
+				File:        e.File,
+				Key:         e.Key,
+				MaskedValue: maskSecret(e.Value),
+				Reason:      reason,
+			})
+		}
+	}
+
+	var secrets []string
+	for _, v := range loaded.Secrets {
+		if rotated[v] && !blocked[v] {
+			secrets = append(secrets, v)
+		}
+	}
+
+	sortLeft(left)
+	return secrets, left
+}
+
+// FilterDismissed leaves out the values triaged as needing no cleanup: those
+// whose every occurrence is a false positive or an accepted risk. A value
+// with one occurrence still to handle is redacted everywhere.
+func FilterDismissed(loaded *LoadSecretsResult, store *triage.Store) ([]string, []LeftSecret) {
+	dismissed := make(map[string][]LeftSecret)
+	pending := make(map[string]bool)
+	seen := make(map[string]bool)
+
+	for _, e := range loaded.Entries {
+		reason := ""
+		switch store.Status(e.File, e.Key, e.Value) {
+		case triage.StatusFalsePositive:
+			reason = ReasonFalsePositive
+		case triage.StatusAccepted:
+			reason = ReasonAccepted
+		default:
+			pending[e.Value] = true
+			continue
+		}
+		// Stream results list an occurrence per commit
+		if fingerprint := triage.Fingerprint(e.File, e.Key, e.Value); !seen[fingerprint] {
+			seen[fingerprint] = true
+			dismissed[e.Value] = append(dismissed[e.Value], LeftSecret{
+				File:        e.File,
+				Key:         e.Key,
+				MaskedValue: maskSecret(e.Value),
+				Reason:      reason,
+			})
+		}
+	}
+
+	var secrets []string
+	var left []LeftSecret
+	for _, v := range loaded.Secrets {
+		if kept, ok := dismissed[v]; ok && !pending[v] {
+			left = append(left, kept...)
+			continue
+		}
+		secrets = append(secrets, v)
+	}
+	sortLeft(left)
+	return secrets, left
+}
+
+// sortLeft orders the values left by file and key
+func sortLeft(left []LeftSecret) {
+	sort.Slice(left, func(i, j int) bool {
+		if left[i].File != left[j].File {
+			return left[i].File < left[j].File
+		}
+		return left[i].Key < left[j].Key
+	})
+}
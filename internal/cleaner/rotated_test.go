@@ -0,0 +1,84 @@
+package cleaner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+)
+
+func TestFilterRotatedKeepsValuesInUse(t *testing.T) {
+	store, err := triage.Load(filepath.Join(t.TempDir(), ".gitsecret-baseline.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	store.Set("app.conf", "db_password", "old-password", triage.StatusRotated, "")
+	store.Set("app.conf", "db_password", "live-password", triage.StatusConfirmed, "")
+	store.Set("app.conf", "token", "shared-token", triage.StatusRotated, "")
+	store.Set("ci.yml", "token", "shared-token", triage.StatusAccepted, "")
+	store.Set("test.conf", "password", "changeme", triage.StatusFalsePositive, "")
+
+	loaded := &LoadSecretsResult{
+		Secrets: []string{"old-password", "live-password", "shared-token", "changeme", "unreviewed"},
+		Entries: []SecretEntry{
+			{File: "app.conf", Key: "db_password", Value: "old-password"},
+			{File: "app.conf", Key: "db_password", Value: "live-password"},
+			{File: "app.conf", Key: "token", Value: "shared-token"},
+			{File: "ci.yml", Key: "token", Value: "shared-token"},
+			{File: "test.conf", Key: "password", Value: "changeme"},
+			{File: "app.conf", Key: "api_key", Value: "unreviewed"},
+		},
+	}
+
+	secrets, left := FilterRotated(loaded, store)
+	if len(secrets) != 1 || secrets[0] != "old-password" {
+		t.Fatalf("secrets = %v, want only the rotated value", secrets)
+	}
+
+	reasons := make(map[string]string)
+	for _, l := range left {
+		reasons[l.File+"/"+l.Key] = l.Reason
+	}
+	want := map[string]string{
+		"app.conf/db_password": ReasonNotRotated,
+		"ci.yml/token":         ReasonAccepted,
+		"app.conf/api_key":     ReasonUnreviewed,
+	}
+	if len(left) != len(want) {
+		t.Fatalf("left = %+v, want %d entries", left, len(want))
+	}
+	for k, reason := range want {
+		if reasons[k] != reason {
+			t.Errorf("%s: reason = %q, want %q", k, reasons[k], reason)
+		}
+	}
+}
+
+func TestFilterDismissedKeepsValuesToHandle(t *testing.T) {
+	store, err := triage.Load(filepath.Join(t.TempDir(), ".gitsecret-baseline.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	store.Set("test.conf", "password", "changeme", triage.StatusFalsePositive, "")
+	store.Set("app.conf", "token", "shared-token", triage.StatusAccepted, "")
+	store.Set("legacy.conf", "key", "legacy-key", triage.StatusAccepted, "")
+
+	loaded := &LoadSecretsResult{
+		Secrets: []string{"changeme", "shared-token", "legacy-key"},
+		Entries: []SecretEntry{
+			{File: "test.conf", Key: "password", Value: "changeme"},
+			{File: "test.conf", Key: "password", Value: "changeme"},
+			{File: "app.conf", Key: "token", Value: "shared-token"},
+			{File: "ci.yml", Key: "token", Value: "shared-token"},
+			{File: "legacy.conf", Key: "key", Value: "legacy-key"},
+		},
+	}
+
+	secrets, left := FilterDismissed(loaded, store)
+	if len(secrets) != 1 || secrets[0] != "shared-token" {
+		t.Fatalf("secrets = %v, want the value still unreviewed in ci.yml", secrets)
+	}
+	if len(left) != 2 || left[0].Reason != ReasonAccepted || left[1].Reason != ReasonFalsePositive {
+		t.Errorf("left = %+v, want legacy.conf accepted and test.conf false positive once", left)
+	}
+}
@@ -0,0 +1,58 @@
+package cleaner
+
+import "sort"
+
+// Candidate is a value about to be cleaned and where it was found, for a
+// last review of the dry run before the actual clean
+type Candidate struct {
+	Value       string
+	MaskedValue string
+	File        string // First occurrence, by file then key
+	Key         string
+	Occurrences int // File/key pairs holding the value
+}
+
+// Candidates describes the values of secrets from the scan entries, sorted
+// by file and key
+func Candidates(loaded *LoadSecretsResult, secrets []string) []Candidate {
+	index := make(map[string]int, len(secrets))
+	candidates := make([]Candidate, 0, len(secrets))
+	for _, v := range secrets {
+		index[v] = len(candidates)
+		candidates = append(candidates, Candidate{Value: v, MaskedValue: maskSecret(v)})
+	}
+
+	for _, e := range loaded.Entries {
+		i, ok := index[e.Value]
+		if !ok {
+			continue
+		}
+		c := &candidates[i]
+		c.Occurrences++
+		if c.File == "" || e.File < c.File || (e.File == c.File && e.Key < c.Key) {
+			c.File, c.Key = e.File, e.Key
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].File != candidates[j].File {
+			return candidates[i].File < candidates[j].File
+		}
+		return candidates[i].Key < candidates[j].Key
+	})
+	return candidates
+}
+
+// Exclude returns the secrets that are not excluded
+func Exclude(secrets []string, excluded map[string]bool) []string {
+	if len(excluded) == 0 {
+		return secrets
+	}
+	kept := make([]string, 0, len(secrets))
+	for _, v := range secrets {
+		if !excluded[v] {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
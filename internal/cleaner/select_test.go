@@ -0,0 +1,32 @@
+package cleaner
+
+import "testing"
+
+func TestCandidatesAndExclude(t *testing.T) {
+	loaded := &LoadSecretsResult{
+		Secrets: []string{"token-value-1234", "password-5678"},
+		Entries: []SecretEntry{
+			{File: "b.conf", Key: "token", Value: "token-value-1234"},
+			{File: "a.conf", Key: "token", Value: "token-value-1234"},
+			{File: "b.conf", Key: "password", Value: "password-5678"},
+			{File: "c.conf", Key: "other", Value: "not-selected"},
+		},
+	}
+
+	candidates := Candidates(loaded, loaded.Secrets)
+	if len(candidates) != 2 {
+		t.Fatalf("candidates = %+v, want 2", candidates)
+	}
+	first := candidates[0]
+	if first.Value != "token-value-1234" || first.File != "a.conf" || first.Occurrences != 2 {
+		t.Errorf("first candidate = %+v, want the token at its first file with 2 occurrences", first)
+	}
+	if first.MaskedValue == first.Value {
+		t.Errorf("MaskedValue %q is not masked", first.MaskedValue)
+	}
+
+	kept := Exclude(loaded.Secrets, map[string]bool{"token-value-1234": true})
+	if len(kept) != 1 || kept[0] != "password-5678" {
+		t.Errorf("Exclude = %v, want only the password", kept)
+	}
+}
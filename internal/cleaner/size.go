@@ -0,0 +1,113 @@
+package cleaner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// RepoSize is the size of a repository's history, from which the duration
+// of a rewrite is estimated before it starts
+type RepoSize struct {
+	Commits int   // Commits reachable from any ref
+	Objects int64 // Loose and packed objects
+	Bytes   int64 // Disk size of the objects
+}
+
+// Rough rewrite speeds of each tool, in commits and object bytes per
+// second. filter-branch checks out every commit (--tree-filter), so it is
+// slower by orders of magnitude.
+var rewriteRates = map[string]struct{ commits, bytes float64 }{
+	"filter-repo":   {commits: 2000, bytes: 50 << 20},
+	"bfg":           {commits: 1000, bytes: 30 << 20},
+	ToolNative:      {commits: 300, bytes: 10 << 20},
+	"filter-branch": {commits: 5, bytes: 1 << 20},
+}
+
+// A rewrite is warned about from any of these
+const (
+	LargeRepoCommits  = 100000
+	LargeRepoBytes    = 1 << 30
+	LargeRepoDuration = 30 * time.Minute
+)
+
+// EstimateSize measures the history of a repository, from git count-objects
+// and rev-list
+func EstimateSize(repoPath string) (RepoSize, error) {
+	var size RepoSize
+	out, err := gitOutput(repoPath, "count-objects", "-v")
+	if err != nil {
+		return size, err
+	}
+	counts := bufio.NewScanner(bytes.NewReader(out))
+	for counts.Scan() {
+		name, value, ok := strings.Cut(counts.Text(), ": ")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.ParseInt(value, 10, 64)
+		switch name {
+		case "count", "in-pack":
+			size.Objects += n
+		case "size", "size-pack":
+			size.Bytes += n * 1024 // In KiB
+		}
+	}
+
+	out, err = gitOutput(repoPath, "rev-list", "--all", "--count")
+	if err != nil {
+		return size, err
+	}
+	size.Commits, _ = strconv.Atoi(strings.TrimSpace(string(out)))
+	return size, nil
+}
+
+// Duration estimates how long a tool (auto: the best available) takes to
+// rewrite the history
+func (s RepoSize) Duration(tool string) time.Duration {
+	if tool == "" || tool == "auto" {
+		tool = selectBestTool()
+	}
+	rate, ok := rewriteRates[tool]
+	if !ok {
+		rate = rewriteRates["filter-branch"]
+	}
+	seconds := float64(s.Commits)/rate.commits + float64(s.Bytes)/rate.bytes
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Large reports whether rewriting the history with a tool may take long
+// enough to plan for: many commits, a big object store or a long estimate
+func (s RepoSize) Large(tool string) bool {
+	return s.Commits >= LargeRepoCommits || s.Bytes >= LargeRepoBytes || s.Duration(tool) >= LargeRepoDuration
+}
+
+// FormatDuration writes an estimate in the largest fitting unit
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "under a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("about %d min", int(d.Round(time.Minute).Minutes()))
+	}
+	return fmt.Sprintf("about %.1f h", d.Hours())
+}
+
+// gitOutput runs a git command in a repository
+func gitOutput(repoPath string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	done := debugbundle.Track(cmd)
+	out, err := cmd.Output()
+	done(err)
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
@@ -0,0 +1,28 @@
+package cleaner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRepoSizeDuration(t *testing.T) {
+	small := RepoSize{Commits: 500, Objects: 3000, Bytes: 20 << 20}
+	if d := small.Duration("filter-repo"); d >= time.Minute {
+		t.Errorf("filter-repo on %+v = %s, want under a minute", small, d)
+	}
+	if small.Large("filter-repo") {
+		t.Errorf("%+v is large for filter-repo", small)
+	}
+	// filter-branch checks out every commit
+	if !small.Large("filter-branch") && small.Duration("filter-branch") <= small.Duration("filter-repo") {
+		t.Errorf("filter-branch is not slower than filter-repo")
+	}
+
+	huge := RepoSize{Commits: 250000, Objects: 2000000, Bytes: 4 << 30}
+	if !huge.Large("filter-repo") {
+		t.Errorf("%+v is not large", huge)
+	}
+	if got := FormatDuration(90 * time.Minute); got != "about 1.5 h" {
+		t.Errorf("FormatDuration = %q, want about 1.5 h", got)
+	}
+}
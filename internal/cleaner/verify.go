@@ -0,0 +1,65 @@
+package cleaner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+)
+
+// Survivor is a cleaned value that a scan of the rewritten history still
+// finds, e.g. a value split across lines that no tool replaced
+type Survivor struct {
+	File        string
+	Key         string
+	MaskedValue string
+	Commits     int // Commits of the rewritten history holding it
+}
+
+// Survivors lists the findings of a scan of the cleaned history that hold
+// one of the cleaned values, in the files the clean covered (all if paths
+// is empty). A finding survives when its value is a cleaned one or holds
+// one: a line of a private key, a value the scanner now reads in full.
+func Survivors(scan *model.ScanResult, cleaned []string, paths PathFilter) []Survivor {
+	exact := make(map[string]bool, len(cleaned))
+	for _, v := range cleaned {
+		exact[v] = true
+	}
+	// Short values would be found inside unrelated ones
+	var pieces []string
+	for _, v := range splitMultiline(cleaned) {
+		if len(v) >= MinBareLength {
+			pieces = append(pieces, v)
+		}
+	}
+	survives := func(value string) bool {
+		if exact[value] {
+			return true
+		}
+		for _, piece := range pieces {
+			if strings.Contains(value, piece) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var survivors []Survivor
+	for _, secret := range scan.Secrets {
+		if !paths.Match(secret.File) {
+			continue
+		}
+		for _, v := range secret.History {
+			if survives(v.Value) {
+				survivors = append(survivors, Survivor{File: secret.File, Key: secret.Key, MaskedValue: v.MaskedValue, Commits: len(v.Commits)})
+			}
+		}
+	}
+	sort.SliceStable(survivors, func(i, j int) bool {
+		if survivors[i].File != survivors[j].File {
+			return survivors[i].File < survivors[j].File
+		}
+		return survivors[i].Key < survivors[j].Key
+	})
+	return survivors
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/analyzer"
+	"github.com/Drilmo/git-secret-scanner/internal/auditlog"
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/i18n"
+	"github.com/Drilmo/git-secret-scanner/internal/plain"
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+	"github.com/Drilmo/git-secret-scanner/internal/workspace"
+)
+
+// rawValuesConfirmation must be typed to write a report with raw values
+const rawValuesConfirmation = "show values"
+
+func runAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	outputPath := fs.String("output", "", "report file, .csv or .html (default: print a text report)")
+	force := fs.Bool("force", false, "overwrite an existing report file without asking")
+	showValues := fs.Bool("show-values", false, "write raw values into the report (asks for confirmation, recorded in the audit log)")
+	anonymize := fs.Bool("anonymize", false, "author pseudonyms and no values, for sharing outside")
+	maxSecrets := fs.Int("max", 50, "secrets listed in the text report (0: all)")
+	configPath := fs.String("config", "", "configuration file used for severities (default: auto-detect)")
+	repoPath := fs.String("repo", ".", "repository whose latest workspace scan is analyzed without RESULTS")
+	baselinePath := fs.String("baseline", "", "triage store whose false positives and accepted risks are left out (default: REPO/"+config.BaselineFile+", REPO being the one of the results' workspace)")
+	all := fs.Bool("all", false, "also report the values triaged as false positives or accepted risks")
+	locale := fs.String("locale", "", "numbers and dates of the CSV and HTML reports: iso, en, en-GB, fr, de (default: settings.locale, else iso)")
+	lang := fs.String("lang", "", "language of the reports: en, fr (default: "+config.LanguageEnv+", settings.language, else the system's)")
+	var input string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		input, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if input == "" {
+		input = fs.Arg(0)
+	}
+	if input == "" {
+		ws, err := workspace.Lookup(*repoPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if input = ws.LatestScan(); input == "" {
+			return fmt.Errorf("analyze: missing results file (no scan in the workspace of %s)", ws.Repo)
+		}
+		fmt.Fprintf(os.Stderr, "Analyzing %s\n", input)
+	}
+	if *showValues && *anonymize {
+		return fmt.Errorf("analyze: --show-values and --anonymize cannot be combined")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if *locale == "" {
+		*locale = cfg.Settings.Locale
+	}
+	reportLocale, err := analyzer.LookupLocale(*locale)
+	if err != nil {
+		return fmt.Errorf("analyze: %w", err)
+	}
+	if *lang == "" {
+		*lang = i18n.Resolve(cfg.Settings.Language)
+	} else if !slices.Contains(config.Languages, *lang) {
+		return fmt.Errorf("analyze: unknown language %q (%s)", *lang, strings.Join(config.Languages, ", "))
+	}
+
+	a := analyzer.New()
+	opts := analyzer.AnalyzeOptions{ShowValues: *showValues, MaxSecrets: *maxSecrets}
+	if !*all {
+		if *baselinePath == "" {
+			*baselinePath = filepath.Join(workspace.ResultsRepo(input, *repoPath), config.BaselineFile)
+		}
+		store, err := triage.Load(*baselinePath)
+		if err != nil {
+			return err
+		}
+		opts.Skip = store.Dismissed
+	}
+	var result *analyzer.Analysis
+	if strings.HasSuffix(input, ".jsonl") {
+		result, err = a.AnalyzeJSONL(input, opts)
+	} else {
+		result, err = a.AnalyzeJSON(input, opts)
+	}
+	if err != nil {
+		return err
+	}
+	if result.Excluded > 0 {
+		fmt.Fprintf(os.Stderr, "%d values triaged as false positives or accepted risks in %s left out (--all to include them)\n",
+			result.Excluded, *baselinePath)
+	}
+	result.Health = analyzer.ComputeHealth(result, cfg.SeverityForType, time.Now())
+	result.Hotspots = analyzer.ComputeHotspots(result, result.Repository, 10)
+	result.Locale = reportLocale
+	result.Language = *lang
+	if *anonymize {
+		analyzer.Anonymize(result, os.Getenv(analyzer.AnonymizeSaltEnv))
+	}
+
+	if *outputPath != "" {
+		if *outputPath, err = checkOutput("analyze", *outputPath, *force); err != nil {
+			return err
+		}
+	}
+	dest := *outputPath
+	if dest == "" {
+		dest = "stdout"
+	}
+	if *showValues {
+		if err := confirmRawValues(bufio.NewReader(os.Stdin), "analyze", "The report ("+dest+")"); err != nil {
+			return err
+		}
+		// No entry, no report
+		err := auditlog.Record(auditlog.Entry{
+			Action: auditlog.ActionRawValuesReport,
+			Input:  input,
+			Output: dest,
+			Values: result.Stats.UniqueValues,
+		})
+		if err != nil {
+			return fmt.Errorf("analyze: cannot write the audit log, report not written: %w", err)
+		}
+	}
+
+	switch {
+	case *outputPath == "":
+		fmt.Print(plain.Text(analyzer.GenerateReport(result, *showValues, *maxSecrets)))
+		return nil
+	case strings.HasSuffix(strings.ToLower(*outputPath), ".html"):
+		err = analyzer.ExportHTML(result, *outputPath)
+	default:
+		err = analyzer.ExportCSV(result, *outputPath)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d secrets analyzed: %s\n", result.Stats.UniqueSecrets, *outputPath)
+	return nil
+}
+
+// confirmRawValues asks to type rawValuesConfirmation on standard input
+// (read through in) before what a command writes shows raw values
+func confirmRawValues(in *bufio.Reader, command, what string) error {
+	fmt.Fprintf(os.Stderr, "%s will contain raw secret values; this is recorded in %s.\n", what, auditlog.Path())
+	fmt.Fprintf(os.Stderr, "Type %q to confirm: ", rawValuesConfirmation)
+	line, _ := in.ReadString('\n')
+	if strings.TrimSpace(line) != rawValuesConfirmation {
+		return fmt.Errorf("%s: raw values not confirmed, nothing shown", command)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/analyzer"
+)
+
+func runAnonymize(args []string) error {
+	fs := flag.NewFlagSet("anonymize", flag.ContinueOnError)
+	outputPath := fs.String("output", "", "anonymized copy (default: RESULTS with .anon before the extension)")
+	force := fs.Bool("force", false, "overwrite an existing output file without asking")
+	salt := fs.String("salt", os.Getenv(analyzer.AnonymizeSaltEnv), "secret salt of the author pseudonyms (also "+analyzer.AnonymizeSaltEnv+")")
+	var input string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		input, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if input == "" {
+		input = fs.Arg(0)
+	}
+	if input == "" {
+		return fmt.Errorf("anonymize: missing results file")
+	}
+
+	output := *outputPath
+	if output == "" {
+		ext := filepath.Ext(input)
+		output = strings.TrimSuffix(input, ext) + ".anon" + ext
+	}
+	if output == input {
+		return fmt.Errorf("anonymize: the output must differ from the input")
+	}
+
+	output, err := checkOutput("anonymize", output, *force)
+	if err != nil {
+		return err
+	}
+	removed, err := analyzer.AnonymizeResults(input, output, *salt)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d values removed, authors replaced by pseudonyms: %s\n", removed, output)
+	if *salt == "" {
+		fmt.Fprintln(os.Stderr, "Warning: no salt, pseudonyms can be reversed by guessing author names (use --salt)")
+	}
+	return nil
+}
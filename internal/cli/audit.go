@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+
+	"github.com/Drilmo/git-secret-scanner/internal/analyzer"
+	"github.com/Drilmo/git-secret-scanner/internal/auditlog"
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/plain"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+)
+
+// auditKeys maps a keystroke to the status it records
+var auditKeys = map[byte]string{
+	'y': triage.StatusConfirmed,
+	'r': triage.StatusRotated,
+	'n': triage.StatusFalsePositive,
+	'a': triage.StatusAccepted,
+}
+
+func runAuditFindings(args []string) error {
+	fs := flag.NewFlagSet("audit-findings", flag.ContinueOnError)
+	repoPath := fs.String("repo", "", "repository the results come from (default: from the result file, else .)")
+	baselinePath := fs.String("baseline", "", "triage store (default: REPO/"+config.BaselineFile+")")
+	all := fs.Bool("all", false, "also review findings that already have a decision")
+	showValues := fs.Bool("show-values", false, "show raw values instead of masked ones")
+	configPath := fs.String("config", "", "configuration file used for severities (default: auto-detect)")
+
+	var resultsPath string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		resultsPath, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if resultsPath == "" {
+		resultsPath = fs.Arg(0)
+	}
+	if resultsPath == "" {
+		return fmt.Errorf("audit-findings: missing results file")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	pager, err := analyzer.OpenResults(resultsPath)
+	if err != nil {
+		return err
+	}
+
+	repo := *repoPath
+	if repo == "" {
+		repo = pager.Repository
+	}
+	if repo == "" {
+		repo = "."
+	}
+	if *baselinePath == "" {
+		*baselinePath = filepath.Join(repo, config.BaselineFile)
+	}
+
+	store, err := triage.Load(*baselinePath)
+	if err != nil {
+		return err
+	}
+
+	keys := newKeyReader()
+	if *showValues {
+		if err := confirmRawValues(keys.reader, "audit-findings", "The review"); err != nil {
+			return err
+		}
+		// No entry, no values
+		err := auditlog.Record(auditlog.Entry{
+			Action: auditlog.ActionRawValuesReview,
+			Input:  resultsPath,
+			Output: "terminal",
+		})
+		if err != nil {
+			return fmt.Errorf("audit-findings: cannot write the audit log, values not shown: %w", err)
+		}
+	}
+	const pageSize = 50
+	reviewed, skipped := 0, 0
+
+	for page := 0; page < pager.PageCount(pageSize); page++ {
+		rows, err := pager.Page(page, pageSize)
+		if err != nil {
+			return err
+		}
+
+		for i, secret := range rows {
+			for _, h := range secret.History {
+				if !*all && store.Status(secret.File, secret.Key, h.Value) != "" {
+					continue
+				}
+
+				printFinding(page*pageSize+i+1, pager.Len(), secret, h, cfg, repo, *showValues)
+				if current := store.Status(secret.File, secret.Key, h.Value); current != "" {
+					fmt.Printf("  Current decision: %s\n", current)
+				}
+				fmt.Print("  [y] real secret  [r] rotated  [n] false positive  [a] accept risk  [s] skip  [q] quit > ")
+
+				key, err := keys.read()
+				fmt.Println()
+				if err != nil || key == 'q' {
+					fmt.Print(plain.Text(fmt.Sprintf("\n%d decisions recorded, %d skipped → %s\n", reviewed, skipped, store.Path())))
+					return nil
+				}
+
+				status, ok := auditKeys[key]
+				if !ok {
+					skipped++
+					continue
+				}
+				store.Set(secret.File, secret.Key, h.Value, status, "")
+				// Save after every decision so an interrupted session loses nothing
+				if err := store.Save(); err != nil {
+					return err
+				}
+				reviewed++
+			}
+		}
+	}
+
+	fmt.Print(plain.Text(fmt.Sprintf("\nDone: %d decisions recorded, %d skipped → %s\n", reviewed, skipped, store.Path())))
+	return nil
+}
+
+// printFinding shows one value of a secret with its context
+func printFinding(n, total int, secret analyzer.ScanSecret, h analyzer.ScanValueEntry, cfg *config.Config, repo string, showValues bool) {
+	value := h.MaskedValue
+	if showValues {
+		value = h.Value
+	}
+
+	fmt.Printf("\n[%d/%d] %-8s %s  %s (%s)\n", n, total, strings.ToUpper(cfg.SeverityForType(secret.Type)), secret.File, secret.Key, secret.Type)
+	fmt.Printf("  Value: %s\n", value)
+	fmt.Print(plain.Text(fmt.Sprintf("  Seen %s → %s in %d commit(s) by %s\n",
+		shortDate(h.FirstSeen), shortDate(h.LastSeen), len(h.Commits), strings.Join(h.Authors, ", "))))
+
+	if len(h.Commits) == 0 {
+		return
+	}
+	commit := h.Commits[len(h.Commits)-1]
+	lines, first, at := contextLines(repo, commit, secret.File, h.Value, 2)
+	if lines == nil {
+		return
+	}
+	fmt.Printf("  Context (%s:%s):\n", shortHash(commit), secret.File)
+	patterns := cfg.GetCompiledPatterns()
+	for i, line := range lines {
+		if !showValues {
+			line = strings.ReplaceAll(line, h.Value, h.MaskedValue)
+			// Neighbouring lines may hold other secrets
+			line = maskExtractedValue(line, patterns)
+		}
+		marker := " "
+		if i == at {
+			marker = ">"
+		}
+		fmt.Printf("  %s %5d | %s\n", marker, first+i, line)
+	}
+}
+
+// contextLines returns up to radius lines around the first line holding value
+// in file at commit ("current" reads the working tree). first is the line
+// number of lines[0] and at the index of the matching line.
+func contextLines(repo, commit, file, value string, radius int) (lines []string, first, at int) {
+	var data []byte
+	var err error
+	switch {
+	case commit == "current":
+		data, err = os.ReadFile(filepath.Join(repo, file))
+	case file == scanner.CommitMessageFile:
+		cmd := exec.Command("git", "log", "-1", "--format=%B", commit)
+		cmd.Dir = repo
+		data, err = cmd.Output()
+	default:
+		cmd := exec.Command("git", "show", commit+":"+file)
+		cmd.Dir = repo
+		data, err = cmd.Output()
+	}
+	if err != nil {
+		return nil, 0, 0
+	}
+
+	all := strings.Split(string(data), "\n")
+	for i, line := range all {
+		if !strings.Contains(line, value) {
+			continue
+		}
+		start, end := max(0, i-radius), min(len(all), i+radius+1)
+		return all[start:end], start + 1, i - start
+	}
+	return nil, 0, 0
+}
+
+// maskExtractedValue masks the value of a key/value line matched by an extraction pattern
+func maskExtractedValue(line string, patterns []*config.CompiledPattern) string {
+	for _, p := range patterns {
+		match := p.Regex.FindStringSubmatchIndex(line)
+		if match == nil || len(match) <= 2*p.ValueGroup+1 || match[2*p.ValueGroup] < 0 {
+			continue
+		}
+		start, end := match[2*p.ValueGroup], match[2*p.ValueGroup+1]
+		value := strings.TrimSpace(line[start:end])
+		if strings.Contains(value, "*") {
+			return line // Already masked
+		}
+		return line[:start] + strings.Replace(line[start:end], value, maskValue(value), 1) + line[end:]
+	}
+	return line
+}
+
+// maskValue masks a value the same way scan results do
+func maskValue(value string) string {
+	if len(value) <= 4 {
+		return "****"
+	}
+	maskLen := min(len(value)-4, 16)
+	return value[:2] + strings.Repeat("*", maskLen) + value[len(value)-2:]
+}
+
+// shortDate keeps the date part of an RFC 3339 timestamp
+func shortDate(date string) string {
+	if len(date) >= 10 {
+		return date[:10]
+	}
+	return date
+}
+
+// keyReader reads single keystrokes from a terminal, or lines when stdin is redirected
+type keyReader struct {
+	raw    bool
+	reader *bufio.Reader
+}
+
+func newKeyReader() *keyReader {
+	return &keyReader{raw: term.IsTerminal(os.Stdin.Fd()), reader: bufio.NewReader(os.Stdin)}
+}
+
+// read returns the next key, lowercased
+func (k *keyReader) read() (byte, error) {
+	if k.raw {
+		state, err := term.MakeRaw(os.Stdin.Fd())
+		if err == nil {
+			defer term.Restore(os.Stdin.Fd(), state)
+			buf := make([]byte, 1)
+			if _, err := os.Stdin.Read(buf); err != nil {
+				return 0, err
+			}
+			// Ctrl+C and Ctrl+D quit like q
+			if buf[0] == 3 || buf[0] == 4 {
+				return 'q', nil
+			}
+			fmt.Printf("%c", buf[0])
+			return toLowerByte(buf[0]), nil
+		}
+	}
+
+	line, err := k.reader.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		if err != nil {
+			return 0, err
+		}
+		return 's', nil
+	}
+	return toLowerByte(line[0]), nil
+}
+
+func toLowerByte(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 32
+	}
+	return c
+}
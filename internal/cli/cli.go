@@ -0,0 +1,189 @@
+// Package cli implements the non-interactive gitsecret subcommands.
+// Running gitsecret without arguments starts the TUI instead.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+	"github.com/Drilmo/git-secret-scanner/internal/plain"
+)
+
+const usage = `Usage: gitsecret [--require-signed-config] [--debug-bundle FILE.zip]
+                 [--script FILE] [--plain] [command]
+
+Without a command, the interactive TUI is started.
+
+Global options:
+  --require-signed-config
+        Refuse configuration files without a valid signature from the
+        trusted key (also GITSECRET_REQUIRE_SIGNED_CONFIG=1)
+  --debug-bundle FILE.zip
+        Record the git and tool commands run, their timings and error
+        output, and the tool versions into a zip to attach to bug reports
+        (values are never recorded)
+  --script FILE
+        Drive the TUI from a file of steps (key, type, sleep, expect),
+        one per line, for runbooks and demo recordings ("-": standard
+        input, keys then come from the terminal)
+  --plain
+        Plain output: no colors or text styles, ASCII instead of emoji,
+        symbols and box drawing, for pipes, dumb terminals and screen
+        readers (also NO_COLOR set, or TERM=dumb)
+
+Commands:
+  scan [--repo DIR] [--mode full|stream] [--source both|current|history]
+       [--branch REF | --range A..B | --diff-base BASE | --patch FILE]
+       [--output FILE] [--config FILE | --no-repo-config] [--profile NAME]
+       [--removed] [--submodules]
+       [--incremental] [--backend auto|git|go-git] [--summary-json]
+       [--max-file-size KB] [--large-files skip|head] [--force]
+        Scan a repository and write the results (default: a new file in
+        the workspace of the repository, see workspace). An existing
+        output file is only overwritten once confirmed, or with --force
+  watch [--repo DIR] [--branch REF] [--output FILE.jsonl] [--interval 30s]
+        [--config FILE | --no-repo-config] [--profile NAME] [--notify CMD]
+        Keep running and scan new commits and modified files as they appear
+  schedule FILE [--once]
+        Run recurring scans from a cron-style schedule file and report
+        the findings that are new since the previous run
+  analyze [RESULTS | --repo DIR] [--output FILE.csv|FILE.html] [--max N]
+          [--anonymize] [--show-values] [--config FILE]
+          [--locale iso|en|en-GB|fr|de] [--lang en|fr] [--baseline FILE]
+          [--all] [--force]
+        Analyze scan results into a report (values masked; --show-values
+        writes raw values after a typed confirmation, recorded in the
+        audit log). Without RESULTS, the latest scan in the workspace of
+        --repo (default: the current directory) is analyzed. Values
+        triaged as false positives or accepted risks are left out unless
+        --all. The report is in English or French (--lang, else
+        GITSECRET_LANG, settings.language, then the system locale)
+  anonymize RESULTS [--output FILE] [--salt SALT] [--force]
+        Write a copy of scan results without values and with stable
+        author pseudonyms, for sharing outside the organization
+  evidence [--repo DIR] [--branch REF] [--output FILE.tar.gz] [--config FILE]
+           [--case REF]
+        Collect a read-only evidence bundle (findings, repository metadata,
+        commit and tree hashes, tool version, config hash, SHA256 manifest)
+  evidence verify BUNDLE...
+        Check every file of an evidence bundle against its manifest
+  audit-findings RESULTS [--repo DIR] [--baseline FILE] [--all] [--show-values]
+        Review findings one by one and record decisions in the baseline
+        (y: real secret, r: rotated, n: false positive, a: accept risk, s: skip)
+  config export [--config FILE] [--bundle FILE.tar.gz] [--baseline FILE]
+        Print the effective configuration, or write a shareable bundle
+        (configuration + rule packs + baseline)
+  config import BUNDLE [--config FILE] [--repo DIR] [--force]
+        Verify and install a configuration bundle
+  config keygen [--out FILE]
+        Create a config signing key pair
+  config sign [--key FILE] CONFIG...
+        Write CONFIG.sig next to each configuration file
+  config verify CONFIG...
+        Check signatures against the trusted key (GITSECRET_CONFIG_PUBKEY)
+  config validate [CONFIG...]
+        Report invalid and ineffective rules (default: auto-detected config);
+        fails when one is invalid
+  rules test DIR [--config FILE] [--min-precision 0.95] [--min-recall 0.9]
+        Match the rules over sample files annotated with the findings
+        they should produce ("# expect: api_key", "# expect: none" on the
+        line before) and report precision and recall; fails below the
+        minimums (default 1: any mismatch)
+  patterns update [URL] [--sha256 HEX] [--force]
+        Download a versioned rule pack, check it against URL.sha256 (or
+        the given digest) and install it in ~/.config/git-secret-scanner/rules;
+        without URL, update the installed packs from their own URLs.
+        Configurations use the packs they list in rulePacks
+  workspace ls
+        List the workspaces: the directories under
+        ~/.local/share/git-secret-scanner/workspaces (or GITSECRET_WORKSPACES)
+        where each repository's scans and reports are kept
+  workspace clean [--repo DIR] [--keep N] [--max-age-days N] [--missing]
+                  [--all] [--config FILE]
+        Apply the retention (settings.workspace: 10 most recent scans by
+        default), remove the workspaces of deleted repositories (--missing)
+        or everything (--all)
+  help  Show this help
+`
+
+// Script is the file of steps driving the TUI (--script)
+var Script string
+
+// ParseGlobalFlags applies the options that come before the command and
+// returns the remaining arguments. They also apply to the TUI.
+func ParseGlobalFlags(args []string) []string {
+	all := args
+	for len(args) > 0 {
+		switch {
+		case args[0] == "--require-signed-config":
+			config.RequireSignedConfig()
+		case args[0] == "--debug-bundle" && len(args) > 1:
+			debugbundle.Start(args[1], Version, all)
+			args = args[1:]
+		case strings.HasPrefix(args[0], "--debug-bundle="):
+			debugbundle.Start(strings.TrimPrefix(args[0], "--debug-bundle="), Version, all)
+		case args[0] == "--script" && len(args) > 1:
+			Script = args[1]
+			args = args[1:]
+		case strings.HasPrefix(args[0], "--script="):
+			Script = strings.TrimPrefix(args[0], "--script=")
+		case args[0] == "--plain":
+			plain.Enable()
+		default:
+			return args
+		}
+		args = args[1:]
+	}
+	return args
+}
+
+// CheckPolicy fails early when the configuration that would be used breaks the signature policy
+func CheckPolicy() error {
+	if !config.SignedConfigRequired() {
+		return nil
+	}
+	_, err := config.LoadAuto()
+	return err
+}
+
+// Run executes the subcommand in args (os.Args without the program name)
+func Run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return nil
+	}
+
+	switch args[0] {
+	case "scan":
+		return runScan(args[1:])
+	case "watch":
+		return runWatch(args[1:])
+	case "schedule":
+		return runSchedule(args[1:])
+	case "analyze":
+		return runAnalyze(args[1:])
+	case "anonymize":
+		return runAnonymize(args[1:])
+	case "evidence":
+		return runEvidence(args[1:])
+	case "audit-findings":
+		return runAuditFindings(args[1:])
+	case "config":
+		return runConfig(args[1:])
+	case "rules":
+		return runRules(args[1:])
+	case "patterns":
+		return runPatterns(args[1:])
+	case "workspace":
+		return runWorkspace(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("config: expected a subcommand (export, import, keygen, sign, verify, validate)")
+	}
+
+	switch args[0] {
+	case "export":
+		return runConfigExport(args[1:])
+	case "import":
+		return runConfigImport(args[1:])
+	case "keygen":
+		return runConfigKeygen(args[1:])
+	case "sign":
+		return runConfigSign(args[1:])
+	case "verify":
+		return runConfigVerify(args[1:])
+	case "validate":
+		return runConfigValidate(args[1:])
+	default:
+		return fmt.Errorf("config: unknown subcommand: %s", args[0])
+	}
+}
+
+// loadConfig loads the given file, or auto-detects the configuration when empty
+func loadConfig(path string) (*config.Config, error) {
+	if path == "" {
+		return config.LoadAuto()
+	}
+	return config.Load(path)
+}
+
+// loadRepoConfig loads the configuration of a scan of repoPath: path when
+// set, else the repository's own file (config.RepoConfigPath) unless noRepo,
+// else the auto-detected one. It returns the repository file used, if any.
+func loadRepoConfig(path, repoPath string, noRepo bool) (*config.Config, string, error) {
+	if path == "" && !noRepo {
+		if repoFile := config.RepoConfigPath(repoPath); repoFile != "" {
+			cfg, err := config.Load(repoFile)
+			return cfg, repoFile, err
+		}
+	}
+	cfg, err := loadConfig(path)
+	return cfg, "", err
+}
+
+func runConfigExport(args []string) error {
+	fs := flag.NewFlagSet("config export", flag.ContinueOnError)
+	configPath := fs.String("config", "", "configuration file (default: auto-detect)")
+	bundlePath := fs.String("bundle", "", "write a bundle archive to this file")
+	baselinePath := fs.String("baseline", config.BaselineFile, "baseline file to include in the bundle")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if *bundlePath == "" {
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	path := *configPath
+	if path == "" {
+		path = config.AutoPath()
+	}
+	manifest, err := config.ExportBundle(*bundlePath, config.BundleSources{
+		Config:       cfg,
+		ConfigPath:   path,
+		RulePacksDir: config.RulePacksDir(),
+		BaselinePath: *baselinePath,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("Bundle written to %s (%d files)\n", *bundlePath, len(manifest.Files))
+	return nil
+}
+
+func runConfigImport(args []string) error {
+	fs := flag.NewFlagSet("config import", flag.ContinueOnError)
+	configPath := fs.String("config", config.UserConfigPath(), "where to install the configuration")
+	repoPath := fs.String("repo", "", "repository receiving the baseline (default: baseline is not installed)")
+	force := fs.Bool("force", false, "overwrite existing files")
+
+	// Accept the bundle path before or after the flags
+	var bundlePath string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		bundlePath, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if bundlePath == "" {
+		bundlePath = fs.Arg(0)
+	}
+	if bundlePath == "" {
+		return fmt.Errorf("config import: missing bundle file")
+	}
+
+	targets := config.BundleTargets{
+		ConfigPath:   *configPath,
+		RulePacksDir: config.RulePacksDir(),
+		Force:        *force,
+	}
+	if *repoPath != "" {
+		targets.BaselinePath = filepath.Join(*repoPath, config.BaselineFile)
+	}
+
+	manifest, installed, err := config.ImportBundle(bundlePath, targets)
+	if err != nil {
+		return fmt.Errorf("failed to import bundle: %w", err)
+	}
+
+	fmt.Printf("Imported bundle created %s\n", manifest.Created.Format("2006-01-02 15:04"))
+	for _, path := range installed {
+		fmt.Fprintf(os.Stdout, "  %s\n", path)
+	}
+	return nil
+}
+
+func runConfigKeygen(args []string) error {
+	fs := flag.NewFlagSet("config keygen", flag.ContinueOnError)
+	keyPath := fs.String("out", "config-signing.key", "where to write the private key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(*keyPath); err == nil {
+		return fmt.Errorf("%s already exists", *keyPath)
+	}
+
+	publicKey, privateKey, err := config.GenerateSigningKey()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*keyPath, []byte(privateKey+"\n"), 0600); err != nil {
+		return err
+	}
+
+	fmt.Printf("Private key written to %s (keep it with the security team)\n", *keyPath)
+	fmt.Printf("Public key: %s\n", publicKey)
+	return nil
+}
+
+func runConfigSign(args []string) error {
+	fs := flag.NewFlagSet("config sign", flag.ContinueOnError)
+	keyPath := fs.String("key", "config-signing.key", "private key file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("config sign: missing configuration file")
+	}
+
+	key, err := os.ReadFile(*keyPath)
+	if err != nil {
+		return err
+	}
+	for _, path := range fs.Args() {
+		if err := config.SignFile(path, string(key)); err != nil {
+			return fmt.Errorf("failed to sign %s: %w", path, err)
+		}
+		fmt.Printf("Signed %s -> %s%s\n", path, path, config.SignatureExt)
+	}
+	return nil
+}
+
+func runConfigVerify(args []string) error {
+	fs := flag.NewFlagSet("config verify", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("config verify: missing configuration file")
+	}
+
+	for _, path := range fs.Args() {
+		if err := config.VerifyFile(path); err != nil {
+			return err
+		}
+		fmt.Printf("%s: signature OK\n", path)
+	}
+	return nil
+}
+
+func runConfigValidate(args []string) error {
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{config.AutoPath()}
+	}
+
+	invalid := 0
+	for _, path := range paths {
+		name := path
+		if name == "" {
+			name = "built-in defaults"
+		}
+		issues, err := config.ValidateFile(path)
+		if err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			fmt.Printf("%s: OK\n", name)
+			continue
+		}
+		plural := "s"
+		if len(issues) == 1 {
+			plural = ""
+		}
+		fmt.Printf("%s: %d problem%s\n", name, len(issues), plural)
+		for _, issue := range issues {
+			fmt.Printf("  %-7s  %s: %s\n", issue.Level, issue.Field, issue.Message)
+			if issue.Level == config.IssueError {
+				invalid++
+			}
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("config validate: %d invalid rules", invalid)
+	}
+	return nil
+}
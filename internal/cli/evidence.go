@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/evidence"
+)
+
+// Version is the tool version recorded in evidence bundles (set by main)
+var Version = "dev"
+
+func runEvidence(args []string) error {
+	if len(args) > 0 && args[0] == "verify" {
+		return runEvidenceVerify(args[1:])
+	}
+
+	fs := flag.NewFlagSet("evidence", flag.ContinueOnError)
+	repoPath := fs.String("repo", ".", "repository to collect")
+	branch := fs.String("branch", config.EnvDefault(config.BranchEnv, "--all"), "branch to scan (for git history; also "+config.BranchEnv+")")
+	outputPath := fs.String("output", "evidence.tar.gz", "bundle file (outside the repository, never overwritten)")
+	configPath := fs.String("config", "", "configuration file (default: auto-detect)")
+	caseRef := fs.String("case", "", "incident or case reference recorded in the bundle")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	result, err := evidence.Collect(*outputPath, evidence.Options{
+		Repo:        *repoPath,
+		Branch:      *branch,
+		Config:      cfg,
+		ToolVersion: Version,
+		Case:        *caseRef,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Evidence bundle written to %s (%d findings, %d commits)\n", result.Output, result.Findings, result.Commits)
+	fmt.Printf("Bundle SHA256:   %s\n", result.SHA256)
+	fmt.Printf("Manifest SHA256: %s\n", result.ManifestSHA256)
+	fmt.Println("Record both checksums in the chain-of-custody log.")
+	return nil
+}
+
+func runEvidenceVerify(args []string) error {
+	fs := flag.NewFlagSet("evidence verify", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("evidence verify: missing bundle file")
+	}
+
+	for _, path := range fs.Args() {
+		sum, err := evidence.Verify(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fmt.Printf("%s: all files match the manifest (manifest SHA256 %s)\n", path, sum)
+	}
+	return nil
+}
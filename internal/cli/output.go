@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+
+	"github.com/Drilmo/git-secret-scanner/internal/workspace"
+)
+
+// checkOutput returns the file a command writes to path: path itself when
+// it does not exist yet or force is set, else what the user chooses
+// (overwrite, or a timestamped name beside it; cancelling is an error).
+// Without a terminal to ask, the file is overwritten with a warning.
+func checkOutput(command, path string, force bool) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil || force {
+		return path, nil
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s: %s is a directory", command, path)
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		fmt.Fprintf(os.Stderr, "WARNING: overwriting %s (--force to skip this warning)\n", path)
+		return path, nil
+	}
+
+	stamped := workspace.Stamped(path, time.Now())
+	keys := newKeyReader()
+	for {
+		fmt.Fprintf(os.Stderr, "%s already exists: [o] overwrite  [t] write %s  [c] cancel > ", path, stamped)
+		key, err := keys.read()
+		fmt.Fprintln(os.Stderr)
+		switch {
+		case err != nil || key == 'c' || key == 'q':
+			return "", fmt.Errorf("%s: %s exists, nothing written", command, path)
+		case key == 'o':
+			return path, nil
+		case key == 't':
+			return stamped, nil
+		}
+	}
+}
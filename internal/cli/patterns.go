@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+func runPatterns(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("patterns: expected a subcommand (update)")
+	}
+
+	switch args[0] {
+	case "update":
+		return runPatternsUpdate(args[1:])
+	default:
+		return fmt.Errorf("patterns: unknown subcommand: %s", args[0])
+	}
+}
+
+func runPatternsUpdate(args []string) error {
+	fs := flag.NewFlagSet("patterns update", flag.ContinueOnError)
+	pin := fs.String("sha256", "", "expected sha256 of the pack (default: read from URL.sha256)")
+	force := fs.Bool("force", false, "install even when the pack is older than the installed one")
+
+	// Accept the URL before or after the flags
+	var url string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		url, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if url == "" {
+		url = fs.Arg(0)
+	}
+
+	urls := []string{url}
+	if url == "" {
+		if *pin != "" {
+			return fmt.Errorf("patterns update: --sha256 needs the URL of the pack")
+		}
+		var err error
+		if urls, err = installedPackURLs(); err != nil {
+			return err
+		}
+		if len(urls) == 0 {
+			return fmt.Errorf("patterns update: no installed pack has an update URL, give the release URL of a pack")
+		}
+	}
+
+	for _, url := range urls {
+		if err := updatePack(url, *pin, *force); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installedPackURLs lists the update URLs of the installed rule packs
+func installedPackURLs() ([]string, error) {
+	files, _ := filepath.Glob(filepath.Join(config.RulePacksDir(), "*.json"))
+	var urls []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		// Other rule files (config import) are no packs: skipped
+		if pack, err := config.ParseRulePack(data); err == nil && pack.URL != "" && !slices.Contains(urls, pack.URL) {
+			urls = append(urls, pack.URL)
+		}
+	}
+	return urls, nil
+}
+
+// updatePack fetches a rule pack and installs it unless the installed
+// version is the same or newer
+func updatePack(url, pin string, force bool) error {
+	pack, data, sig, err := config.FetchRulePack(url, pin)
+	if err != nil {
+		return fmt.Errorf("patterns update: %w", err)
+	}
+	installed, err := config.InstalledRulePack(pack.Name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: installed %s pack unreadable, replacing it: %v\n", pack.Name, err)
+	}
+	previous := "not installed"
+	if installed != nil {
+		previous = installed.Version
+		switch order := config.CompareVersions(pack.Version, installed.Version); {
+		case order == 0 && !force:
+			fmt.Printf("%s %s is up to date\n", pack.Name, pack.Version)
+			return nil
+		case order < 0 && !force:
+			return fmt.Errorf("patterns update: %s %s is older than the installed %s (--force to install it)",
+				pack.Name, pack.Version, installed.Version)
+		}
+	}
+
+	if err := config.InstallRulePack(pack, data, sig); err != nil {
+		return fmt.Errorf("patterns update: %w", err)
+	}
+	fmt.Printf("%s %s installed to %s (was %s)\n", pack.Name, pack.Version, config.RulePackPath(pack.Name), previous)
+
+	if cfg, err := config.LoadAuto(); err == nil && !slices.Contains(cfg.RulePacks, pack.Name) {
+		fmt.Printf("Scans use it once a configuration lists it: rulePacks: [%s]\n", pack.Name)
+	}
+	return nil
+}
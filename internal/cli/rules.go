@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Drilmo/git-secret-scanner/internal/ruletest"
+)
+
+func runRules(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("rules: expected a subcommand (test)")
+	}
+
+	switch args[0] {
+	case "test":
+		return runRulesTest(args[1:])
+	default:
+		return fmt.Errorf("rules: unknown subcommand: %s", args[0])
+	}
+}
+
+func runRulesTest(args []string) error {
+	fs := flag.NewFlagSet("rules test", flag.ContinueOnError)
+	configPath := fs.String("config", "", "configuration file (default: auto-detect)")
+	minPrecision := fs.Float64("min-precision", 1, "fail below this precision (0-1)")
+	minRecall := fs.Float64("min-recall", 1, "fail below this recall (0-1)")
+
+	// Accept the corpus directory before or after the flags
+	var dir string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		dir, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if dir == "" {
+		dir = fs.Arg(0)
+	}
+	if dir == "" {
+		return fmt.Errorf("rules test: missing sample directory")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	report, err := ruletest.Run(cfg, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range report.Missed {
+		fmt.Printf("%s:%d: missed %s\n", m.File, m.Line, m.Type)
+	}
+	for _, m := range report.Unexpected {
+		fmt.Printf("%s:%d: unexpected %s (key %s)\n", m.File, m.Line, m.Type, m.Key)
+	}
+	for _, file := range report.Ignored {
+		fmt.Printf("%s: ignored by the configuration, its expected findings are missed\n", file)
+	}
+
+	fmt.Printf("%d files: %d expected findings found, %d missed, %d unexpected\n",
+		report.Files, report.TruePositives, report.FalseNegatives, report.FalsePositives)
+	fmt.Printf("Precision: %.1f%%  Recall: %.1f%%\n", 100*report.Precision(), 100*report.Recall())
+
+	if report.Precision() < *minPrecision || report.Recall() < *minRecall {
+		return fmt.Errorf("rules test: below the required precision (%.2f) or recall (%.2f)", *minPrecision, *minRecall)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+	"github.com/Drilmo/git-secret-scanner/internal/workspace"
+)
+
+func runScan(args []string) (err error) {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	repoPath := fs.String("repo", ".", "repository to scan")
+	mode := fs.String("mode", "full", "full (aggregated JSON) or stream (JSONL)")
+	source := fs.String("source", "both", "both, current or history")
+	branch := fs.String("branch", config.EnvDefault(config.BranchEnv, "--all"), "branch to scan (for git history): a ref, --all, --remotes[=PATTERN] or a glob such as origin/feature/* (also "+config.BranchEnv+")")
+	revRange := fs.String("range", "", "only scan commits in a revision range (e.g. v1.0..HEAD)")
+	patchPath := fs.String("patch", "", "scan the added lines of a unified diff file instead of a repository (- for stdin)")
+	diffBase := fs.String("diff-base", "", "only scan lines HEAD adds since it diverged from a base branch (git diff BASE...HEAD)")
+	outputPath := fs.String("output", config.EnvDefault(config.OutputEnv, ""), "output file (also "+config.OutputEnv+"; default: a new file in the workspace of the repository)")
+	force := fs.Bool("force", false, "overwrite an existing output file without asking")
+	configPath := fs.String("config", os.Getenv(config.ConfigEnv), "configuration file (also "+config.ConfigEnv+"; default: the repository's .gitsecretscanner.json, else auto-detect)")
+	noRepoConfig := fs.Bool("no-repo-config", false, "ignore the configuration file of the scanned repository")
+	profile := fs.String("profile", "", "profile of the configuration to apply (its profiles section)")
+	removed := fs.Bool("removed", false, "also match deleted lines (flagged removed-in-history)")
+	backend := fs.String("backend", "auto", "history backend: auto, git or go-git (built in, no git binary needed)")
+	submodules := fs.Bool("submodules", false, "also scan the history of initialized submodules")
+	context := fs.Int("context", scanner.DefaultContextLines, "lines of code kept before and after each finding (0: line number only)")
+	incremental := fs.Bool("incremental", false, "only scan commits added since the last scan to the output file")
+	summaryJSON := fs.Bool("summary-json", false, "print a single-line JSON summary to stdout (other messages go to stderr)")
+	maxFileSize := fs.Int("max-file-size", 0, "size in KB above which working-tree files are skipped or cut (default: settings.maxFileSizeKB, 1024)")
+	largeFiles := fs.String("large-files", "", "oversized files: skip, or head to scan their first settings.largeFileHeadKB (default: settings.largeFiles)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	done := debugbundle.Step(fmt.Sprintf("scan (mode %s, source %s, backend %s)", *mode, *source, *backend))
+	var lastProgress map[string]scanner.Progress
+	start := time.Now()
+	defer func() {
+		// Scripts read the summary line whatever happened: a failed scan
+		// gives one with its error, and the exit status is not zero
+		if *summaryJSON && err != nil {
+			summary := scanner.FailedSummary(*outputPath, *mode, err)
+			summary.Finish(lastProgress, time.Since(start))
+			fmt.Fprintln(os.Stdout, summary.JSON())
+		}
+		// Where each phase stopped tells a silent failure from an empty repository
+		for _, phase := range []string{"current", "history"} {
+			if p, ok := lastProgress[phase]; ok {
+				debugbundle.Notef("%s: %d/%d processed, %d commits, %d found, %d long lines skipped",
+					phase, p.Current, p.Total, p.Commits, p.Found, p.Skipped)
+			}
+		}
+		done(err)
+	}()
+
+	switch *source {
+	case "both", "current", "history":
+	default:
+		return fmt.Errorf("scan: invalid source: %s", *source)
+	}
+
+	switch *backend {
+	case "auto":
+		*backend = scanner.BackendAuto
+	case scanner.BackendGit, scanner.BackendGoGit:
+	default:
+		return fmt.Errorf("scan: invalid backend: %s", *backend)
+	}
+
+	switch *largeFiles {
+	case "", config.LargeFilesSkip, config.LargeFilesHead:
+	default:
+		return fmt.Errorf("scan: invalid large-files: %s", *largeFiles)
+	}
+
+	// A patch has no repository to take the configuration from
+	cfg, repoConfig, err := loadRepoConfig(*configPath, *repoPath, *noRepoConfig || *patchPath != "")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if repoConfig != "" {
+		*configPath = repoConfig
+	}
+	// Per-run overrides of the settings
+	if err := cfg.OverrideFileSizes(*maxFileSize, *largeFiles); err != nil {
+		return fmt.Errorf("scan: %w", err)
+	}
+	s := scanner.New(cfg)
+
+	// Without an output file, the results go to the repository's workspace,
+	// whose old scans are then pruned
+	var ws *workspace.Workspace
+	if *outputPath == "" {
+		if ws, err = workspace.Open(*repoPath); err != nil {
+			return err
+		}
+		if *incremental {
+			*outputPath, err = ws.IncrementalScan()
+		} else {
+			*outputPath, err = ws.NewScan(time.Now())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Keep the last report of each phase for the summary
+	lastProgress = make(map[string]scanner.Progress)
+	opts := scanner.ScanOptions{
+		Branch:     *branch,
+		ConfigPath: *configPath,
+		Profile:    *profile,
+		Range:      *revRange,
+		Submodules: *submodules,
+		Removed:    *removed,
+		Backend:    *backend,
+		Context:    max(*context, 0),
+		OnProgress: func(p scanner.Progress) { lastProgress[p.Phase] = p },
+	}
+
+	// With --summary-json, stdout only carries the summary line
+	messages := os.Stdout
+	if *summaryJSON {
+		messages = os.Stderr
+	}
+	if repoConfig != "" {
+		fmt.Fprintf(messages, "Using the repository configuration %s\n", repoConfig)
+	}
+	var summary *scanner.Summary
+	withCurrent := *source == "both"
+	if *incremental && *source == "current" {
+		return fmt.Errorf("scan: --incremental needs git history (source both or history)")
+	}
+	if *diffBase != "" && (*incremental || *revRange != "") {
+		return fmt.Errorf("scan: --diff-base cannot be combined with --incremental or --range")
+	}
+	if *submodules && (*incremental || *diffBase != "" || *patchPath != "") {
+		return fmt.Errorf("scan: --submodules cannot be combined with --incremental, --diff-base or --patch")
+	}
+	if *backend == scanner.BackendGoGit && (*incremental || *diffBase != "" || *submodules) {
+		return fmt.Errorf("scan: --incremental, --diff-base and --submodules need the git backend")
+	}
+	if *patchPath != "" && (*incremental || *revRange != "" || *diffBase != "") {
+		return fmt.Errorf("scan: --patch cannot be combined with --incremental, --range or --diff-base")
+	}
+
+	// Workspace scans are new files, incremental scans update theirs
+	output := func(path string) (string, error) {
+		if ws != nil || *incremental {
+			return path, nil
+		}
+		return checkOutput("scan", path, *force)
+	}
+
+	switch *mode {
+	case "stream":
+		path, err := output(withExtension(*outputPath, ".jsonl"))
+		if err != nil {
+			return err
+		}
+		var count int
+		switch {
+		case *patchPath != "":
+			count, err = s.ScanPatchStream(*patchPath, path, opts)
+		case *diffBase != "":
+			count, err = s.ScanDiffStream(*repoPath, *diffBase, path, opts)
+		case *incremental:
+			count, err = s.ScanStreamIncremental(*repoPath, path, withCurrent, opts)
+		case *source == "current":
+			count, err = s.ScanCurrentStream(*repoPath, path, opts)
+		case *source == "history":
+			count, err = s.ScanStream(*repoPath, path, opts)
+		default:
+			count, err = s.ScanBothStream(*repoPath, path, opts)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(messages, "%d entries written to %s\n", count, path)
+		if summary, err = scanner.SummarizeStream(path, cfg); err != nil {
+			return err
+		}
+
+	case "full":
+		path, err := output(withExtension(*outputPath, ".json"))
+		if err != nil {
+			return err
+		}
+		var result *scanner.ScanResult
+		switch {
+		case *patchPath != "":
+			result, err = s.ScanPatch(*patchPath, opts)
+		case *diffBase != "":
+			result, err = s.ScanDiff(*repoPath, *diffBase, opts)
+		case *incremental:
+			result, err = s.ScanIncremental(*repoPath, path, withCurrent, opts)
+		case *source == "current":
+			result, err = s.ScanCurrent(*repoPath, opts)
+		case *source == "history":
+			result, err = s.Scan(*repoPath, opts)
+		default:
+			result, err = s.ScanBoth(*repoPath, opts)
+		}
+		if err != nil {
+			return err
+		}
+		if !*incremental {
+			if err := scanner.SaveResult(result, path); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(messages, "%d secrets (%d values) written to %s\n", result.SecretsFound, result.TotalValues, path)
+		summary = scanner.Summarize(result, cfg, path)
+
+	default:
+		return fmt.Errorf("scan: invalid mode: %s", *mode)
+	}
+
+	fmt.Fprintf(messages, "Processed %s\n", scanner.StatsFrom(lastProgress))
+	if ws != nil {
+		if _, err := ws.Prune(workspace.RetentionOf(cfg.Settings.Workspace), time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: workspace retention: %v\n", err)
+		}
+	}
+
+	if summary.Reintroduced > 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: %d cleaned secret value(s) reintroduced (status %q in %s): rotate them again\n",
+			summary.Reintroduced, scanner.StatusReintroduced, summary.Output)
+	}
+
+	if *summaryJSON {
+		summary.Finish(lastProgress, time.Since(start))
+		fmt.Fprintln(os.Stdout, summary.JSON())
+	}
+	return nil
+}
+
+// withExtension replaces a .json/.jsonl extension or appends ext
+func withExtension(path, ext string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(path, ".jsonl"), ".json")
+	if base == path && !strings.HasSuffix(path, ext) {
+		return path + ext
+	}
+	return base + ext
+}
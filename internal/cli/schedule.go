@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+	"github.com/Drilmo/git-secret-scanner/internal/schedule"
+)
+
+func runSchedule(args []string) error {
+	fs := flag.NewFlagSet("schedule", flag.ContinueOnError)
+	once := fs.Bool("once", false, "run every job now and exit")
+	var path string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		path, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if path == "" {
+		path = fs.Arg(0)
+	}
+	if path == "" {
+		return fmt.Errorf("schedule: missing schedule file")
+	}
+
+	cfg, err := schedule.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if *once {
+		failed := 0
+		for i := range cfg.Jobs {
+			if !runScheduledJob(cfg, &cfg.Jobs[i], time.Now()) {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d jobs failed", failed, len(cfg.Jobs))
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Info("Scheduler started", "jobs", len(cfg.Jobs), "output", cfg.OutputDir, "retention", cfg.Retention)
+	for {
+		jobs, at := cfg.Next(time.Now())
+		if at.IsZero() {
+			return fmt.Errorf("schedule: no upcoming run")
+		}
+		names := make([]string, len(jobs))
+		for i, job := range jobs {
+			names[i] = job.Name
+		}
+		log.Info("Next run", "at", at.Format("2006-01-02 15:04"), "jobs", names)
+
+		timer := time.NewTimer(time.Until(at))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Info("Scheduler stopped")
+			return nil
+		case <-timer.C:
+		}
+		for _, job := range jobs {
+			runScheduledJob(cfg, job, at)
+		}
+	}
+}
+
+// runScheduledJob runs one job and reports the findings that are new since
+// the previous run. Failures are logged so the scheduler keeps going.
+func runScheduledJob(cfg *schedule.Config, job *schedule.Job, now time.Time) bool {
+	log.Info("Scanning", "job", job.Name, "repo", job.Repo)
+	run, err := cfg.RunJob(job, now)
+	if err != nil {
+		log.Error("Scan failed", "job", job.Name, "err", err)
+		return false
+	}
+
+	if run.First {
+		log.Info("Scan complete (first run, nothing to compare)", "job", job.Name, "findings", run.Findings, "output", run.Output)
+		return true
+	}
+	log.Info("Scan complete", "job", job.Name, "findings", run.Findings, "new", len(run.New), "resolved", run.Resolved, "output", run.Output)
+
+	for _, entry := range run.New {
+		severity := scanner.EntrySeverity(run.Config, entry)
+		command := cfg.Notify
+		if entry.Status == scanner.StatusReintroduced {
+			log.Error("Cleaned secret reintroduced", "job", job.Name, "severity", severity, "file", entry.File, "key", entry.Key,
+				"value", entry.MaskedValue, "commit", shortHash(entry.Commit), "author", entry.Author)
+			if cfg.NotifyReintroduced != "" {
+				command = cfg.NotifyReintroduced
+			}
+		} else {
+			log.Warn("New secret", "job", job.Name, "severity", severity, "file", entry.File, "key", entry.Key,
+				"value", entry.MaskedValue, "commit", shortHash(entry.Commit), "author", entry.Author)
+		}
+		if command != "" {
+			if err := runNotify(command, entry, severity); err != nil {
+				log.Error("Notification failed", "err", err)
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	repoPath := fs.String("repo", ".", "repository to watch")
+	branch := fs.String("branch", config.EnvDefault(config.BranchEnv, "--all"), "branches to watch (also "+config.BranchEnv+")")
+	outputPath := fs.String("output", "secrets-watch.jsonl", "JSONL file receiving new findings")
+	configPath := fs.String("config", os.Getenv(config.ConfigEnv), "configuration file (also "+config.ConfigEnv+"; default: the repository's .gitsecretscanner.json, else auto-detect)")
+	noRepoConfig := fs.Bool("no-repo-config", false, "ignore the configuration file of the watched repository")
+	profile := fs.String("profile", "", "profile of the configuration to apply (its profiles section)")
+	interval := fs.Duration("interval", 30*time.Second, "polling interval")
+	notify := fs.String("notify", "", "shell command run for each new finding (details in GITSECRET_* variables)")
+	notifyReintroduced := fs.String("notify-reintroduced", "", "shell command run instead of --notify for secrets reintroduced after a cleanup")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, repoConfig, err := loadRepoConfig(*configPath, *repoPath, *noRepoConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if repoConfig != "" {
+		*configPath = repoConfig
+		log.Info("Using the repository configuration", "config", repoConfig)
+	}
+	s := scanner.New(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	path := withExtension(*outputPath, ".jsonl")
+	log.Info("Watching repository", "repo", *repoPath, "branch", *branch, "output", path, "interval", *interval)
+
+	opts := scanner.WatchOptions{
+		ScanOptions: scanner.ScanOptions{Branch: *branch, ConfigPath: *configPath, Profile: *profile},
+		Interval:    *interval,
+	}
+	opts.OnFinding = func(entry scanner.StreamEntry) {
+		severity := scanner.EntrySeverity(cfg, entry)
+		command := *notify
+		if entry.Status == scanner.StatusReintroduced {
+			log.Error("Cleaned secret reintroduced", "severity", severity, "file", entry.File, "key", entry.Key,
+				"value", entry.MaskedValue, "commit", shortHash(entry.Commit), "author", entry.Author)
+			if *notifyReintroduced != "" {
+				command = *notifyReintroduced
+			}
+		} else {
+			log.Warn("Secret found", "severity", severity, "file", entry.File, "key", entry.Key,
+				"value", entry.MaskedValue, "commit", shortHash(entry.Commit), "author", entry.Author)
+		}
+		if command != "" {
+			if err := runNotify(command, entry, severity); err != nil {
+				log.Error("Notification failed", "err", err)
+			}
+		}
+	}
+
+	if err := s.Watch(ctx, *repoPath, path, opts); err != nil {
+		return err
+	}
+	log.Info("Watch stopped")
+	return nil
+}
+
+// runNotify runs the notification command for a finding. The raw value is
+// never passed on: the command receives the masked entry as JSON on stdin.
+func runNotify(command string, entry scanner.StreamEntry, severity string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	entry.Value = ""
+	data, _ := json.Marshal(entry)
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"GITSECRET_FILE="+entry.File,
+		"GITSECRET_KEY="+entry.Key,
+		"GITSECRET_TYPE="+entry.Type,
+		"GITSECRET_SEVERITY="+severity,
+		"GITSECRET_STATUS="+entry.Status,
+		"GITSECRET_MASKED_VALUE="+entry.MaskedValue,
+		"GITSECRET_COMMIT="+entry.Commit,
+		"GITSECRET_AUTHOR="+entry.Author,
+	)
+	return cmd.Run()
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+	"github.com/Drilmo/git-secret-scanner/internal/workspace"
+)
+
+func runWorkspace(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("workspace: expected a subcommand (ls, clean)")
+	}
+
+	switch args[0] {
+	case "ls":
+		return runWorkspaceList(args[1:])
+	case "clean":
+		return runWorkspaceClean(args[1:])
+	default:
+		return fmt.Errorf("workspace: unknown subcommand: %s", args[0])
+	}
+}
+
+func runWorkspaceList(args []string) error {
+	fs := flag.NewFlagSet("workspace ls", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	all, err := workspace.List()
+	if err != nil {
+		return err
+	}
+	if len(all) == 0 {
+		fmt.Printf("No workspace in %s\n", workspace.Root())
+		return nil
+	}
+	for _, w := range all {
+		fmt.Println(w.Repo)
+		latest := "none"
+		if path := w.LatestScan(); path != "" {
+			if info, err := os.Stat(path); err == nil {
+				latest = filepath.Base(path) + ", " + info.ModTime().Format("2006-01-02 15:04")
+			}
+		}
+		fmt.Printf("  %d scans (latest: %s), %d reports, %s\n",
+			len(w.Scans()), latest, len(w.Reports()), model.FormatBytes(w.Size()))
+		fmt.Printf("  %s\n", w.Dir)
+		if w.Missing() {
+			fmt.Println("  repository missing: `gitsecret workspace clean --missing` removes the workspace")
+		}
+	}
+	return nil
+}
+
+func runWorkspaceClean(args []string) error {
+	fs := flag.NewFlagSet("workspace clean", flag.ContinueOnError)
+	repoPath := fs.String("repo", "", "only the workspace of this repository (default: every workspace)")
+	configPath := fs.String("config", "", "configuration giving the retention (default: each repository's, else auto-detect)")
+	keep := fs.Int("keep", 0, "most recent scans kept (default: settings.workspace.keepScans, 10)")
+	maxAge := fs.Int("max-age-days", 0, "remove scans and reports older than this (default: settings.workspace.maxAgeDays)")
+	missing := fs.Bool("missing", false, "also remove the workspaces of repositories that no longer exist")
+	all := fs.Bool("all", false, "remove the workspaces entirely")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *keep < 0 || *maxAge < 0 {
+		return fmt.Errorf("workspace clean: --keep and --max-age-days cannot be negative")
+	}
+
+	var targets []*workspace.Workspace
+	if *repoPath != "" {
+		w, err := workspace.Lookup(*repoPath)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("workspace clean: no workspace for %s", w.Repo)
+		}
+		if err != nil {
+			return err
+		}
+		targets = append(targets, w)
+	} else {
+		var err error
+		if targets, err = workspace.List(); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for _, w := range targets {
+		if *all || (*missing && w.Missing()) {
+			size := w.Size()
+			if err := w.Remove(); err != nil {
+				return err
+			}
+			fmt.Printf("%s: workspace removed (%s)\n", w.Repo, model.FormatBytes(size))
+			continue
+		}
+
+		cfg, _, err := loadRepoConfig(*configPath, w.Repo, false)
+		if err != nil {
+			return fmt.Errorf("%s: failed to load config: %w", w.Repo, err)
+		}
+		retention := workspace.RetentionOf(cfg.Settings.Workspace)
+		if *keep > 0 {
+			retention.Keep = *keep
+		}
+		if *maxAge > 0 {
+			retention.MaxAge = time.Duration(*maxAge) * 24 * time.Hour
+		}
+		removed, err := w.Prune(retention, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", w.Repo, err)
+		}
+		plural := "s"
+		if len(removed) == 1 {
+			plural = ""
+		}
+		fmt.Printf("%s: %d file%s removed\n", w.Repo, len(removed), plural)
+	}
+	return nil
+}
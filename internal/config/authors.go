@@ -0,0 +1,62 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Author identity modes (settings.authors)
+const (
+	AuthorsKeep = "keep" // Default: names as recorded by git
+	AuthorsHash = "hash" // Stable pseudonyms: per-author counts without names
+	AuthorsOmit = "omit" // A single placeholder: no per-author data at all
+)
+
+// OmittedAuthor replaces every author in AuthorsOmit mode
+const OmittedAuthor = "redacted"
+
+// AuthorSaltEnv names the variable holding the secret salt of pseudonyms
+const AuthorSaltEnv = "GITSECRET_ANON_SALT"
+
+// authorMarkers are author values that name a source, not a person
+var authorMarkers = map[string]bool{"": true, "current": true, "patch": true, "unknown": true, OmittedAuthor: true}
+
+// Pseudonym returns the stable pseudonym of an author ("author-1a2b3c4d").
+// The same name and salt always give the same pseudonym, so reports stay
+// comparable. Without a salt a pseudonym can be reversed by guessing names.
+func Pseudonym(author, salt string) string {
+	if authorMarkers[author] || strings.HasPrefix(author, "author-") {
+		return author
+	}
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(author))))
+	return "author-" + hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// AuthorIdentity returns what scans record for an author, following
+// settings.authors
+func (c *Config) AuthorIdentity(author string) string {
+	switch c.Settings.Authors {
+	case AuthorsHash:
+		return Pseudonym(author, os.Getenv(AuthorSaltEnv))
+	case AuthorsOmit:
+		if authorMarkers[author] {
+			return author
+		}
+		return OmittedAuthor
+	}
+	return author
+}
+
+// validateAuthors rejects unknown settings.authors values
+func (c *Config) validateAuthors() error {
+	switch c.Settings.Authors {
+	case "", AuthorsKeep, AuthorsHash, AuthorsOmit:
+		return nil
+	}
+	return fmt.Errorf("invalid settings.authors %q (keep, hash or omit)", c.Settings.Authors)
+}
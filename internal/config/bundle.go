@@ -0,0 +1,320 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BundleManifest describes the content of a configuration bundle
+type BundleManifest struct {
+	Version int               `json:"version"`
+	Created time.Time         `json:"created"`
+	Files   map[string]string `json:"files"` // Archive path -> SHA256
+}
+
+// Bundle archive layout. The configuration is patterns.json, or
+// patterns.yaml, .yml or .toml for a file exported in that format; a signed
+// file or rule pack is followed by its signature (name + SignatureExt).
+const (
+	bundleManifest   = "manifest.json"
+	bundleConfig     = "patterns.json"
+	bundleRulesDir   = "rules/"
+	bundleBaseline   = "baseline.json"
+	bundleFormatVers = 1
+)
+
+// BundleSources lists what goes into an exported bundle
+type BundleSources struct {
+	Config       *Config // Configuration to export (required)
+	ConfigPath   string  // File Config was loaded from, exported as it is with its signature (optional)
+	RulePacksDir string  // Directory of *.json rule packs (optional)
+	BaselinePath string  // Baseline file (optional)
+}
+
+// ExportBundle writes a gzipped tar archive with the configuration, rule packs and baseline.
+// A configuration file is exported as it is, so that its signature still
+// applies; the defaults and layered or remote configurations are written
+// merged, in JSON, which the signature policy refuses.
+func ExportBundle(outputPath string, src BundleSources) (*BundleManifest, error) {
+	files := map[string][]byte{}
+
+	if layers := Layers(src.ConfigPath); len(layers) == 1 && !IsRemote(layers[0]) {
+		name := "patterns." + ConfigFormat(src.ConfigPath)
+		if err := addBundleFile(files, name, src.ConfigPath); err != nil {
+			return nil, err
+		}
+	} else {
+		if SignedConfigRequired() {
+			return nil, fmt.Errorf("signed configuration required: only a single configuration file can be exported with its signature")
+		}
+		data, err := json.MarshalIndent(src.Config, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[bundleConfig] = data
+	}
+
+	if src.RulePacksDir != "" {
+		packs, _ := filepath.Glob(filepath.Join(src.RulePacksDir, "*.json"))
+		for _, pack := range packs {
+			if err := addBundleFile(files, bundleRulesDir+filepath.Base(pack), pack); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if src.BaselinePath != "" {
+		if data, err := os.ReadFile(src.BaselinePath); err == nil {
+			files[bundleBaseline] = data
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	manifest := &BundleManifest{
+		Version: bundleFormatVers,
+		Created: time.Now().UTC(),
+		Files:   make(map[string]string, len(files)),
+	}
+	for name, data := range files {
+		manifest.Files[name] = checksum(data)
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	writeFile := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: manifest.Created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := writeFile(bundleManifest, manifestData); err != nil {
+		return nil, err
+	}
+	for name, data := range files {
+		if err := writeFile(name, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// addBundleFile adds the file at path to a bundle under name, followed by
+// its signature when it has one
+func addBundleFile(files map[string][]byte, name, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	files[name] = data
+	if sig, err := os.ReadFile(path + SignatureExt); err == nil {
+		files[name+SignatureExt] = sig
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// bundleConfigName returns the archive name of the configuration of a
+// bundle ("" if it has none)
+func bundleConfigName(files map[string][]byte) string {
+	for _, name := range ConfigFileNames {
+		if _, ok := files[name]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// BundleTargets lists where the content of an imported bundle is installed
+type BundleTargets struct {
+	ConfigPath   string // Where to write the configuration (its extension follows the format of the bundle's)
+	RulePacksDir string // Where to install rule packs
+	BaselinePath string // Where to write the baseline (skipped if empty)
+	Force        bool   // Overwrite existing files
+}
+
+// ImportBundle verifies a bundle against its manifest and installs its files.
+// Under the signature policy the configuration and rule packs must carry a
+// signature of the trusted key.
+func ImportBundle(bundlePath string, dst BundleTargets) (*BundleManifest, []string, error) {
+	files, manifest, err := readBundle(bundlePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The configuration must be loadable before anything is written
+	configName := bundleConfigName(files)
+	data, err := configJSON(ConfigFormat(configName), files[configName])
+	if err == nil {
+		err = json.Unmarshal(data, DefaultConfig())
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("bundle configuration is invalid: %w", err)
+	}
+	if SignedConfigRequired() {
+		if err := verifyBundle(files, configName); err != nil {
+			return nil, nil, fmt.Errorf("signed configuration required: %w", err)
+		}
+	}
+
+	configPath := dst.ConfigPath
+	if format := ConfigFormat(configName); ConfigFormat(configPath) != format {
+		configPath = WithFormatExtension(configPath, format)
+	}
+	targets := map[string]string{configName: configPath}
+	if _, ok := files[configName+SignatureExt]; ok {
+		targets[configName+SignatureExt] = configPath + SignatureExt
+	}
+	for name := range files {
+		switch {
+		case strings.HasPrefix(name, bundleRulesDir) && dst.RulePacksDir != "":
+			targets[name] = filepath.Join(dst.RulePacksDir, path.Base(name))
+		case name == bundleBaseline && dst.BaselinePath != "":
+			targets[name] = dst.BaselinePath
+		}
+	}
+
+	if !dst.Force {
+		for _, target := range targets {
+			if _, err := os.Stat(target); err == nil {
+				return nil, nil, fmt.Errorf("%s already exists (use --force to overwrite)", target)
+			}
+		}
+	}
+
+	var installed []string
+	for name, target := range targets {
+		if dir := filepath.Dir(target); dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return nil, installed, err
+			}
+		}
+		if err := os.WriteFile(target, files[name], 0644); err != nil {
+			return nil, installed, err
+		}
+		installed = append(installed, target)
+	}
+
+	return manifest, installed, nil
+}
+
+// verifyBundle checks the signatures of the configuration and rule packs of
+// a bundle against the trusted key
+func verifyBundle(files map[string][]byte, configName string) error {
+	key, err := TrustedKey()
+	if err != nil {
+		return err
+	}
+	for name, data := range files {
+		if name != configName && !(strings.HasPrefix(name, bundleRulesDir) && strings.HasSuffix(name, ".json")) {
+			continue
+		}
+		sig, ok := files[name+SignatureExt]
+		if !ok {
+			return fmt.Errorf("%s: %w", name, ErrUnsignedConfig)
+		}
+		if err := verifySignature(name, data, sig, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBundle extracts all files of a bundle in memory and checks their checksums
+func readBundle(bundlePath string) (map[string][]byte, *BundleManifest, error) {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a configuration bundle: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("corrupted bundle: %w", err)
+		}
+		// Reject path traversal and anything that isn't a regular file
+		name := path.Clean(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || strings.HasPrefix(name, "..") || path.IsAbs(name) {
+			return nil, nil, fmt.Errorf("unexpected entry in bundle: %s", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, 64*1024*1024))
+		if err != nil {
+			return nil, nil, err
+		}
+		files[name] = data
+	}
+
+	var manifest BundleManifest
+	if err := json.Unmarshal(files[bundleManifest], &manifest); err != nil {
+		return nil, nil, fmt.Errorf("bundle manifest is missing or invalid")
+	}
+	delete(files, bundleManifest)
+
+	if bundleConfigName(files) == "" {
+		return nil, nil, fmt.Errorf("bundle has no %s", bundleConfig)
+	}
+	for name, data := range files {
+		expected, ok := manifest.Files[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s is not listed in the bundle manifest", name)
+		}
+		if checksum(data) != expected {
+			return nil, nil, fmt.Errorf("checksum mismatch for %s", name)
+		}
+	}
+	for name := range manifest.Files {
+		if _, ok := files[name]; !ok {
+			return nil, nil, fmt.Errorf("%s is missing from the bundle", name)
+		}
+	}
+
+	return files, &manifest, nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	rules := filepath.Join(src, "rules")
+	os.MkdirAll(rules, 0755)
+	os.WriteFile(filepath.Join(rules, "team.json"), []byte(`{"keywords":[]}`), 0644)
+	baseline := filepath.Join(src, BaselineFile)
+	os.WriteFile(baseline, []byte(`[]`), 0644)
+
+	cfg := DefaultConfig()
+	cfg.Settings.MinSecretLength = 12
+
+	bundle := filepath.Join(src, "bundle.tar.gz")
+	manifest, err := ExportBundle(bundle, BundleSources{Config: cfg, RulePacksDir: rules, BaselinePath: baseline})
+	if err != nil {
+		t.Fatalf("ExportBundle: %v", err)
+	}
+	if len(manifest.Files) != 3 {
+		t.Fatalf("manifest lists %d files, want 3", len(manifest.Files))
+	}
+
+	dst := t.TempDir()
+	targets := BundleTargets{
+		ConfigPath:   filepath.Join(dst, "patterns.json"),
+		RulePacksDir: filepath.Join(dst, "rules"),
+		BaselinePath: filepath.Join(dst, "repo", BaselineFile),
+	}
+	if _, installed, err := ImportBundle(bundle, targets); err != nil || len(installed) != 3 {
+		t.Fatalf("ImportBundle: installed %v, err %v", installed, err)
+	}
+
+	loaded, err := Load(targets.ConfigPath)
+	if err != nil || loaded.Settings.MinSecretLength != 12 {
+		t.Fatalf("imported config not loadable: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targets.RulePacksDir, "team.json")); err != nil {
+		t.Errorf("rule pack not installed: %v", err)
+	}
+
+	// A second import must not silently overwrite
+	if _, _, err := ImportBundle(bundle, targets); err == nil {
+		t.Error("expected an error when targets already exist")
+	}
+}
+
+func TestBundleRoundTripUnderSignaturePolicy(t *testing.T) {
+	pub, priv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITSECRET_REQUIRE_SIGNED_CONFIG", "1")
+	t.Setenv("GITSECRET_CONFIG_PUBKEY", pub)
+
+	src := t.TempDir()
+	path := filepath.Join(src, "patterns.yaml")
+	os.WriteFile(path, []byte("settings: {minSecretLength: 12}\n"), 0644)
+	rules := filepath.Join(src, "rules")
+	os.MkdirAll(rules, 0755)
+	pack := filepath.Join(rules, "team.json")
+	os.WriteFile(pack, []byte(`{"keywords":[]}`), 0644)
+	for _, file := range []string{path, pack} {
+		if err := SignFile(file, priv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := filepath.Join(src, "bundle.tar.gz")
+	manifest, err := ExportBundle(bundle, BundleSources{Config: cfg, ConfigPath: path, RulePacksDir: rules})
+	if err != nil {
+		t.Fatalf("ExportBundle: %v", err)
+	}
+	if len(manifest.Files) != 4 {
+		t.Fatalf("manifest lists %v, want the config, the pack and their signatures", manifest.Files)
+	}
+
+	// The file is installed as it was signed: its signature still applies
+	dst := t.TempDir()
+	targets := BundleTargets{ConfigPath: filepath.Join(dst, "patterns.json"), RulePacksDir: filepath.Join(dst, "rules")}
+	if _, installed, err := ImportBundle(bundle, targets); err != nil || len(installed) != 4 {
+		t.Fatalf("ImportBundle: installed %v, err %v", installed, err)
+	}
+	loaded, err := Load(filepath.Join(dst, "patterns.yaml"))
+	if err != nil || loaded.Settings.MinSecretLength != 12 {
+		t.Fatalf("imported config rejected: %v", err)
+	}
+	if err := VerifyFile(filepath.Join(targets.RulePacksDir, "team.json")); err != nil {
+		t.Errorf("imported rule pack: %v", err)
+	}
+
+	// An unsigned pack is refused before anything is written
+	os.Remove(pack + SignatureExt)
+	if _, err := ExportBundle(bundle, BundleSources{Config: cfg, ConfigPath: path, RulePacksDir: rules}); err != nil {
+		t.Fatal(err)
+	}
+	targets.ConfigPath = filepath.Join(t.TempDir(), "patterns.json")
+	if _, _, err := ImportBundle(bundle, targets); !errors.Is(err, ErrUnsignedConfig) {
+		t.Errorf("unsigned rule pack: err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(targets.ConfigPath), "patterns.yaml")); err == nil {
+		t.Error("the configuration of a refused bundle was installed")
+	}
+
+	// Merged settings have no signature
+	if _, err := ExportBundle(bundle, BundleSources{Config: cfg}); err == nil {
+		t.Error("exported an unsigned configuration under the policy")
+	}
+}
@@ -1,10 +1,11 @@
 package config
 
 import (
-	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -12,24 +13,98 @@ import (
 type Config struct {
 	ExtractionPatterns      []ExtractionPattern `json:"extractionPatterns"`
 	Keywords                []KeywordGroup      `json:"keywords"`
+	LanguagePacks           []string            `json:"languagePacks,omitempty"` // Built-in packs to add (fr, de, es)
+	RulePacks               []string            `json:"rulePacks,omitempty"`     // Installed rule packs merged in (see RulePack)
 	IgnoredValues           []string            `json:"ignoredValues"`
 	IgnoredFiles            []string            `json:"ignoredFiles"`
 	ExcludeBinaryExtensions []string            `json:"excludeBinaryExtensions"`
 	Settings                Settings            `json:"settings"`
+	Profiles                map[string]Profile  `json:"profiles,omitempty"` // Variants chosen per scan (see Profile)
+
+	ignore *IgnoreRules // .secretignore of the scanned repository
 }
 
 // KeywordGroup represents a group of search patterns
 type KeywordGroup struct {
-	Name        string   `json:"name"`
-	Patterns    []string `json:"patterns"`
-	Description string   `json:"description"`
+	Name         string   `json:"name"`
+	Patterns     []string `json:"patterns"`
+	Regex        bool     `json:"regex,omitempty"`        // Patterns are regexes rather than substrings
+	ValuePattern string   `json:"valuePattern,omitempty"` // Regex the whole value must match to count
+	Severity     string   `json:"severity,omitempty"`     // Severity of the findings (default: built-in by name, else low)
+	Description  string   `json:"description"`
 }
 
 // Settings holds scanner settings
 type Settings struct {
-	MinSecretLength int  `json:"minSecretLength"`
-	MaxSecretLength int  `json:"maxSecretLength"`
-	CaseSensitive   bool `json:"caseSensitive"`
+	MinSecretLength int               `json:"minSecretLength"`
+	MaxSecretLength int               `json:"maxSecretLength"`
+	CaseSensitive   bool              `json:"caseSensitive"`
+	EnvAllValues    bool              `json:"envAllValues,omitempty"`    // Every non-placeholder .env value is a candidate
+	StructuredFiles bool              `json:"structuredFiles,omitempty"` // Walk valid YAML/JSON/TOML/INI files by key path (working tree)
+	Authors         string            `json:"authors,omitempty"`         // AuthorsKeep (default), AuthorsHash or AuthorsOmit
+	Palette         string            `json:"palette,omitempty"`         // TUI colors: PaletteDefault or PaletteColorBlind
+	Theme           string            `json:"theme,omitempty"`           // TUI look: ThemeDark (default), ThemeLight or ThemeHighContrast
+	ThemeColors     ThemeColors       `json:"themeColors,omitempty"`     // Colors replacing those of the theme
+	Locale          string            `json:"locale,omitempty"`          // Numbers and dates of the CSV and HTML reports (ReportLocales)
+	Language        string            `json:"language,omitempty"`        // Text of the reports and the TUI (Languages; LanguageEnv first, default: the system's)
+	MaxFileSizeKB   int               `json:"maxFileSizeKB,omitempty"`   // Larger working-tree files are skipped or cut (DefaultMaxFileSizeKB)
+	LargeFiles      string            `json:"largeFiles,omitempty"`      // LargeFilesSkip (default) or LargeFilesHead
+	LargeFileHeadKB int               `json:"largeFileHeadKB,omitempty"` // Head of oversized files scanned (DefaultLargeFileHeadKB)
+	Network         NetworkSettings   `json:"network,omitempty"`
+	Workspace       WorkspaceSettings `json:"workspace,omitempty"`
+}
+
+// TUI color palettes (settings.palette)
+const (
+	PaletteDefault    = "default"
+	PaletteColorBlind = "colorblind" // Okabe-Ito colors, told apart with any color vision
+)
+
+// Report locales (settings.locale): CSV separator, decimal mark and date
+// layouts of the analysis exports
+const (
+	LocaleISO  = "iso" // Default: ; separated, 1.5, 2024-01-31
+	LocaleEN   = "en"  // , separated, 1.5, 01/31/2024
+	LocaleENGB = "en-GB"
+	LocaleFR   = "fr" // ; separated, 1,5, 31/01/2024
+	LocaleDE   = "de" // ; separated, 1,5, 31.01.2024
+)
+
+// ReportLocales lists the values of settings.locale
+var ReportLocales = []string{LocaleISO, LocaleEN, LocaleENGB, LocaleFR, LocaleDE}
+
+// validateLocale rejects unknown settings.locale values
+func (c *Config) validateLocale() error {
+	if c.Settings.Locale == "" || slices.Contains(ReportLocales, c.Settings.Locale) {
+		return nil
+	}
+	return fmt.Errorf("invalid settings.locale %q (%s)", c.Settings.Locale, strings.Join(ReportLocales, ", "))
+}
+
+// Languages of the reports and the TUI (settings.language)
+const (
+	LanguageEN = "en" // Default
+	LanguageFR = "fr"
+)
+
+// Languages lists the values of settings.language
+var Languages = []string{LanguageEN, LanguageFR}
+
+// validateLanguage rejects unknown settings.language values
+func (c *Config) validateLanguage() error {
+	if c.Settings.Language == "" || slices.Contains(Languages, c.Settings.Language) {
+		return nil
+	}
+	return fmt.Errorf("invalid settings.language %q (%s)", c.Settings.Language, strings.Join(Languages, ", "))
+}
+
+// NetworkSettings configures outbound HTTP(S) connections. Empty proxy
+// fields fall back to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables.
+type NetworkSettings struct {
+	HTTPProxy  string `json:"httpProxy,omitempty"`
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	NoProxy    string `json:"noProxy,omitempty"`  // Comma-separated hosts, domains (.corp) or CIDRs
+	CABundle   string `json:"caBundle,omitempty"` // PEM file trusted in addition to the system roots
 }
 
 // ExtractionPattern defines a regex pattern for extracting key-value pairs
@@ -47,25 +122,51 @@ type CompiledPattern struct {
 	ValueGroup int
 }
 
+// Severity levels, from most to least urgent
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+)
+
+// Severities lists all severity levels, most urgent first
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// defaultSeverities maps built-in keyword group names to their severity
+var defaultSeverities = map[string]string{
+	"private_key":       SeverityCritical,
+	"aws":               SeverityCritical,
+	"connection_string": SeverityCritical,
+	"database":          SeverityCritical,
+	"password":          SeverityHigh,
+	"secret":            SeverityHigh,
+	"api_key":           SeverityHigh,
+	"encryption":        SeverityHigh,
+	"token":             SeverityMedium,
+	"oauth":             SeverityMedium,
+	"credentials":       SeverityMedium,
+}
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
 		ExtractionPatterns: []ExtractionPattern{
 			{
 				Name:        "key_equals_value",
-				Pattern:     `^\s*([a-zA-Z_][\w.$/-]*)\s*=\s*(.+)$`,
+				Pattern:     `^\s*([\p{L}_][\p{L}\p{N}_.$/-]*)\s*=\s*(.+)$`,
 				ValueGroup:  2,
 				Description: "Standard key=value format",
 			},
 			{
 				Name:        "yaml_colon",
-				Pattern:     `^\s*([a-zA-Z_][\w._-]*)\s*:\s+['"]?([^'"\n=]+)['"]?\s*$`,
+				Pattern:     `^\s*([\p{L}_][\p{L}\p{N}_.-]*)\s*:\s+['"]?([^'"\n=]+)['"]?\s*$`,
 				ValueGroup:  2,
 				Description: "YAML key: value format",
 			},
 			{
 				Name:        "json_quoted",
-				Pattern:     `"([a-zA-Z_][\w._]*)"\s*:\s*"([^"]+)"`,
+				Pattern:     `"([\p{L}_][\p{L}\p{N}_.]*)"\s*:\s*"([^"]+)"`,
 				ValueGroup:  2,
 				Description: "JSON \"key\": \"value\" format",
 			},
@@ -227,61 +328,162 @@ func DefaultConfig() *Config {
 
 // Load loads configuration from file or returns default
 // If path is empty, returns built-in defaults (no auto-detection)
+// Settings set in the environment (GITSECRET_MIN_LENGTH) override the file
+// The path may list several files merged in order (see Layers)
 func Load(path string) (*Config, error) {
 	if path == "" {
-		return DefaultConfig(), nil
+		config := DefaultConfig()
+		return config, config.applyEnv()
 	}
 	return loadFromFile(path)
 }
 
+// BaselineFile is the repository-local file holding accepted findings
+const BaselineFile = ".gitsecret-baseline.json"
+
+// UserConfigDir returns the per-user configuration directory
+func UserConfigDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "git-secret-scanner")
+}
+
+// UserConfigPath returns the per-user configuration file
+func UserConfigPath() string {
+	return filepath.Join(UserConfigDir(), "patterns.json")
+}
+
+// RulePacksDir returns the directory holding custom rule packs (*.json)
+func RulePacksDir() string {
+	return filepath.Join(UserConfigDir(), "rules")
+}
+
 // LoadAuto tries to find a config file in common locations, or returns default
 func LoadAuto() (*Config, error) {
-	locations := []string{
-		"patterns.json",
-		"config/patterns.json",
-		filepath.Join(os.Getenv("HOME"), ".config", "git-secret-scanner", "patterns.json"),
-	}
+	return Load(AutoPath())
+}
 
-	for _, loc := range locations {
-		if _, err := os.Stat(loc); err == nil {
-			return loadFromFile(loc)
+// AutoPath returns the config file LoadAuto uses ("" for the defaults):
+// GITSECRET_CONFIG when set, else the first file found
+func AutoPath() string {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path
+	}
+	for _, dir := range []string{".", "config", UserConfigDir()} {
+		for _, name := range ConfigFileNames {
+			loc := filepath.Join(dir, name)
+			if _, err := os.Stat(loc); err == nil {
+				return loc
+			}
 		}
 	}
+	return ""
+}
 
-	return DefaultConfig(), nil
+// RepoConfigPath returns the configuration file at the root of a repository
+// ("" if none). Scans use it rather than the auto-detected one.
+func RepoConfigPath(repoPath string) string {
+	for _, name := range RepoConfigNames {
+		loc := filepath.Join(repoPath, name)
+		if info, err := os.Stat(loc); err == nil && !info.IsDir() {
+			return loc
+		}
+	}
+	return ""
 }
 
 func loadFromFile(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	// Refuse unsigned or modified files when the organization pins the config
+	config, err := readLayers(path, true)
 	if err != nil {
 		return nil, err
 	}
-
-	config := DefaultConfig()
-	if err := json.Unmarshal(data, config); err != nil {
-		return nil, err
+	if err := config.validateAuthors(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.validateLocale(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.validateLanguage(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.validateLargeFiles(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.validateWorkspace(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.validateTheme(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.validateKeywords(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 
-	return config, nil
+	return config, config.applyEnv()
 }
 
 // GetAllKeywords returns all search keywords from config
 func (c *Config) GetAllKeywords() []string {
 	var keywords []string
-	for _, group := range c.Keywords {
+	for _, group := range c.KeywordGroups() {
 		keywords = append(keywords, group.Patterns...)
 	}
 	return keywords
 }
 
+// SeverityForType returns the severity of a finding type.
+// The type may be a keyword pattern or a keyword group name; unknown types are low.
+func (c *Config) SeverityForType(findingType string) string {
+	typeLower := strings.ToLower(findingType)
+	for _, group := range c.KeywordGroups() {
+		if strings.ToLower(group.Name) == typeLower {
+			return severityForGroup(group)
+		}
+		for _, p := range group.Patterns {
+			if strings.ToLower(p) == typeLower {
+				return severityForGroup(group)
+			}
+		}
+	}
+	return SeverityLow
+}
+
+// severityForGroup returns the severity set on a keyword group, else the
+// built-in one of its name
+func severityForGroup(group KeywordGroup) string {
+	if group.Severity != "" {
+		return group.Severity
+	}
+	if sev, ok := defaultSeverities[toLower(group.Name)]; ok {
+		return sev
+	}
+	return SeverityLow
+}
+
+// SeverityRank returns the sort rank of a severity (0 is most urgent)
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
 // ShouldIgnoreFile checks if a file should be ignored based on patterns
 func (c *Config) ShouldIgnoreFile(filePath string) bool {
+	// .env, Terraform and credential files escape the extension patterns
+	// (source code, *.local, *.json...) and are only skipped inside ignored
+	// directories
+	env := IsEnvFile(filePath) || IsTerraformFile(filePath) || CredentialFileKind(filePath) != ""
 	for _, pattern := range c.IgnoredFiles {
+		if env && !strings.Contains(pattern, "**") && !strings.HasSuffix(pattern, "/") {
+			continue
+		}
 		if matchPattern(pattern, filePath) {
 			return true
 		}
 	}
-	return false
+	return c.ignore.MatchPath(filePath)
 }
 
 // matchPattern checks if a file path matches a glob-like pattern
@@ -350,7 +552,7 @@ func (c *Config) ShouldIgnoreValue(value string) bool {
 		}
 	}
 
-	return false
+	return c.ignore.MatchValue(value)
 }
 
 // looksLikeCode checks if a value appears to be code rather than a secret
@@ -424,9 +626,9 @@ func contains(s, substr string) bool {
 	return false
 }
 
-// Save saves configuration to file
+// Save saves configuration to file, in the format of its extension
 func (c *Config) Save(path string) error {
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := encodeConfig(ConfigFormat(path), c)
 	if err != nil {
 		return err
 	}
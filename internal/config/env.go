@@ -0,0 +1,65 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// EnvType is the finding type of .env values reported because of the
+// envAllValues setting rather than a keyword
+const EnvType = "env"
+
+// envCodeExtensions are source files whose name starts with ".env." (e.g.
+// .env.ts, a typed loader) and are not dotenv files
+var envCodeExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".cjs": true, ".ts": true,
+	".py": true, ".rb": true, ".go": true, ".php": true, ".java": true,
+}
+
+// IsEnvFile reports whether a path is a dotenv file: .env, .env.local,
+// .env.production, prod.env...
+func IsEnvFile(filePath string) bool {
+	base := path.Base(strings.ReplaceAll(filePath, "\\", "/"))
+	if envCodeExtensions[path.Ext(base)] {
+		return false
+	}
+	return base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env")
+}
+
+// ParseEnvLine parses one dotenv line: KEY=value with an optional "export"
+// prefix, single or double quotes, and comments (whole line, or after an
+// unquoted value). ok is false for blank lines, comments and other lines.
+func ParseEnvLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' {
+		return "", "", false
+	}
+	line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+	key, rest, found := strings.Cut(line, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" || strings.ContainsAny(key, " \t\"'") {
+		return "", "", false
+	}
+	rest = strings.TrimSpace(rest)
+
+	if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+		quote := rest[0]
+		for i := 1; i < len(rest); i++ {
+			switch {
+			case rest[i] == '\\' && quote == '"':
+				i++ // Escaped character
+			case rest[i] == quote:
+				// Escapes are kept: the value must match the file text to be cleaned
+				return key, rest[1:i], true
+			}
+		}
+		return "", "", false // Multi-line value
+	}
+
+	// Unquoted: a " #" starts a comment
+	if i := strings.Index(rest, " #"); i != -1 {
+		rest = rest[:i]
+	}
+	return key, strings.TrimSpace(rest), true
+}
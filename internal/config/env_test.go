@@ -0,0 +1,41 @@
+package config
+
+import "testing"
+
+func TestParseEnvLine(t *testing.T) {
+	cases := []struct {
+		line, key, value string
+		ok               bool
+	}{
+		{"DB_PASSWORD=s3cret", "DB_PASSWORD", "s3cret", true},
+		{"export API_TOKEN = 'abc def' # prod", "API_TOKEN", "abc def", true},
+		{`SECRET="a\"b#c" # comment`, "SECRET", `a\"b#c`, true},
+		{"STRIPE=sk_live_123 # rotated 2024", "STRIPE", "sk_live_123", true},
+		{"HASH=abc#def", "HASH", "abc#def", true},
+		{"# DB_PASSWORD=old", "", "", false},
+		{`MULTI="-----BEGIN`, "", "", false},
+		{"not an assignment", "", "", false},
+	}
+	for _, tc := range cases {
+		key, value, ok := ParseEnvLine(tc.line)
+		if key != tc.key || value != tc.value || ok != tc.ok {
+			t.Errorf("ParseEnvLine(%q) = %q, %q, %v; want %q, %q, %v", tc.line, key, value, ok, tc.key, tc.value, tc.ok)
+		}
+	}
+}
+
+func TestEnvFilesEscapeExtensionIgnores(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.IgnoredFiles = append(cfg.IgnoredFiles, "*.local", "*.env")
+	for _, path := range []string{".env", "app/.env.local", "deploy/prod.env", ".env.production"} {
+		if !IsEnvFile(path) || cfg.ShouldIgnoreFile(path) {
+			t.Errorf("Expected %s to be a scanned .env file", path)
+		}
+	}
+	if IsEnvFile("src/.env.ts") || !cfg.ShouldIgnoreFile("src/.env.ts") {
+		t.Error("Expected .env.ts to be source code")
+	}
+	if !cfg.ShouldIgnoreFile("node_modules/pkg/.env") {
+		t.Error("Expected .env files in ignored directories to stay ignored")
+	}
+}
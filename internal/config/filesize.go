@@ -0,0 +1,47 @@
+package config
+
+import "fmt"
+
+// Working-tree file size limits (settings.maxFileSizeKB, settings.largeFiles)
+const (
+	DefaultMaxFileSizeKB   = 1024 // Larger files are skipped by default
+	DefaultLargeFileHeadKB = 64   // Head scanned in LargeFilesHead mode
+
+	LargeFilesSkip = "skip" // Default: oversized files are not scanned
+	LargeFilesHead = "head" // Only the first settings.largeFileHeadKB are scanned
+)
+
+// MaxFileSize is the size in bytes above which a working-tree file is
+// skipped, or only its head scanned
+func (c *Config) MaxFileSize() int64 {
+	if c.Settings.MaxFileSizeKB > 0 {
+		return int64(c.Settings.MaxFileSizeKB) * 1024
+	}
+	return DefaultMaxFileSizeKB * 1024
+}
+
+// LargeFileHead is the number of bytes scanned in a file over MaxFileSize:
+// 0 when oversized files are skipped
+func (c *Config) LargeFileHead() int64 {
+	if c.Settings.LargeFiles != LargeFilesHead {
+		return 0
+	}
+	if c.Settings.LargeFileHeadKB > 0 {
+		return int64(c.Settings.LargeFileHeadKB) * 1024
+	}
+	return DefaultLargeFileHeadKB * 1024
+}
+
+// validateLargeFiles rejects unknown settings.largeFiles values and
+// negative sizes
+func (c *Config) validateLargeFiles() error {
+	switch c.Settings.LargeFiles {
+	case "", LargeFilesSkip, LargeFilesHead:
+	default:
+		return fmt.Errorf("invalid settings.largeFiles %q (skip or head)", c.Settings.LargeFiles)
+	}
+	if c.Settings.MaxFileSizeKB < 0 || c.Settings.LargeFileHeadKB < 0 {
+		return fmt.Errorf("settings.maxFileSizeKB and settings.largeFileHeadKB cannot be negative")
+	}
+	return nil
+}
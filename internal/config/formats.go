@@ -0,0 +1,142 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileNames are the configuration files looked for in a directory,
+// in order
+var ConfigFileNames = []string{"patterns.json", "patterns.yaml", "patterns.yml", "patterns.toml"}
+
+// RepoConfigNames are the configuration files looked for at the root of a
+// scanned repository, in order
+var RepoConfigNames = append([]string{
+	".gitsecretscanner.json", ".gitsecretscanner.yaml", ".gitsecretscanner.yml", ".gitsecretscanner.toml",
+}, ConfigFileNames...)
+
+// ConfigExtensions are the extensions of the configuration formats
+var ConfigExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// ConfigFormat returns the format of a configuration file from its
+// extension: FormatYAML, FormatTOML, or FormatJSON for any other
+func ConfigFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	}
+	return FormatJSON
+}
+
+// WithFormatExtension gives a configuration path the extension of format,
+// replacing a configuration extension already there
+func WithFormatExtension(path, format string) string {
+	ext := filepath.Ext(path)
+	for _, configExt := range ConfigExtensions {
+		if strings.EqualFold(ext, configExt) {
+			path = strings.TrimSuffix(path, ext)
+			break
+		}
+	}
+	return path + "." + format
+}
+
+// configJSON converts a configuration file of any format to JSON. YAML and
+// TOML go through JSON, so every format has the keys of the json tags.
+func configJSON(format string, data []byte) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			return []byte("{}"), nil // Empty file: the defaults
+		}
+		return json.Marshal(doc)
+	case FormatTOML:
+		var doc map[string]any
+		if _, err := toml.Decode(string(data), &doc); err != nil {
+			return nil, err
+		}
+		return json.Marshal(doc)
+	}
+	return data, nil
+}
+
+// encodeConfig writes a configuration in format, with the keys of the json
+// tags. YAML keeps the order of the JSON fields, TOML sorts the keys.
+func encodeConfig(format string, config *Config) ([]byte, error) {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatYAML:
+		// JSON is YAML: decoded as a node tree it keeps the field order
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		blockStyle(&doc)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), enc.Close()
+	case FormatTOML:
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(tomlValue(doc)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return data, nil
+}
+
+// blockStyle drops the flow style and quotes a YAML tree read from JSON
+// (the encoder still quotes strings that need it)
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// tomlValue prepares a JSON value for TOML, which has no null and tells
+// integers from floats
+func tomlValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for key, value := range t {
+			if value == nil {
+				delete(t, key)
+				continue
+			}
+			t[key] = tomlValue(value)
+		}
+	case []any:
+		for i, value := range t {
+			t[i] = tomlValue(value)
+		}
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1<<53 {
+			return int64(t)
+		}
+	}
+	return v
+}
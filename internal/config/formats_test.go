@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestYAMLAndTOMLConfigs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"patterns.yaml": "keywords:\n  - name: vault\n    patterns: [vault_key]\n    severity: critical\nignoredValues: [\"yes\"]\nsettings:\n  minSecretLength: 6\n  maxSecretLength: 200\n  structuredFiles: true\n",
+		"patterns.toml": "ignoredValues = [\"yes\"]\n\n[settings]\nminSecretLength = 6\nmaxSecretLength = 200\nstructuredFiles = true\n\n[[keywords]]\nname = \"vault\"\npatterns = [\"vault_key\"]\nseverity = \"critical\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", name, err)
+		}
+		if len(cfg.Keywords) != 1 || cfg.Keywords[0].Severity != SeverityCritical || cfg.Settings.MinSecretLength != 6 ||
+			!cfg.Settings.StructuredFiles || !reflect.DeepEqual(cfg.IgnoredValues, []string{"yes"}) {
+			t.Errorf("%s: got %+v", name, cfg)
+		}
+	}
+
+	// Saved configurations read back the same in every format
+	want := DefaultConfig()
+	want.LanguagePacks = []string{"fr"}
+	want.Settings.Palette = PaletteColorBlind
+	for _, format := range []string{FormatJSON, FormatYAML, FormatTOML} {
+		path := WithFormatExtension(filepath.Join(dir, "saved.json"), format)
+		if err := want.Save(path); err != nil {
+			t.Fatalf("Save(%s): %v", path, err)
+		}
+		got, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", path, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s does not read back the configuration saved", path)
+		}
+	}
+}
+
+func TestRepoConfigPath(t *testing.T) {
+	repo := t.TempDir()
+	if path := RepoConfigPath(repo); path != "" {
+		t.Errorf("RepoConfigPath = %q in an empty repository", path)
+	}
+	for _, name := range []string{"patterns.yaml", ".gitsecretscanner.toml"} {
+		if err := os.WriteFile(filepath.Join(repo, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A directory of that name is no configuration
+	if err := os.Mkdir(filepath.Join(repo, ".gitsecretscanner.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if path := RepoConfigPath(repo); path != filepath.Join(repo, ".gitsecretscanner.toml") {
+		t.Errorf("RepoConfigPath = %q, want .gitsecretscanner.toml first", path)
+	}
+}
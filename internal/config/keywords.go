@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KeywordRegexp compiles a pattern of a regex keyword group; it ignores case
+// unless settings.caseSensitive is set
+func (c *Config) KeywordRegexp(pattern string) (*regexp.Regexp, error) {
+	if !c.Settings.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// ValueRegexp compiles the value validator of a group, anchored to match
+// whole values (nil when the group accepts any value)
+func (g KeywordGroup) ValueRegexp() (*regexp.Regexp, error) {
+	if g.ValuePattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + g.ValuePattern + ")$")
+}
+
+// validateKeywords rejects unknown severities, invalid regex keywords and
+// value validators
+func (c *Config) validateKeywords() error {
+	for _, group := range c.Keywords {
+		if group.Severity != "" && SeverityRank(group.Severity) == len(Severities) {
+			return fmt.Errorf("keyword group %q: invalid severity %q (critical, high, medium or low)", group.Name, group.Severity)
+		}
+		if _, err := group.ValueRegexp(); err != nil {
+			return fmt.Errorf("keyword group %q: invalid valuePattern: %w", group.Name, err)
+		}
+		if !group.Regex {
+			continue
+		}
+		for _, p := range group.Patterns {
+			if _, err := c.KeywordRegexp(p); err != nil {
+				return fmt.Errorf("keyword group %q: invalid pattern %q: %w", group.Name, p, err)
+			}
+		}
+	}
+	return nil
+}
+
+// KeywordGroupOf returns the keyword group a finding type comes from: the
+// group holding the keyword pattern, or the group of that name (regex
+// groups report their name)
+func (c *Config) KeywordGroupOf(findingType string) (KeywordGroup, bool) {
+	for _, group := range c.KeywordGroups() {
+		if strings.EqualFold(group.Name, findingType) {
+			return group, true
+		}
+		for _, p := range group.Patterns {
+			if strings.EqualFold(p, findingType) {
+				return group, true
+			}
+		}
+	}
+	return KeywordGroup{}, false
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInvalidKeywordPatternsRejected(t *testing.T) {
+	for _, group := range []string{
+		`{"name": "aws", "regex": true, "patterns": ["aws_(access|secret"]}`,
+		`{"name": "aws", "patterns": ["aws_secret"], "valuePattern": "[A-Z"}`,
+		`{"name": "aws", "patterns": ["aws_secret"], "severity": "urgent"}`,
+	} {
+		path := filepath.Join(t.TempDir(), "patterns.json")
+		if err := os.WriteFile(path, []byte(`{"keywords": [`+group+`]}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), `keyword group "aws"`) {
+			t.Errorf("Load(%s) error = %v, want the invalid pattern reported", group, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"regexp"
+	"sort"
+)
+
+// LanguagePack adds localized keyword patterns to the built-in groups
+type LanguagePack struct {
+	Name     string
+	Patterns map[string][]string // Keyword group name -> localized patterns
+}
+
+// languagePacks holds the built-in packs, selectable with "languagePacks" in the config
+var languagePacks = map[string]LanguagePack{
+	"fr": {
+		Name: "Français",
+		Patterns: map[string][]string{
+			"password":          {"mot_de_passe", "motdepasse", "mot-de-passe", "mdp"},
+			"secret":            {"cle_secrete", "clé_secrète"},
+			"api_key":           {"cle_api", "clé_api", "cle_d_api"},
+			"token":             {"jeton"},
+			"credentials":       {"identifiants"},
+			"private_key":       {"cle_privee", "clé_privée"},
+			"connection_string": {"chaine_connexion", "chaîne_connexion", "chaine_de_connexion"},
+			"encryption":        {"cle_chiffrement", "clé_chiffrement", "chiffrement"},
+		},
+	},
+	"de": {
+		Name: "Deutsch",
+		Patterns: map[string][]string{
+			"password":          {"passwort", "kennwort"},
+			"secret":            {"geheimnis", "geheimschluessel", "geheimschlüssel"},
+			"api_key":           {"api_schluessel", "api_schlüssel", "apischluessel"},
+			"token":             {"zugangstoken"},
+			"credentials":       {"zugangsdaten", "anmeldedaten"},
+			"private_key":       {"privater_schluessel", "privater_schlüssel"},
+			"connection_string": {"verbindungszeichenfolge"},
+			"encryption":        {"verschluesselung", "verschlüsselung", "schluessel", "schlüssel"},
+		},
+	},
+	"es": {
+		Name: "Español",
+		Patterns: map[string][]string{
+			"password":          {"contraseña", "contrasena", "clave_acceso"},
+			"secret":            {"secreto"},
+			"api_key":           {"clave_api"},
+			"credentials":       {"credenciales"},
+			"private_key":       {"clave_privada"},
+			"connection_string": {"cadena_conexion", "cadena_de_conexion"},
+			"encryption":        {"clave_cifrado", "cifrado"},
+		},
+	},
+}
+
+// LanguagePackCodes returns the codes of the built-in language packs, sorted
+func LanguagePackCodes() []string {
+	codes := make([]string, 0, len(languagePacks))
+	for code := range languagePacks {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// GetLanguagePack returns a built-in language pack by code
+func GetLanguagePack(code string) (LanguagePack, bool) {
+	pack, ok := languagePacks[toLower(code)]
+	return pack, ok
+}
+
+// KeywordGroups returns the configured keyword groups with the patterns of
+// the selected language packs merged in. Unknown pack codes are ignored.
+func (c *Config) KeywordGroups() []KeywordGroup {
+	if len(c.LanguagePacks) == 0 {
+		return c.Keywords
+	}
+
+	groups := make([]KeywordGroup, len(c.Keywords))
+	index := make(map[string]int, len(c.Keywords))
+	for i, group := range c.Keywords {
+		groups[i] = group
+		groups[i].Patterns = append([]string(nil), group.Patterns...)
+		index[toLower(group.Name)] = i
+	}
+
+	for _, code := range c.LanguagePacks {
+		pack, ok := GetLanguagePack(code)
+		if !ok {
+			continue
+		}
+		// Iterate groups in a stable order so keyword order is deterministic
+		names := make([]string, 0, len(pack.Patterns))
+		for name := range pack.Patterns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			i, ok := index[name]
+			if !ok {
+				groups = append(groups, KeywordGroup{Name: name, Description: pack.Name})
+				i = len(groups) - 1
+				index[name] = i
+			}
+			for _, p := range pack.Patterns[name] {
+				if groups[i].Regex {
+					p = regexp.QuoteMeta(p)
+				}
+				if !containsPattern(groups[i].Patterns, p) {
+					groups[i].Patterns = append(groups[i].Patterns, p)
+				}
+			}
+		}
+	}
+	return groups
+}
+
+func containsPattern(patterns []string, p string) bool {
+	for _, existing := range patterns {
+		if toLower(existing) == toLower(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestLanguagePacksMergeIntoGroups(t *testing.T) {
+	cfg := DefaultConfig()
+	before := len(cfg.GetAllKeywords())
+
+	cfg.LanguagePacks = []string{"es", "de", "unknown"}
+	if len(cfg.GetAllKeywords()) <= before {
+		t.Fatal("language packs added no keywords")
+	}
+	if got := cfg.SeverityForType("contraseña"); got != SeverityHigh {
+		t.Errorf("contraseña severity = %s, want high (password group)", got)
+	}
+	if len(cfg.Keywords[0].Patterns) != len(DefaultConfig().Keywords[0].Patterns) {
+		t.Error("KeywordGroups modified the configured groups")
+	}
+
+	// Localized keys must be extractable
+	for _, p := range cfg.GetCompiledPatterns() {
+		if p.Name != "key_equals_value" {
+			continue
+		}
+		match := p.Regex.FindStringSubmatch("contraseña=Hola123!")
+		if match == nil || match[1] != "contraseña" {
+			t.Errorf("key with non-ASCII letters not extracted: %v", match)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// A configuration path may list several files, separated like $PATH
+// (org.yaml:repo.json). The first file is loaded as a single file would
+// be, over the built-in defaults; an empty first entry (:repo.json) keeps
+// the defaults as they are. Each following file is an overlay merged into
+// the result:
+//
+//   - extractionPatterns and keywords: an entry replaces the one of the
+//     same name, other entries are appended
+//   - ignoredValues, ignoredFiles, excludeBinaryExtensions, languagePacks,
+//     rulePacks: entries are added to the list
+//   - settings: only the settings the overlay sets are changed
+//   - profiles: a profile replaces the one of the same name
+//
+// The rule packs listed are then merged the same way, in order.
+
+// Layers splits a configuration path into its files, in order. URLs keep
+// the colons of their scheme and port (see IsRemote).
+func Layers(path string) []string {
+	parts := filepath.SplitList(path)
+	layers := make([]string, 0, len(parts))
+	for i := 0; i < len(parts); i++ {
+		layer := parts[i]
+		scheme := strings.ToLower(layer)
+		if (scheme == "http" || scheme == "https") && i+1 < len(parts) && strings.HasPrefix(parts[i+1], "//") {
+			i++
+			layer += ":" + parts[i]
+			for i+1 < len(parts) && portPattern.MatchString(parts[i+1]) {
+				i++
+				layer += ":" + parts[i]
+			}
+		}
+		layers = append(layers, layer)
+	}
+	return layers
+}
+
+// portPattern matches what follows the colon of a URL's port
+var portPattern = regexp.MustCompile(`^[0-9]+([/?#].*)?$`)
+
+// JoinLayers adds an overlay to a configuration path. The path keeps its
+// files; an empty path stands for the built-in defaults.
+func JoinLayers(path, overlay string) string {
+	return path + string(os.PathListSeparator) + overlay
+}
+
+// IsLayered reports whether a configuration path lists several files
+func IsLayered(path string) bool {
+	return len(Layers(path)) > 1
+}
+
+// readLayers merges the files of a configuration path, checking their
+// signatures when policy is set. The result is not validated.
+func readLayers(path string, policy bool) (*Config, error) {
+	config := DefaultConfig()
+	for i, file := range Layers(path) {
+		if file == "" {
+			continue
+		}
+		data, err := readLayer(file, policy)
+		if err != nil {
+			return nil, err
+		}
+		data, err = configJSON(ConfigFormat(layerName(file)), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if i == 0 {
+			err = json.Unmarshal(data, config)
+		} else {
+			err = config.merge(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	if err := config.mergeRulePacks(policy); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// readLayer returns the content of a file or URL of a configuration path
+func readLayer(file string, policy bool) ([]byte, error) {
+	if IsRemote(file) {
+		return readRemote(file, policy)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if policy {
+		if err := checkPolicy(file, data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// merge applies an overlay, in JSON
+func (c *Config) merge(data []byte) error {
+	var overlay Config
+	if err := json.Unmarshal(data, &overlay); err != nil {
+		return err
+	}
+
+	for _, ep := range overlay.ExtractionPatterns {
+		i := 0
+		for i < len(c.ExtractionPatterns) && c.ExtractionPatterns[i].Name != ep.Name {
+			i++
+		}
+		if i < len(c.ExtractionPatterns) {
+			c.ExtractionPatterns[i] = ep
+		} else {
+			c.ExtractionPatterns = append(c.ExtractionPatterns, ep)
+		}
+	}
+	c.Keywords = replaceGroups(c.Keywords, overlay.Keywords)
+	for name, profile := range overlay.Profiles {
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]Profile)
+		}
+		c.Profiles[name] = profile
+	}
+	c.IgnoredValues = appendMissing(c.IgnoredValues, overlay.IgnoredValues)
+	c.IgnoredFiles = appendMissing(c.IgnoredFiles, overlay.IgnoredFiles)
+	c.ExcludeBinaryExtensions = appendMissing(c.ExcludeBinaryExtensions, overlay.ExcludeBinaryExtensions)
+	c.LanguagePacks = appendMissing(c.LanguagePacks, overlay.LanguagePacks)
+	c.RulePacks = appendMissing(c.RulePacks, overlay.RulePacks)
+
+	// Decoded over the current settings, the ones not in the overlay are kept
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if settings, ok := fields["settings"]; ok {
+		return json.Unmarshal(settings, &c.Settings)
+	}
+	return nil
+}
+
+// appendMissing appends the items not already in list
+func appendMissing(list, items []string) []string {
+	for _, item := range items {
+		if !containsPattern(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
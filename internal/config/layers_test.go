@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMergesLayers(t *testing.T) {
+	dir := t.TempDir()
+	org := filepath.Join(dir, "org.yaml")
+	repo := filepath.Join(dir, "repo.json")
+	if err := os.WriteFile(org, []byte(`keywords:
+  - {name: password, patterns: [password, passwd]}
+  - {name: internal, patterns: [corp_token], severity: high}
+ignoredValues: [changeme]
+settings: {minSecretLength: 8, maxSecretLength: 200, network: {httpProxy: "http://proxy:3128"}}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(repo, []byte(`{
+  "keywords": [{"name": "internal", "patterns": ["corp_token", "corp_key"], "severity": "critical"}],
+  "ignoredValues": ["CHANGEME", "dummy"],
+  "settings": {"minSecretLength": 12}
+}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(JoinLayers(org, repo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Keywords) != 2 || config.Keywords[1].Name != "internal" ||
+		len(config.Keywords[1].Patterns) != 2 || config.Keywords[1].Severity != "critical" {
+		t.Errorf("keywords not merged by name: %+v", config.Keywords)
+	}
+	if len(config.IgnoredValues) != 2 || config.IgnoredValues[1] != "dummy" {
+		t.Errorf("ignoredValues = %v, want [changeme dummy]", config.IgnoredValues)
+	}
+	s := config.Settings
+	if s.MinSecretLength != 12 || s.MaxSecretLength != 200 || s.Network.HTTPProxy != "http://proxy:3128" {
+		t.Errorf("settings not merged key by key: %+v", s)
+	}
+
+	// An empty first layer keeps the defaults and merges the file over them
+	config, err = Load(JoinLayers("", repo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Keywords) != len(DefaultConfig().Keywords)+1 {
+		t.Errorf("got %d keyword groups, want the defaults and internal", len(config.Keywords))
+	}
+	if config.Settings.MaxSecretLength != DefaultConfig().Settings.MaxSecretLength {
+		t.Errorf("maxSecretLength = %d, want the default", config.Settings.MaxSecretLength)
+	}
+}
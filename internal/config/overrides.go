@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables overriding the configuration and the scan defaults,
+// so containers and CI jobs can be set up without files
+const (
+	ConfigEnv    = "GITSECRET_CONFIG"     // Configuration path (layers allowed), in place of auto-detection and the repository's file
+	MinLengthEnv = "GITSECRET_MIN_LENGTH" // Overrides settings.minSecretLength
+	BranchEnv    = "GITSECRET_BRANCH"     // Branch scanned by default
+	OutputEnv    = "GITSECRET_OUTPUT"     // Output file of a scan by default
+	LanguageEnv  = "GITSECRET_LANG"       // Language of the reports and the TUI, over settings.language
+)
+
+// EnvDefault returns the value of an environment variable, or def when it
+// is unset or empty
+func EnvDefault(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// applyEnv overrides the settings set in the environment. Under the
+// signature policy the settings are the signed ones: an override is an
+// error, not a way around the policy.
+func (c *Config) applyEnv() error {
+	v := os.Getenv(MinLengthEnv)
+	if v == "" {
+		return nil
+	}
+	if SignedConfigRequired() {
+		return fmt.Errorf("signed configuration required: %s cannot override the signed settings", MinLengthEnv)
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return fmt.Errorf("%s: invalid length %q", MinLengthEnv, v)
+	}
+	c.Settings.MinSecretLength = n
+	return nil
+}
+
+// OverrideFileSizes applies the size limit (KB, 0 to keep it) and large-file
+// strategy ("" to keep it) of a single run. Under the signature policy they
+// are the signed ones: an override is an error, as for the environment.
+func (c *Config) OverrideFileSizes(maxFileSizeKB int, largeFiles string) error {
+	if maxFileSizeKB <= 0 && largeFiles == "" {
+		return nil
+	}
+	if SignedConfigRequired() {
+		return fmt.Errorf("signed configuration required: --max-file-size and --large-files cannot override the signed settings")
+	}
+	if maxFileSizeKB > 0 {
+		c.Settings.MaxFileSizeKB = maxFileSizeKB
+	}
+	if largeFiles != "" {
+		c.Settings.LargeFiles = largeFiles
+	}
+	return nil
+}
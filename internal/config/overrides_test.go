@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvironmentOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ci.yaml")
+	if err := os.WriteFile(path, []byte("settings: {minSecretLength: 6}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnv, path)
+	t.Setenv(MinLengthEnv, "12")
+
+	if got := AutoPath(); got != path {
+		t.Errorf("AutoPath() = %q, want %s", got, ConfigEnv)
+	}
+	for _, load := range []func() (*Config, error){LoadAuto, func() (*Config, error) { return Load("") }} {
+		config, err := load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Settings.MinSecretLength != 12 {
+			t.Errorf("minSecretLength = %d, want 12 from %s", config.Settings.MinSecretLength, MinLengthEnv)
+		}
+	}
+
+	t.Setenv(MinLengthEnv, "short")
+	if _, err := Load(path); err == nil {
+		t.Errorf("an invalid %s was accepted", MinLengthEnv)
+	}
+}
+
+func TestEnvironmentOverridesUnderSignaturePolicy(t *testing.T) {
+	pub, priv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITSECRET_REQUIRE_SIGNED_CONFIG", "1")
+	t.Setenv("GITSECRET_CONFIG_PUBKEY", pub)
+	path := filepath.Join(t.TempDir(), "patterns.json")
+	if err := DefaultConfig().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignFile(path, priv); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("signed config rejected: %v", err)
+	}
+
+	// Raising the length would hide the shorter secrets the signed config reports
+	t.Setenv(MinLengthEnv, "40")
+	if _, err := Load(path); err == nil {
+		t.Errorf("%s overrode a signed config", MinLengthEnv)
+	}
+	if _, err := Load(""); err == nil {
+		t.Errorf("%s overrode the defaults under the policy", MinLengthEnv)
+	}
+	t.Setenv(MinLengthEnv, "")
+
+	// Nor can the flags of a run scan more or less of the files
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.OverrideFileSizes(0, ""); err != nil {
+		t.Errorf("no override: %v", err)
+	}
+	for _, override := range []struct {
+		kb    int
+		large string
+	}{{4096, ""}, {0, LargeFilesHead}} {
+		if err := cfg.OverrideFileSizes(override.kb, override.large); err == nil {
+			t.Errorf("OverrideFileSizes(%d, %q) overrode a signed config", override.kb, override.large)
+		}
+	}
+	if cfg.Settings.MaxFileSizeKB != DefaultConfig().Settings.MaxFileSizeKB || cfg.Settings.LargeFiles != DefaultConfig().Settings.LargeFiles {
+		t.Errorf("settings changed: %+v", cfg.Settings)
+	}
+}
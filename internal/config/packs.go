@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A rule pack is a versioned set of rules published at a release URL and
+// installed into RulePacksDir by `gitsecret patterns update`, so detection
+// rules can follow new providers between releases:
+//
+//	{
+//	  "name": "community",
+//	  "version": "2026.10.1",
+//	  "url": "https://example.com/releases/latest/community.json",
+//	  "rules": {"extractionPatterns": [...], "keywords": [...], "ignoredValues": [...]}
+//	}
+//
+// A configuration uses the packs it lists in rulePacks: their rules are
+// merged as overlays (see layers.go) after the files of the configuration
+// path. The checksum of a pack is published next to it, as URL.sha256.
+
+// ChecksumExt is appended to a rule pack URL to find its checksum
+const ChecksumExt = ".sha256"
+
+// RulePack is a rule pack file
+type RulePack struct {
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Description string          `json:"description,omitempty"`
+	URL         string          `json:"url,omitempty"` // Where updates are published
+	Rules       json.RawMessage `json:"rules"`         // Overlay of extraction patterns, keywords and ignore lists
+}
+
+// packNamePattern matches the names of rule packs, used as file names
+var packNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// checksumPattern matches a checksum file: the digest, then an optional
+// file name (sha256sum output)
+var checksumPattern = regexp.MustCompile(`^([0-9a-fA-F]{64})(\s|$)`)
+
+// RulePackPath returns the file an installed rule pack is read from
+func RulePackPath(name string) string {
+	return filepath.Join(RulePacksDir(), name+".json")
+}
+
+// ParseRulePack decodes and checks a rule pack: a name, a version and rules
+// that form a valid overlay
+func ParseRulePack(data []byte) (*RulePack, error) {
+	var pack RulePack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, err
+	}
+	if !packNamePattern.MatchString(pack.Name) {
+		return nil, fmt.Errorf("invalid rule pack name %q (lowercase letters, digits, - and _)", pack.Name)
+	}
+	if pack.Version == "" {
+		return nil, fmt.Errorf("rule pack %s: missing version", pack.Name)
+	}
+	if len(pack.Rules) == 0 {
+		return nil, fmt.Errorf("rule pack %s: missing rules", pack.Name)
+	}
+	merged := DefaultConfig()
+	if err := merged.merge(pack.Rules); err != nil {
+		return nil, fmt.Errorf("rule pack %s: %w", pack.Name, err)
+	}
+	for _, issue := range merged.Validate() {
+		if issue.Level == IssueError {
+			return nil, fmt.Errorf("rule pack %s: %s", pack.Name, issue)
+		}
+	}
+	return &pack, nil
+}
+
+// InstalledRulePack reads an installed rule pack (nil if not installed)
+func InstalledRulePack(name string) (*RulePack, error) {
+	data, err := os.ReadFile(RulePackPath(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseRulePack(data)
+}
+
+// FetchRulePack downloads a rule pack and checks it against pin, or the
+// checksum published at URL.sha256 when pin is "". With a signature policy,
+// the signature at URL.sig is fetched and checked too. The returned files
+// are the pack and its signature (nil without a policy), to install as is.
+func FetchRulePack(target, pin string) (*RulePack, []byte, []byte, error) {
+	data, err := fetchRemote(target)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if pin == "" {
+		checksum, err := fetchRemote(target + ChecksumExt)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("checksum: %w", err)
+		}
+		match := checksumPattern.FindSubmatch(checksum)
+		if match == nil {
+			return nil, nil, nil, fmt.Errorf("%s%s: not a sha256 checksum", target, ChecksumExt)
+		}
+		pin = string(match[1])
+	}
+	if sum := digest(data); sum != strings.ToLower(pin) {
+		return nil, nil, nil, fmt.Errorf("%s: sha256 %s does not match the checksum %s", target, sum, strings.ToLower(pin))
+	}
+
+	var sig []byte
+	if SignedConfigRequired() {
+		key, err := TrustedKey()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("signed configuration required: %w", err)
+		}
+		if sig, err = fetchRemote(target + SignatureExt); err != nil {
+			return nil, nil, nil, fmt.Errorf("signed configuration required: %s: %w (%v)", target, ErrUnsignedConfig, err)
+		}
+		if err := verifySignature(target, data, sig, key); err != nil {
+			return nil, nil, nil, fmt.Errorf("signed configuration required: %w", err)
+		}
+	}
+
+	pack, err := ParseRulePack(data)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %w", target, err)
+	}
+	return pack, data, sig, nil
+}
+
+// InstallRulePack writes a fetched rule pack (and its signature, if any)
+// into RulePacksDir, replacing the installed version
+func InstallRulePack(pack *RulePack, data, sig []byte) error {
+	if err := os.MkdirAll(RulePacksDir(), 0755); err != nil {
+		return err
+	}
+	path := RulePackPath(pack.Name)
+	if sig != nil {
+		if err := writeReplacing(path+SignatureExt, sig); err != nil {
+			return err
+		}
+	}
+	return writeReplacing(path, data)
+}
+
+// writeReplacing writes a file through a temporary one, so readers never
+// see it half written
+func writeReplacing(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// CompareVersions orders two pack versions, compared number by number
+// (2026.9.2 < 2026.10.1); parts that are not numbers compare as text
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(strings.TrimPrefix(a, "v"), "."), strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+// mergeRulePacks merges the rules of the packs a configuration lists,
+// checking their signatures when policy is set
+func (c *Config) mergeRulePacks(policy bool) error {
+	for _, name := range c.RulePacks {
+		if !packNamePattern.MatchString(name) {
+			return fmt.Errorf("rulePacks: invalid name %q", name)
+		}
+		data, err := readLayer(RulePackPath(name), policy)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("rule pack %s is not installed (gitsecret patterns update URL)", name)
+		}
+		if err != nil {
+			return err
+		}
+		pack, err := ParseRulePack(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", RulePackPath(name), err)
+		}
+		if err := c.merge(pack.Rules); err != nil {
+			return fmt.Errorf("%s: %w", RulePackPath(name), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRulePackUpdate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	pack := `{"name": "community", "version": "2026.10.1",
+  "rules": {"keywords": [{"name": "stripe", "patterns": ["sk_live"], "severity": "critical"}], "ignoredValues": ["sk_live_4eC39HqLyjWD"]}}`
+	checksum := digest([]byte(pack)) + "  community.json\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/community.json":
+			w.Write([]byte(pack))
+		case "/community.json" + ChecksumExt:
+			w.Write([]byte(checksum))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetched, data, _, err := FetchRulePack(server.URL+"/community.json", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.Name != "community" || fetched.Version != "2026.10.1" {
+		t.Fatalf("pack = %+v", fetched)
+	}
+	if _, _, _, err := FetchRulePack(server.URL+"/community.json", strings.Repeat("0", 64)); err == nil {
+		t.Error("a pack not matching the pinned digest was accepted")
+	}
+	if err := InstallRulePack(fetched, data, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A configuration uses the packs it lists
+	path := filepath.Join(t.TempDir(), "patterns.yaml")
+	os.WriteFile(path, []byte("keywords: [{name: password, patterns: [password]}]\nrulePacks: [community]\n"), 0644)
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Keywords) != 2 || config.Keywords[1].Name != "stripe" || !config.ShouldIgnoreValue("sk_live_4eC39HqLyjWD") ||
+		DefaultConfig().ShouldIgnoreValue("sk_live_4eC39HqLyjWD") {
+		t.Errorf("pack rules not merged: %+v", config.Keywords)
+	}
+	os.WriteFile(path, []byte("rulePacks: [missing]\n"), 0644)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("missing pack: err = %v", err)
+	}
+
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{{"2026.9.2", "2026.10.1", -1}, {"v1.2.0", "1.2.0", 0}, {"1.10", "1.9", 1}} {
+		if got := CompareVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("CompareVersions(%s, %s) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Profile is a named variant of a configuration, chosen per scan (scan
+// --profile, ScanOptions.Profile, the TUI scan form), so one file can serve
+// a strict audit, CI and a quick check:
+//
+//	profiles:
+//	  quick:
+//	    keywordGroups: [password, api_keys]
+//	    settings: {minSecretLength: 12}
+//
+// The profile is applied over the rest of the file: its keywords replace the
+// group of the same name or are added, its ignored values and files are
+// added, and only the settings it sets change. keywordGroups then keeps the
+// listed groups and the profile's own, dropping the others (language pack
+// patterns included).
+type Profile struct {
+	Description   string          `json:"description,omitempty"`
+	KeywordGroups []string        `json:"keywordGroups,omitempty"` // Groups kept, by name (all when empty)
+	Keywords      []KeywordGroup  `json:"keywords,omitempty"`
+	IgnoredValues []string        `json:"ignoredValues,omitempty"`
+	IgnoredFiles  []string        `json:"ignoredFiles,omitempty"`
+	Settings      json.RawMessage `json:"settings,omitempty"`
+}
+
+// ProfileNames returns the profiles of the configuration, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithProfile returns a copy of the configuration with a profile applied.
+// Settings set in the environment (GITSECRET_MIN_LENGTH) still win.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		known := "none defined"
+		if len(c.Profiles) > 0 {
+			known = strings.Join(c.ProfileNames(), ", ")
+		}
+		return nil, fmt.Errorf("unknown profile %q (profiles: %s)", name, known)
+	}
+
+	applied := *c
+	applied.Keywords = replaceGroups(slices.Clone(c.Keywords), profile.Keywords)
+	applied.IgnoredValues = appendMissing(slices.Clone(c.IgnoredValues), profile.IgnoredValues)
+	applied.IgnoredFiles = appendMissing(slices.Clone(c.IgnoredFiles), profile.IgnoredFiles)
+	if len(profile.Settings) > 0 {
+		if err := json.Unmarshal(profile.Settings, &applied.Settings); err != nil {
+			return nil, fmt.Errorf("profile %q: settings: %w", name, err)
+		}
+	}
+
+	if len(profile.KeywordGroups) > 0 {
+		keep := slices.Clone(profile.KeywordGroups)
+		for _, group := range profile.Keywords {
+			keep = append(keep, group.Name)
+		}
+		if err := applied.keepGroups(profile.KeywordGroups, keep); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+
+	for _, check := range []func() error{applied.validateAuthors, applied.validateLocale, applied.validateLanguage, applied.validateLargeFiles, applied.validateWorkspace, applied.validateTheme, applied.validateKeywords} {
+		if err := check(); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+	return &applied, applied.applyEnv()
+}
+
+// WithGroups returns a copy of the configuration keeping only the named
+// keyword groups, language pack groups included (TUI scan form). Under the
+// signature policy the signed groups are all looked for: it is an error.
+func (c *Config) WithGroups(names []string) (*Config, error) {
+	if SignedConfigRequired() {
+		return nil, fmt.Errorf("signed configuration required: keyword groups cannot override the signed settings")
+	}
+	applied := *c
+	if err := applied.keepGroups(names, names); err != nil {
+		return nil, err
+	}
+	return &applied, nil
+}
+
+// keepGroups drops the keyword groups not in keep, after checking that the
+// groups named in known exist. The language pack patterns are resolved into
+// their groups first, kept or dropped with them.
+func (c *Config) keepGroups(known, keep []string) error {
+	groups := slices.Clone(c.KeywordGroups())
+	for _, kept := range known {
+		if !slices.ContainsFunc(groups, func(g KeywordGroup) bool { return strings.EqualFold(g.Name, kept) }) {
+			return fmt.Errorf("unknown keyword group %q", kept)
+		}
+	}
+	c.Keywords = slices.DeleteFunc(groups, func(g KeywordGroup) bool {
+		return !slices.ContainsFunc(keep, func(k string) bool { return strings.EqualFold(g.Name, k) })
+	})
+	c.LanguagePacks = nil
+	return nil
+}
+
+// replaceGroups replaces the groups of list with the ones of the same name
+// in groups, and appends the others
+func replaceGroups(list, groups []KeywordGroup) []KeywordGroup {
+	for _, group := range groups {
+		i := slices.IndexFunc(list, func(g KeywordGroup) bool { return g.Name == group.Name })
+		if i >= 0 {
+			list[i] = group
+		} else {
+			list = append(list, group)
+		}
+	}
+	return list
+}
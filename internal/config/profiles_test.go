@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "patterns.yaml")
+	if err := os.WriteFile(path, []byte(`keywords:
+  - {name: password, patterns: [password, passwd]}
+  - {name: api_keys, patterns: [api_key], severity: medium}
+  - {name: tokens, patterns: [token]}
+settings: {minSecretLength: 6}
+profiles:
+  quick:
+    description: Passwords and keys only
+    keywordGroups: [password]
+    keywords: [{name: api_keys, patterns: [api_key, apikey], severity: critical}]
+    settings: {minSecretLength: 12}
+  broken:
+    keywordGroups: [nonexistent]
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := config.ProfileNames(); len(names) != 2 || names[0] != "broken" || names[1] != "quick" {
+		t.Fatalf("ProfileNames = %v", names)
+	}
+
+	quick, err := config.WithProfile("quick")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quick.Keywords) != 2 || quick.Keywords[0].Name != "password" ||
+		quick.Keywords[1].Name != "api_keys" || quick.Keywords[1].Severity != "critical" {
+		t.Errorf("keywords = %+v, want password and the profile's api_keys", quick.Keywords)
+	}
+	if quick.Settings.MinSecretLength != 12 || quick.Settings.MaxSecretLength != config.Settings.MaxSecretLength {
+		t.Errorf("settings = %+v, want only minSecretLength changed", quick.Settings)
+	}
+	if len(config.Keywords) != 3 || config.Settings.MinSecretLength != 6 {
+		t.Error("WithProfile changed the configuration it was called on")
+	}
+
+	if _, err := config.WithProfile("missing"); err == nil || !strings.Contains(err.Error(), "broken, quick") {
+		t.Errorf("unknown profile: err = %v", err)
+	}
+	tokens, err := config.WithGroups([]string{"Tokens"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens.Keywords) != 1 || tokens.Keywords[0].Name != "tokens" || len(config.Keywords) != 3 {
+		t.Errorf("WithGroups = %+v, want the tokens group only", tokens.Keywords)
+	}
+	if _, err := config.WithGroups([]string{"nonexistent"}); err == nil {
+		t.Error("WithGroups accepted an unknown group")
+	}
+
+	issues := config.Validate()
+	if len(issues) != 1 || issues[0].Field != "profiles.broken" {
+		t.Errorf("Validate = %v, want the broken profile", issues)
+	}
+
+	// Under the signature policy no group of the signed configuration can
+	// be left out
+	t.Setenv("GITSECRET_REQUIRE_SIGNED_CONFIG", "1")
+	if _, err := config.WithGroups([]string{"tokens"}); err == nil || !strings.Contains(err.Error(), "signed configuration required") {
+		t.Errorf("WithGroups under the signature policy: err = %v", err)
+	}
+}
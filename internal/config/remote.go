@@ -0,0 +1,195 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A configuration path, or any layer of it, may be an http(s) URL, so that a
+// security team can host the canonical patterns file every scanner pulls:
+//
+//	https://security.example.com/patterns.yaml#sha256=<hex digest>
+//
+// The file is cached under RemoteCacheDir and fetched again once
+// RemoteCacheTTL has passed; when the host cannot be reached the cached copy
+// is used, however old. The optional sha256 fragment pins the content: a
+// file with another digest is refused (and not cached), and a cached copy
+// matching the pin is used without fetching. With a signature policy, the
+// signature is fetched from the URL followed by SignatureExt.
+
+// RemoteCacheTTL is how long a fetched configuration is used before it is
+// fetched again
+const RemoteCacheTTL = time.Hour
+
+// maxRemoteConfig bounds the size of a fetched configuration
+const maxRemoteConfig = 10 << 20
+
+// RemoteClient returns the client fetching remote configurations. The
+// command line sets it to httpclient.New, which cannot be imported here
+// (it reads NetworkSettings).
+var RemoteClient = func(settings NetworkSettings) (*http.Client, error) {
+	return &http.Client{Timeout: 30 * time.Second}, nil
+}
+
+// pinPattern matches the fragment pinning the digest of a remote file
+var pinPattern = regexp.MustCompile(`^sha256=([0-9a-fA-F]{64})$`)
+
+// IsRemote reports whether a configuration path is an http(s) URL
+func IsRemote(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+// RemoteCacheDir returns the directory holding fetched configurations
+func RemoteCacheDir() string {
+	return filepath.Join(UserConfigDir(), "cache")
+}
+
+// parseRemote splits a remote configuration path into the URL to fetch and
+// the pinned digest ("" if none)
+func parseRemote(location string) (string, string, error) {
+	target, fragment, _ := strings.Cut(location, "#")
+	if _, err := url.Parse(target); err != nil {
+		return "", "", err
+	}
+	if fragment == "" {
+		return target, "", nil
+	}
+	match := pinPattern.FindStringSubmatch(fragment)
+	if match == nil {
+		return "", "", fmt.Errorf("%s: invalid pin #%s (want #sha256=<64 hex digits>)", target, fragment)
+	}
+	return target, strings.ToLower(match[1]), nil
+}
+
+// layerName is the file name a layer's format is told by: a URL's path,
+// without its query and pin
+func layerName(layer string) string {
+	if !IsRemote(layer) {
+		return layer
+	}
+	target, _, _ := strings.Cut(layer, "#")
+	if u, err := url.Parse(target); err == nil {
+		return path.Base(u.Path)
+	}
+	return target
+}
+
+// readRemote returns the content of a remote configuration, checking its
+// signature when policy is set
+func readRemote(location string, policy bool) ([]byte, error) {
+	target, pin, err := parseRemote(location)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fetchCached(target, pin)
+	if err != nil {
+		return nil, err
+	}
+	if policy && SignedConfigRequired() {
+		key, err := TrustedKey()
+		if err != nil {
+			return nil, fmt.Errorf("signed configuration required: %w", err)
+		}
+		sig, err := fetchCached(target+SignatureExt, "")
+		if err != nil {
+			return nil, fmt.Errorf("signed configuration required: %s: %w (%v)", target, ErrUnsignedConfig, err)
+		}
+		if err := verifySignature(target, data, sig, key); err != nil {
+			return nil, fmt.Errorf("signed configuration required: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// fetchCached returns a remote file from the cache while it is fresh (or
+// matches the pin), fetching it otherwise
+func fetchCached(target, pin string) ([]byte, error) {
+	sum := sha256.Sum256([]byte(target))
+	cache := filepath.Join(RemoteCacheDir(), hex.EncodeToString(sum[:]))
+
+	cached, cacheErr := os.ReadFile(cache)
+	usable := cacheErr == nil && (pin == "" || digest(cached) == pin)
+	if usable {
+		info, err := os.Stat(cache)
+		if pin != "" || (err == nil && time.Since(info.ModTime()) < RemoteCacheTTL) {
+			return cached, nil
+		}
+	}
+
+	data, err := fetchRemote(target)
+	if err != nil {
+		if usable {
+			return cached, nil
+		}
+		return nil, err
+	}
+	if pin != "" && digest(data) != pin {
+		return nil, fmt.Errorf("%s: sha256 %s does not match the pinned %s", target, digest(data), pin)
+	}
+
+	// The cache only saves a download: failing to write it is no error
+	if err := os.MkdirAll(RemoteCacheDir(), 0755); err == nil {
+		tmp := cache + ".tmp"
+		if os.WriteFile(tmp, data, 0644) == nil {
+			os.Rename(tmp, cache)
+		}
+	}
+	return data, nil
+}
+
+// fetchRemote downloads a file through the network settings of the user
+// configuration (proxy, CA bundle)
+func fetchRemote(target string) ([]byte, error) {
+	client, err := RemoteClient(userNetworkSettings())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(target)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", target, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfig+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", target, err)
+	}
+	if len(data) > maxRemoteConfig {
+		return nil, fmt.Errorf("%s: larger than %d MB", target, maxRemoteConfig>>20)
+	}
+	return data, nil
+}
+
+// userNetworkSettings reads settings.network from the user configuration,
+// the remote file being the one that would hold them otherwise
+func userNetworkSettings() NetworkSettings {
+	var user struct {
+		Settings struct {
+			Network NetworkSettings `json:"network"`
+		} `json:"settings"`
+	}
+	if data, err := os.ReadFile(UserConfigPath()); err == nil {
+		json.Unmarshal(data, &user)
+	}
+	return user.Settings.Network
+}
+
+// digest returns the hex sha256 of data
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
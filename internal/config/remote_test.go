@@ -0,0 +1,75 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadRemote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	content := "keywords:\n  - {name: internal, patterns: [corp_token], severity: high}\n"
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(content))
+	}))
+	location := server.URL + "/patterns.yaml"
+
+	config, err := Load(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Keywords) != 1 || config.Keywords[0].Name != "internal" {
+		t.Fatalf("keywords = %+v, want the remote group", config.Keywords)
+	}
+
+	// Fresh: served from the cache
+	if _, err := Load(location); err != nil || requests.Load() != 1 {
+		t.Fatalf("second load: err=%v, %d requests, want 1", err, requests.Load())
+	}
+
+	// The pin must match the digest of the content
+	good := location + "#sha256=" + digest([]byte(content))
+	if _, err := Load(good); err != nil {
+		t.Errorf("pinned load: %v", err)
+	}
+	bad := location + "#sha256=" + strings.Repeat("0", 64)
+	if _, err := Load(bad); err == nil || !strings.Contains(err.Error(), "sha256") {
+		t.Errorf("mismatched pin: err = %v", err)
+	}
+
+	// Stale and the host down: the cached copy is used
+	sum := sha256.Sum256([]byte(location))
+	cache := filepath.Join(RemoteCacheDir(), hex.EncodeToString(sum[:]))
+	old := time.Now().Add(-2 * RemoteCacheTTL)
+	if err := os.Chtimes(cache, old, old); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+	if _, err := Load(location); err != nil {
+		t.Fatalf("offline load with a cached copy: %v", err)
+	}
+}
+
+func TestLayersKeepURLs(t *testing.T) {
+	if os.PathListSeparator != ':' {
+		t.Skip("URLs only hold the list separator where it is a colon")
+	}
+	got := Layers("https://rules.example.com:8443/org.yaml#sha256=ab::repo.json:http://localhost/x.json")
+	want := []string{"https://rules.example.com:8443/org.yaml#sha256=ab", "", "repo.json", "http://localhost/x.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Layers = %q, want %q", got, want)
+	}
+	if IsLayered("https://rules.example.com/patterns.json") {
+		t.Error("a single URL is not layered")
+	}
+}
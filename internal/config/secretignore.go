@@ -0,0 +1,210 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SecretIgnoreFile is the repository-local file of ignore rules, read at the
+// root of every scanned repository
+const SecretIgnoreFile = ".secretignore"
+
+// IgnoreRules are the rules of a .secretignore file, one per line:
+//
+//	fixtures/          paths, gitignore-style ("!" re-includes)
+//	key:test_*         keys, glob, case-insensitive
+//	value:^dummy-      values, regex
+type IgnoreRules struct {
+	paths  []ignorePath
+	keys   []*regexp.Regexp
+	values []*regexp.Regexp
+}
+
+// ignorePath is a gitignore-style path pattern
+type ignorePath struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool // Trailing "/": matches directories only
+}
+
+// LoadSecretIgnore reads the .secretignore file of a repository (nil if it
+// has none). Its rules hide findings: when signed configurations are
+// required, it needs a valid .secretignore.sig like any configuration.
+func LoadSecretIgnore(repoPath string) (*IgnoreRules, error) {
+	path := filepath.Join(repoPath, SecretIgnoreFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPolicy(path, data); err != nil {
+		return nil, err
+	}
+	return ParseSecretIgnore(bytes.NewReader(data), path)
+}
+
+// ParseSecretIgnore reads ignore rules; name locates errors
+func ParseSecretIgnore(r io.Reader, name string) (*IgnoreRules, error) {
+	rules := &IgnoreRules{}
+	lines := bufio.NewScanner(r)
+	n := 0
+	for lines.Scan() {
+		n++
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "key:"):
+			glob := strings.TrimSpace(line[len("key:"):])
+			rules.keys = append(rules.keys, regexp.MustCompile("(?i)^"+globRegexp(glob, false)+"$"))
+		case strings.HasPrefix(line, "value:"):
+			re, err := regexp.Compile(strings.TrimSpace(line[len("value:"):]))
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid value regex: %w", name, n, err)
+			}
+			rules.values = append(rules.values, re)
+		default:
+			rules.paths = append(rules.paths, parseIgnorePath(line))
+		}
+	}
+	return rules, lines.Err()
+}
+
+// parseIgnorePath compiles a gitignore-style pattern. Without a slash
+// (trailing one aside) it matches a name at any depth, otherwise a path from
+// the repository root.
+func parseIgnorePath(pattern string) ignorePath {
+	var p ignorePath
+	if strings.HasPrefix(pattern, "!") {
+		p.negate = true
+		pattern = pattern[1:]
+	}
+	pattern = strings.TrimPrefix(pattern, `\`) // "\!name", "\#name"
+	if strings.HasSuffix(pattern, "/") {
+		p.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	prefix := "(?:.*/)?"
+	if strings.Contains(pattern, "/") {
+		prefix = ""
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+	p.re = regexp.MustCompile("^" + prefix + globRegexp(pattern, true) + "$")
+	return p
+}
+
+// globRegexp translates a glob to a regex. With pathname, "*" and "?" stop
+// at "/" and "**" spans directories.
+func globRegexp(glob string, pathname bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case pathname && strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case pathname && strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*' && pathname:
+			sb.WriteString("[^/]*")
+		case c == '*':
+			sb.WriteString(".*")
+		case c == '?' && pathname:
+			sb.WriteString("[^/]")
+		case c == '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// MatchPath reports whether the rules ignore a file. The last matching
+// pattern wins; a pattern matching a parent directory matches the file.
+func (r *IgnoreRules) MatchPath(filePath string) bool {
+	if r == nil {
+		return false
+	}
+	filePath = strings.ReplaceAll(filePath, "\\", "/")
+	ignored := false
+	for _, p := range r.paths {
+		if p.matches(filePath) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the pattern matches the file or one of its directories
+func (p ignorePath) matches(filePath string) bool {
+	if !p.dirOnly && p.re.MatchString(filePath) {
+		return true
+	}
+	for i := strings.IndexByte(filePath, '/'); i != -1; i = next(filePath, i) {
+		if p.re.MatchString(filePath[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the index of the slash after i, or -1
+func next(s string, i int) int {
+	j := strings.IndexByte(s[i+1:], '/')
+	if j == -1 {
+		return -1
+	}
+	return i + 1 + j
+}
+
+// MatchKey reports whether the rules ignore a key
+func (r *IgnoreRules) MatchKey(key string) bool {
+	if r == nil {
+		return false
+	}
+	for _, re := range r.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchValue reports whether the rules ignore a value
+func (r *IgnoreRules) MatchValue(value string) bool {
+	if r == nil {
+		return false
+	}
+	for _, re := range r.values {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithIgnoreRules returns a copy of the configuration that also applies the
+// rules of a repository (replacing those of the previous one)
+func (c *Config) WithIgnoreRules(rules *IgnoreRules) *Config {
+	merged := *c
+	merged.ignore = rules
+	return &merged
+}
+
+// ShouldIgnoreKey checks if a key is ignored by the repository rules
+func (c *Config) ShouldIgnoreKey(key string) bool {
+	return c.ignore.MatchKey(key)
+}
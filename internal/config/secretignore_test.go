@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretIgnoreRules(t *testing.T) {
+	rules, err := ParseSecretIgnore(strings.NewReader(`
+# Test data
+fixtures/
+!fixtures/real.conf
+*.snap
+/build/out.conf
+docs/**/sample.yml
+key:test_*
+value:^dummy-[0-9]+$
+`), SecretIgnoreFile)
+	if err != nil {
+		t.Fatalf("ParseSecretIgnore: %v", err)
+	}
+
+	paths := map[string]bool{
+		"fixtures/app.conf":       true,
+		"src/fixtures/app.conf":   true,
+		"fixtures/real.conf":      false,
+		"ui/__snapshots__/a.snap": true,
+		"build/out.conf":          true,
+		"src/build/out.conf":      false,
+		"docs/api/v1/sample.yml":  true,
+		"docs/sample.yml":         true,
+		"app.conf":                false,
+	}
+	for path, want := range paths {
+		if got := rules.MatchPath(path); got != want {
+			t.Errorf("MatchPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if !rules.MatchKey("TEST_PASSWORD") || rules.MatchKey("db_password") {
+		t.Error("key:test_* should match test_ keys only, case-insensitive")
+	}
+	if !rules.MatchValue("dummy-42") || rules.MatchValue("dummy-prod") {
+		t.Error("value regex mismatch")
+	}
+
+	cfg := DefaultConfig().WithIgnoreRules(rules)
+	if !cfg.ShouldIgnoreFile("fixtures/app.conf") || !cfg.ShouldIgnoreValue("dummy-42") || !cfg.ShouldIgnoreKey("test_token") {
+		t.Error("rules not merged into the configuration")
+	}
+	if DefaultConfig().ShouldIgnoreValue("dummy-42") {
+		t.Error("the default configuration should not ignore dummy-42")
+	}
+
+	if _, err := ParseSecretIgnore(strings.NewReader("value:(unclosed"), SecretIgnoreFile); err == nil || !strings.Contains(err.Error(), ":1:") {
+		t.Errorf("invalid regex error = %v, want its line", err)
+	}
+}
+
+func TestSecretIgnoreSignaturePolicy(t *testing.T) {
+	pub, priv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := t.TempDir()
+	path := filepath.Join(repo, SecretIgnoreFile)
+	if err := os.WriteFile(path, []byte("value:.*\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if rules, err := LoadSecretIgnore(repo); err != nil || !rules.MatchValue("s3cr3t") {
+		t.Fatalf("without the policy: %v, %v", rules, err)
+	}
+
+	// A repository cannot hide its findings from a signed configuration
+	t.Setenv("GITSECRET_REQUIRE_SIGNED_CONFIG", "1")
+	t.Setenv("GITSECRET_CONFIG_PUBKEY", pub)
+	if _, err := LoadSecretIgnore(repo); !errors.Is(err, ErrUnsignedConfig) {
+		t.Fatalf("unsigned %s: got %v, want ErrUnsignedConfig", SecretIgnoreFile, err)
+	}
+
+	if err := SignFile(path, priv); err != nil {
+		t.Fatal(err)
+	}
+	if rules, err := LoadSecretIgnore(repo); err != nil || !rules.MatchValue("s3cr3t") {
+		t.Fatalf("signed %s: %v, %v", SecretIgnoreFile, rules, err)
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestSeverityForType(t *testing.T) {
+	cfg := DefaultConfig()
+
+	testCases := []struct {
+		findingType string
+		expected    string
+	}{
+		{"private_key", SeverityCritical},
+		{"rsa_private", SeverityCritical},
+		{"password", SeverityHigh},
+		{"PWD", SeverityHigh},
+		{"bearer", SeverityMedium},
+		{"unknown_type", SeverityLow},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.findingType, func(t *testing.T) {
+			if got := cfg.SeverityForType(tc.findingType); got != tc.expected {
+				t.Errorf("Expected severity '%s' for '%s', got '%s'", tc.expected, tc.findingType, got)
+			}
+		})
+	}
+}
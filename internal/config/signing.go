@@ -0,0 +1,135 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Signature policy. Both values can be baked into a wrapper build with
+// -ldflags "-X github.com/Drilmo/git-secret-scanner/internal/config.requireSignedConfig=true
+// -X github.com/Drilmo/git-secret-scanner/internal/config.trustedConfigKey=<base64 public key>"
+// or provided through GITSECRET_REQUIRE_SIGNED_CONFIG and GITSECRET_CONFIG_PUBKEY.
+var (
+	requireSignedConfig string
+	trustedConfigKey    string
+)
+
+// ErrUnsignedConfig is returned when the policy requires a signature and the config has none
+var ErrUnsignedConfig = errors.New("configuration is not signed")
+
+// SignatureExt is appended to a config path to find its detached signature
+const SignatureExt = ".sig"
+
+// RequireSignedConfig enables the signature policy for this process (e.g. from a command-line flag)
+func RequireSignedConfig() {
+	requireSignedConfig = "true"
+}
+
+// SignedConfigRequired reports whether configuration files must carry a valid signature
+func SignedConfigRequired() bool {
+	if v := os.Getenv("GITSECRET_REQUIRE_SIGNED_CONFIG"); v != "" {
+		// The environment can enable the policy but never lift a built-in one
+		if v == "1" || strings.EqualFold(v, "true") {
+			return true
+		}
+	}
+	return requireSignedConfig == "true"
+}
+
+// TrustedKey returns the public key configuration signatures are checked against
+func TrustedKey() (ed25519.PublicKey, error) {
+	encoded := trustedConfigKey
+	if encoded == "" {
+		encoded = os.Getenv("GITSECRET_CONFIG_PUBKEY")
+	}
+	if encoded == "" {
+		return nil, errors.New("no trusted config key (set GITSECRET_CONFIG_PUBKEY)")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid trusted config key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// GenerateSigningKey creates a new key pair, both base64-encoded
+func GenerateSigningKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv), nil
+}
+
+// SignFile writes the detached signature of path to path+SignatureExt
+func SignFile(path, privateKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return errors.New("invalid signing key")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	sig := ed25519.Sign(ed25519.PrivateKey(key), data)
+	return os.WriteFile(path+SignatureExt, []byte(base64.StdEncoding.EncodeToString(sig)+"\n"), 0644)
+}
+
+// VerifyFile checks the detached signature of path against the trusted key
+func VerifyFile(path string) error {
+	key, err := TrustedKey()
+	if err != nil {
+		return err
+	}
+	return verifyData(path, nil, key)
+}
+
+// verifyData checks data (read from path when nil) against path's signature
+func verifyData(path string, data []byte, key ed25519.PublicKey) error {
+	encoded, err := os.ReadFile(path + SignatureExt)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s: %w", path, ErrUnsignedConfig)
+	}
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		if data, err = os.ReadFile(path); err != nil {
+			return err
+		}
+	}
+	return verifySignature(path, data, encoded, key)
+}
+
+// verifySignature checks data against an encoded signature, path naming
+// the file in errors
+func verifySignature(path string, data, encoded []byte, key ed25519.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return fmt.Errorf("%s: malformed signature", path)
+	}
+	if !ed25519.Verify(key, data, sig) {
+		return fmt.Errorf("%s: signature does not match (file modified or signed with another key)", path)
+	}
+	return nil
+}
+
+// checkPolicy enforces the signature policy on config data loaded from path
+func checkPolicy(path string, data []byte) error {
+	if !SignedConfigRequired() {
+		return nil
+	}
+	key, err := TrustedKey()
+	if err != nil {
+		return fmt.Errorf("signed configuration required: %w", err)
+	}
+	if err := verifyData(path, data, key); err != nil {
+		return fmt.Errorf("signed configuration required: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSignedConfigPolicy(t *testing.T) {
+	pub, priv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITSECRET_REQUIRE_SIGNED_CONFIG", "1")
+	t.Setenv("GITSECRET_CONFIG_PUBKEY", pub)
+
+	path := filepath.Join(t.TempDir(), "patterns.json")
+	if err := DefaultConfig().Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); !errors.Is(err, ErrUnsignedConfig) {
+		t.Fatalf("unsigned config: got %v, want ErrUnsignedConfig", err)
+	}
+
+	if err := SignFile(path, priv); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("signed config rejected: %v", err)
+	}
+
+	// Weakening the config locally invalidates the signature
+	cfg := DefaultConfig()
+	cfg.Keywords = cfg.Keywords[:1]
+	cfg.Save(path)
+	if _, err := Load(path); err == nil {
+		t.Fatal("modified config was accepted")
+	}
+
+	// Built-in defaults never need a signature
+	os.Remove(path)
+	if _, err := Load(""); err != nil {
+		t.Fatalf("defaults rejected: %v", err)
+	}
+}
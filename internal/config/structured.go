@@ -0,0 +1,286 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the files parsed as a tree when settings.structuredFiles is on
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+	FormatINI  = "ini"
+)
+
+// structuredExtensions maps file extensions to their format
+var structuredExtensions = map[string]string{
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".json": FormatJSON,
+	".toml": FormatTOML,
+	".ini":  FormatINI,
+	".cfg":  FormatINI,
+	".cnf":  FormatINI,
+}
+
+// StructuredValue is a scalar of a structured file with the keys leading
+// to it
+type StructuredValue struct {
+	Path  string // db.credentials.password, servers[0].token
+	Key   string // Last key of the path (password, token)
+	Value string
+	Line  int // Line number in the file (0 if unknown)
+}
+
+// StructuredFormat returns the format of a YAML, JSON, TOML or INI file
+// ("" for other files)
+func StructuredFormat(filePath string) string {
+	ext := strings.ToLower(path.Ext(strings.ReplaceAll(filePath, "\\", "/")))
+	return structuredExtensions[ext]
+}
+
+// ParseStructured walks a file of the given format and returns its scalar
+// values in file order. Values are decoded (quotes and escapes removed);
+// booleans and nulls are left out. An error means the file is not valid.
+func ParseStructured(format string, data []byte) ([]StructuredValue, error) {
+	var values []StructuredValue
+	var err error
+	switch format {
+	case FormatYAML:
+		values, err = parseYAMLValues(data)
+	case FormatJSON:
+		values, err = parseJSONValues(data)
+	case FormatTOML:
+		values, err = parseTOMLValues(data)
+	case FormatINI:
+		values, err = parseINIValues(data)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Line < values[j].Line })
+	return values, nil
+}
+
+// joinPath appends a key to a path
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// itemPath is the path of a list item
+func itemPath(prefix string, i int) string {
+	return prefix + "[" + strconv.Itoa(i) + "]"
+}
+
+// parseYAMLValues walks every document of a YAML stream. Aliases and merge
+// keys are skipped: their values are reported where they are defined.
+func parseYAMLValues(data []byte) ([]StructuredValue, error) {
+	var values []StructuredValue
+	var walk func(node *yaml.Node, prefix, key string)
+	walk = func(node *yaml.Node, prefix, key string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, prefix, key)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				name := node.Content[i].Value
+				if name == "<<" {
+					continue
+				}
+				walk(node.Content[i+1], joinPath(prefix, name), name)
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				walk(child, itemPath(prefix, i), key)
+			}
+		case yaml.ScalarNode:
+			if node.Tag == "!!null" || node.Tag == "!!bool" || prefix == "" {
+				return
+			}
+			values = append(values, StructuredValue{Path: prefix, Key: key, Value: node.Value, Line: node.Line})
+		}
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		walk(&doc, "", "")
+	}
+}
+
+// parseJSONValues walks a JSON document token by token, to keep the file
+// order and the line of each value
+func parseJSONValues(data []byte) ([]StructuredValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	// Lines are counted from the last value on, the offsets only grow
+	line, counted := 1, 0
+	lineAt := func(offset int64) int {
+		line += bytes.Count(data[counted:offset], []byte{'\n'})
+		counted = int(offset)
+		return line
+	}
+
+	var values []StructuredValue
+	var walk func(prefix, key string) error
+	walk = func(prefix, key string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{':
+				for dec.More() {
+					name, err := dec.Token()
+					if err != nil {
+						return err
+					}
+					if err := walk(joinPath(prefix, name.(string)), name.(string)); err != nil {
+						return err
+					}
+				}
+			case '[':
+				for i := 0; dec.More(); i++ {
+					if err := walk(itemPath(prefix, i), key); err != nil {
+						return err
+					}
+				}
+			}
+			_, err := dec.Token() // Closing delimiter
+			return err
+		case string:
+			if prefix != "" {
+				values = append(values, StructuredValue{Path: prefix, Key: key, Value: t, Line: lineAt(dec.InputOffset())})
+			}
+		case json.Number:
+			if prefix != "" {
+				values = append(values, StructuredValue{Path: prefix, Key: key, Value: t.String(), Line: lineAt(dec.InputOffset())})
+			}
+		}
+		return nil
+	}
+
+	if err := walk("", ""); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: data after the top-level value")
+	}
+	return values, nil
+}
+
+// parseTOMLValues walks a TOML document. The decoder does not keep the
+// position of values: each is given the first line holding both its key
+// and its text.
+func parseTOMLValues(data []byte) ([]StructuredValue, error) {
+	var doc map[string]any
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, err
+	}
+
+	var values []StructuredValue
+	var walk func(v any, prefix, key string)
+	walk = func(v any, prefix, key string) {
+		switch t := v.(type) {
+		case map[string]any:
+			names := make([]string, 0, len(t))
+			for name := range t {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				walk(t[name], joinPath(prefix, name), name)
+			}
+		case []map[string]any:
+			for i, item := range t {
+				walk(item, itemPath(prefix, i), key)
+			}
+		case []any:
+			for i, item := range t {
+				walk(item, itemPath(prefix, i), key)
+			}
+		case string:
+			values = append(values, StructuredValue{Path: prefix, Key: key, Value: t})
+		case int64:
+			values = append(values, StructuredValue{Path: prefix, Key: key, Value: strconv.FormatInt(t, 10)})
+		}
+	}
+	walk(doc, "", "")
+
+	lines := strings.Split(string(data), "\n")
+	used := make(map[int]bool)
+	for i := range values {
+		v := &values[i]
+		for n, line := range lines {
+			if !used[n] && strings.Contains(line, v.Key) && strings.Contains(line, v.Value) {
+				v.Line = n + 1
+				used[n] = true
+				break
+			}
+		}
+	}
+	return values, nil
+}
+
+// parseINIValues reads an INI file: [sections], key = value or key: value
+// assignments, and ; or # comments. Any other line (continuation lines
+// included) makes the file invalid.
+func parseINIValues(data []byte) ([]StructuredValue, error) {
+	var values []StructuredValue
+	section := ""
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("invalid INI: line %d: unterminated section", n+1)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		if sep <= 0 {
+			return nil, fmt.Errorf("invalid INI: line %d: not a section or an assignment", n+1)
+		}
+		key := strings.TrimSpace(line[:sep])
+		value := strings.TrimSpace(line[sep+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		if value == "" {
+			continue
+		}
+		values = append(values, StructuredValue{Path: joinPath(section, key), Key: key, Value: value, Line: n + 1})
+	}
+	return values, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStructured(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+		want   []StructuredValue
+	}{
+		{FormatYAML, "db:\n  credentials:\n    password: \"s3cr#t: x\"\n    enabled: true\nservers:\n  - token: abc123\n", []StructuredValue{
+			{Path: "db.credentials.password", Key: "password", Value: "s3cr#t: x", Line: 3},
+			{Path: "servers[0].token", Key: "token", Value: "abc123", Line: 6},
+		}},
+		{FormatJSON, "{\"db\": {\n  \"password\": \"p@ss\",\n  \"port\": 5432, \"tls\": null},\n \"keys\": [\"k1\"]}", []StructuredValue{
+			{Path: "db.password", Key: "password", Value: "p@ss", Line: 2},
+			{Path: "db.port", Key: "port", Value: "5432", Line: 3},
+			{Path: "keys[0]", Key: "keys", Value: "k1", Line: 4},
+		}},
+		{FormatTOML, "[database]\nuser = \"app\"\npassword = 'hunter22'\n", []StructuredValue{
+			{Path: "database.user", Key: "user", Value: "app", Line: 2},
+			{Path: "database.password", Key: "password", Value: "hunter22", Line: 3},
+		}},
+		{FormatINI, "; comment\n[client]\npassword = \"a=b\"\nhost: localhost\n", []StructuredValue{
+			{Path: "client.password", Key: "password", Value: "a=b", Line: 3},
+			{Path: "client.host", Key: "host", Value: "localhost", Line: 4},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := ParseStructured(tt.format, []byte(tt.data))
+		if err != nil {
+			t.Errorf("%s: %v", tt.format, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tt.format, got, tt.want)
+		}
+	}
+
+	for format, data := range map[string]string{
+		FormatYAML: "a: [b\n",
+		FormatJSON: "{\"a\": 1} {}",
+		FormatTOML: "a = \n",
+		FormatINI:  "server {\n  listen 80;\n}\n",
+	} {
+		if _, err := ParseStructured(format, []byte(data)); err == nil {
+			t.Errorf("%s: invalid file %q accepted", format, data)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// TerraformType is the finding type of sensitive Terraform attributes whose
+// name contains no keyword (access_key, connection_string...)
+const TerraformType = "terraform"
+
+// terraformSuffixes are the state and variable files Terraform writes
+var terraformSuffixes = []string{".tfstate", ".tfstate.backup", ".tfvars", ".tfvars.json"}
+
+// terraformSensitive are fragments of provider attribute names holding
+// credentials (aws_iam_access_key.secret, azurerm_storage_account
+// .primary_connection_string, tls_private_key.private_key_pem...)
+var terraformSensitive = []string{
+	"password", "secret", "access_key", "private_key", "token",
+	"connection_string", "primary_key", "secondary_key", "client_key",
+	"kube_config", "kubeconfig", "bcrypt_hash", "sas_url",
+}
+
+// IsTerraformFile reports whether a path is a Terraform state or variables
+// file: terraform.tfstate, *.tfstate.backup, *.tfvars, *.tfvars.json
+func IsTerraformFile(filePath string) bool {
+	base := strings.ToLower(path.Base(strings.ReplaceAll(filePath, "\\", "/")))
+	for _, suffix := range terraformSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitiveTerraformAttribute reports whether an attribute (or output)
+// name denotes a credential
+func IsSensitiveTerraformAttribute(name string) bool {
+	name = strings.ToLower(name)
+	for _, fragment := range terraformSensitive {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// TerraformLine is one line of a state file (JSON, one attribute per line)
+// or variables file (HCL or JSON)
+type TerraformLine struct {
+	Key    string // Attribute or object name, "" for list items
+	Value  string // String value as written (escapes kept, so it can be cleaned)
+	String bool   // The line assigns a string value
+	Opens  bool   // The line opens an object or a list
+	Closes bool   // The line closes an object or a list
+}
+
+// ParseTerraformLine parses `"key": "value",`, `key = "value"`, and the
+// lines that open (`"key": {`, `key = [`) or close (`},`) a block
+func ParseTerraformLine(line string) TerraformLine {
+	line = strings.TrimSpace(line)
+	var tl TerraformLine
+	if line == "" || line[0] == '#' || strings.HasPrefix(line, "//") {
+		return tl
+	}
+	if line[0] == '}' || line[0] == ']' {
+		tl.Closes = true
+		return tl
+	}
+	if last := line[len(line)-1]; last == '{' || last == '[' {
+		tl.Opens = true
+	}
+
+	// Key: quoted (JSON, HCL map keys) or bare (HCL), then ":" or "="
+	rest := line
+	if rest[0] == '"' {
+		end := strings.IndexByte(rest[1:], '"')
+		if end == -1 {
+			return tl
+		}
+		tl.Key, rest = rest[1:end+1], rest[end+2:]
+	} else {
+		end := strings.IndexAny(rest, " =:")
+		if end == -1 {
+			return tl
+		}
+		tl.Key, rest = rest[:end], rest[end:]
+	}
+	rest = strings.TrimSpace(rest)
+	if rest == "" || (rest[0] != ':' && rest[0] != '=') {
+		tl.Key = ""
+		return tl
+	}
+	rest = strings.TrimSpace(rest[1:])
+
+	if rest == "" || rest[0] != '"' {
+		return tl
+	}
+	for i := 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++ // Escaped character
+		case '"':
+			tl.Value, tl.String = rest[1:i], true
+			return tl
+		}
+	}
+	return tl
+}
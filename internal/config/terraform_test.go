@@ -0,0 +1,25 @@
+package config
+
+import "testing"
+
+func TestParseTerraformLine(t *testing.T) {
+	cases := []struct {
+		line string
+		want TerraformLine
+	}{
+		{`      "password": "s3cr\"et",`, TerraformLine{Key: "password", Value: `s3cr\"et`, String: true}},
+		{`db_password = "s3cret" # prod`, TerraformLine{Key: "db_password", Value: "s3cret", String: true}},
+		{`  "outputs": {`, TerraformLine{Key: "outputs", Opens: true}},
+		{`storage = {`, TerraformLine{Key: "storage", Opens: true}},
+		{`resource "aws_db_instance" "main" {`, TerraformLine{Opens: true}},
+		{`    {`, TerraformLine{Opens: true}},
+		{`  ],`, TerraformLine{Closes: true}},
+		{`  "version": 4,`, TerraformLine{Key: "version"}},
+		{`# password = "old"`, TerraformLine{}},
+	}
+	for _, tc := range cases {
+		if got := ParseTerraformLine(tc.line); got != tc.want {
+			t.Errorf("ParseTerraformLine(%q) = %+v, want %+v", tc.line, got, tc.want)
+		}
+	}
+}
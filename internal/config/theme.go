@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TUI themes (settings.theme)
+const (
+	ThemeDark         = "dark" // Default: the purple look on a dark terminal
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast" // Bright colors, bold text, no gray
+)
+
+// Themes lists the values of settings.theme
+var Themes = []string{ThemeDark, ThemeLight, ThemeHighContrast}
+
+// ThemeColors overrides colors of the TUI theme (settings.themeColors). Each
+// is a hex color ("#7C3AED", "#FFF") or an ANSI color number (0 to 255).
+type ThemeColors struct {
+	Primary   string `json:"primary,omitempty"`   // Titles, borders, keys and selection
+	Secondary string `json:"secondary,omitempty"` // Success
+	Danger    string `json:"danger,omitempty"`    // Errors
+	Warning   string `json:"warning,omitempty"`
+	Muted     string `json:"muted,omitempty"` // Help and secondary text
+	Text      string `json:"text,omitempty"`  // Values
+	Critical  string `json:"critical,omitempty"`
+	High      string `json:"high,omitempty"`
+	Medium    string `json:"medium,omitempty"`
+	Low       string `json:"low,omitempty"`
+}
+
+// Named returns the colors set, by their key in settings.themeColors
+func (c ThemeColors) Named() map[string]string {
+	named := make(map[string]string)
+	for name, value := range map[string]string{
+		"primary": c.Primary, "secondary": c.Secondary, "danger": c.Danger, "warning": c.Warning,
+		"muted": c.Muted, "text": c.Text, SeverityCritical: c.Critical, SeverityHigh: c.High,
+		SeverityMedium: c.Medium, SeverityLow: c.Low,
+	} {
+		if value != "" {
+			named[name] = value
+		}
+	}
+	return named
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether a terminal can show a color as written
+func validColor(color string) bool {
+	if hexColor.MatchString(color) {
+		return true
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// validateTheme rejects unknown settings.theme values and colors of
+// settings.themeColors a terminal cannot show
+func (c *Config) validateTheme() error {
+	if c.Settings.Theme != "" && !slices.Contains(Themes, c.Settings.Theme) {
+		return fmt.Errorf("invalid settings.theme %q (%s)", c.Settings.Theme, strings.Join(Themes, ", "))
+	}
+	named := c.Settings.ThemeColors.Named()
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if !validColor(named[name]) {
+			return fmt.Errorf("invalid settings.themeColors.%s %q (#RGB, #RRGGBB or an ANSI color from 0 to 255)", name, named[name])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTheme(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Settings.Theme = ThemeLight
+	cfg.Settings.ThemeColors = ThemeColors{Primary: "#005F87", Muted: "240", Critical: "#F00"}
+	if err := cfg.validateTheme(); err != nil {
+		t.Errorf("valid theme rejected: %v", err)
+	}
+
+	for _, tc := range []struct {
+		theme  string
+		colors ThemeColors
+		want   string
+	}{
+		{theme: "solarized", want: "settings.theme"},
+		{colors: ThemeColors{Primary: "purple"}, want: "settings.themeColors.primary"},
+		{colors: ThemeColors{Low: "256"}, want: "settings.themeColors.low"},
+		{colors: ThemeColors{Text: "#12345"}, want: "settings.themeColors.text"},
+	} {
+		cfg.Settings.Theme, cfg.Settings.ThemeColors = tc.theme, tc.colors
+		if err := cfg.validateTheme(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("theme %q colors %+v: error %v, want one about %s", tc.theme, tc.colors, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Levels of the problems found by Validate
+const (
+	IssueError   = "error"   // The rule cannot work as written
+	IssueWarning = "warning" // The rule works but does nothing, or less than it says
+)
+
+// Issue is a problem found in a configuration
+type Issue struct {
+	Level   string // IssueError or IssueWarning
+	Field   string // Where: extractionPatterns[2] (aws_key), ignoredValues[0]
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Level, i.Field, i.Message)
+}
+
+// ValidateFile reads a configuration file without the checks of Load, so
+// every problem is reported rather than the first. An error means the file
+// cannot be read or decoded. An empty path validates the built-in defaults;
+// a layered path (see Layers) validates the merged configuration.
+func ValidateFile(path string) ([]Issue, error) {
+	config, err := readLayers(path, false)
+	if err != nil {
+		return nil, err
+	}
+	return config.Validate(), nil
+}
+
+// Validate reports the rules of the configuration that are invalid, and the
+// ones that can never take effect: extraction patterns that do not compile
+// (GetCompiledPatterns skips them) or have no value group, empty keyword
+// groups, ignored values and files that no value or path can reach.
+func (c *Config) Validate() []Issue {
+	var issues []Issue
+	add := func(level, field, format string, args ...any) {
+		issues = append(issues, Issue{Level: level, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, check := range []func() error{c.validateAuthors, c.validateLocale, c.validateLanguage, c.validateLargeFiles, c.validateWorkspace, c.validateTheme} {
+		if err := check(); err != nil {
+			add(IssueError, "settings", "%v", err)
+		}
+	}
+	if c.Settings.MinSecretLength > c.Settings.MaxSecretLength {
+		add(IssueError, "settings", "minSecretLength (%d) is above maxSecretLength (%d): every value is ignored",
+			c.Settings.MinSecretLength, c.Settings.MaxSecretLength)
+	}
+
+	for i, ep := range c.ExtractionPatterns {
+		field := fmt.Sprintf("extractionPatterns[%d] (%s)", i, ep.Name)
+		if strings.TrimSpace(ep.Pattern) == "" {
+			add(IssueError, field, "empty pattern")
+			continue
+		}
+		regex, err := regexp.Compile(ep.Pattern)
+		if err != nil {
+			add(IssueError, field, "invalid regex, the pattern is skipped: %v", err)
+			continue
+		}
+		if ep.ValueGroup < 0 || ep.ValueGroup > regex.NumSubexp() {
+			add(IssueError, field, "valueGroup %d out of range: the pattern has %d groups", ep.ValueGroup, regex.NumSubexp())
+		}
+	}
+
+	for i, group := range c.Keywords {
+		field := fmt.Sprintf("keywords[%d] (%s)", i, group.Name)
+		if group.Severity != "" && SeverityRank(group.Severity) == len(Severities) {
+			add(IssueError, field, "invalid severity %q (critical, high, medium or low)", group.Severity)
+		}
+		if _, err := group.ValueRegexp(); err != nil {
+			add(IssueError, field, "invalid valuePattern: %v", err)
+		}
+		empty := true
+		for _, p := range group.Patterns {
+			if strings.TrimSpace(p) == "" {
+				add(IssueWarning, field, "empty pattern")
+				continue
+			}
+			empty = false
+			if group.Regex {
+				if _, err := c.KeywordRegexp(p); err != nil {
+					add(IssueError, field, "invalid pattern %q: %v", p, err)
+				}
+			}
+		}
+		if empty {
+			add(IssueWarning, field, "no patterns: the group matches nothing")
+		}
+	}
+
+	for _, name := range c.ProfileNames() {
+		if _, err := c.WithProfile(name); err != nil {
+			add(IssueError, fmt.Sprintf("profiles.%s", name), "%v", err)
+		}
+	}
+
+	c.validateIgnoredValues(add)
+	c.validateIgnoredFiles(add)
+	return issues
+}
+
+// validateIgnoredValues reports ignored values that ignore everything, or
+// that no candidate value can reach: longer than maxSecretLength, or
+// containing an other ignored value (which ignores the same values first).
+// The built-in values are not reported: they cover both case settings.
+func (c *Config) validateIgnoredValues(add func(level, field, format string, args ...any)) {
+	fold := func(s string) string {
+		if c.Settings.CaseSensitive {
+			return s
+		}
+		return toLower(s)
+	}
+	builtin := make(map[string]bool)
+	for _, v := range DefaultConfig().IgnoredValues {
+		builtin[v] = true
+	}
+	for i, v := range c.IgnoredValues {
+		field := fmt.Sprintf("ignoredValues[%d]", i)
+		if v == "" {
+			add(IssueWarning, field, "empty value: every value is ignored")
+			continue
+		}
+		if len(v) > c.Settings.MaxSecretLength {
+			add(IssueWarning, field, "%q is longer than maxSecretLength (%d): unreachable", v, c.Settings.MaxSecretLength)
+			continue
+		}
+		for j, other := range c.IgnoredValues {
+			if builtin[v] || other == "" || i == j || !contains(fold(v), fold(other)) {
+				continue
+			}
+			if fold(v) != fold(other) || j < i {
+				add(IssueWarning, field, "%q is already ignored by %q (ignoredValues[%d])", v, other, j)
+				break
+			}
+		}
+	}
+}
+
+// validateIgnoredFiles reports ignored file patterns matchPattern can never
+// match: paths are relative, and other globs than *.ext, dir/ and dir/**
+// are compared as exact paths
+func (c *Config) validateIgnoredFiles(add func(level, field, format string, args ...any)) {
+	seen := make(map[string]int)
+	for i, pattern := range c.IgnoredFiles {
+		field := fmt.Sprintf("ignoredFiles[%d]", i)
+		switch {
+		case pattern == "":
+			add(IssueWarning, field, "empty pattern: matches nothing")
+		case strings.HasPrefix(pattern, "**"):
+			add(IssueWarning, field, "%q matches every file: only the part before ** is compared, as a prefix", pattern)
+		case strings.HasPrefix(pattern, "/") || filepath.IsAbs(pattern):
+			add(IssueWarning, field, "%q is absolute: scanned paths are relative to the repository", pattern)
+		case !strings.Contains(pattern, "**") && !strings.HasPrefix(pattern, "*.") &&
+			strings.ContainsAny(pattern, "*?["):
+			add(IssueWarning, field, "%q is compared as an exact path: only *.ext, dir/ and dir/** are patterns", pattern)
+		case strings.HasPrefix(pattern, "*.") && strings.ContainsAny(pattern[2:], "*?["):
+			add(IssueWarning, field, "%q is an extension pattern: the rest is matched literally", pattern)
+		}
+		if j, ok := seen[pattern]; ok && pattern != "" {
+			add(IssueWarning, field, "%q repeats ignoredFiles[%d]", pattern, j)
+		}
+		if _, ok := seen[pattern]; !ok {
+			seen[pattern] = i
+		}
+	}
+}
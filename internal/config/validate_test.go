@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	if issues := DefaultConfig().Validate(); len(issues) != 0 {
+		t.Errorf("defaults have problems: %v", issues)
+	}
+
+	path := filepath.Join(t.TempDir(), "patterns.yaml")
+	data := `extractionPatterns:
+  - {name: broken, pattern: "([a-z]+", valueGroup: 1}
+  - {name: group, pattern: "(\\w+)=(\\w+)", valueGroup: 3}
+  - {name: ok, pattern: "(\\w+)=(\\w+)", valueGroup: 2}
+keywords:
+  - {name: empty, patterns: []}
+  - {name: aws, regex: true, patterns: ["aws_(", "aws"], severity: urgent}
+ignoredValues: [test, test123, ""]
+ignoredFiles: ["*.md", "test_*.py", "**/fixtures", "*.md"]
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load accepted the invalid configuration")
+	}
+	issues, err := ValidateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"error: extractionPatterns[0] (broken): invalid regex",
+		"error: extractionPatterns[1] (group): valueGroup 3 out of range",
+		"warning: keywords[0] (empty): no patterns",
+		`error: keywords[1] (aws): invalid severity "urgent"`,
+		`error: keywords[1] (aws): invalid pattern "aws_("`,
+		`warning: ignoredValues[1]: "test123" is already ignored by "test"`,
+		"warning: ignoredValues[2]: empty value",
+		`warning: ignoredFiles[1]: "test_*.py" is compared as an exact path`,
+		`warning: ignoredFiles[2]: "**/fixtures" matches every file`,
+		`warning: ignoredFiles[3]: "*.md" repeats ignoredFiles[0]`,
+	}
+	if len(issues) != len(want) {
+		t.Errorf("got %d issues, want %d: %v", len(issues), len(want), issues)
+	}
+	for i := 0; i < len(issues) && i < len(want); i++ {
+		if !strings.HasPrefix(issues[i].String(), want[i]) {
+			t.Errorf("issue %d = %q, want %q...", i, issues[i], want[i])
+		}
+	}
+}
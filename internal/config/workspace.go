@@ -0,0 +1,22 @@
+package config
+
+import "fmt"
+
+// DefaultKeepScans is how many scans a workspace keeps per repository when
+// settings.workspace.keepScans is not set
+const DefaultKeepScans = 10
+
+// WorkspaceSettings is the retention policy of the workspaces, the per
+// repository directories receiving scans and reports (settings.workspace)
+type WorkspaceSettings struct {
+	KeepScans  int `json:"keepScans,omitempty"`  // Most recent scans kept (DefaultKeepScans)
+	MaxAgeDays int `json:"maxAgeDays,omitempty"` // Older scans and reports are removed (0: no age limit)
+}
+
+// validateWorkspace rejects negative retention values
+func (c *Config) validateWorkspace() error {
+	if c.Settings.Workspace.KeepScans < 0 || c.Settings.Workspace.MaxAgeDays < 0 {
+		return fmt.Errorf("settings.workspace.keepScans and settings.workspace.maxAgeDays cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+// Package debugbundle records what a run of gitsecret did, for bug reports:
+// the command line, the external commands with their timings, exit codes and
+// error output, the steps of the run and the versions of git and the
+// cleaning tools. Values are never recorded: the values being cleaned are
+// registered with Redact and masked, credentials in URLs are masked and long
+// arguments (patterns, callbacks) are left out.
+package debugbundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bundle archive layout
+const (
+	InvocationFile  = "invocation.json"
+	EnvironmentFile = "environment.json"
+	CommandsFile    = "commands.jsonl"
+	EventsFile      = "events.log"
+)
+
+const (
+	maxArgLength = 256       // Longer arguments are left out
+	maxStderr    = 16 * 1024 // Tail of the error output kept per command
+	minRedacted  = 4         // Shorter registered values are not masked
+)
+
+// Command is an external command run during the recording
+type Command struct {
+	Args       []string `json:"args"`
+	Dir        string   `json:"dir,omitempty"`
+	Start      string   `json:"start"`
+	DurationMS int64    `json:"durationMs"`
+	ExitCode   int      `json:"exitCode"` // -1 if it could not run
+	Error      string   `json:"error,omitempty"`
+	Stderr     string   `json:"stderr,omitempty"`
+}
+
+// Invocation describes the recorded run
+type Invocation struct {
+	Args       []string `json:"args"`
+	Version    string   `json:"version"`
+	Dir        string   `json:"dir"`
+	Start      string   `json:"start"`
+	DurationMS int64    `json:"durationMs"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// Environment describes the machine and the tools available
+type Environment struct {
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	GoVersion string            `json:"goVersion"`
+	Tools     map[string]string `json:"tools"`            // Version output, or the error
+	EnvSet    []string          `json:"envSet,omitempty"` // GITSECRET_* variables set (names only)
+	CPUs      int               `json:"cpus"`
+}
+
+// recorder holds what was recorded so far
+type recorder struct {
+	mu       sync.Mutex
+	path     string
+	version  string
+	args     []string
+	start    time.Time
+	commands []Command
+	events   []string
+	secrets  []string
+}
+
+var (
+	activeMu sync.Mutex
+	active   *recorder
+)
+
+// Start records the run into a zip written by Finish. args is the command
+// line, version the gitsecret version.
+func Start(path, version string, args []string) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	active = &recorder{path: path, version: version, args: append([]string{}, args...), start: time.Now()}
+}
+
+// Active reports whether the run is being recorded
+func Active() bool {
+	return current() != nil
+}
+
+func current() *recorder {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	return active
+}
+
+// Redact registers values that must not appear in the bundle
+func Redact(values ...string) {
+	r := current()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range values {
+		if len(v) >= minRedacted {
+			r.secrets = append(r.secrets, v)
+		}
+	}
+}
+
+// Track records an external command. It is called before the command starts
+// (to keep the tail of its error output); the returned function is called
+// with the result once it has exited.
+func Track(cmd *exec.Cmd) func(err error) {
+	r := current()
+	if r == nil {
+		return func(error) {}
+	}
+	stderr := &tailBuffer{max: maxStderr}
+	if cmd.Stderr == nil {
+		cmd.Stderr = stderr
+	} else {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, stderr)
+	}
+	start := time.Now()
+
+	return func(err error) {
+		c := Command{
+			Args:       cmd.Args,
+			Dir:        cmd.Dir,
+			Start:      start.Format(time.RFC3339Nano),
+			DurationMS: time.Since(start).Milliseconds(),
+		}
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+		case errors.As(err, &exitErr):
+			c.ExitCode = exitErr.ExitCode()
+			c.Error = err.Error()
+		default:
+			c.ExitCode = -1
+			c.Error = err.Error()
+		}
+		c.Stderr = stderr.String()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.commands = append(r.commands, c)
+	}
+}
+
+// Step records the start of a step of the run; the returned function
+// records its end
+func Step(name string) func(err error) {
+	r := current()
+	if r == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	r.event("%s: started", name)
+	return func(err error) {
+		if err != nil {
+			r.event("%s: failed after %s: %v", name, time.Since(start).Round(time.Millisecond), err)
+			return
+		}
+		r.event("%s: done in %s", name, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// Notef records a line in the event log
+func Notef(format string, args ...any) {
+	if r := current(); r != nil {
+		r.event(format, args...)
+	}
+}
+
+func (r *recorder) event(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf("+%.3fs %s", time.Since(r.start).Seconds(), fmt.Sprintf(format, args...)))
+}
+
+// Finish writes the bundle with the outcome of the run and stops the
+// recording. It returns the path written, or "" when nothing was recorded.
+func Finish(runErr error) (string, error) {
+	activeMu.Lock()
+	r := active
+	active = nil
+	activeMu.Unlock()
+	if r == nil {
+		return "", nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sanitize := r.sanitizer()
+
+	dir, _ := os.Getwd()
+	inv := Invocation{
+		Args:       sanitizeArgs(r.args, sanitize),
+		Version:    r.version,
+		Dir:        sanitize(dir),
+		Start:      r.start.Format(time.RFC3339),
+		DurationMS: time.Since(r.start).Milliseconds(),
+	}
+	if runErr != nil {
+		inv.Error = sanitize(runErr.Error())
+	}
+
+	var commands bytes.Buffer
+	for _, c := range r.commands {
+		c.Args = sanitizeArgs(c.Args, sanitize)
+		c.Dir = sanitize(c.Dir)
+		c.Error = sanitize(c.Error)
+		c.Stderr = sanitize(c.Stderr)
+		line, err := json.Marshal(c)
+		if err != nil {
+			return "", err
+		}
+		commands.Write(append(line, '\n'))
+	}
+
+	var events bytes.Buffer
+	for _, e := range r.events {
+		events.WriteString(sanitize(e) + "\n")
+	}
+
+	invData, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	envData, err := json.MarshalIndent(collectEnvironment(sanitize), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{InvocationFile, invData},
+		{EnvironmentFile, envData},
+		{CommandsFile, commands.Bytes()},
+		{EventsFile, events.Bytes()},
+	}
+	f, err := os.Create(r.path)
+	if err != nil {
+		return "", err
+	}
+	zw := zip.NewWriter(f)
+	for _, file := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: r.start})
+		if err != nil {
+			f.Close()
+			return "", err
+		}
+		if _, err := w.Write(file.data); err != nil {
+			f.Close()
+			return "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return "", err
+	}
+	return r.path, f.Close()
+}
+
+// urlCredentials matches the user information of a URL
+var urlCredentials = regexp.MustCompile(`(://)[^/@\s]+@`)
+
+// sanitizer returns the function masking what must not leave the machine:
+// registered values (as written and regex-escaped), URL credentials and the
+// home directory
+func (r *recorder) sanitizer() func(string) string {
+	var pairs []string
+	secrets := append([]string{}, r.secrets...)
+	sort.SliceStable(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	for _, v := range secrets {
+		pairs = append(pairs, v, "***")
+		if quoted := regexp.QuoteMeta(v); quoted != v {
+			pairs = append(pairs, quoted, "***")
+		}
+	}
+	home, _ := os.UserHomeDir()
+	if len(home) > 1 {
+		pairs = append(pairs, home, "~")
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	return func(s string) string {
+		return urlCredentials.ReplaceAllString(replacer.Replace(s), "${1}***@")
+	}
+}
+
+// sanitizeArgs masks the arguments of a command and leaves out the long ones
+func sanitizeArgs(args []string, sanitize func(string) string) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		if len(arg) > maxArgLength {
+			out[i] = fmt.Sprintf("[%d bytes left out]", len(arg))
+			continue
+		}
+		out[i] = sanitize(arg)
+	}
+	return out
+}
+
+// collectEnvironment describes the machine and the versions of the tools
+func collectEnvironment(sanitize func(string) string) Environment {
+	env := Environment{
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		CPUs:      runtime.NumCPU(),
+		Tools:     make(map[string]string),
+	}
+	for name, args := range map[string][]string{
+		"git":             {"git", "--version"},
+		"git-filter-repo": {"git", "filter-repo", "--version"},
+		"bfg":             {"bfg", "--version"},
+		"java":            {"java", "-version"},
+	} {
+		out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+		version := strings.TrimSpace(string(out))
+		if err != nil {
+			version = strings.TrimSpace("unavailable: " + err.Error() + "\n" + version)
+		}
+		if i := strings.IndexByte(version, '\n'); i != -1 && err == nil {
+			version = version[:i]
+		}
+		env.Tools[name] = sanitize(version)
+	}
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "GITSECRET_") {
+			env.EnvSet = append(env.EnvSet, name)
+		}
+	}
+	sort.Strings(env.EnvSet)
+	return env
+}
+
+// tailBuffer keeps the last bytes written to it
+type tailBuffer struct {
+	mu   sync.Mutex
+	max  int
+	data []byte
+	cut  bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > b.max {
+		b.data = append(b.data[:0], b.data[len(b.data)-b.max:]...)
+		b.cut = true
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cut {
+		return "[…]" + string(b.data)
+	}
+	return string(b.data)
+}
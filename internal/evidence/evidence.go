@@ -0,0 +1,421 @@
+// Package evidence collects a read-only, tamper-evident evidence bundle of a
+// repository for incident response: findings, repository metadata, object
+// hashes, tool version and configuration, sealed by a SHA256 manifest.
+// Nothing in the repository is written, so the chain of custody is kept.
+package evidence
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// Bundle archive layout
+const (
+	ManifestFile   = "SHA256SUMS" // sha256sum format, verifiable with `sha256sum -c`
+	FindingsFile   = "findings.jsonl"
+	RepositoryFile = "repository.json"
+	ObjectsFile    = "objects.txt"
+	ToolFile       = "tool.json"
+	ConfigFile     = "config.json"
+)
+
+// Options describes what to collect
+type Options struct {
+	Repo        string
+	Branch      string // Default: --all
+	Config      *config.Config
+	ToolVersion string
+	Case        string // Incident or case reference recorded in the metadata
+}
+
+// Repository is the metadata of the collected repository
+type Repository struct {
+	Path        string            `json:"path"`
+	Head        string            `json:"head"`
+	HeadRef     string            `json:"headRef,omitempty"`
+	Remotes     map[string]string `json:"remotes,omitempty"` // Credentials in URLs are redacted
+	Refs        map[string]string `json:"refs"`
+	Commits     int               `json:"commits"`
+	Case        string            `json:"case,omitempty"`
+	CollectedAt time.Time         `json:"collectedAt"`
+	CollectedBy string            `json:"collectedBy"`
+}
+
+// Tool records what produced the bundle
+type Tool struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	GoVersion    string `json:"goVersion"`
+	Platform     string `json:"platform"`
+	ConfigSHA256 string `json:"configSha256"`
+	Branch       string `json:"branch"`
+}
+
+// Result summarizes a collected bundle
+type Result struct {
+	Output         string
+	SHA256         string // Of the bundle file, to record in the custody log
+	ManifestSHA256 string
+	Findings       int
+	Commits        int
+}
+
+// Collect writes the evidence bundle of a repository to output. The output
+// must be outside the repository.
+func Collect(output string, opts Options) (*Result, error) {
+	if opts.Branch == "" {
+		opts.Branch = "--all"
+	}
+	if inside(opts.Repo, output) {
+		return nil, fmt.Errorf("the bundle must be written outside the repository")
+	}
+
+	collectedAt := time.Now().UTC()
+	repo, err := describeRepository(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	repo.Case = opts.Case
+	repo.CollectedAt = collectedAt
+	repo.CollectedBy = collector()
+
+	objects, err := listObjects(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	repo.Commits = strings.Count(objects, "\n")
+
+	// The scan writes to a private temporary directory, never to the repository
+	tmp, err := os.MkdirTemp("", "gitsecret-evidence-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	findingsPath := filepath.Join(tmp, FindingsFile)
+	found, err := scanner.New(opts.Config).ScanBothStream(opts.Repo, findingsPath, scanner.ScanOptions{Branch: opts.Branch})
+	if err != nil {
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+	findings, err := os.ReadFile(findingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	configData, err := json.MarshalIndent(opts.Config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	tool := Tool{
+		Name:         "git-secret-scanner",
+		Version:      opts.ToolVersion,
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		ConfigSHA256: checksum(configData),
+		Branch:       opts.Branch,
+	}
+
+	repoData, err := json.MarshalIndent(repo, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	toolData, err := json.MarshalIndent(tool, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{
+		FindingsFile:   findings,
+		RepositoryFile: repoData,
+		ObjectsFile:    []byte(objects),
+		ToolFile:       toolData,
+		ConfigFile:     configData,
+	}
+	manifest := buildManifest(files)
+
+	if err := writeBundle(output, files, manifest, collectedAt); err != nil {
+		os.Remove(output)
+		return nil, err
+	}
+	bundleSum, err := fileChecksum(output)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Output:         output,
+		SHA256:         bundleSum,
+		ManifestSHA256: checksum(manifest),
+		Findings:       found,
+		Commits:        repo.Commits,
+	}, nil
+}
+
+// describeRepository reads HEAD, refs and remotes without touching the
+// repository (go-git only reads, and needs no git binary)
+func describeRepository(repoPath string) (*Repository, error) {
+	repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	abs, _ := filepath.Abs(repoPath)
+	info := &Repository{Path: abs, Refs: make(map[string]string), Remotes: make(map[string]string)}
+
+	if head, err := repo.Head(); err == nil {
+		info.Head = head.Hash().String()
+		if head.Name() != plumbing.HEAD {
+			info.HeadRef = head.Name().String()
+		}
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference {
+			info.Refs[ref.Name().String()] = ref.Hash().String()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	for _, remote := range remotes {
+		if urls := remote.Config().URLs; len(urls) > 0 {
+			info.Remotes[remote.Config().Name] = redactURL(urls[0])
+		}
+	}
+	return info, nil
+}
+
+// listObjects returns one "commit tree" line per commit reachable from any
+// ref. Tree hashes cover every file of each snapshot, so the list pins the
+// whole history as collected.
+func listObjects(repoPath string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	commits, err := repo.Log(&git.LogOptions{All: true})
+	if err != nil {
+		if err == plumbing.ErrReferenceNotFound {
+			return "", nil // Empty repository
+		}
+		return "", err
+	}
+	var lines []string
+	err = commits.ForEach(func(c *object.Commit) error {
+		lines = append(lines, c.Hash.String()+" "+c.TreeHash.String())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(lines)
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// buildManifest returns the sha256sum-style manifest of the files, sorted by name
+func buildManifest(files map[string][]byte) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&sb, "%s  %s\n", checksum(files[name]), name)
+	}
+	return []byte(sb.String())
+}
+
+// writeBundle writes the files and their manifest as a gzipped tar archive
+// readable only by the collector (findings hold plaintext values)
+func writeBundle(output string, files map[string][]byte, manifest []byte, created time.Time) error {
+	out, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	writeFile := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0444, Size: int64(len(data)), ModTime: created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := writeFile(ManifestFile, manifest); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeFile(name, files[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Sync()
+}
+
+// Verify checks every file of a bundle against its manifest and returns the
+// manifest checksum. Missing, extra or altered files are errors.
+func Verify(bundlePath string) (string, error) {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("not an evidence bundle: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	sums := make(map[string]string)
+	var manifest []byte
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("corrupted bundle: %w", err)
+		}
+		name := path.Clean(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || strings.HasPrefix(name, "..") || path.IsAbs(name) {
+			return "", fmt.Errorf("unexpected entry in bundle: %s", hdr.Name)
+		}
+		if name == ManifestFile {
+			if manifest, err = io.ReadAll(tr); err != nil {
+				return "", err
+			}
+			continue
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, tr); err != nil {
+			return "", err
+		}
+		sums[name] = hex.EncodeToString(h.Sum(nil))
+	}
+	if manifest == nil {
+		return "", fmt.Errorf("bundle has no %s", ManifestFile)
+	}
+
+	listed := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(manifest)), "\n") {
+		sum, name, ok := strings.Cut(line, "  ")
+		if !ok {
+			return "", fmt.Errorf("invalid manifest line: %q", line)
+		}
+		listed[name] = true
+		actual, present := sums[name]
+		switch {
+		case !present:
+			return "", fmt.Errorf("%s is missing from the bundle", name)
+		case actual != sum:
+			return "", fmt.Errorf("checksum mismatch for %s", name)
+		}
+	}
+	for name := range sums {
+		if !listed[name] {
+			return "", fmt.Errorf("%s is not listed in the manifest", name)
+		}
+	}
+	return checksum(manifest), nil
+}
+
+// inside reports whether target is in the directory dir
+func inside(dir, target string) bool {
+	absDir, err1 := filepath.Abs(dir)
+	absTarget, err2 := filepath.Abs(target)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absTarget)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// redactURL hides the password of a remote URL
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// collector identifies who collected the bundle (user@host)
+func collector() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, _ := os.Hostname()
+	return name + "@" + host
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
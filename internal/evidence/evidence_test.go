@@ -0,0 +1,97 @@
+package evidence
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=Alice", "-c", "user.email=alice@example.org", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, "app.conf"), []byte("db_password=Sup3rS3cret!\n"), 0644)
+	return dir
+}
+
+func TestCollectAndVerify(t *testing.T) {
+	repo := newRepo(t)
+	output := filepath.Join(t.TempDir(), "evidence.tar.gz")
+
+	result, err := Collect(output, Options{Repo: repo, Config: config.DefaultConfig(), ToolVersion: "test", Case: "IR-1"})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if result.Findings != 1 || result.Commits != 1 {
+		t.Errorf("Expected 1 finding and 1 commit, got %+v", result)
+	}
+	sum, err := Verify(output)
+	if err != nil || sum != result.ManifestSHA256 {
+		t.Fatalf("Verify = %s, %v; want manifest %s", sum, err, result.ManifestSHA256)
+	}
+
+	if _, err := Collect(output, Options{Repo: repo, Config: config.DefaultConfig()}); err == nil {
+		t.Error("Expected an existing bundle not to be overwritten")
+	}
+	if _, err := Collect(filepath.Join(repo, "evidence.tar.gz"), Options{Repo: repo, Config: config.DefaultConfig()}); err == nil {
+		t.Error("Expected a bundle inside the repository to be refused")
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	repo := newRepo(t)
+	dir := t.TempDir()
+	output := filepath.Join(dir, "evidence.tar.gz")
+	if _, err := Collect(output, Options{Repo: repo, Config: config.DefaultConfig()}); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	// Rewrite the bundle with one finding altered
+	in, _ := os.Open(output)
+	gz, _ := gzip.NewReader(in)
+	tr := tar.NewReader(gz)
+	tampered := filepath.Join(dir, "tampered.tar.gz")
+	out, _ := os.Create(tampered)
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		data, _ := io.ReadAll(tr)
+		if hdr.Name == FindingsFile {
+			data = []byte(strings.Replace(string(data), "Sup3rS3cret!", "nothing-here", -1))
+		}
+		hdr.Size = int64(len(data))
+		tw.WriteHeader(hdr)
+		tw.Write(data)
+	}
+	tw.Close()
+	gw.Close()
+	out.Close()
+	in.Close()
+
+	if _, err := Verify(tampered); err == nil || !strings.Contains(err.Error(), FindingsFile) {
+		t.Errorf("Expected a checksum mismatch on %s, got %v", FindingsFile, err)
+	}
+}
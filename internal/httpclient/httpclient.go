@@ -0,0 +1,137 @@
+// Package httpclient builds the HTTP client shared by every outbound
+// feature, so that proxies and custom certificate authorities configured in
+// the settings (or the environment) apply everywhere.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// DefaultTimeout bounds every request made with a client from New
+const DefaultTimeout = 30 * time.Second
+
+// New returns a client honoring the proxy and CA bundle settings. Proxy
+// fields left empty fall back to HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func New(settings config.NetworkSettings) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc(settings)
+
+	if settings.CABundle != "" {
+		pool, err := certPool(settings.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &http.Client{Transport: transport, Timeout: DefaultTimeout}, nil
+}
+
+// certPool returns the system roots plus the certificates of a PEM bundle,
+// e.g. the root of a TLS-intercepting corporate proxy
+func certPool(path string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificate found in CA bundle %s", path)
+	}
+	return pool, nil
+}
+
+// proxyFunc selects the proxy of a request from the settings, falling back
+// to the environment for each field left empty
+func proxyFunc(settings config.NetworkSettings) func(*http.Request) (*url.URL, error) {
+	httpProxy := firstNonEmpty(settings.HTTPProxy, os.Getenv("HTTP_PROXY"), os.Getenv("http_proxy"))
+	httpsProxy := firstNonEmpty(settings.HTTPSProxy, os.Getenv("HTTPS_PROXY"), os.Getenv("https_proxy"))
+	noProxy := firstNonEmpty(settings.NoProxy, os.Getenv("NO_PROXY"), os.Getenv("no_proxy"))
+
+	return func(req *http.Request) (*url.URL, error) {
+		proxy := httpProxy
+		if req.URL.Scheme == "https" {
+			proxy = httpsProxy
+		}
+		if proxy == "" || bypassProxy(req.URL, noProxy) {
+			return nil, nil
+		}
+		if !strings.Contains(proxy, "://") {
+			proxy = "http://" + proxy
+		}
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address %q: %w", proxy, err)
+		}
+		return u, nil
+	}
+}
+
+// bypassProxy reports whether a URL matches a NO_PROXY list: "*", hosts,
+// domains (with or without a leading dot, subdomains included), IPs and
+// CIDRs, each optionally restricted to a port
+func bypassProxy(u *url.URL, noProxy string) bool {
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		domain := strings.TrimPrefix(entryHost, ".")
+		name := strings.ToLower(host)
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
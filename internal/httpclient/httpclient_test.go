@@ -0,0 +1,73 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+func TestProxySettings(t *testing.T) {
+	proxy := proxyFunc(config.NetworkSettings{
+		HTTPSProxy: "proxy.corp:3128",
+		NoProxy:    ".internal.corp, 10.0.0.0/8, git.example.org:8443",
+	})
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://rules.example.com/pack.json", "http://proxy.corp:3128"},
+		{"https://vault.internal.corp/v1", ""},
+		{"https://internal.corp/", ""},
+		{"https://10.1.2.3/", ""},
+		{"https://git.example.org:8443/", ""},
+		{"https://git.example.org/", "http://proxy.corp:3128"},
+		{"https://localhost:8080/", ""},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		got, err := proxy(&http.Request{URL: u})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.url, err)
+		}
+		if (got == nil && tt.want != "") || (got != nil && got.String() != tt.want) {
+			t.Errorf("%s: proxy = %v, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestCABundleIsTrusted(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plain, _ := New(config.NetworkSettings{})
+	if _, err := plain.Get(server.URL); err == nil {
+		t.Fatal("self-signed server trusted without the CA bundle")
+	}
+
+	client, err := New(config.NetworkSettings{CABundle: bundle})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with CA bundle: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := New(config.NetworkSettings{CABundle: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("missing CA bundle accepted")
+	}
+}
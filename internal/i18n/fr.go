@@ -0,0 +1,140 @@
+package i18n
+
+// fr is the French catalog
+var fr = map[string]string{
+	// Text report (analyzer.GenerateReport)
+	"SECRET ANALYSIS REPORT":                     "RAPPORT D'ANALYSE DES SECRETS",
+	"GLOBAL STATISTICS":                          "STATISTIQUES GLOBALES",
+	"Entries analyzed:":                          "Entrées analysées:",
+	"Unique secrets:":                            "Secrets uniques:",
+	"Distinct values:":                           "Valeurs différentes:",
+	"Health score:":                              "Score d'hygiène:",
+	"TOP AUTHORS (who changes the most secrets)": "TOP AUTEURS (qui modifie le plus de secrets)",
+	"TOP FILES (most affected)":                  "TOP FICHIERS (les plus impactés)",
+	"HOTSPOT FILES (secrets per 1000 lines)":     "FICHIERS SENSIBLES (secrets pour 1000 lignes)",
+	"deleted":                                    "supprimé",
+	"%.1f/KLOC (%d lines)":                       "%.1f/KLOC (%d lignes)",
+	"SECRET TYPES":                               "TYPES DE SECRETS",
+	"SECRETS SORTED BY CHANGE FREQUENCY":         "SECRETS TRIÉS PAR FRÉQUENCE DE CHANGEMENT",
+	"Key: %s":                                    "Clé: %s",
+	"Type: %-15s Changes: %-5d Occurrences: %d":  "Type: %-15s Changements: %-5d Occurrences: %d",
+	"Severity: %s":                               "Sévérité: %s",
+	"Authors: %s":                                "Auteurs: %s",
+	"Period: %s → %s":                            "Période: %s → %s",
+	"Value history:":                             "Historique des valeurs:",
+	"(%dx by %s)":                                "(%dx par %s)",
+	"line %d, offset %d":                         "ligne %d, offset %d",
+	"line %d":                                    "ligne %d",
+	"... and %d more secrets":                    "... et %d autres secrets",
+	"issuer %s":                                  "émetteur %s",
+	"no expiry (revoke it)":                      "sans expiration (à révoquer)",
+	"expired on %s":                              "expiré le %s",
+	"VALID until %s (revoke it)":                 "VALIDE jusqu'au %s (à révoquer)",
+
+	// Health trends (analyzer.Health.Direction)
+	"improving": "en amélioration",
+	"worsening": "en dégradation",
+
+	// Statistics CSV (analyzer.ExportStatsCSV); the columns of the secrets
+	// CSV stay in English for the scripts reading them
+	"SUMMARY":        "RÉSUMÉ",
+	"AUTHORS":        "AUTEURS",
+	"FILES":          "FICHIERS",
+	"HOTSPOTS":       "FICHIERS SENSIBLES",
+	"Metric":         "Indicateur",
+	"Value":          "Valeur",
+	"Total Entries":  "Entrées analysées",
+	"Unique Secrets": "Secrets uniques",
+	"Unique Values":  "Valeurs différentes",
+	"Health Score":   "Score d'hygiène",
+	"Health Grade":   "Note d'hygiène",
+	"Active Secrets": "Secrets actifs",
+	"Trend":          "Tendance",
+	"Author":         "Auteur",
+	"Count":          "Nombre",
+	"File":           "Fichier",
+	"Values":         "Valeurs",
+	"Lines":          "Lignes",
+	"PerKLOC":        "ParKLOC",
+
+	// HTML report (analyzer.ExportHTML)
+	"Secret Analysis Report": "Rapport d'analyse des secrets",
+	"Generated %s · CONTAINS RAW SECRET VALUES: handle as confidential": "Généré le %s · CONTIENT LES VALEURS BRUTES DES SECRETS: à traiter comme confidentiel",
+	"Generated %s · values are masked":                                  "Généré le %s · valeurs masquées",
+	"entries analyzed":                                                  "entrées analysées",
+	"unique secrets":                                                    "secrets uniques",
+	"distinct values":                                                   "valeurs différentes",
+	"Penalties: density %d, severity %d, active %d, trend %d":           "Pénalités: densité %d, sévérité %d, actifs %d, tendance %d",
+	"health score · %d active, %s":                                      "score d'hygiène · %d actifs, %s",
+	"Top authors":                                                       "Top auteurs",
+	"Top files":                                                         "Top fichiers",
+	"Secret types":                                                      "Types de secrets",
+	"No data":                                                           "Aucune donnée",
+	"Hotspot files":                                                     "Fichiers sensibles",
+	"Secrets / KLOC":                                                    "Secrets / KLOC",
+	"Key":                                                               "Clé",
+	"Severity":                                                          "Sévérité",
+	"Changes":                                                           "Changements",
+	"Authors":                                                           "Auteurs",
+	"First seen":                                                        "Première apparition",
+	"Last seen":                                                         "Dernière apparition",
+	"Value history":                                                     "Historique des valeurs",
+	"%d value(s)":                                                       "%d valeur(s)",
+
+	// TUI main menu
+	"Scan Repository": "Scanner un dépôt",
+	"Search for passwords and secrets in git history": "Chercher mots de passe et secrets dans l'historique git",
+	"Analyze Results": "Analyser les résultats",
+	"View statistics, authors, and frequency of changes": "Statistiques, auteurs et fréquence des changements",
+	"Clean History": "Nettoyer l'historique",
+	"Remove secrets from git history (rewrite commits)": "Retirer les secrets de l'historique git (réécrit les commits)",
+	"Check Tools": "Vérifier les outils",
+	"Verify and install cleaning tools (git-filter-repo, BFG)": "Vérifier et installer les outils de nettoyage (git-filter-repo, BFG)",
+	"Integrations": "Intégrations",
+	"GitHub, GitLab and Jira tokens for notifications and tickets": "Jetons GitHub, GitLab et Jira des notifications et des tickets",
+	"Quit":                                 "Quitter",
+	"Exit the application":                 "Quitter l'application",
+	"Resume Last Session":                  "Reprendre la dernière session",
+	"Reopen %s from %s without rescanning": "Rouvrir %s du %s sans rescanner",
+	"↑/↓: navigate • enter: select • esc: quit": "↑/↓: naviguer • entrée: choisir • esc: quitter",
+
+	// TUI screen titles
+	"Scanning Repository":     "Scan du dépôt",
+	"Scan Cancelled":          "Scan annulé",
+	"Scan Complete":           "Scan terminé",
+	"Analyzing Results":       "Analyse en cours",
+	"Analysis Results":        "Résultats de l'analyse",
+	"Select Secrets to Clean": "Secrets à nettoyer",
+	"Confirm Clean":           "Confirmer le nettoyage",
+	"Cleaning Repository":     "Nettoyage du dépôt",
+	"Clean Failed":            "Échec du nettoyage",
+	"Dry Run Results":         "Résultats de la simulation",
+	"Clean Complete":          "Nettoyage terminé",
+	"Clean Cancelled":         "Nettoyage annulé",
+	"Git Credentials":         "Identifiants git",
+
+	// TUI analysis results
+	"Statistics":     "Statistiques",
+	"Total entries:": "Entrées analysées:",
+	"Unique values:": "Valeurs différentes:",
+	"Left out:":      "Écartées:",
+	"%d triaged as false positives or accepted risks":              "%d triées comme faux positifs ou risques acceptés",
+	"%d active secrets, trend %s (%d new this quarter, %d before)": "%d secrets actifs, tendance %s (%d nouvelles ce trimestre, %d avant)",
+	"penalties: density -%d, severity -%d, active -%d, trend -%d":  "pénalités: densité -%d, sévérité -%d, actifs -%d, tendance -%d",
+	"Top Authors":                             "Top auteurs",
+	"Hotspot Files":                           "Fichiers sensibles",
+	"not in working tree":                     "absent de l'arbre de travail",
+	"%.1f per KLOC (%d lines)":                "%.1f pour 1000 lignes (%d lignes)",
+	"Most Changed Secrets":                    "Secrets les plus modifiés",
+	"%d changes, %d occurrences, authors: %s": "%d changements, %d occurrences, auteurs: %s",
+	"Report exported:":                        "Rapport exporté:",
+	"b: browse all • d: dashboard • o: open file • 1-4: toggle severity • esc: back to menu": "b: tout parcourir • d: tableau de bord • o: ouvrir le fichier • 1-4: filtrer les sévérités • esc: retour au menu",
+
+	// TUI dashboard
+	"Dashboard":                  "Tableau de bord",
+	"No analysis to chart":       "Aucune analyse à représenter",
+	"Findings by type":           "Détections par type",
+	"Findings by author":         "Détections par auteur",
+	"New values by month":        "Nouvelles valeurs par mois",
+	"d/esc: back to the results": "d/esc: retour aux résultats",
+}
@@ -0,0 +1,83 @@
+// Package i18n translates the text of the analyzer reports and the TUI
+// (settings.language, GITSECRET_LANG). Messages are their English text,
+// gettext style: a language without a translation for a message shows it in
+// English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// catalogs holds the translations of the languages other than English
+var catalogs = map[string]map[string]string{
+	config.LanguageFR: fr,
+}
+
+// Printer translates messages into one language
+type Printer struct {
+	lang    string
+	catalog map[string]string
+}
+
+// For returns the printer of a language (config.Languages; English for ""
+// and unknown ones)
+func For(lang string) Printer {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		lang = config.LanguageEN
+	}
+	return Printer{lang: lang, catalog: catalog}
+}
+
+// Lang returns the language of the printer
+func (p Printer) Lang() string {
+	return p.lang
+}
+
+// T translates a message
+func (p Printer) T(msg string) string {
+	if translated, ok := p.catalog[msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format
+func (p Printer) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(p.T(format), args...)
+}
+
+// Resolve returns the language to use: GITSECRET_LANG, else the setting,
+// else the one of the system locale (LC_ALL, LC_MESSAGES, LANG) when
+// translated, else English
+func Resolve(setting string) string {
+	if lang := os.Getenv(config.LanguageEnv); lang != "" {
+		return match(lang)
+	}
+	if setting != "" {
+		return match(setting)
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			return match(locale)
+		}
+	}
+	return config.LanguageEN
+}
+
+// match returns the language of a language or locale name ("fr",
+// "fr_FR.UTF-8", "FR"), English when it has no catalog
+func match(name string) string {
+	lang := strings.ToLower(name)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return config.LanguageEN
+}
@@ -0,0 +1,50 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// verbs matches the fmt verbs of a message
+var verbs = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for msg, translated := range catalog {
+			if want, got := verbs.FindAllString(msg, -1), verbs.FindAllString(translated, -1); !slices.Equal(want, got) {
+				t.Errorf("%s: %q has verbs %v, its translation %q %v", lang, msg, want, translated, got)
+			}
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	for _, tc := range []struct {
+		env, setting, lang, want string
+	}{
+		{"", "", "C.UTF-8", config.LanguageEN},
+		{"", "", "fr_FR.UTF-8", config.LanguageFR},
+		{"", config.LanguageEN, "fr_FR.UTF-8", config.LanguageEN},
+		{"", config.LanguageFR, "", config.LanguageFR},
+		{"fr", config.LanguageEN, "", config.LanguageFR},
+		{"de", config.LanguageFR, "", config.LanguageEN},
+	} {
+		t.Setenv(config.LanguageEnv, tc.env)
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tc.lang)
+		if got := Resolve(tc.setting); got != tc.want {
+			t.Errorf("Resolve(%q) with %s=%q, LANG=%q = %q, want %q", tc.setting, config.LanguageEnv, tc.env, tc.lang, got, tc.want)
+		}
+	}
+
+	if got := For(config.LanguageFR).Sprintf("line %d", 3); got != "ligne 3" {
+		t.Errorf("French line = %q", got)
+	}
+	if got := For("xx").T("line %d"); got != "line %d" {
+		t.Errorf("unknown language = %q, want the English message", got)
+	}
+}
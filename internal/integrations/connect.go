@@ -0,0 +1,131 @@
+package integrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Result is the outcome of a connectivity test
+type Result struct {
+	User        string   // Account the token belongs to
+	Scopes      []string // Scopes of the token (when ScopesKnown)
+	ScopesKnown bool     // The service reports them (not for fine-grained or Jira tokens)
+	Missing     []string // Needed scopes the token lacks
+}
+
+// Test calls the API of an integration with a token, returning the account
+// it belongs to and the needed scopes it lacks
+func (i Integration) Test(client *http.Client, account Account, token string) (*Result, error) {
+	base := i.URL(account)
+	if base == "" {
+		return nil, fmt.Errorf("%s: no URL set", i.Title)
+	}
+	if i.NeedsUser && account.User == "" {
+		return nil, fmt.Errorf("%s: no user (account email) set", i.Title)
+	}
+
+	result := &Result{}
+	switch i.Name {
+	case "github":
+		var user struct {
+			Login string `json:"login"`
+		}
+		header, err := i.get(client, base+"/user", account, token, &user)
+		if err != nil {
+			return nil, err
+		}
+		result.User = user.Login
+		// Classic tokens list their scopes, fine-grained ones do not
+		if scopes, ok := header["X-Oauth-Scopes"]; ok {
+			result.ScopesKnown = true
+			result.Scopes = splitScopes(strings.Join(scopes, ","))
+		}
+	case "gitlab":
+		var user struct {
+			Username string `json:"username"`
+		}
+		if _, err := i.get(client, base+"/api/v4/user", account, token, &user); err != nil {
+			return nil, err
+		}
+		result.User = user.Username
+		var self struct {
+			Scopes []string `json:"scopes"`
+		}
+		// Older instances lack the endpoint: scopes unknown
+		if _, err := i.get(client, base+"/api/v4/personal_access_tokens/self", account, token, &self); err == nil {
+			result.ScopesKnown = true
+			result.Scopes = self.Scopes
+		}
+	case "jira":
+		var user struct {
+			DisplayName  string `json:"displayName"`
+			EmailAddress string `json:"emailAddress"`
+		}
+		if _, err := i.get(client, base+"/rest/api/2/myself", account, token, &user); err != nil {
+			return nil, err
+		}
+		result.User = user.DisplayName
+		if user.EmailAddress != "" {
+			result.User += " <" + user.EmailAddress + ">"
+		}
+	default:
+		return nil, fmt.Errorf("unknown integration %q", i.Name)
+	}
+
+	if result.ScopesKnown {
+		for _, scope := range i.Scopes {
+			if !slices.Contains(result.Scopes, scope) {
+				result.Missing = append(result.Missing, scope)
+			}
+		}
+	}
+	return result, nil
+}
+
+// get calls an API endpoint with the token, decoding its JSON response
+func (i Integration) get(client *http.Client, url string, account Account, token string, v any) (http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	switch i.Name {
+	case "gitlab":
+		req.Header.Set("PRIVATE-TOKEN", token)
+	case "jira":
+		req.SetBasicAuth(account.User, token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", i.Title, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, fmt.Errorf("%s: token rejected (%s)", i.Title, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s: %s: %s", i.Title, url, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v); err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", i.Title, url, err)
+	}
+	return resp.Header, nil
+}
+
+// splitScopes splits a comma separated scope list
+func splitScopes(list string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(list, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
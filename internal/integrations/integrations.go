@@ -0,0 +1,138 @@
+// Package integrations holds the accounts of the services findings are
+// reported to (GitHub, GitLab, Jira): their settings, their tokens in the
+// OS keyring and a connectivity test listing the scopes a token lacks.
+package integrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// Integration is a service the tool can report to
+type Integration struct {
+	Name       string // Key of the settings file and the keyring
+	Title      string
+	Scopes     []string // Token scopes the features need
+	Purpose    string   // What the scopes are used for
+	DefaultURL string   // API base; self-hosted instances change it ("": must be set)
+	NeedsUser  bool     // The account (email) goes with the token (basic auth)
+	TokenHelp  string   // Where tokens are created
+}
+
+// All lists the supported integrations
+var All = []Integration{
+	{
+		Name:       "github",
+		Title:      "GitHub",
+		Scopes:     []string{"repo"},
+		Purpose:    "open issues for findings",
+		DefaultURL: "https://api.github.com",
+		TokenHelp:  "https://github.com/settings/tokens",
+	},
+	{
+		Name:       "gitlab",
+		Title:      "GitLab",
+		Scopes:     []string{"api"},
+		Purpose:    "open issues for findings",
+		DefaultURL: "https://gitlab.com",
+		TokenHelp:  "<url>/-/user_settings/personal_access_tokens",
+	},
+	{
+		Name:      "jira",
+		Title:     "Jira",
+		Scopes:    []string{"read:jira-work", "write:jira-work"},
+		Purpose:   "create tickets for findings",
+		NeedsUser: true,
+		TokenHelp: "https://id.atlassian.com/manage-profile/security/api-tokens",
+	},
+}
+
+// Lookup returns an integration by name
+func Lookup(name string) (Integration, bool) {
+	for _, i := range All {
+		if i.Name == name {
+			return i, true
+		}
+	}
+	return Integration{}, false
+}
+
+// Account is the non-secret part of an integration's settings
+type Account struct {
+	URL  string `json:"url,omitempty"`  // API base (DefaultURL if empty)
+	User string `json:"user,omitempty"` // Email, when NeedsUser
+}
+
+// Settings holds the accounts by integration name. Tokens are never in it.
+type Settings map[string]Account
+
+// SettingsPath returns the file holding the integration settings
+func SettingsPath() string {
+	return filepath.Join(config.UserConfigDir(), "integrations.json")
+}
+
+// LoadSettings reads the integration settings (empty if none were saved)
+func LoadSettings() (Settings, error) {
+	settings := Settings{}
+	data, err := os.ReadFile(SettingsPath())
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("%s: %w", SettingsPath(), err)
+	}
+	return settings, nil
+}
+
+// Save writes the integration settings
+func (s Settings) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.UserConfigDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(SettingsPath(), append(data, '\n'), 0600)
+}
+
+// URL returns the API base of an integration's account
+func (i Integration) URL(account Account) string {
+	if account.URL != "" {
+		return strings.TrimRight(account.URL, "/")
+	}
+	return i.DefaultURL
+}
+
+// TokenEnv is the variable whose token replaces the keyring's, for CI
+// and machines without a keyring
+func (i Integration) TokenEnv() string {
+	return "GITSECRET_" + strings.ToUpper(i.Name) + "_TOKEN"
+}
+
+// Token sources
+const (
+	SourceEnv     = "environment"
+	SourceKeyring = "keyring"
+)
+
+// Token returns the token of an integration and where it comes from: its
+// TokenEnv variable, else the keyring. ErrNotFound means none is set.
+func (i Integration) Token(keyring Keyring) (token, source string, err error) {
+	if token := os.Getenv(i.TokenEnv()); token != "" {
+		return token, SourceEnv, nil
+	}
+	token, err = keyring.Get(i.Name)
+	if err != nil {
+		return "", "", err
+	}
+	return token, SourceKeyring, nil
+}
@@ -0,0 +1,103 @@
+package integrations
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// memoryKeyring is a Keyring for tests
+type memoryKeyring map[string]string
+
+func (k memoryKeyring) Get(name string) (string, error) {
+	if token, ok := k[name]; ok {
+		return token, nil
+	}
+	return "", ErrNotFound
+}
+
+func (k memoryKeyring) Set(name, token string) error { k[name] = token; return nil }
+func (k memoryKeyring) Delete(name string) error     { delete(k, name); return nil }
+
+func TestIntegrations(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	github, _ := Lookup("github")
+	gitlab, _ := Lookup("gitlab")
+	jira, _ := Lookup("jira")
+
+	// Tokens: the environment wins over the keyring
+	keyring := memoryKeyring{}
+	if _, _, err := github.Token(keyring); !errors.Is(err, ErrNotFound) {
+		t.Errorf("no token: err = %v, want ErrNotFound", err)
+	}
+	keyring.Set("github", "from-keyring")
+	if token, source, _ := github.Token(keyring); token != "from-keyring" || source != SourceKeyring {
+		t.Errorf("token = %q from %s, want the keyring's", token, source)
+	}
+	t.Setenv(github.TokenEnv(), "from-env")
+	if token, source, _ := github.Token(keyring); token != "from-env" || source != SourceEnv {
+		t.Errorf("token = %q from %s, want the environment's", token, source)
+	}
+
+	// Settings keep no token
+	settings := Settings{"jira": {URL: "https://example.com/", User: "dev@example.com"}}
+	if err := settings.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSettings()
+	if err != nil || !reflect.DeepEqual(loaded, settings) {
+		t.Fatalf("loaded %+v (%v), want %+v", loaded, err, settings)
+	}
+	if url := jira.URL(loaded["jira"]); url != "https://example.com" {
+		t.Errorf("jira URL = %q", url)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			if r.Header.Get("Authorization") != "Bearer gh-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("X-OAuth-Scopes", "read:org, gist")
+			w.Write([]byte(`{"login": "octocat"}`))
+		case "/api/v4/user":
+			w.Write([]byte(`{"username": "tanuki"}`))
+		case "/api/v4/personal_access_tokens/self":
+			w.Write([]byte(`{"scopes": ["api", "read_user"]}`))
+		case "/rest/api/2/myself":
+			if user, token, _ := r.BasicAuth(); user != "dev@example.com" || token != "jira-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"displayName": "Dev", "emailAddress": "dev@example.com"}`))
+		}
+	}))
+	defer server.Close()
+
+	result, err := github.Test(server.Client(), Account{URL: server.URL}, "gh-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.User != "octocat" || !result.ScopesKnown || !reflect.DeepEqual(result.Missing, []string{"repo"}) {
+		t.Errorf("github = %+v, want octocat missing repo", result)
+	}
+	if _, err := github.Test(server.Client(), Account{URL: server.URL}, "wrong"); err == nil {
+		t.Error("github: rejected token accepted")
+	}
+
+	result, err = gitlab.Test(server.Client(), Account{URL: server.URL}, "gl-token")
+	if err != nil || result.User != "tanuki" || len(result.Missing) != 0 {
+		t.Errorf("gitlab = %+v (%v), want tanuki with all scopes", result, err)
+	}
+
+	if _, err := jira.Test(server.Client(), Account{URL: server.URL}, "jira-token"); err == nil {
+		t.Error("jira without a user: no error")
+	}
+	result, err = jira.Test(server.Client(), Account{URL: server.URL, User: "dev@example.com"}, "jira-token")
+	if err != nil || result.User != "Dev <dev@example.com>" || result.ScopesKnown {
+		t.Errorf("jira = %+v (%v)", result, err)
+	}
+}
@@ -0,0 +1,145 @@
+package integrations
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// KeyringService is the service the tokens are stored under
+const KeyringService = "git-secret-scanner"
+
+// Keyring errors
+var (
+	ErrNotFound  = errors.New("no token stored")
+	ErrNoKeyring = errors.New("no OS keyring available (macOS security or Linux secret-tool)")
+)
+
+// Keyring stores one token per integration
+type Keyring interface {
+	Get(name string) (string, error)
+	Set(name, token string) error
+	Delete(name string) error
+}
+
+// SystemKeyring returns the keyring of the OS: the macOS keychain through
+// security, the Secret Service (GNOME Keyring, KWallet) through secret-tool
+// elsewhere. Its methods return ErrNoKeyring when neither is available.
+// Tokens go through the standard input of the tools, never their command
+// line, which any local user can list.
+func SystemKeyring() Keyring {
+	if runtime.GOOS == "darwin" {
+		return macKeyring{}
+	}
+	return secretToolKeyring{}
+}
+
+// macKeyring stores tokens as generic passwords of the login keychain
+type macKeyring struct{}
+
+func (macKeyring) Get(name string) (string, error) {
+	out, err := keyringCommand(nil, "security", "find-generic-password", "-s", KeyringService, "-a", name, "-w")
+	if exitCode(err) == 44 { // errSecItemNotFound
+		return "", ErrNotFound
+	}
+	return strings.TrimRight(out, "\n"), err
+}
+
+// Set runs the command in the interactive mode of security, read from its
+// standard input: add-generic-password only takes the token as an argument
+func (macKeyring) Set(name, token string) error {
+	if strings.ContainsAny(token, "\r\n") {
+		return errors.New("the token holds a line break")
+	}
+	command := securityCommand("add-generic-password", "-U", "-s", KeyringService, "-a", name, "-w", token)
+	_, stderr, err := runKeyringTool([]byte(command+"\n"), "security", "-q", "-i")
+	if err != nil {
+		return err
+	}
+	// The interactive mode exits with 0 whatever its commands did
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("security: %s", msg)
+	}
+	return nil
+}
+
+// securityCommand quotes the words of a line of security -i: in double
+// quotes, with backslashes escaping quotes and backslashes
+func securityCommand(words ...string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		w = strings.ReplaceAll(w, `\`, `\\`)
+		quoted[i] = `"` + strings.ReplaceAll(w, `"`, `\"`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+func (macKeyring) Delete(name string) error {
+	_, err := keyringCommand(nil, "security", "delete-generic-password", "-s", KeyringService, "-a", name)
+	if exitCode(err) == 44 {
+		return nil
+	}
+	return err
+}
+
+// secretToolKeyring stores tokens in the Secret Service, the token going
+// through standard input
+type secretToolKeyring struct{}
+
+func (secretToolKeyring) Get(name string) (string, error) {
+	out, err := keyringCommand(nil, "secret-tool", "lookup", "service", KeyringService, "account", name)
+	if exitCode(err) == 1 && out == "" {
+		return "", ErrNotFound
+	}
+	return strings.TrimRight(out, "\n"), err
+}
+
+func (secretToolKeyring) Set(name, token string) error {
+	label := KeyringService + " " + name
+	_, err := keyringCommand([]byte(token), "secret-tool", "store", "--label", label, "service", KeyringService, "account", name)
+	return err
+}
+
+func (secretToolKeyring) Delete(name string) error {
+	_, err := keyringCommand(nil, "secret-tool", "clear", "service", KeyringService, "account", name)
+	return err
+}
+
+// keyringCommand runs a keyring tool, with input on its standard input
+func keyringCommand(input []byte, name string, args ...string) (string, error) {
+	out, _, err := runKeyringTool(input, name, args...)
+	return out, err
+}
+
+// runKeyringTool runs a keyring tool and returns what it wrote on its
+// standard output and error
+func runKeyringTool(input []byte, name string, args ...string) (string, string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", "", ErrNoKeyring
+	}
+	cmd := exec.Command(name, args...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), stderr.String(), fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return stdout.String(), stderr.String(), fmt.Errorf("%s: %w", name, err)
+	}
+	return stdout.String(), stderr.String(), nil
+}
+
+// exitCode returns the exit status of a failed command (-1 if it did not run)
+func exitCode(err error) int {
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return exit.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,39 @@
+package integrations
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMacKeyringSetKeepsTokenOffCommandLine(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as security")
+	}
+	// A security that records its arguments and standard input
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" > \"$0.args\"\ncat > \"$0.stdin\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "security"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	token := `ghp_"quoted\token`
+	if err := (macKeyring{}).Set("github", token); err != nil {
+		t.Fatal(err)
+	}
+	args, _ := os.ReadFile(filepath.Join(dir, "security.args"))
+	if strings.Contains(string(args), "ghp_") {
+		t.Errorf("the token is on the command line: %s", args)
+	}
+	stdin, _ := os.ReadFile(filepath.Join(dir, "security.stdin"))
+	if want := `"-w" "ghp_\"quoted\\token"` + "\n"; !strings.HasSuffix(string(stdin), want) {
+		t.Errorf("stdin = %q, want it to end with %q", stdin, want)
+	}
+
+	if err := (macKeyring{}).Set("github", "two\nlines"); err == nil {
+		t.Error("a token with a line break was accepted")
+	}
+}
@@ -0,0 +1,69 @@
+package model
+
+// EntrySecret wraps a JSONL entry as a single-value secret, as it would
+// appear in a JSON result holding only this finding
+func EntrySecret(entry StreamEntry) Secret {
+	return Secret{
+		File:             entry.File,
+		Key:              entry.Key,
+		Type:             entry.Type,
+		Severity:         entry.Severity,
+		ChangeCount:      1,
+		TotalOccurrences: 1,
+		Authors:          []string{entry.Author},
+		History: []SecretValue{{
+			Value:       entry.Value,
+			MaskedValue: entry.MaskedValue,
+			Commits:     []string{entry.Commit},
+			Authors:     []string{entry.Author},
+			FirstSeen:   entry.Date,
+			LastSeen:    entry.Date,
+			Status:      entry.Status,
+			JWT:         entry.JWT,
+			Line:        entry.Line,
+			Offset:      entry.Offset,
+			Context:     entry.Context,
+		}},
+	}
+}
+
+// Entries lists the values of a secret as JSONL entries, one per value:
+// the entry is dated of the last time the value was seen, with the first
+// commit and author recorded for it
+func (s Secret) Entries() []StreamEntry {
+	entries := make([]StreamEntry, 0, len(s.History))
+	for _, h := range s.History {
+		entry := StreamEntry{
+			File:        s.File,
+			Key:         s.Key,
+			Value:       h.Value,
+			MaskedValue: h.MaskedValue,
+			Type:        s.Type,
+			Severity:    s.Severity,
+			Date:        h.LastSeen,
+			Status:      h.Status,
+			JWT:         h.JWT,
+			Line:        h.Line,
+			Offset:      h.Offset,
+			Context:     h.Context,
+		}
+		if len(h.Commits) > 0 {
+			entry.Commit = h.Commits[0]
+		}
+		if len(h.Authors) > 0 {
+			entry.Author = h.Authors[0]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Entries flattens a result into JSONL entries, secret by secret (see
+// Secret.Entries)
+func (r *ScanResult) Entries() []StreamEntry {
+	var entries []StreamEntry
+	for _, secret := range r.Secrets {
+		entries = append(entries, secret.Entries()...)
+	}
+	return entries
+}
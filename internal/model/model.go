@@ -0,0 +1,134 @@
+// Package model holds the result formats shared by the scanner, the
+// analyzer and the cleaner: the aggregated JSON result of a scan and the
+// entries of a JSONL stream. The scanner writes them, the other packages
+// read them; keeping one declaration keeps the files they exchange in sync.
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatusRemovedInHistory flags values found in lines deleted by a commit
+const StatusRemovedInHistory = "removed-in-history"
+
+// StatusReintroduced flags values that a cleanup removed (recorded in the
+// baseline) and that were committed or written again afterwards
+const StatusReintroduced = "reintroduced"
+
+// InterruptedMarker is the last line of a JSONL output whose scan was
+// stopped before the end; it decodes as a StreamEntry with Interrupted set
+const InterruptedMarker = `{"interrupted":true}`
+
+// Secret represents a found secret
+type Secret struct {
+	File             string        `json:"file"`
+	Key              string        `json:"key"`
+	Type             string        `json:"type"`
+	Severity         string        `json:"severity,omitempty"` // Of the keyword group when scanned
+	ChangeCount      int           `json:"changeCount"`
+	TotalOccurrences int           `json:"totalOccurrences"`
+	Authors          []string      `json:"authors"`
+	History          []SecretValue `json:"history"`
+}
+
+// SecretValue represents a specific value of a secret
+type SecretValue struct {
+	Value       string     `json:"value"`
+	MaskedValue string     `json:"maskedValue"`
+	Commits     []string   `json:"commits"`
+	Authors     []string   `json:"authors"`
+	FirstSeen   string     `json:"firstSeen"`
+	LastSeen    string     `json:"lastSeen"`
+	Status      string     `json:"status,omitempty"` // StatusRemovedInHistory or StatusReintroduced
+	JWT         *JWTClaims `json:"jwt,omitempty"`    // Decoded claims when the value holds a JWT
+
+	// Location of the latest occurrence
+	Line    int          `json:"line,omitempty"`
+	Offset  int          `json:"offset,omitempty"` // Characters before the value in its line (split JSON lines)
+	Context *CodeContext `json:"context,omitempty"`
+}
+
+// ScanResult holds the complete scan results
+type ScanResult struct {
+	Repository   string     `json:"repository"`
+	Branch       string     `json:"branch"`
+	SecretsFound int        `json:"secretsFound"`
+	TotalValues  int        `json:"totalValues"`
+	Secrets      []Secret   `json:"secrets"`
+	ScanDate     time.Time  `json:"scanDate"`
+	Stats        *ScanStats `json:"stats,omitempty"`       // Data processed by the scan that wrote the file
+	Interrupted  bool       `json:"interrupted,omitempty"` // The scan was stopped: the findings are partial
+}
+
+// StreamEntry represents a single entry for streaming output
+type StreamEntry struct {
+	File        string     `json:"file"`
+	Key         string     `json:"key"`
+	Value       string     `json:"value"`
+	MaskedValue string     `json:"maskedValue"`
+	Type        string     `json:"type"`
+	Severity    string     `json:"severity,omitempty"` // Of the keyword group when scanned
+	Commit      string     `json:"commit"`
+	Author      string     `json:"author"`
+	Date        string     `json:"date"`
+	Status      string     `json:"status,omitempty"` // StatusRemovedInHistory or StatusReintroduced
+	JWT         *JWTClaims `json:"jwt,omitempty"`    // Decoded claims when the value holds a JWT
+
+	Line    int          `json:"line,omitempty"`    // Line number in the file (0 if unknown)
+	Offset  int          `json:"offset,omitempty"`  // Characters before the value in its line (split JSON lines)
+	Context *CodeContext `json:"context,omitempty"` // Lines around the finding, secrets masked
+
+	Interrupted bool `json:"interrupted,omitempty"` // Only set on InterruptedMarker, which is no finding
+}
+
+// CodeContext holds the lines around a finding, secrets masked
+type CodeContext struct {
+	Start int      `json:"start"` // Line number of the first line
+	Lines []string `json:"lines"`
+}
+
+// JWTClaims is what a JWT says about itself, decoded without verifying the
+// signature, to prioritize rotation
+type JWTClaims struct {
+	Algorithm string `json:"alg,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	ExpiresAt string `json:"exp,omitempty"` // RFC3339; empty if the token never expires
+	Expired   bool   `json:"expired"`       // At scan time
+}
+
+// ExpiredAt reports whether the token has expired at t
+func (c *JWTClaims) ExpiredAt(t time.Time) bool {
+	exp, err := time.Parse(time.RFC3339, c.ExpiresAt)
+	return err == nil && !exp.After(t)
+}
+
+// ScanStats measures the data a scan went through: how much of the
+// repository it covered, and a base to compare the speed of versions
+type ScanStats struct {
+	Commits int   `json:"commits"` // Commits walked
+	Files   int   `json:"files"`   // Working-tree files read
+	Lines   int   `json:"lines"`   // Lines matched against the keywords
+	Bytes   int64 `json:"bytes"`   // Bytes of diff and file content read
+}
+
+// String summarizes the statistics on one line
+func (s ScanStats) String() string {
+	return fmt.Sprintf("%d commits, %d files, %d lines, %s", s.Commits, s.Files, s.Lines, FormatBytes(s.Bytes))
+}
+
+// FormatBytes writes a size in B, KB, MB or GB
+func FormatBytes(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size, unit := float64(n)/1024, "KB"
+	for _, next := range []string{"MB", "GB"} {
+		if size < 1024 {
+			break
+		}
+		size, unit = size/1024, next
+	}
+	return fmt.Sprintf("%.1f %s", size, unit)
+}
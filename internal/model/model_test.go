@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanResultRoundTrip(t *testing.T) {
+	result := ScanResult{
+		Repository:   "/repo",
+		Branch:       "--all",
+		SecretsFound: 1,
+		TotalValues:  2,
+		Secrets: []Secret{{
+			File: "app.conf", Key: "password", Type: "password", Severity: "high",
+			ChangeCount: 2, TotalOccurrences: 3, Authors: []string{"alice", "bob"},
+			History: []SecretValue{
+				{Value: "hunter22", MaskedValue: "hu****22", Commits: []string{"c2", "c1"}, Authors: []string{"alice"},
+					FirstSeen: "2024-01-01T00:00:00Z", LastSeen: "2024-02-01T00:00:00Z", Status: StatusRemovedInHistory,
+					Line: 3, Context: &CodeContext{Start: 2, Lines: []string{"[db]", "password = hu****22"}}},
+				{Value: "eyJhbGciOi.eyJzdWIiOi.sig", Commits: []string{"c3"}, Authors: []string{"bob"},
+					FirstSeen: "2024-03-01T00:00:00Z", LastSeen: "2024-03-01T00:00:00Z",
+					JWT: &JWTClaims{Algorithm: "HS256", ExpiresAt: "2024-06-01T00:00:00Z", Expired: true}},
+			},
+		}},
+		ScanDate:    time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC),
+		Stats:       &ScanStats{Commits: 3, Lines: 40, Bytes: 2048},
+		Interrupted: true,
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ScanResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, result) {
+		t.Errorf("JSON round trip changed the result:\n got %+v\nwant %+v", decoded, result)
+	}
+
+	entries := result.Entries()
+	if len(entries) != 2 || entries[0].Commit != "c2" || entries[0].Date != "2024-02-01T00:00:00Z" ||
+		entries[0].Status != StatusRemovedInHistory || entries[1].JWT == nil || entries[1].Author != "bob" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestStreamEntryRoundTrip(t *testing.T) {
+	entry := StreamEntry{
+		File: "app.conf", Key: "api_key", Value: "sk_live_123456", MaskedValue: "sk**********56",
+		Type: "api_key", Severity: "critical", Commit: "abc123", Author: "alice", Date: "2024-01-01T00:00:00Z",
+		Status: StatusReintroduced, Line: 7, Offset: 12,
+		Context: &CodeContext{Start: 6, Lines: []string{"", "api_key = sk**********56"}},
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded StreamEntry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, entry) {
+		t.Errorf("JSON round trip changed the entry:\n got %+v\nwant %+v", decoded, entry)
+	}
+
+	secret := EntrySecret(entry)
+	if len(secret.History) != 1 || secret.ChangeCount != 1 || secret.History[0].Status != StatusReintroduced {
+		t.Fatalf("unexpected secret: %+v", secret)
+	}
+	if back := secret.Entries(); len(back) != 1 || !reflect.DeepEqual(back[0], entry) {
+		t.Errorf("entry -> secret -> entry changed the entry:\n got %+v\nwant %+v", back, entry)
+	}
+
+	var marker StreamEntry
+	if err := json.Unmarshal([]byte(InterruptedMarker), &marker); err != nil || !marker.Interrupted {
+		t.Errorf("InterruptedMarker does not decode as a marker entry: %+v, %v", marker, err)
+	}
+}
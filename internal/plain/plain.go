@@ -0,0 +1,70 @@
+// Package plain is the plain output mode (--plain, NO_COLOR, TERM=dumb): no
+// colors or text styles, and ASCII in place of emoji, symbols and box
+// drawing, so the output stays readable when piped, in dumb terminals and
+// with screen readers.
+package plain
+
+import (
+	"os"
+	"strings"
+)
+
+// enabled starts from the environment: NO_COLOR (https://no-color.org/) set
+// to anything, or a dumb terminal
+var enabled = os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb"
+
+// Enable turns the plain mode on (--plain)
+func Enable() {
+	enabled = true
+}
+
+// Enabled reports whether the output is plain
+func Enabled() bool {
+	return enabled
+}
+
+// replacer spells out status symbols, drops decorative emoji and draws
+// arrows, bullets, bars and boxes in ASCII. Longer sequences come first:
+// they win over their prefixes.
+var replacer = strings.NewReplacer(
+	// Status symbols
+	"⚠️  WARNING: ", "WARNING: ",
+	"⚠️  ", "WARNING: ",
+	"⚠ ", "WARNING: ",
+	"ℹ️  ", "NOTE: ",
+	"✓ ", "OK: ",
+	"✗ ", "ERROR: ",
+	"✅ ", "",
+	"❌ ", "",
+	"⏹ ", "",
+
+	// Decorative emoji
+	"⚙️  ", "",
+	"🔑 ", "", "🔗 ", "", "🧪 ", "", "📄 ", "", "📁 ", "", "📂 ", "", "📊 ", "",
+	"🔍 ", "", "🔧 ", "", "📦 ", "", "📋 ", "", "📝 ", "", "🧹 ", "", "⚡ ", "- ",
+	"️", "",
+
+	// Arrows, bullets and marks
+	"↑/↓", "up/down", "←/→", "left/right",
+	"↑", "up", "↓", "down", "←", "left", "→", "->",
+	"▸", ">", "›", ">",
+	"  • ", "  - ", " • ", ", ", "•", "-",
+	"…", "...", "—", "-", "·", "-",
+	"▲", "^", "▼", "v", "◆", "+", "●", "*", "○", "o",
+	"█", "#", "░", ".",
+
+	// Box drawing
+	"─", "-", "━", "-", "═", "=",
+	"│", "|", "┃", "|", "║", "|",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+", "├", "+", "┤", "+",
+	"┬", "+", "┴", "+", "┼", "+", "╭", "+", "╮", "+", "╰", "+", "╯", "+",
+)
+
+// Text rewrites s in ASCII when the output is plain, and returns it as is
+// otherwise
+func Text(s string) string {
+	if !enabled {
+		return s
+	}
+	return replacer.Replace(s)
+}
@@ -0,0 +1,27 @@
+package plain
+
+import "testing"
+
+func TestText(t *testing.T) {
+	defer func(was bool) { enabled = was }(enabled)
+
+	enabled = false
+	if got := Text("✓ Done • ↑/↓: move"); got != "✓ Done • ↑/↓: move" {
+		t.Errorf("Text changed the output outside plain mode: %q", got)
+	}
+
+	enabled = true
+	for in, want := range map[string]string{
+		"✓ Installed":                        "OK: Installed",
+		"⚠️  WARNING: This will rewrite git": "WARNING: This will rewrite git",
+		"⚙️  Configuration":                  "Configuration",
+		"↑/↓: navigate • esc: back":          "up/down: navigate, esc: back",
+		"  • item":                           "  - item",
+		"╭──╮\n│ a│\n╰──╯":                   "+--+\n| a|\n+--+",
+		"[▲ CRIT] a…":                        "[^ CRIT] a...",
+	} {
+		if got := Text(in); got != want {
+			t.Errorf("Text(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
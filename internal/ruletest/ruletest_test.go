@@ -0,0 +1,48 @@
+package ruletest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+func TestRunComparesFindingsWithAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.conf": "# expect: password\n" +
+			"db_passwd=SuperSecret123!\n" + // Matches the group name
+			"# expect: none\n" +
+			"timeout=30\n" +
+			"api_key=abcdefghijklmnop123\n" + // Not annotated: unexpected
+			"// expect: secret\n" +
+			"color=blue\n", // Missed
+		"notes.md": "# expect: token\ntoken=abcdefghijklmnop123\n", // Ignored file
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := Run(config.DefaultConfig(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Files != 1 || len(report.Ignored) != 1 || report.Ignored[0] != "notes.md" {
+		t.Errorf("files = %d, ignored = %v, want app.conf matched and notes.md ignored", report.Files, report.Ignored)
+	}
+	if report.TruePositives != 1 || report.FalseNegatives != 2 || report.FalsePositives != 1 {
+		t.Errorf("got %d found, %d missed, %d unexpected, want 1, 2 and 1", report.TruePositives, report.FalseNegatives, report.FalsePositives)
+	}
+	if len(report.Unexpected) != 1 || report.Unexpected[0].Line != 5 || report.Unexpected[0].Key != "api_key" {
+		t.Errorf("unexpected = %+v, want api_key on line 5", report.Unexpected)
+	}
+	if len(report.Missed) != 2 || report.Missed[0].Line != 7 || report.Missed[0].Type != "secret" {
+		t.Errorf("missed = %+v, want secret on line 7 first", report.Missed)
+	}
+	if report.Precision() != 0.5 || report.Recall() != 1.0/3 {
+		t.Errorf("precision = %v, recall = %v, want 0.5 and 1/3", report.Precision(), report.Recall())
+	}
+}
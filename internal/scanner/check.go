@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CheckRepository verifies that a path is a directory inside a git
+// repository, before a scan or a clean is started on it
+func CheckRepository(repoPath string) error {
+	info, err := os.Stat(repoPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s does not exist", repoPath)
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", repoPath)
+	}
+	if _, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true}); err != nil {
+		return fmt.Errorf("%s is not a git repository", repoPath)
+	}
+	return nil
+}
+
+// CheckRevisions verifies that the revisions of a history scan (a branch,
+// --all, a v1.0..HEAD range) resolve in the repository, with the backend a
+// scan would use. The history is not walked.
+func CheckRevisions(repoPath, revisions string) error {
+	if !(ScanOptions{}).useGoGit() {
+		return checkRange(repoPath, revisions)
+	}
+
+	repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	resolve := func(rev string) error {
+		if rev == "" {
+			rev = "HEAD"
+		}
+		if _, err := repo.ResolveRevision(plumbing.Revision(rev)); err != nil {
+			return fmt.Errorf("invalid revision %q: %w", rev, err)
+		}
+		return nil
+	}
+	for _, rev := range strings.Fields(revisions) {
+		switch {
+		case rev == "--all":
+		case isRefSelector(rev):
+			option, pattern, _ := refSelector(rev)
+			refs, err := selectedRefCommits(repo, option, pattern)
+			if err != nil {
+				return err
+			}
+			if len(refs) == 0 {
+				return fmt.Errorf("no ref matches %q", rev)
+			}
+		case strings.Contains(rev, "..."):
+			return fmt.Errorf("symmetric range %q needs the git backend", rev)
+		case strings.Contains(rev, ".."):
+			from, to, _ := strings.Cut(rev, "..")
+			if err := resolve(from); err != nil {
+				return err
+			}
+			if err := resolve(to); err != nil {
+				return err
+			}
+		default:
+			if err := resolve(strings.TrimPrefix(rev, "^")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
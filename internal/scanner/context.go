@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+)
+
+// DefaultContextLines is the number of lines kept before and after a finding
+// by the CLI and the TUI
+const DefaultContextLines = 2
+
+// maxContextLine bounds the length of a context line (minified files)
+const maxContextLine = 200
+
+// CodeContext holds the lines around a finding, secrets masked
+type CodeContext = model.CodeContext
+
+// contextWindow follows the lines of one file (or one side of a diff) and
+// numbers its findings. With a size, the findings are held back until the
+// lines after them are read, then emitted with their context.
+type contextWindow struct {
+	scanner *Scanner
+	size    int
+	emit    func(f finding)
+	line    int      // Number of the last line pushed
+	before  []string // Last lines pushed, the current one included
+	pending []*finding
+}
+
+// newContextWindow creates a window keeping size lines around findings
+func (s *Scanner) newContextWindow(size int, emit func(f finding)) *contextWindow {
+	return &contextWindow{scanner: s, size: max(size, 0), emit: emit}
+}
+
+// push passes the next line, numbered n. A gap in the numbering (next hunk)
+// ends the context of the pending findings.
+func (w *contextWindow) push(n int, line string) {
+	if n != w.line+1 {
+		w.flush()
+	}
+	w.line = n
+	if w.size == 0 {
+		return
+	}
+
+	kept := w.pending[:0]
+	for _, f := range w.pending {
+		f.context.Lines = append(f.context.Lines, w.scanner.contextLine(line, f.value))
+		if len(f.context.Lines) > f.line-f.context.Start+w.size {
+			w.emit(*f)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+	w.pending = kept
+
+	if len(w.before) > w.size {
+		copy(w.before, w.before[1:])
+		w.before = w.before[:w.size]
+	}
+	w.before = append(w.before, line)
+}
+
+// add emits a finding of the last line pushed, once its context is read
+func (w *contextWindow) add(f finding) {
+	f.line = w.line
+	if w.size == 0 {
+		w.emit(f)
+		return
+	}
+	f.context = &CodeContext{Start: w.line - len(w.before) + 1}
+	for _, line := range w.before {
+		f.context.Lines = append(f.context.Lines, w.scanner.contextLine(line, f.value))
+	}
+	w.pending = append(w.pending, &f)
+}
+
+// flush emits the pending findings with the context read so far and
+// restarts the numbering (end of file or of hunk)
+func (w *contextWindow) flush() {
+	for _, f := range w.pending {
+		w.emit(*f)
+	}
+	w.pending = w.pending[:0]
+	w.before = w.before[:0]
+	w.line = 0
+}
+
+// contextLine masks value, and any other secret the line holds, and
+// shortens the line
+func (s *Scanner) contextLine(line, value string) string {
+	line = strings.ReplaceAll(line, value, maskSecret(value))
+	if _, _, other, ok := s.matchLine(line); ok && other != value {
+		line = strings.ReplaceAll(line, other, maskSecret(other))
+	}
+	if len(line) > maxContextLine {
+		cut := maxContextLine
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		line = line[:cut] + "…"
+	}
+	return line
+}
+
+// diffContext numbers the findings of a unified diff: added lines on the
+// new side of the hunks, deleted lines on the old side
+type diffContext struct {
+	added, removed *contextWindow
+}
+
+// newDiffContext creates the windows of both sides of a diff
+func (s *Scanner) newDiffContext(size int, emit func(f finding)) *diffContext {
+	return &diffContext{added: s.newContextWindow(size, emit), removed: s.newContextWindow(size, emit)}
+}
+
+// feed passes a diff line, after changedLine has updated the parser
+func (d *diffContext) feed(p *diffParser, line string) {
+	if !p.inHunk || p.file == "" {
+		d.flush()
+		return
+	}
+	switch {
+	case line == "" || line[0] == ' ':
+		d.added.push(p.newLine, strings.TrimPrefix(line, " "))
+		d.removed.push(p.oldLine, strings.TrimPrefix(line, " "))
+	case line[0] == '+':
+		d.added.push(p.newLine, line[1:])
+	case line[0] == '-':
+		d.removed.push(p.oldLine, line[1:])
+	case line[0] == '@':
+		d.flush()
+	}
+}
+
+// add emits a finding of the last line fed, on the side it was found
+func (d *diffContext) add(f finding) {
+	if f.removed {
+		d.removed.add(f)
+	} else {
+		d.added.add(f)
+	}
+}
+
+// flush emits the pending findings of both sides
+func (d *diffContext) flush() {
+	d.added.flush()
+	d.removed.flush()
+}
@@ -0,0 +1,357 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// diffParser tracks the file and hunk state while reading a unified diff
+type diffParser struct {
+	file   string
+	inHunk bool
+
+	// Line numbers of the current line in the old and new file
+	oldLine, newLine int
+}
+
+// reset starts a new patch (e.g. at a commit header)
+func (p *diffParser) reset() {
+	p.file = ""
+	p.inHunk = false
+	p.oldLine, p.newLine = 0, 0
+}
+
+// hunkHeader reads the line numbers a hunk header "@@ -a,b +c,d @@" starts at
+func (p *diffParser) hunkHeader(line string) {
+	p.oldLine, p.newLine = 0, 0
+	for _, field := range strings.Fields(line)[1:] {
+		if len(field) < 2 {
+			continue
+		}
+		start, _, _ := strings.Cut(field[1:], ",")
+		n, _ := strconv.Atoi(start)
+		switch field[0] {
+		case '-':
+			p.oldLine = n - 1
+		case '+':
+			p.newLine = n - 1
+			return
+		}
+	}
+}
+
+// count numbers a line of the current hunk
+func (p *diffParser) count(line string) {
+	switch {
+	case line == "" || line[0] == ' ':
+		p.oldLine++
+		p.newLine++
+	case line[0] == '+':
+		p.newLine++
+	case line[0] == '-':
+		p.oldLine++
+	}
+}
+
+// line returns the number of the current line, on the old side for a
+// deleted line
+func (p *diffParser) line(removed bool) int {
+	if removed {
+		return p.oldLine
+	}
+	return p.newLine
+}
+
+// changedLine feeds one diff line to the parser and returns the content of
+// added lines (and of deleted lines withRemoved), with the file they belong
+// to. Ignored files yield nothing.
+func (s *Scanner) changedLine(p *diffParser, line string, withRemoved bool) (file, text string, removed, ok bool) {
+	if strings.HasPrefix(line, "diff --git") {
+		p.reset()
+		if idx := strings.Index(line, " b/"); idx != -1 {
+			p.file = line[idx+3:]
+		}
+		return "", "", false, false
+	}
+
+	if !p.inHunk {
+		// File header: take the new path from "+++ b/..." when present
+		if strings.HasPrefix(line, "+++ ") {
+			if strings.HasPrefix(line, "+++ b/") {
+				p.file = line[6:]
+			}
+			// Check if file should be ignored
+			if p.file != "" && s.config.ShouldIgnoreFile(p.file) {
+				p.file = "" // Reset to skip this file
+			}
+		} else if strings.HasPrefix(line, "@@") {
+			p.inHunk = true
+			p.hunkHeader(line)
+		}
+		return "", "", false, false
+	}
+
+	if strings.HasPrefix(line, "@@") {
+		p.hunkHeader(line)
+		return "", "", false, false
+	}
+	p.count(line)
+	if p.file == "" || line == "" {
+		return "", "", false, false
+	}
+	switch {
+	case line[0] == '+':
+		return p.file, line[1:], false, true
+	case line[0] == '-' && withRemoved:
+		return p.file, line[1:], true, true
+	}
+	return "", "", false, false
+}
+
+// patchCommit marks findings from a patch file without commit headers
+var patchCommit = commitInfo{hash: "patch", author: "patch"}
+
+// walkPatch matches the added lines of a unified diff, attributing every
+// finding to commit. Patches written by `git format-patch` carry their own
+// commit headers, which take precedence.
+func (s *Scanner) walkPatch(r io.Reader, commit commitInfo, opts ScanOptions, emit func(f finding)) int {
+	read := &countingReader{r: r}
+	reader := bufio.NewReaderSize(read, maxLineLength)
+	var patch diffParser
+	var pems pemDiff
+	var tf terraformWalker
+	lines := s.newDiffContext(opts.Context, emit)
+	var inHeader bool
+	var found, skipped, commits, matched int
+
+	for {
+		line, tooLong, err := readLineHead(reader)
+		if err != nil {
+			break
+		}
+		if tooLong {
+			if n, ok := s.splitDiffLine(&patch, line, reader, false, commit, emit); ok {
+				found += n
+			} else {
+				skipped++
+			}
+			continue
+		}
+
+		// format-patch: "From <hash> <date>" starts a commit, followed by
+		// mail headers and the message up to the first diff
+		if hash, ok := mboxCommit(line); ok {
+			commit = commitInfo{hash: hash, author: "unknown"}
+			patch.reset()
+			inHeader = true
+			commits++
+			continue
+		}
+		if inHeader {
+			if !strings.HasPrefix(line, "diff --git") {
+				if author, ok := strings.CutPrefix(line, "From: "); ok {
+					if i := strings.Index(author, " <"); i != -1 {
+						author = author[:i]
+					}
+					commit.author = s.config.AuthorIdentity(strings.Trim(author, `"`))
+				} else if date, ok := strings.CutPrefix(line, "Date: "); ok {
+					if t, err := mail.ParseDate(date); err == nil {
+						commit.date = t.Format(time.RFC3339)
+					}
+				}
+				continue
+			}
+			inHeader = false
+		}
+
+		file, added, _, ok := s.changedLine(&patch, line, false)
+		tf.feedDiff(&patch, line)
+		lines.feed(&patch, line)
+		pems.feed(&patch, line, false, func(file, label, block string, _ bool) {
+			found++
+			emit(finding{file: file, key: label, value: block, keyword: PEMKeyword, commit: commit, line: pemLine(&patch, block, false)})
+		})
+		if !ok {
+			continue
+		}
+		matched++
+		if len(added) >= minifiedLineLength && isJSONLine(added) {
+			found += emitSplit(s.splitJSON(added, nil), finding{file: file, commit: commit, line: patch.line(false)}, emit)
+			continue
+		}
+		keyword, key, value, ok := s.matchFileLine(file, added, &tf)
+		if !ok {
+			continue
+		}
+		found++
+		lines.add(finding{file: file, key: key, value: value, keyword: keyword, commit: commit})
+	}
+	lines.flush()
+
+	commits = max(commits, 1)
+	opts.report(Progress{Phase: "diff", Current: commits, Total: commits, Commits: commits, Found: found, Skipped: skipped, Lines: matched, Bytes: read.n})
+	return found
+}
+
+// mboxCommit returns the commit hash of a format-patch "From <hash> ..." line
+func mboxCommit(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, "From ")
+	if !ok || len(rest) < 41 || rest[40] != ' ' {
+		return "", false
+	}
+	for _, c := range rest[:40] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return "", false
+		}
+	}
+	return rest[:40], true
+}
+
+// headCommit describes HEAD, to which lines of a branch diff are attributed
+func headCommit(repoPath string) (commitInfo, error) {
+	cmd := exec.Command("git", "log", "-1", "--pretty=format:%H|%an|%aI", "HEAD")
+	cmd.Dir = repoPath
+	done := debugbundle.Track(cmd)
+	out, err := cmd.Output()
+	done(err)
+	if err != nil {
+		return commitInfo{}, fmt.Errorf("cannot resolve HEAD: %w", err)
+	}
+	parts := strings.SplitN(strings.TrimSpace(string(out)), "|", 3)
+	if len(parts) < 3 {
+		return commitInfo{}, fmt.Errorf("cannot resolve HEAD")
+	}
+	return commitInfo{hash: parts[0], author: parts[1], date: parts[2]}, nil
+}
+
+// walkDiff matches the lines added on HEAD since it diverged from base
+// (`git diff base...HEAD`), so only secrets introduced by the branch are reported
+func (s *Scanner) walkDiff(repoPath, base string, opts ScanOptions, emit func(f finding)) (int, error) {
+	emit = flagReintroduced(repoPath, emit)
+	if err := s.useRepoIgnores(repoPath); err != nil {
+		return 0, err
+	}
+	if err := checkRange(repoPath, base+"...HEAD"); err != nil {
+		return 0, err
+	}
+	head, err := headCommit(repoPath)
+	if err != nil {
+		return 0, err
+	}
+	head.author = s.config.AuthorIdentity(head.author)
+
+	args := []string{
+		"-c", "core.quotepath=off",
+		"diff", base + "...HEAD",
+		"--no-color",
+		"--no-ext-diff",
+	}
+	args = append(args, s.pathspecArgs()...)
+
+	cmd := opts.gitCommand(args...)
+	cmd.Dir = repoPath
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, err
+	}
+	done := debugbundle.Track(cmd)
+	if err := cmd.Start(); err != nil {
+		done(err)
+		return 0, err
+	}
+
+	found := s.walkPatch(stdout, head, opts, emit)
+	err = cmd.Wait()
+	done(err)
+	if err := opts.interrupted(); err != nil {
+		return found, err
+	}
+	if err != nil {
+		return found, fmt.Errorf("git diff failed: %w", err)
+	}
+	return found, nil
+}
+
+// ScanDiff scans only the lines a branch adds on top of base
+func (s *Scanner) ScanDiff(repoPath, base string, opts ScanOptions) (*ScanResult, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return nil, err
+	}
+	opts, stats := opts.collectStats()
+	index := newSecretIndex()
+	_, err := s.walkDiff(repoPath, base, opts, index.add)
+	return s.walkResult(index, repoPath, base+"...HEAD", stats, err)
+}
+
+// ScanDiffStream scans only the lines a branch adds on top of base to JSONL
+func (s *Scanner) ScanDiffStream(repoPath, base, outputPath string, opts ScanOptions) (int, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return 0, err
+	}
+	w, err := newStreamWriter(outputPath, s.config)
+	if err != nil {
+		return 0, err
+	}
+	defer w.Close()
+
+	_, err = s.walkDiff(repoPath, base, opts, w.write)
+	return w.count, w.finish(err)
+}
+
+// openPatch opens a patch file, or standard input for "-"
+func openPatch(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// ScanPatch scans the added lines of a unified diff file (plain `git diff`
+// output or `git format-patch` mails) without needing the repository
+func (s *Scanner) ScanPatch(patchPath string, opts ScanOptions) (*ScanResult, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return nil, err
+	}
+	r, err := openPatch(patchPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	opts, stats := opts.collectStats()
+	index := newSecretIndex()
+	s.walkPatch(r, patchCommit, opts, index.add)
+	result := index.result(patchPath, "patch", s.config)
+	result.Stats = stats()
+	return result, nil
+}
+
+// ScanPatchStream scans the added lines of a unified diff file to JSONL
+func (s *Scanner) ScanPatchStream(patchPath, outputPath string, opts ScanOptions) (int, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return 0, err
+	}
+	r, err := openPatch(patchPath)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	w, err := newStreamWriter(outputPath, s.config)
+	if err != nil {
+		return 0, err
+	}
+	defer w.Close()
+
+	s.walkPatch(r, patchCommit, opts, w.write)
+	return w.count, nil
+}
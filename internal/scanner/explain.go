@@ -0,0 +1,53 @@
+package scanner
+
+import "strings"
+
+// Explanation tells how a line goes through the rules, step by step (TUI
+// pattern playground)
+type Explanation struct {
+	Keyword string // First keyword in the line ("" if none)
+	Group   string // Its keyword group
+	Pattern string // First extraction pattern matching the line ("" if none)
+	Key     string // Key and value the pattern extracts
+	Value   string
+	Ignored bool   // The value is dropped by the ignore rules (ShouldIgnoreValue)
+	Rejects string // Keyword whose group rejects the value (valuePattern), if no group accepts it
+
+	// What a scan reports for the line (URL credentials and JWTs match
+	// whatever the keywords)
+	Found      bool
+	FoundType  string
+	FoundKey   string
+	FoundValue string
+}
+
+// Explain matches a line of a generic file and records each step
+func (s *Scanner) Explain(line string) Explanation {
+	var e Explanation
+	if keyword, ok := s.findKeyword(line, nil); ok {
+		e.Keyword = keyword
+		if group, ok := s.config.KeywordGroupOf(keyword); ok {
+			e.Group = group.Name
+		}
+	}
+	for _, pattern := range s.extractionPatterns {
+		match := pattern.Regex.FindStringSubmatch(line)
+		if match != nil && len(match) > pattern.ValueGroup {
+			e.Pattern = pattern.Name
+			if len(match) > 1 {
+				e.Key = strings.TrimSpace(match[1])
+			}
+			e.Value = strings.TrimSpace(match[pattern.ValueGroup])
+			e.Ignored = s.config.ShouldIgnoreValue(e.Value)
+			break
+		}
+	}
+	if e.Keyword != "" && e.Pattern != "" && !e.Ignored {
+		if _, ok := s.matchKeyword(line, e.Value); !ok {
+			e.Rejects = e.Keyword
+		}
+	}
+
+	e.FoundType, e.FoundKey, e.FoundValue, e.Found = s.matchLine(line)
+	return e
+}
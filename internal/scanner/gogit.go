@@ -0,0 +1,318 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// History backends (ScanOptions.Backend)
+const (
+	BackendAuto  = ""       // git when installed, else go-git
+	BackendGit   = "git"    // Shell out to `git log -p`
+	BackendGoGit = "go-git" // Built-in Go implementation, no git binary needed
+)
+
+// useGoGit reports whether the history walk runs without the git binary
+func (o ScanOptions) useGoGit() bool {
+	switch o.Backend {
+	case BackendGoGit:
+		return true
+	case BackendAuto:
+		_, err := exec.LookPath("git")
+		return err != nil
+	}
+	return false
+}
+
+// historyStream is the textual history read by walkHistory. wait releases
+// the producer and returns its error.
+type historyStream struct {
+	io.Reader
+	wait func() error
+}
+
+// gitHistory runs `git log -p` over the revisions of opts
+func (s *Scanner) gitHistory(repoPath string, opts ScanOptions) (*historyStream, int, error) {
+	if opts.Range != "" {
+		if err := checkRange(repoPath, opts.Range); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	total := countCommits(repoPath, opts.revisions(), opts.Exclude)
+
+	cmd := opts.gitCommand(s.historyArgs(opts.revisions(), opts.Exclude)...)
+	cmd.Dir = repoPath
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, 0, err
+	}
+	done := debugbundle.Track(cmd)
+	if err := cmd.Start(); err != nil {
+		done(err)
+		return nil, 0, err
+	}
+
+	wait := func() error {
+		err := cmd.Wait()
+		done(err)
+		if err != nil {
+			return fmt.Errorf("git log failed: %w", err)
+		}
+		return nil
+	}
+	return &historyStream{Reader: stdout, wait: wait}, total, nil
+}
+
+// goGitHistory produces the same stream as gitHistory with go-git: commits
+// newest first, each with its message and its patch against the first
+// parent. Like `git log -p`, merge commits have no patch.
+func (s *Scanner) goGitHistory(repoPath string, opts ScanOptions) (*historyStream, int, error) {
+	repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	commits, err := walkRevisions(repo, opts.revisions(), opts.Exclude)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		for _, c := range commits {
+			if err = s.writeCommit(pw, c); err != nil {
+				break
+			}
+		}
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	wait := func() error {
+		// Unblocks the producer if the reader stopped early
+		pr.Close()
+		if err := <-done; err != nil && err != io.ErrClosedPipe {
+			return fmt.Errorf("history walk failed: %w", err)
+		}
+		return nil
+	}
+	return &historyStream{Reader: pr, wait: wait}, len(commits), nil
+}
+
+// walkRevisions returns the commits reachable from the revisions (refs,
+// --all, A..B ranges and ^A exclusions) but not from exclude, newest first
+func walkRevisions(repo *git.Repository, revisions string, exclude []string) ([]*object.Commit, error) {
+	var include, hide []plumbing.Hash
+
+	resolve := func(rev string) (plumbing.Hash, error) {
+		if rev == "" {
+			rev = "HEAD"
+		}
+		hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("invalid revision %q: %w", rev, err)
+		}
+		return *hash, nil
+	}
+
+	revs := strings.Fields(revisions)
+	if len(revs) == 0 {
+		revs = []string{"HEAD"}
+	}
+	for _, rev := range revs {
+		switch {
+		case rev == "--all":
+			refs, err := allRefCommits(repo)
+			if err != nil {
+				return nil, err
+			}
+			include = append(include, refs...)
+		case isRefSelector(rev):
+			option, pattern, _ := refSelector(rev)
+			refs, err := selectedRefCommits(repo, option, pattern)
+			if err != nil {
+				return nil, err
+			}
+			include = append(include, refs...)
+		case strings.HasPrefix(rev, "^"):
+			hash, err := resolve(rev[1:])
+			if err != nil {
+				return nil, err
+			}
+			hide = append(hide, hash)
+		case strings.Contains(rev, "..."):
+			return nil, fmt.Errorf("symmetric range %q needs the git backend", rev)
+		case strings.Contains(rev, ".."):
+			from, to, _ := strings.Cut(rev, "..")
+			fromHash, err := resolve(from)
+			if err != nil {
+				return nil, err
+			}
+			toHash, err := resolve(to)
+			if err != nil {
+				return nil, err
+			}
+			hide = append(hide, fromHash)
+			include = append(include, toHash)
+		default:
+			hash, err := resolve(rev)
+			if err != nil {
+				return nil, err
+			}
+			include = append(include, hash)
+		}
+	}
+	for _, rev := range exclude {
+		hash, err := resolve(rev)
+		if err != nil {
+			return nil, err
+		}
+		hide = append(hide, hash)
+	}
+
+	hidden := make(map[plumbing.Hash]bool)
+	if _, err := reachable(repo, hide, hidden, nil); err != nil {
+		return nil, err
+	}
+	commits, err := reachable(repo, include, make(map[plumbing.Hash]bool), hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same order as git log: most recent commit date first
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Committer.When.After(commits[j].Committer.When)
+	})
+	return commits, nil
+}
+
+// reachable returns the commits reachable from the starting points, marking
+// them in seen and stopping at the commits in stop
+func reachable(repo *git.Repository, from []plumbing.Hash, seen, stop map[plumbing.Hash]bool) ([]*object.Commit, error) {
+	var commits []*object.Commit
+	queue := append([]plumbing.Hash(nil), from...)
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		if seen[hash] || stop[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit %s: %w", hash, err)
+		}
+		commits = append(commits, commit)
+		queue = append(queue, commit.ParentHashes...)
+	}
+	return commits, nil
+}
+
+// allRefCommits returns the commits pointed to by every branch, tag and
+// remote ref
+func allRefCommits(repo *git.Repository) ([]plumbing.Hash, error) {
+	return refCommits(repo, func(plumbing.ReferenceName) bool { return true })
+}
+
+// refCommits returns the commits of the refs whose name matches, annotated
+// tags peeled
+func refCommits(repo *git.Repository, match func(name plumbing.ReferenceName) bool) ([]plumbing.Hash, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []plumbing.Hash
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || !match(ref.Name()) {
+			return nil
+		}
+		hash := ref.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			commit, err := tag.Commit()
+			if err != nil {
+				return nil // Tag of a tree or blob
+			}
+			hash = commit.Hash
+		}
+		hashes = append(hashes, hash)
+		return nil
+	})
+	return hashes, err
+}
+
+// writeCommit writes one commit in the format of historyArgs
+func (s *Scanner) writeCommit(w io.Writer, c *object.Commit) error {
+	_, err := fmt.Fprintf(w, "COMMIT|%s|%s|%s\n%s\n%s\n%s\n",
+		c.Hash, c.Author.Name, c.Author.When.Format("2006-01-02T15:04:05-07:00"),
+		messageStart, strings.TrimRight(c.Message, "\n"), messageEnd)
+	if err != nil || c.NumParents() > 1 {
+		return err
+	}
+
+	tree, err := c.Tree()
+	if err != nil {
+		return err
+	}
+	var parentTree *object.Tree
+	if c.NumParents() == 1 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return err
+		}
+	}
+
+	changes, err := object.DiffTreeWithOptions(context.Background(), parentTree, tree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return err
+	}
+
+	// Same filter as pathspecArgs
+	kept := changes[:0]
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		if !s.hasBinaryExtension(name) {
+			kept = append(kept, change)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+
+	patch, err := kept.Patch()
+	if err != nil {
+		return err
+	}
+	return patch.Encode(w)
+}
+
+// hasBinaryExtension reports whether a path ends with an excluded extension
+func (s *Scanner) hasBinaryExtension(path string) bool {
+	for _, ext := range s.config.ExcludeBinaryExtensions {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+)
+
+// ErrInterrupted is returned by a scan stopped through ScanOptions.Interrupt.
+// Scans returning a result return what was found so far, marked
+// Interrupted; stream scans end their file with InterruptedMarker.
+var ErrInterrupted = errors.New("scan interrupted")
+
+// InterruptedMarker is the last line of a JSONL output whose scan was
+// interrupted. It holds no finding: readers skip it.
+const InterruptedMarker = model.InterruptedMarker
+
+// interruptGrace is how long an interrupted git process may take to exit
+// before it is killed
+const interruptGrace = 2 * time.Second
+
+// interrupted returns ErrInterrupted once the Interrupt context is done
+func (o ScanOptions) interrupted() error {
+	if o.Interrupt != nil && o.Interrupt.Err() != nil {
+		return ErrInterrupted
+	}
+	return nil
+}
+
+// gitCommand prepares a git process that receives an interrupt signal when
+// the scan is interrupted, and is killed if it does not exit in time
+func (o ScanOptions) gitCommand(args ...string) *exec.Cmd {
+	if o.Interrupt == nil {
+		return exec.Command("git", args...)
+	}
+	cmd := exec.CommandContext(o.Interrupt, "git", args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = interruptGrace
+	return cmd
+}
+
+// finish ends the output of a stream scan: an interrupted scan leaves
+// InterruptedMarker as the last line. err is returned as is.
+func (w *streamWriter) finish(err error) error {
+	if errors.Is(err, ErrInterrupted) {
+		w.file.WriteString(InterruptedMarker + "\n")
+	}
+	return err
+}
+
+// walkResult builds the result of a walk: an interrupted walk still returns
+// what it found, marked Interrupted, along with its error
+func (s *Scanner) walkResult(index secretIndex, repoPath, branch string, stats func() *ScanStats, err error) (*ScanResult, error) {
+	if err != nil && !errors.Is(err, ErrInterrupted) {
+		return nil, err
+	}
+	result := index.result(repoPath, branch, s.config)
+	result.Stats = stats()
+	result.Interrupted = err != nil
+	return result, err
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+)
+
+// JWTKeyword is the finding type of tokens recognized by shape (the
+// built-in keyword, so they get the token severity)
+const JWTKeyword = "token"
+
+// jwtPattern matches a compact JWT: base64url header and payload, both JSON
+// objects (so starting with "eyJ"), and a signature that may be empty
+var jwtPattern = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]*`)
+
+// JWTClaims is what a JWT says about itself, decoded without verifying the
+// signature, to prioritize rotation
+type JWTClaims = model.JWTClaims
+
+// findJWT returns the first JWT of a line whose header decodes
+func findJWT(line string) (string, bool) {
+	if !strings.Contains(line, "eyJ") {
+		return "", false
+	}
+	for _, token := range jwtPattern.FindAllString(line, -1) {
+		if decodeJWT(token, time.Now()) != nil {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// decodeJWT decodes the header and payload of the first JWT in value, or
+// returns nil when value holds none. Expiry is evaluated at now.
+func decodeJWT(value string, now time.Time) *JWTClaims {
+	if !strings.Contains(value, "eyJ") {
+		return nil
+	}
+	token := jwtPattern.FindString(value)
+	if token == "" {
+		return nil
+	}
+	parts := strings.SplitN(token, ".", 3)
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if !decodeSegment(parts[0], &header) || header.Alg == "" {
+		return nil
+	}
+	var payload struct {
+		Iss string          `json:"iss"`
+		Sub json.RawMessage `json:"sub"`
+		Exp json.Number     `json:"exp"`
+	}
+	if !decodeSegment(parts[1], &payload) {
+		return nil
+	}
+
+	claims := &JWTClaims{Algorithm: header.Alg, Issuer: payload.Iss}
+	if err := json.Unmarshal(payload.Sub, &claims.Subject); err != nil && len(payload.Sub) > 0 {
+		claims.Subject = string(payload.Sub) // Numeric subject
+	}
+	if exp, err := payload.Exp.Float64(); err == nil {
+		expires := time.Unix(int64(exp), 0).UTC()
+		claims.ExpiresAt = expires.Format(time.RFC3339)
+		claims.Expired = !expires.After(now)
+	}
+	return claims
+}
+
+// decodeSegment decodes a base64url JSON segment into v
+func decodeSegment(segment string, v any) bool {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
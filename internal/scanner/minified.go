@@ -0,0 +1,190 @@
+package scanner
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// minifiedLineLength is the length from which a line holding JSON is split
+// into its key/value pairs: minified or concatenated JSON keeps a whole
+// document on one line, where line matching finds one value at most
+const minifiedLineLength = 4096
+
+// maxSplitString bounds the strings kept while splitting; longer ones are
+// no secrets (maxSecretLength) and are skipped
+const maxSplitString = 64 * 1024
+
+// isJSONLine reports whether a line may hold a JSON document
+func isJSONLine(line string) bool {
+	line = strings.TrimLeft(line, " \t")
+	return line != "" && (line[0] == '{' || line[0] == '[')
+}
+
+// splitFinding is a finding of a split line, offset characters into it
+type splitFinding struct {
+	keyword, key, value string
+	offset              int
+}
+
+// splitJSON matches the string values of a JSON line as values of a
+// structured file (the keyword must be in the key, reported by key path).
+// When reader is not nil, the line was too long to be read whole: head is
+// its start and the rest is read from reader, chunk by chunk.
+func (s *Scanner) splitJSON(head string, reader *bufio.Reader) []splitFinding {
+	var found []splitFinding
+	split := jsonSplitter{emit: func(v config.StructuredValue, offset int) {
+		if keyword, key, value, ok := s.matchStructured(v); ok {
+			found = append(found, splitFinding{keyword: keyword, key: key, value: value, offset: offset})
+		}
+	}}
+	split.feed([]byte(head))
+	if reader != nil {
+		finishLine(reader, split.feed)
+	}
+	return found
+}
+
+// jsonLevel is an object or array open in a jsonSplitter
+type jsonLevel struct {
+	path      string
+	key       string // Key the level is the value of
+	array     bool
+	index     int    // Current item of an array
+	expectKey bool   // Next string of an object is a key
+	member    string // Current key of an object
+}
+
+// jsonSplitter tokenizes JSON fed in chunks and emits its string values
+// with their path and character offset. It only tracks nesting, so it
+// reads truncated and concatenated documents; values with escapes are
+// skipped, as they are not written as is in the file.
+type jsonSplitter struct {
+	emit  func(v config.StructuredValue, offset int)
+	chars int // Characters fed so far
+	stack []jsonLevel
+
+	inString, escaped, hasEscape, overflow bool
+	str                                    []byte
+	start                                  int // Offset of the current string
+}
+
+func (j *jsonSplitter) feed(chunk []byte) {
+	for _, c := range chunk {
+		if utf8.RuneStart(c) {
+			j.chars++
+		}
+		if j.inString {
+			j.feedString(c)
+			continue
+		}
+		switch c {
+		case '"':
+			j.inString, j.hasEscape, j.overflow = true, false, false
+			j.str = j.str[:0]
+			j.start = j.chars
+		case '{', '[':
+			path, key := j.valuePath()
+			j.stack = append(j.stack, jsonLevel{path: path, key: key, array: c == '[', expectKey: c == '{'})
+		case '}', ']':
+			if len(j.stack) > 0 {
+				j.stack = j.stack[:len(j.stack)-1]
+			}
+		case ':':
+			if top := j.top(); top != nil && !top.array {
+				top.expectKey = false
+			}
+		case ',':
+			if top := j.top(); top != nil {
+				if top.array {
+					top.index++
+				} else {
+					top.expectKey, top.member = true, ""
+				}
+			}
+		}
+	}
+}
+
+// feedString reads a character of a string
+func (j *jsonSplitter) feedString(c byte) {
+	switch {
+	case j.escaped:
+		j.escaped = false
+	case c == '\\':
+		j.escaped, j.hasEscape = true, true
+	case c == '"':
+		j.inString = false
+		j.endString()
+		return
+	}
+	if len(j.str) < maxSplitString {
+		j.str = append(j.str, c)
+	} else {
+		j.overflow = true
+	}
+}
+
+// endString handles a complete string, key or value
+func (j *jsonSplitter) endString() {
+	top := j.top()
+	if top != nil && !top.array && top.expectKey {
+		top.member = string(j.str)
+		if j.hasEscape {
+			if key, err := strconv.Unquote(`"` + top.member + `"`); err == nil {
+				top.member = key
+			}
+		}
+		return
+	}
+	path, key := j.valuePath()
+	if path == "" || j.hasEscape || j.overflow {
+		return
+	}
+	j.emit(config.StructuredValue{Path: path, Key: key, Value: string(j.str)}, j.start)
+}
+
+func (j *jsonSplitter) top() *jsonLevel {
+	if len(j.stack) == 0 {
+		return nil
+	}
+	return &j.stack[len(j.stack)-1]
+}
+
+// valuePath returns the path and key of the value at the current position
+func (j *jsonSplitter) valuePath() (path, key string) {
+	top := j.top()
+	switch {
+	case top == nil:
+		return "", ""
+	case top.array:
+		return top.path + "[" + strconv.Itoa(top.index) + "]", top.key
+	case top.path == "":
+		return top.member, top.member
+	}
+	return top.path + "." + top.member, top.member
+}
+
+// splitDiffLine matches a changed line of a diff too long to be read whole,
+// head being its start, when it holds JSON. Otherwise it reads the rest of
+// the line and returns false.
+func (s *Scanner) splitDiffLine(patch *diffParser, head string, reader *bufio.Reader, withRemoved bool, commit commitInfo, emit func(f finding)) (int, bool) {
+	file, text, removed, ok := s.changedLine(patch, head, withRemoved)
+	if !ok || commit.hash == "" || !isJSONLine(text) {
+		finishLine(reader, nil)
+		return 0, false
+	}
+	return emitSplit(s.splitJSON(text, reader), finding{file: file, commit: commit, removed: removed, line: patch.line(removed)}, emit), true
+}
+
+// emitSplit emits the findings of a split line, at the location of f
+func emitSplit(found []splitFinding, f finding, emit func(f finding)) int {
+	for _, sf := range found {
+		f.keyword, f.key, f.value, f.offset = sf.keyword, sf.key, sf.value, sf.offset
+		emit(f)
+	}
+	return len(found)
+}
@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"regexp"
+	"strings"
+)
+
+// PEMKeyword is the finding type of private key blocks (the built-in
+// keyword group, so they are critical)
+const PEMKeyword = "private_key"
+
+// pemBegin matches the first line of a PEM private key block, capturing the
+// label ("RSA PRIVATE KEY", "OPENSSH PRIVATE KEY", "PRIVATE KEY"...)
+var pemBegin = regexp.MustCompile(`-----BEGIN ((?:[A-Z0-9]+ )*PRIVATE KEY)-----`)
+
+// maxPEMLines bounds a block, so a BEGIN line without END cannot swallow a file
+const maxPEMLines = 200
+
+// pemBlock collects the lines of a private key block spread over several
+// lines, which the line-based extractor cannot see
+type pemBlock struct {
+	label   string
+	lines   []string
+	changed bool // A line of the block was added (or deleted) by the diff
+}
+
+// feed passes the next line of the same file. It returns the label and the
+// whole block (lines joined with "\n") once the END line is read, if one
+// of its lines was changed.
+func (b *pemBlock) feed(line string, changed bool) (label, block string, ok bool) {
+	line = strings.TrimSpace(line)
+	if b.label == "" {
+		if m := pemBegin.FindStringSubmatch(line); m != nil {
+			b.label = m[1]
+			b.lines = append(b.lines[:0], line[strings.Index(line, "-----BEGIN"):])
+			b.changed = changed
+		}
+		return "", "", false
+	}
+
+	b.changed = b.changed || changed
+	if end := "-----END " + b.label + "-----"; strings.Contains(line, end) {
+		b.lines = append(b.lines, line[:strings.Index(line, end)+len(end)])
+		label, block, ok = b.label, strings.Join(b.lines, "\n"), b.changed
+		b.reset()
+		return label, block, ok
+	}
+	b.lines = append(b.lines, line)
+	if len(b.lines) > maxPEMLines {
+		b.reset()
+	}
+	return "", "", false
+}
+
+// reset drops a block in progress (new file, or a line that breaks it)
+func (b *pemBlock) reset() {
+	b.label = ""
+	b.lines = b.lines[:0]
+	b.changed = false
+}
+
+// pemDiff finds key blocks in a unified diff. A replaced key often keeps
+// its BEGIN and END lines as context, so each side of a hunk is rebuilt
+// from the context lines plus the added (or deleted) lines.
+type pemDiff struct {
+	added, removed pemBlock
+}
+
+// feed passes a diff line, after changedLine has updated the parser, and
+// emits the blocks it completes
+func (d *pemDiff) feed(p *diffParser, line string, withRemoved bool, emit func(file, label, block string, removed bool)) {
+	if !p.inHunk || p.file == "" || line == "" {
+		d.reset()
+		return
+	}
+
+	var toAdded, toRemoved, changed bool
+	switch line[0] {
+	case ' ':
+		toAdded, toRemoved = true, withRemoved
+	case '+':
+		toAdded, changed = true, true
+	case '-':
+		toRemoved, changed = withRemoved, true
+	default: // Next hunk header, "\ No newline at end of file"
+		d.reset()
+		return
+	}
+
+	if toAdded {
+		if label, block, ok := d.added.feed(line[1:], changed); ok {
+			emit(p.file, label, block, false)
+		}
+	}
+	if toRemoved {
+		if label, block, ok := d.removed.feed(line[1:], changed); ok {
+			emit(p.file, label, block, true)
+		}
+	}
+}
+
+// reset drops the blocks in progress
+func (d *pemDiff) reset() {
+	d.added.reset()
+	d.removed.reset()
+}
+
+// isPEMBlock reports whether a value is a private key block
+func isPEMBlock(value string) bool {
+	return strings.HasPrefix(value, "-----BEGIN ") && strings.Contains(value, "\n")
+}
+
+// pemFingerprint is a stable identifier of a key block: the SHA256 of the
+// decoded key body, so indentation and line endings do not change it
+func pemFingerprint(block string) string {
+	var body strings.Builder
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSpace(line)
+		// Skip the BEGIN/END lines and headers such as "Proc-Type: 4,ENCRYPTED"
+		if strings.HasPrefix(line, "-----") || strings.Contains(line, ":") {
+			continue
+		}
+		body.WriteString(line)
+	}
+	data, err := base64.StdEncoding.DecodeString(body.String())
+	if err != nil {
+		data = []byte(body.String())
+	}
+	sum := sha256.Sum256(data)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// pemLine is the line number of the BEGIN line of a block the diff just
+// completed, on the side it was found
+func pemLine(p *diffParser, block string, removed bool) int {
+	return p.line(removed) - strings.Count(block, "\n")
+}
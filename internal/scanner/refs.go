@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Besides refs and ranges, the revisions of a history scan may select refs
+// by kind, as git log does: --branches, --remotes and --tags, optionally
+// with a pattern (--remotes=origin/*). A bare glob (origin/feature/*)
+// selects the local and remote-tracking branches it matches. Remote-tracking
+// refs are walked from the objects already fetched, without checking them
+// out.
+
+// refOptions are the ref kinds git log selects with an option
+var refOptions = map[string]func(name plumbing.ReferenceName) bool{
+	"--branches": plumbing.ReferenceName.IsBranch,
+	"--remotes":  plumbing.ReferenceName.IsRemote,
+	"--tags":     plumbing.ReferenceName.IsTag,
+}
+
+// isRefGlob reports whether a revision is a bare ref glob
+func isRefGlob(rev string) bool {
+	return !strings.HasPrefix(rev, "-") && !strings.HasPrefix(rev, "^") &&
+		!strings.Contains(rev, "..") && strings.ContainsAny(rev, "*?[")
+}
+
+// refSelector returns the ref kind and pattern a revision selects refs by
+// (ok false for a plain ref or range)
+func refSelector(rev string) (option, pattern string, ok bool) {
+	option, pattern, _ = strings.Cut(rev, "=")
+	if _, known := refOptions[option]; known {
+		return option, pattern, true
+	}
+	if isRefGlob(rev) {
+		return "", rev, true
+	}
+	return "", "", false
+}
+
+// isRefSelector reports whether a revision selects refs by kind or glob
+func isRefSelector(rev string) bool {
+	_, _, ok := refSelector(rev)
+	return ok
+}
+
+// revisionArgs splits revisions into git arguments, bare globs given to
+// git as --branches= and --remotes= patterns
+func revisionArgs(revisions string) []string {
+	var args []string
+	for _, rev := range strings.Fields(revisions) {
+		if isRefGlob(rev) {
+			args = append(args, "--branches="+rev, "--remotes="+rev)
+			continue
+		}
+		args = append(args, rev)
+	}
+	return args
+}
+
+// selectedRefCommits returns the commits of the refs a selector matches,
+// like git: a pattern without wildcards selects the refs under it, and *
+// also matches slashes
+func selectedRefCommits(repo *git.Repository, option, pattern string) ([]plumbing.Hash, error) {
+	if pattern != "" && !strings.ContainsAny(pattern, "*?[") {
+		pattern = strings.TrimSuffix(pattern, "/") + "/*"
+	}
+	glob, err := refGlob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ref pattern %q: %w", pattern, err)
+	}
+	return refCommits(repo, func(name plumbing.ReferenceName) bool {
+		if option != "" {
+			if !refOptions[option](name) {
+				return false
+			}
+		} else if !name.IsBranch() && !name.IsRemote() {
+			return false
+		}
+		return glob == nil || glob.MatchString(name.Short())
+	})
+}
+
+// refGlob compiles a shell glob over ref names (nil for an empty pattern)
+func refGlob(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end == -1 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+)
+
+// StatusReintroduced flags values that a cleanup removed (recorded in the
+// baseline) and that were committed or written again afterwards
+const StatusReintroduced = model.StatusReintroduced
+
+// flagReintroduced wraps emit to flag the findings of values cleaned before
+// their commit date (working tree findings are always after). Returns emit
+// unchanged when the repository has no cleanup on record.
+func flagReintroduced(repoPath string, emit func(f finding)) func(f finding) {
+	store, err := triage.Load(filepath.Join(repoPath, config.BaselineFile))
+	if err != nil || len(store.Cleaned) == 0 {
+		return emit
+	}
+	return func(f finding) {
+		if cleanedAt, ok := store.CleanedAt(f.value); ok {
+			date, err := time.Parse(time.RFC3339, f.commit.date)
+			f.reintroduced = err != nil || date.After(cleanedAt)
+		}
+		emit(f)
+	}
+}
+
+// EntrySeverity is the severity to report a finding with: the one recorded
+// by the scan, else that of its type in cfg. A reintroduced secret is
+// critical whatever its type, as the cleanup has regressed.
+func EntrySeverity(cfg *config.Config, entry StreamEntry) string {
+	if entry.Status == StatusReintroduced {
+		return config.SeverityCritical
+	}
+	if entry.Severity != "" {
+		return entry.Severity
+	}
+	return cfg.SeverityForType(entry.Type)
+}
+
+// SecretSeverity is EntrySeverity for an aggregated secret: critical when
+// any of its values was reintroduced
+func SecretSeverity(cfg *config.Config, secret Secret) string {
+	for _, h := range secret.History {
+		if h.Status == StatusReintroduced {
+			return config.SeverityCritical
+		}
+	}
+	if secret.Severity != "" {
+		return secret.Severity
+	}
+	return cfg.SeverityForType(secret.Type)
+}
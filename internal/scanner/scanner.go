@@ -2,74 +2,190 @@ package scanner
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+	"github.com/Drilmo/git-secret-scanner/internal/model"
 )
 
-// Secret represents a found secret
-type Secret struct {
-	File             string         `json:"file"`
-	Key              string         `json:"key"`
-	Type             string         `json:"type"`
-	ChangeCount      int            `json:"changeCount"`
-	TotalOccurrences int            `json:"totalOccurrences"`
-	Authors          []string       `json:"authors"`
-	History          []SecretValue  `json:"history"`
-}
-
-// SecretValue represents a specific value of a secret
-type SecretValue struct {
-	Value       string   `json:"value"`
-	MaskedValue string   `json:"maskedValue"`
-	Commits     []string `json:"commits"`
-	Authors     []string `json:"authors"`
-	FirstSeen   string   `json:"firstSeen"`
-	LastSeen    string   `json:"lastSeen"`
-}
-
-// ScanResult holds the complete scan results
-type ScanResult struct {
-	Repository   string    `json:"repository"`
-	Branch       string    `json:"branch"`
-	SecretsFound int       `json:"secretsFound"`
-	TotalValues  int       `json:"totalValues"`
-	Secrets      []Secret  `json:"secrets"`
-	ScanDate     time.Time `json:"scanDate"`
-}
-
-// StreamEntry represents a single entry for streaming output
-type StreamEntry struct {
-	File        string `json:"file"`
-	Key         string `json:"key"`
-	Value       string `json:"value"`
-	MaskedValue string `json:"maskedValue"`
-	Type        string `json:"type"`
-	Commit      string `json:"commit"`
-	Author      string `json:"author"`
-	Date        string `json:"date"`
+// The result formats are declared in the model package, shared with the
+// analyzer and the cleaner
+type (
+	Secret      = model.Secret
+	SecretValue = model.SecretValue
+	ScanResult  = model.ScanResult
+	StreamEntry = model.StreamEntry
+)
+
+// StatusRemovedInHistory flags values found in lines deleted by a commit
+const StatusRemovedInHistory = model.StatusRemovedInHistory
+
+// Progress describes how far a running scan has got
+type Progress struct {
+	Phase   string // "current" or "history"
+	Current int    // Files (current) or commits (history) processed in this phase
+	Total   int    // Files or commits to process in this phase (0 if unknown)
+	Commits int    // Commits examined so far
+	Found   int    // Findings so far (all phases)
+	Skipped int    // Lines too long to scan, skipped so far in this phase
+	Files   int    // Files read so far in this phase
+	Lines   int    // Lines matched so far in this phase
+	Bytes   int64  // Bytes of diff or file content read so far in this phase
 }
 
 // ScanOptions holds scanning options
 type ScanOptions struct {
-	Branch        string
-	ConfigPath    string
-	MaxConcurrent int
-	OnProgress    func(current, total, found int)
+	Branch     string   // Ref, several refs separated by spaces, --all, --remotes[=pattern] or a ref glob (see refs.go)
+	Range      string   // Revision range (e.g. v1.0..HEAD); replaces Branch for the history
+	ConfigPath string   // Config file, or files merged in order (org.yaml:repo.json, see config.Layers)
+	Profile    string   // Profile of the configuration applied to the scan ("": none, see config.Profile)
+	Groups     []string // Keyword groups kept for the scan, after the profile (all when empty)
+	Exclude    []string // Commits whose history is skipped (already scanned)
+	Submodules bool     // Also walk the history of initialized submodules
+	Removed    bool     // Also match deleted lines (secrets removed in a later commit)
+	Backend    string   // History backend: BackendAuto, BackendGit or BackendGoGit
+	Context    int      // Lines kept before and after each finding (0: line number only)
+	OnProgress func(p Progress)
+
+	// OnFinding is called with the first occurrence of each file, key and
+	// value as a walk finds it (nil: not called). A value in the working
+	// tree and in history is reported by both walks.
+	OnFinding func(entry StreamEntry)
+
+	// Interrupt stops the scan once done: git processes are interrupted and
+	// the scan returns what it found so far with ErrInterrupted (nil: runs
+	// to the end)
+	Interrupt context.Context
+}
+
+// revisions returns what the history walk covers: the range if set, else the branch
+func (o ScanOptions) revisions() string {
+	if o.Range != "" {
+		return o.Range
+	}
+	return o.Branch
+}
+
+// ErrRangeNotIncremental is returned when an incremental scan is given a revision range
+var ErrRangeNotIncremental = errors.New("incremental scans track branches, not revision ranges")
+
+// checkRange verifies that a revision range resolves in the repository, and
+// that ref globs and options select some ref
+func checkRange(repoPath, revRange string) error {
+	args := append([]string{"rev-parse"}, revisionArgs(revRange)...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	done := debugbundle.Track(cmd)
+	err := cmd.Run()
+	done(err)
+	if err != nil {
+		msg := strings.TrimSpace(out.String())
+		if i := strings.IndexByte(msg, '\n'); i != -1 {
+			msg = msg[:i]
+		}
+		return fmt.Errorf("invalid revision range %q: %s", revRange, msg)
+	}
+	if strings.TrimSpace(out.String()) == "" && slices.ContainsFunc(strings.Fields(revRange), isRefSelector) {
+		return fmt.Errorf("no ref matches %q", revRange)
+	}
+	return nil
+}
+
+// report calls OnProgress if set
+func (o ScanOptions) report(p Progress) {
+	if o.OnProgress != nil {
+		o.OnProgress(p)
+	}
+}
+
+// notify wraps emit to call OnFinding with the first occurrence of each
+// file, key and value. Returns emit unchanged without OnFinding.
+func (o ScanOptions) notify(cfg *config.Config, emit func(f finding)) func(f finding) {
+	if o.OnFinding == nil {
+		return emit
+	}
+	seen := make(map[string]bool)
+	return func(f finding) {
+		emit(f)
+		if key := f.file + "|" + f.key + "|" + f.value; !seen[key] {
+			seen[key] = true
+			o.OnFinding(newEntry(f, cfg))
+		}
+	}
+}
+
+// useRepoIgnores applies the .secretignore of a repository to the following
+// matches, in place of the rules of the previously scanned repository
+func (s *Scanner) useRepoIgnores(repoPath string) error {
+	rules, err := config.LoadSecretIgnore(repoPath)
+	if err != nil {
+		return err
+	}
+	s.config = s.config.WithIgnoreRules(rules)
+	return nil
+}
+
+// useProfile applies a profile of the configuration ("" for none), then
+// keeps only some keyword groups (nil for all), to the following scans in
+// place of the previous ones
+func (s *Scanner) useProfile(name string, groups []string) error {
+	if name == s.profile && slices.Equal(groups, s.groups) {
+		return nil
+	}
+	cfg := s.base
+	var err error
+	if name != "" {
+		if cfg, err = cfg.WithProfile(name); err != nil {
+			return err
+		}
+	}
+	if len(groups) > 0 {
+		if cfg, err = cfg.WithGroups(groups); err != nil {
+			return err
+		}
+	}
+	profiled := New(cfg)
+	profiled.base, profiled.profile, profiled.groups = s.base, name, slices.Clone(groups)
+	*s = *profiled
+	return nil
 }
 
 // Scanner performs git history scanning
 type Scanner struct {
 	config             *config.Config
+	base               *config.Config // Configuration given to New, before any profile
+	profile            string         // Profile applied to config
+	groups             []string       // Keyword groups kept after the profile (nil: all)
 	extractionPatterns []*config.CompiledPattern
+	keywords           []keywordMatcher // Keywords in config order
+}
+
+// keywordMatcher matches one keyword: a substring, or a regex in the groups
+// declaring regex
+type keywordMatcher struct {
+	keyword string         // Reported type: the pattern, or the group name for a regex
+	pattern string         // Substring as configured
+	lower   string         // Lowercased substring for case-insensitive matching
+	re      *regexp.Regexp // Regex keywords only
+	value   *regexp.Regexp // Values the group accepts (nil: any)
 }
 
 // New creates a new Scanner
@@ -77,9 +193,30 @@ func New(cfg *config.Config) *Scanner {
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 	}
+	var keywords []keywordMatcher
+	for _, group := range cfg.KeywordGroups() {
+		value, err := group.ValueRegexp()
+		if err != nil {
+			// Skip invalid groups (rejected when loading a config file)
+			continue
+		}
+		for _, p := range group.Patterns {
+			if !group.Regex {
+				keywords = append(keywords, keywordMatcher{keyword: p, pattern: p, lower: strings.ToLower(p), value: value})
+				continue
+			}
+			re, err := cfg.KeywordRegexp(p)
+			if err != nil {
+				continue
+			}
+			keywords = append(keywords, keywordMatcher{keyword: group.Name, re: re, value: value})
+		}
+	}
 	return &Scanner{
 		config:             cfg,
+		base:               cfg,
 		extractionPatterns: cfg.GetCompiledPatterns(),
+		keywords:           keywords,
 	}
 }
 
@@ -89,7 +226,9 @@ func (s *Scanner) extractKeyValue(line string) (key, value string, found bool) {
 		match := pattern.Regex.FindStringSubmatch(line)
 		if match != nil && len(match) > pattern.ValueGroup {
 			// Group 1 is typically the key, ValueGroup indicates which group contains the value
-			key = strings.TrimSpace(match[1])
+			if len(match) > 1 {
+				key = strings.TrimSpace(match[1])
+			}
 			value = strings.TrimSpace(match[pattern.ValueGroup])
 			return key, value, true
 		}
@@ -97,811 +236,1036 @@ func (s *Scanner) extractKeyValue(line string) (key, value string, found bool) {
 	return "", "", false
 }
 
-// Scan performs a full scan of the repository
-func (s *Scanner) Scan(repoPath string, opts ScanOptions) (*ScanResult, error) {
-	if opts.Branch == "" {
-		opts.Branch = "--all"
-	}
-	if opts.MaxConcurrent == 0 {
-		opts.MaxConcurrent = 4
-	}
-
-	keywords := s.config.GetAllKeywords()
-	secretsIndex := make(map[string]*secretData)
-	var mu sync.Mutex
-	var totalFound int
-
-	// Process keywords in batches
-	sem := make(chan struct{}, opts.MaxConcurrent)
-	var wg sync.WaitGroup
+// matchKeyword returns the first configured keyword contained in text whose
+// group accepts value
+func (s *Scanner) matchKeyword(text, value string) (string, bool) {
+	return s.findKeyword(text, func(kw *keywordMatcher) bool {
+		return kw.value == nil || kw.value.MatchString(value)
+	})
+}
 
-	for i, keyword := range keywords {
-		wg.Add(1)
-		sem <- struct{}{}
+// hasKeyword reports whether text contains a keyword, whatever the value
+func (s *Scanner) hasKeyword(text string) bool {
+	_, ok := s.findKeyword(text, nil)
+	return ok
+}
 
-		go func(kw string, idx int) {
-			defer wg.Done()
-			defer func() { <-sem }()
+// findKeyword returns the first keyword contained in text that accept (if
+// set) agrees to
+func (s *Scanner) findKeyword(text string, accept func(*keywordMatcher) bool) (string, bool) {
+	caseSensitive := s.config.Settings.CaseSensitive
+	textLower := text
+	if !caseSensitive {
+		textLower = strings.ToLower(text)
+	}
+	for i := range s.keywords {
+		kw := &s.keywords[i]
+		var found bool
+		switch {
+		case kw.re != nil:
+			found = kw.re.MatchString(text)
+		case caseSensitive:
+			found = strings.Contains(text, kw.pattern)
+		default:
+			found = strings.Contains(textLower, kw.lower)
+		}
+		if found && (accept == nil || accept(kw)) {
+			return kw.keyword, true
+		}
+	}
+	return "", false
+}
 
-			count := s.searchKeyword(repoPath, kw, opts.Branch, secretsIndex, &mu)
+// matchLine checks a line against all keywords and extraction patterns.
+// Credentials embedded in URLs are matched first, keyword or not: the
+// extraction patterns would see the whole URL, which is ignored as a value.
+// JWTs are matched last, by shape.
+func (s *Scanner) matchLine(line string) (keyword, key, value string, found bool) {
+	if key, password, ok := config.ExtractURLCredential(line); ok && !s.config.ShouldIgnoreValue(password) {
+		return config.URLCredentialType, key, password, true
+	}
 
-			mu.Lock()
-			totalFound += count
-			if opts.OnProgress != nil {
-				opts.OnProgress(idx+1, len(keywords), totalFound)
+	if s.hasKeyword(line) {
+		if key, value, ok := s.extractKeyValue(line); ok && !s.config.ShouldIgnoreValue(value) {
+			if keyword, ok := s.matchKeyword(line, value); ok {
+				return keyword, key, value, true
 			}
-			mu.Unlock()
-		}(keyword, i)
+		}
 	}
 
-	wg.Wait()
+	// JWTs are recognized by shape, keyword or not (and whatever their length)
+	if token, ok := findJWT(line); ok {
+		key = "jwt"
+		if k, _, ok := s.extractKeyValue(line); ok {
+			key = k
+		}
+		return JWTKeyword, key, token, true
+	}
+	return "", "", "", false
+}
 
-	// Build result
-	secrets := s.buildSecrets(secretsIndex)
+// matchFileLine matches a line of a file, unless the repository ignores the
+// key. tf follows the nesting of a Terraform file across lines (nil for a
+// line out of context).
+func (s *Scanner) matchFileLine(file, line string, tf *terraformWalker) (keyword, key, value string, found bool) {
+	keyword, key, value, found = s.parseFileLine(file, line, tf)
+	if found && s.config.ShouldIgnoreKey(key) {
+		return "", "", "", false
+	}
+	return keyword, key, value, found
+}
 
-	return &ScanResult{
-		Repository:   repoPath,
-		Branch:       opts.Branch,
-		SecretsFound: len(secrets),
-		TotalValues:  countTotalValues(secrets),
-		Secrets:      secrets,
-		ScanDate:     time.Now(),
-	}, nil
+// parseFileLine matches a line of a file: credential, dotenv and Terraform
+// files go through their own parsers, other files through matchLine
+func (s *Scanner) parseFileLine(file, line string, tf *terraformWalker) (keyword, key, value string, found bool) {
+	if kind := config.CredentialFileKind(file); kind != "" {
+		if keyword, key, value, ok := config.ParseCredentialLine(kind, line); ok && !s.config.ShouldIgnoreValue(value) {
+			return keyword, key, value, true
+		}
+		return s.matchLine(line)
+	}
+	if config.IsTerraformFile(file) {
+		if tf == nil {
+			tf = &terraformWalker{}
+		}
+		return s.matchTerraformLine(tf, line)
+	}
+	if !config.IsEnvFile(file) {
+		return s.matchLine(line)
+	}
+	key, value, ok := config.ParseEnvLine(line)
+	if !ok {
+		return s.matchLine(line) // Not an assignment: a pasted token, a URL...
+	}
+	if key, password, ok := config.ExtractURLCredential(value); ok && !s.config.ShouldIgnoreValue(password) {
+		return config.URLCredentialType, key, password, true
+	}
+	if keyword, ok := s.matchKeyword(key, value); ok && !s.config.ShouldIgnoreValue(value) {
+		return keyword, key, value, true
+	}
+	if token, ok := findJWT(value); ok {
+		return JWTKeyword, key, token, true
+	}
+	if s.config.Settings.EnvAllValues && !s.config.ShouldIgnoreValue(value) {
+		return config.EnvType, key, value, true
+	}
+	return "", "", "", false
 }
 
-type secretData struct {
+type commitInfo struct {
+	hash   string
+	author string
+	date   string
+}
+
+// currentCommit marks findings from the working tree
+var currentCommit = commitInfo{hash: "current", author: "current"}
+
+// finding is a single key/value occurrence found by a walker
+type finding struct {
 	file    string
 	key     string
-	keyType string
-	authors map[string]bool
-	values  map[string]*valueData
+	value   string
+	keyword string
+	commit  commitInfo
+	removed bool // Found in a deleted line
+
+	reintroduced bool         // Value cleaned before this occurrence
+	line         int          // Line number in the file (0 if unknown)
+	offset       int          // Characters before the value in the line (split JSON lines)
+	context      *CodeContext // Lines around the finding, if requested
 }
 
-type valueData struct {
-	commits   []string
-	authors   map[string]bool
-	firstSeen time.Time
-	lastSeen  time.Time
-}
+// CommitMessageFile is the file reported for secrets found in commit messages
+const CommitMessageFile = "<commit message>"
+
+// Marker lines around the commit message in the git log output (control
+// characters cannot clash with message or diff lines)
+const (
+	messageStart = "\x1fMSG"
+	messageEnd   = "\x1fEND"
+)
 
-func (s *Scanner) searchKeyword(repoPath, keyword, branch string, index map[string]*secretData, mu *sync.Mutex) int {
+// historyArgs builds the git log arguments for a single pass over the history
+func (s *Scanner) historyArgs(branch string, exclude []string) []string {
 	args := []string{
+		"-c", "core.quotepath=off",
 		"log",
-		branch,
-		fmt.Sprintf("-S%s", keyword),
-		"--pretty=format:COMMIT_START|%H|%an|%aI",
+	}
+	args = append(args, revisionArgs(branch)...)
+	args = append(args,
+		"--pretty=format:COMMIT|%H|%an|%aI%n" + messageStart + "%n%B%n" + messageEnd,
 		"-p",
+		"--no-color",
+		"--no-ext-diff",
+	)
+	args = append(args, excludeArgs(exclude)...)
+	args = append(args, s.pathspecArgs()...)
+
+	return args
+}
+
+// pathspecArgs excludes binary extensions (all pathspecs after single --)
+func (s *Scanner) pathspecArgs() []string {
+	if len(s.config.ExcludeBinaryExtensions) == 0 {
+		return nil
 	}
+	args := []string{"--"}
+	for _, ext := range s.config.ExcludeBinaryExtensions {
+		args = append(args, fmt.Sprintf(":!*%s", ext))
+	}
+	return args
+}
 
-	// Add file exclusions (all pathspecs after single --)
-	if len(s.config.ExcludeBinaryExtensions) > 0 {
-		args = append(args, "--")
-		for _, ext := range s.config.ExcludeBinaryExtensions {
-			args = append(args, fmt.Sprintf(":!*%s", ext))
-		}
+// excludeArgs builds the revision arguments hiding already scanned commits
+func excludeArgs(exclude []string) []string {
+	if len(exclude) == 0 {
+		return nil
 	}
+	return append([]string{"--not"}, exclude...)
+}
 
+// countCommits returns the number of commits the history walk will visit (0 if unknown)
+func countCommits(repoPath, branch string, exclude []string) int {
+	args := append([]string{"rev-list", "--count"}, revisionArgs(branch)...)
+	args = append(args, excludeArgs(exclude)...)
 	cmd := exec.Command("git", args...)
 	cmd.Dir = repoPath
-
-	stdout, err := cmd.StdoutPipe()
+	done := debugbundle.Track(cmd)
+	out, err := cmd.Output()
+	done(err)
 	if err != nil {
 		return 0
 	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+	return n
+}
 
-	if err := cmd.Start(); err != nil {
-		return 0
+// maxLineLength is the longest line matched; longer lines (minified files,
+// embedded blobs) are skipped and counted
+const maxLineLength = 1024 * 1024
+
+// readLine reads the next line without its line ending. Lines longer than
+// the reader's buffer are consumed and reported with tooLong set.
+func readLine(reader *bufio.Reader) (line string, tooLong bool, err error) {
+	line, tooLong, err = readLineHead(reader)
+	if tooLong {
+		finishLine(reader, nil)
+		return "", true, nil
 	}
+	return line, false, err
+}
 
-	scanner := bufio.NewScanner(stdout)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
-
-	var currentCommit *commitInfo
-	var currentFile string
-	var findings int
-
-	for scanner.Scan() {
-		line := scanner.Text()
+// readLineHead reads the next line without its line ending. Of a line
+// longer than the reader's buffer, it returns the start with tooLong set;
+// finishLine reads the rest.
+func readLineHead(reader *bufio.Reader) (line string, tooLong bool, err error) {
+	data, err := reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return string(data), true, nil
+	}
+	// The last line may have no line ending
+	if err == io.EOF && len(data) > 0 {
+		err = nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimRight(string(data), "\r\n"), false, nil
+}
 
-		if strings.HasPrefix(line, "COMMIT_START|") {
-			parts := strings.SplitN(line, "|", 4)
-			if len(parts) >= 4 {
-				currentCommit = &commitInfo{
-					hash:   parts[1],
-					author: parts[2],
-					date:   parts[3],
-				}
-				currentFile = ""
-			}
-			continue
+// finishLine reads the rest of a line readLineHead returned the start of,
+// passing it chunk by chunk to fn when not nil
+func finishLine(reader *bufio.Reader, fn func(chunk []byte)) {
+	for {
+		data, err := reader.ReadSlice('\n')
+		if err != bufio.ErrBufferFull {
+			data = bytes.TrimRight(data, "\r\n")
+		}
+		if fn != nil {
+			fn(data)
+		}
+		if err != bufio.ErrBufferFull {
+			return
 		}
+	}
+}
 
-		if strings.HasPrefix(line, "diff --git") {
-			if idx := strings.Index(line, " b/"); idx != -1 {
-				currentFile = line[idx+3:]
-				// Check if file should be ignored
-				if s.config.ShouldIgnoreFile(currentFile) {
-					currentFile = "" // Reset to skip this file
-				}
-			}
-			continue
+// walkHistory streams the whole history once with `git log -p` (or its
+// go-git equivalent) and matches every keyword against each added line.
+// baseFound is added to the reported findings total so multi-phase scans
+// report a running count. A failure of git log is returned even after some
+// commits were read.
+func (s *Scanner) walkHistory(repoPath string, opts ScanOptions, baseFound int, emit func(f finding)) (int, error) {
+	emit = flagReintroduced(repoPath, opts.notify(s.config, emit))
+	if err := s.useRepoIgnores(repoPath); err != nil {
+		return 0, err
+	}
+	open := s.gitHistory
+	if opts.useGoGit() {
+		open = s.goGitHistory
+	}
+	history, total, err := open(repoPath, opts)
+	if err != nil {
+		if opts.interrupted() != nil {
+			return 0, ErrInterrupted
 		}
+		return 0, err
+	}
 
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") && currentCommit != nil && currentFile != "" {
-			content := line[1:]
+	read := &countingReader{r: history}
+	reader := bufio.NewReaderSize(read, maxLineLength)
 
-			// Check if contains keyword
-			searchIn := content
-			searchFor := keyword
-			if !s.config.Settings.CaseSensitive {
-				searchIn = strings.ToLower(content)
-				searchFor = strings.ToLower(keyword)
-			}
+	var commit commitInfo
+	var patch diffParser
+	var pems pemDiff
+	var tf terraformWalker
+	lines := s.newDiffContext(opts.Context, emit)
+	var inMessage bool
+	var commits, found, skipped, matched int
 
-			if !strings.Contains(searchIn, searchFor) {
-				continue
-			}
+	progress := func() {
+		opts.report(Progress{Phase: "history", Current: commits, Total: total, Commits: commits, Found: baseFound + found, Skipped: skipped, Lines: matched, Bytes: read.n})
+	}
 
-			// Extract key=value using configured patterns
-			key, value, found := s.extractKeyValue(content)
-			if !found {
-				continue
+	for {
+		line, tooLong, err := readLineHead(reader)
+		if err != nil {
+			break
+		}
+		if tooLong {
+			if inMessage {
+				finishLine(reader, nil)
+				skipped++
+			} else if n, ok := s.splitDiffLine(&patch, line, reader, opts.Removed, commit, emit); ok {
+				found += n
+			} else {
+				skipped++
 			}
+			continue
+		}
 
-			if s.config.ShouldIgnoreValue(value) {
+		// People paste tokens into commit messages too
+		if inMessage {
+			if line == messageEnd {
+				inMessage = false
 				continue
 			}
-
-			findings++
-			secretKey := fmt.Sprintf("%s|%s", currentFile, key)
-
-			mu.Lock()
-			if _, exists := index[secretKey]; !exists {
-				index[secretKey] = &secretData{
-					file:    currentFile,
-					key:     key,
-					keyType: keyword,
-					authors: make(map[string]bool),
-					values:  make(map[string]*valueData),
-				}
+			if line != "" {
+				matched++
 			}
+			if keyword, key, value, ok := s.matchLine(line); ok && commit.hash != "" && !s.config.ShouldIgnoreKey(key) {
+				found++
+				emit(finding{file: CommitMessageFile, key: key, value: value, keyword: keyword, commit: commit})
+			}
+			continue
+		}
+		if line == messageStart {
+			inMessage = true
+			continue
+		}
 
-			entry := index[secretKey]
-			entry.authors[currentCommit.author] = true
-
-			if _, exists := entry.values[value]; !exists {
-				t, _ := time.Parse(time.RFC3339, currentCommit.date)
-				entry.values[value] = &valueData{
-					commits:   []string{},
-					authors:   make(map[string]bool),
-					firstSeen: t,
-					lastSeen:  t,
+		if strings.HasPrefix(line, "COMMIT|") {
+			if opts.interrupted() != nil {
+				break
+			}
+			parts := strings.SplitN(line, "|", 4)
+			if len(parts) >= 4 {
+				commit = commitInfo{
+					hash:   parts[1],
+					author: s.config.AuthorIdentity(parts[2]),
+					date:   parts[3],
+				}
+				patch.reset()
+				commits++
+				// Throttle progress reports while walking commits
+				if commits%50 == 0 {
+					progress()
 				}
 			}
+			continue
+		}
 
-			vd := entry.values[value]
-			vd.commits = append(vd.commits, currentCommit.hash)
-			vd.authors[currentCommit.author] = true
+		file, text, removed, ok := s.changedLine(&patch, line, opts.Removed)
+		tf.feedDiff(&patch, line)
+		lines.feed(&patch, line)
+		if commit.hash != "" {
+			pems.feed(&patch, line, opts.Removed, func(file, label, block string, removed bool) {
+				found++
+				emit(finding{file: file, key: label, value: block, keyword: PEMKeyword, commit: commit, removed: removed, line: pemLine(&patch, block, removed)})
+			})
+		}
+		if !ok || commit.hash == "" {
+			continue
+		}
 
-			t, _ := time.Parse(time.RFC3339, currentCommit.date)
-			if t.Before(vd.firstSeen) {
-				vd.firstSeen = t
-			}
-			if t.After(vd.lastSeen) {
-				vd.lastSeen = t
-			}
-			mu.Unlock()
+		matched++
+		if len(text) >= minifiedLineLength && isJSONLine(text) {
+			found += emitSplit(s.splitJSON(text, nil), finding{file: file, commit: commit, removed: removed, line: patch.line(removed)}, emit)
+			continue
 		}
+		nesting := &tf
+		if removed {
+			nesting = nil // The nesting followed is the one of the new side
+		}
+		keyword, key, value, ok := s.matchFileLine(file, text, nesting)
+		if !ok {
+			continue
+		}
+
+		found++
+		lines.add(finding{file: file, key: key, value: value, keyword: keyword, commit: commit, removed: removed})
 	}
+	lines.flush()
 
-	cmd.Wait()
-	return findings
+	err = history.wait()
+	progress()
+	if err := opts.interrupted(); err != nil {
+		return found, err
+	}
+	// A git log failing after some commits leaves the rest unscanned: the
+	// caller must not save the result as complete, nor its incremental state
+	return found, err
 }
 
-type commitInfo struct {
-	hash   string
-	author string
-	date   string
+// walkCurrent reads every file of the working tree once (including untracked
+// files) and matches every keyword against each line.
+func (s *Scanner) walkCurrent(repoPath string, opts ScanOptions, baseFound int, emit func(f finding)) (int, error) {
+	emit = flagReintroduced(repoPath, opts.notify(s.config, emit))
+	if err := s.useRepoIgnores(repoPath); err != nil {
+		return 0, err
+	}
+	files := s.listCurrentFiles(repoPath)
+	p := Progress{Phase: "current", Total: len(files), Found: baseFound}
+
+	for i, relPath := range files {
+		if err := opts.interrupted(); err != nil {
+			opts.report(p)
+			return p.Found - baseFound, err
+		}
+		fullPath := filepath.Join(repoPath, relPath)
+		s.matchFile(relPath, fullPath, opts.Context, &p, emit)
+		p.Current = i + 1
+
+		// Throttle progress reports on large trees
+		if (i+1)%100 == 0 || i+1 == len(files) {
+			opts.report(p)
+		}
+	}
+
+	return p.Found - baseFound, nil
 }
 
-func (s *Scanner) buildSecrets(index map[string]*secretData) []Secret {
-	secrets := make([]Secret, 0, len(index))
+// listCurrentFiles returns the relative paths of all scannable files in the working tree
+func (s *Scanner) listCurrentFiles(repoPath string) []string {
+	var files []string
 
-	for _, data := range index {
-		history := make([]SecretValue, 0, len(data.values))
+	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip files we can't access
+		}
 
-		for value, vd := range data.values {
-			authors := make([]string, 0, len(vd.authors))
-			for a := range vd.authors {
-				authors = append(authors, a)
+		// Skip directories
+		if info.IsDir() {
+			// Skip .git directory
+			if info.Name() == ".git" {
+				return filepath.SkipDir
 			}
-
-			history = append(history, SecretValue{
-				Value:       value,
-				MaskedValue: maskSecret(value),
-				Commits:     vd.commits,
-				Authors:     authors,
-				FirstSeen:   vd.firstSeen.Format(time.RFC3339),
-				LastSeen:    vd.lastSeen.Format(time.RFC3339),
-			})
+			return nil
 		}
 
-		// Sort by date
-		sort.Slice(history, func(i, j int) bool {
-			ti, _ := time.Parse(time.RFC3339, history[i].FirstSeen)
-			tj, _ := time.Parse(time.RFC3339, history[j].FirstSeen)
-			return ti.Before(tj)
-		})
+		// Get relative path
+		relPath, err := filepath.Rel(repoPath, path)
+		if err != nil {
+			relPath = path
+		}
 
-		authors := make([]string, 0, len(data.authors))
-		for a := range data.authors {
-			authors = append(authors, a)
+		// Check if file should be ignored
+		if s.config.ShouldIgnoreFile(relPath) {
+			return nil
 		}
 
-		totalOccurrences := 0
-		for _, h := range history {
-			totalOccurrences += len(h.Commits)
+		// Check if should exclude binary extensions
+		for _, ext := range s.config.ExcludeBinaryExtensions {
+			if strings.HasSuffix(relPath, ext) {
+				return nil
+			}
 		}
 
-		secrets = append(secrets, Secret{
-			File:             data.file,
-			Key:              data.key,
-			Type:             data.keyType,
-			ChangeCount:      len(history),
-			TotalOccurrences: totalOccurrences,
-			Authors:          authors,
-			History:          history,
-		})
-	}
+		// Skip large files, unless their head is scanned
+		if info.Size() > s.config.MaxFileSize() && s.config.LargeFileHead() == 0 {
+			return nil
+		}
 
-	// Sort by change count
-	sort.Slice(secrets, func(i, j int) bool {
-		return secrets[i].ChangeCount > secrets[j].ChangeCount
+		files = append(files, relPath)
+		return nil
 	})
 
-	return secrets
+	return files
 }
 
-func maskSecret(value string) string {
-	if len(value) <= 4 {
-		return "****"
+// matchFile scans a single file and emits its findings with context lines
+// around them. Its findings, the lines too long to scan and the data read
+// are added to p.
+func (s *Scanner) matchFile(relPath, fullPath string, context int, p *Progress, emit func(f finding)) {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return
 	}
-	maskLen := len(value) - 4
-	if maskLen > 16 {
-		maskLen = 16
+	defer file.Close()
+	p.Files++
+	read := &countingReader{r: file}
+	defer func() { p.Bytes += read.n }()
+
+	reader := bufio.NewReaderSize(read, maxLineLength)
+	var structured map[int][]config.StructuredValue
+	if info, err := file.Stat(); err == nil && info.Size() > s.config.MaxFileSize() {
+		reader = s.fileHead(read)
+	} else if format := s.structuredFormat(relPath); format != "" {
+		// Parsed whole, then read line by line for context and PEM blocks
+		data, err := io.ReadAll(read)
+		if err != nil {
+			return
+		}
+		structured = structuredLines(format, data)
+		reader = bufio.NewReaderSize(bytes.NewReader(data), maxLineLength)
 	}
-	return value[:2] + strings.Repeat("*", maskLen) + value[len(value)-2:]
+	var pem pemBlock
+	var tf terraformWalker
+	lines := s.newContextWindow(context, emit)
+	n := 0
+	for {
+		line, tooLong, err := readLineHead(reader)
+		if err != nil {
+			break
+		}
+		n++
+		if tooLong {
+			if isJSONLine(line) {
+				p.Found += emitSplit(s.splitJSON(line, reader), finding{file: relPath, commit: currentCommit, line: n}, emit)
+			} else {
+				finishLine(reader, nil)
+				p.Skipped++
+			}
+			continue
+		}
+		p.Lines++
+		lines.push(n, line)
+		if label, block, ok := pem.feed(line, true); ok {
+			p.Found++
+			emit(finding{file: relPath, key: label, value: block, keyword: PEMKeyword, commit: currentCommit, line: n - strings.Count(block, "\n")})
+		}
+		if values, ok := structured[n]; ok {
+			for _, v := range values {
+				if keyword, key, value, ok := s.matchStructured(v); ok {
+					p.Found++
+					lines.add(finding{file: relPath, key: key, value: value, keyword: keyword, commit: currentCommit})
+				}
+			}
+			continue
+		}
+		if len(line) >= minifiedLineLength && isJSONLine(line) {
+			p.Found += emitSplit(s.splitJSON(line, nil), finding{file: relPath, commit: currentCommit, line: n}, emit)
+			continue
+		}
+		keyword, key, value, ok := s.matchFileLine(relPath, line, &tf)
+		if !ok {
+			continue
+		}
+		p.Found++
+		lines.add(finding{file: relPath, key: key, value: value, keyword: keyword, commit: currentCommit})
+	}
+	lines.flush()
 }
 
-func countTotalValues(secrets []Secret) int {
-	total := 0
-	for _, s := range secrets {
-		total += s.ChangeCount
+// fileHead reads the head of an oversized file (settings.largeFiles "head"),
+// up to its last complete line so no value is cut
+func (s *Scanner) fileHead(file io.Reader) *bufio.Reader {
+	head := make([]byte, s.config.LargeFileHead())
+	n, _ := io.ReadFull(file, head)
+	if n == len(head) {
+		head = head[:bytes.LastIndexByte(head, '\n')+1]
+	} else {
+		head = head[:n] // Whole file
 	}
-	return total
+	return bufio.NewReaderSize(bytes.NewReader(head), maxLineLength)
 }
 
-// ScanStream performs streaming scan to file
-func (s *Scanner) ScanStream(repoPath, outputPath string, opts ScanOptions) (int, error) {
+// FileFinding is a finding of MatchDir
+type FileFinding struct {
+	File  string // Path relative to the directory
+	Line  int
+	Type  string // Keyword, group name or built-in type (pem, jwt...)
+	Key   string
+	Value string
+}
+
+// MatchDir runs the rules over the files of a directory as a working-tree
+// scan does (same ignored files, .secretignore and file parsers), without
+// git. It returns the files matched and their findings.
+func (s *Scanner) MatchDir(dir string) ([]string, []FileFinding, error) {
+	if err := s.useRepoIgnores(dir); err != nil {
+		return nil, nil, err
+	}
+	files := s.listCurrentFiles(dir)
+	var findings []FileFinding
+	for _, relPath := range files {
+		s.matchFile(relPath, filepath.Join(dir, relPath), 0, &Progress{}, func(f finding) {
+			findings = append(findings, FileFinding{File: f.file, Line: f.line, Type: f.keyword, Key: f.key, Value: f.value})
+		})
+	}
+	return files, findings, nil
+}
+
+// Scan performs a full scan of the repository
+func (s *Scanner) Scan(repoPath string, opts ScanOptions) (*ScanResult, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return nil, err
+	}
 	if opts.Branch == "" {
 		opts.Branch = "--all"
 	}
 
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return 0, err
+	opts, stats := opts.collectStats()
+	index := newSecretIndex()
+	found, err := s.walkHistory(repoPath, opts, 0, index.add)
+	if err == nil && opts.Submodules {
+		_, err = s.walkSubmodules(repoPath, opts, found, index.add)
 	}
-	defer file.Close()
+	return s.walkResult(index, repoPath, opts.revisions(), stats, err)
+}
 
-	// Deduplication set: tracks seen (file|key|value) combinations
-	seen := make(map[string]bool)
+type secretData struct {
+	file    string
+	key     string
+	keyType string
+	authors map[string]bool
+	values  map[string]*valueData
+}
 
-	keywords := s.config.GetAllKeywords()
-	var count int
+type valueData struct {
+	commits      []string
+	authors      map[string]bool
+	firstSeen    time.Time
+	lastSeen     time.Time
+	removed      bool
+	reintroduced bool
+	line         int // Location of the latest occurrence
+	offset       int
+	context      *CodeContext
+}
+
+// secretIndex aggregates findings by file and key
+type secretIndex map[string]*secretData
 
-	for i, keyword := range keywords {
-		c := s.streamKeyword(repoPath, keyword, opts.Branch, file, seen)
-		count += c
+func newSecretIndex() secretIndex {
+	return make(secretIndex)
+}
 
-		if opts.OnProgress != nil {
-			opts.OnProgress(i+1, len(keywords), count)
+// add records a finding in the index
+func (index secretIndex) add(f finding) {
+	secretKey := fmt.Sprintf("%s|%s", f.file, f.key)
+
+	if _, exists := index[secretKey]; !exists {
+		index[secretKey] = &secretData{
+			file:    f.file,
+			key:     f.key,
+			keyType: f.keyword,
+			authors: make(map[string]bool),
+			values:  make(map[string]*valueData),
 		}
 	}
 
-	return count, nil
-}
+	entry := index[secretKey]
+	entry.authors[f.commit.author] = true
 
-func (s *Scanner) streamKeyword(repoPath, keyword, branch string, file *os.File, seen map[string]bool) int {
-	args := []string{
-		"log",
-		branch,
-		fmt.Sprintf("-S%s", keyword),
-		"--pretty=format:COMMIT|%H|%an|%aI",
-		"-p",
+	t := time.Now()
+	if f.commit.date != "" {
+		t, _ = time.Parse(time.RFC3339, f.commit.date)
 	}
 
-	// Add file exclusions (all pathspecs after single --)
-	if len(s.config.ExcludeBinaryExtensions) > 0 {
-		args = append(args, "--")
-		for _, ext := range s.config.ExcludeBinaryExtensions {
-			args = append(args, fmt.Sprintf(":!*%s", ext))
+	if _, exists := entry.values[f.value]; !exists {
+		entry.values[f.value] = &valueData{
+			commits:   []string{},
+			authors:   make(map[string]bool),
+			firstSeen: t,
+			lastSeen:  t,
 		}
 	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = repoPath
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return 0
+	vd := entry.values[f.value]
+	// The working tree is recorded once per value
+	if f.commit.hash != currentCommit.hash || !containsString(vd.commits, currentCommit.hash) {
+		vd.commits = append(vd.commits, f.commit.hash)
 	}
+	vd.authors[f.commit.author] = true
+	vd.removed = vd.removed || f.removed
+	vd.reintroduced = vd.reintroduced || f.reintroduced
 
-	if err := cmd.Start(); err != nil {
-		return 0
+	if t.Before(vd.firstSeen) {
+		vd.firstSeen = t
 	}
+	if t.After(vd.lastSeen) {
+		vd.lastSeen = t
+	}
+	if f.line > 0 && (vd.line == 0 || !t.Before(vd.lastSeen)) {
+		vd.line, vd.offset, vd.context = f.line, f.offset, f.context
+	}
+}
 
-	scanner := bufio.NewScanner(stdout)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
-
-	var currentCommit *commitInfo
-	var currentFile string
-	var count int
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if strings.HasPrefix(line, "COMMIT|") {
-			parts := strings.SplitN(line, "|", 4)
-			if len(parts) >= 4 {
-				currentCommit = &commitInfo{
-					hash:   parts[1],
-					author: parts[2],
-					date:   parts[3],
-				}
-				currentFile = ""
-			}
-			continue
+// load seeds the index with a previous result. With dropCurrent, occurrences
+// from the working tree are discarded because it is about to be rescanned.
+func (index secretIndex) load(previous *ScanResult, dropCurrent bool) {
+	for _, secret := range previous.Secrets {
+		data := &secretData{
+			file:    secret.File,
+			key:     secret.Key,
+			keyType: secret.Type,
+			authors: make(map[string]bool),
+			values:  make(map[string]*valueData),
 		}
 
-		if strings.HasPrefix(line, "diff --git") {
-			if idx := strings.Index(line, " b/"); idx != -1 {
-				currentFile = line[idx+3:]
-				// Check if file should be ignored
-				if s.config.ShouldIgnoreFile(currentFile) {
-					currentFile = "" // Reset to skip this file
+		for _, h := range secret.History {
+			vd := &valueData{authors: make(map[string]bool)}
+			for _, c := range h.Commits {
+				if dropCurrent && c == currentCommit.hash {
+					continue
 				}
+				vd.commits = append(vd.commits, c)
 			}
-			continue
-		}
-
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") && currentCommit != nil && currentFile != "" {
-			content := line[1:]
-
-			searchIn := content
-			searchFor := keyword
-			if !s.config.Settings.CaseSensitive {
-				searchIn = strings.ToLower(content)
-				searchFor = strings.ToLower(keyword)
-			}
-
-			if !strings.Contains(searchIn, searchFor) {
-				continue
-			}
-
-			// Extract key=value using configured patterns
-			key, value, found := s.extractKeyValue(content)
-			if !found {
+			if len(vd.commits) == 0 {
 				continue
 			}
-
-			if s.config.ShouldIgnoreValue(value) {
-				continue
-			}
-
-			// Deduplication: check if we've seen this (file|key|value) combination
-			dedupeKey := fmt.Sprintf("%s|%s|%s", currentFile, key, value)
-			if seen[dedupeKey] {
-				continue
-			}
-			seen[dedupeKey] = true
-
-			entry := StreamEntry{
-				File:        currentFile,
-				Key:         key,
-				Value:       value,
-				MaskedValue: maskSecret(value),
-				Type:        keyword,
-				Commit:      currentCommit.hash,
-				Author:      currentCommit.author,
-				Date:        currentCommit.date,
+			for _, a := range h.Authors {
+				if dropCurrent && a == currentCommit.author {
+					continue
+				}
+				vd.authors[a] = true
+				data.authors[a] = true
 			}
+			vd.firstSeen, _ = time.Parse(time.RFC3339, h.FirstSeen)
+			vd.lastSeen, _ = time.Parse(time.RFC3339, h.LastSeen)
+			vd.removed = h.Status == StatusRemovedInHistory
+			vd.reintroduced = h.Status == StatusReintroduced
+			vd.line, vd.offset, vd.context = h.Line, h.Offset, h.Context
+			data.values[h.Value] = vd
+		}
 
-			data, _ := json.Marshal(entry)
-			file.WriteString(string(data) + "\n")
-			count++
+		if len(data.values) > 0 {
+			index[fmt.Sprintf("%s|%s", secret.File, secret.Key)] = data
 		}
 	}
-
-	cmd.Wait()
-	return count
 }
 
-// GetAllValues extracts all unique secret values from scan result
-func GetAllValues(scanResult *ScanResult) []string {
-	values := make(map[string]bool)
-
-	for _, secret := range scanResult.Secrets {
-		for _, h := range secret.History {
-			if h.Value != "" && !strings.Contains(h.Value, "REMOVED") {
-				values[h.Value] = true
-			}
-		}
-	}
+// result builds the final scan result from the index, with the severities
+// of cfg
+func (index secretIndex) result(repoPath, branch string, cfg *config.Config) *ScanResult {
+	secrets := buildSecrets(index, cfg)
 
-	valueList := make([]string, 0, len(values))
-	for v := range values {
-		valueList = append(valueList, v)
+	return &ScanResult{
+		Repository:   repoPath,
+		Branch:       branch,
+		SecretsFound: len(secrets),
+		TotalValues:  countTotalValues(secrets),
+		Secrets:      secrets,
+		ScanDate:     time.Now(),
 	}
-
-	// Sort by length (longest first) for cleaning
-	sort.Slice(valueList, func(i, j int) bool {
-		return len(valueList[i]) > len(valueList[j])
-	})
-
-	return valueList
 }
 
-// ScanCurrentStream scans current files and writes to JSONL file as it goes
-func (s *Scanner) ScanCurrentStream(repoPath, outputPath string) (int, error) {
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return 0, err
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
 	}
-	defer file.Close()
-
-	// Deduplication set: tracks seen (file|key|value) combinations
-	seen := make(map[string]bool)
+	return false
+}
 
-	keywords := s.config.GetAllKeywords()
-	var count int
+func buildSecrets(index secretIndex, cfg *config.Config) []Secret {
+	secrets := make([]Secret, 0, len(index))
 
-	for _, keyword := range keywords {
-		c := s.streamCurrentFiles(repoPath, keyword, file, seen)
-		count += c
-	}
+	for _, data := range index {
+		history := make([]SecretValue, 0, len(data.values))
 
-	return count, nil
-}
+		for value, vd := range data.values {
+			authors := make([]string, 0, len(vd.authors))
+			for a := range vd.authors {
+				authors = append(authors, a)
+			}
 
-func (s *Scanner) streamCurrentFiles(repoPath, keyword string, outFile *os.File, seen map[string]bool) int {
-	var count int
+			status := ""
+			switch {
+			case vd.reintroduced:
+				status = StatusReintroduced
+			case vd.removed:
+				status = StatusRemovedInHistory
+			}
 
-	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
+			history = append(history, SecretValue{
+				Value:       value,
+				MaskedValue: maskSecret(value),
+				Commits:     vd.commits,
+				Authors:     authors,
+				FirstSeen:   vd.firstSeen.Format(time.RFC3339),
+				LastSeen:    vd.lastSeen.Format(time.RFC3339),
+				Status:      status,
+				JWT:         decodeJWT(value, time.Now()),
+				Line:        vd.line,
+				Offset:      vd.offset,
+				Context:     vd.context,
+			})
 		}
 
-		if info.IsDir() {
-			if info.Name() == ".git" {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+		// Sort by date
+		sort.Slice(history, func(i, j int) bool {
+			ti, _ := time.Parse(time.RFC3339, history[i].FirstSeen)
+			tj, _ := time.Parse(time.RFC3339, history[j].FirstSeen)
+			return ti.Before(tj)
+		})
 
-		relPath, err := filepath.Rel(repoPath, path)
-		if err != nil {
-			relPath = path
+		authors := make([]string, 0, len(data.authors))
+		for a := range data.authors {
+			authors = append(authors, a)
 		}
 
-		if s.config.ShouldIgnoreFile(relPath) {
-			return nil
+		totalOccurrences := 0
+		for _, h := range history {
+			totalOccurrences += len(h.Commits)
 		}
 
-		for _, ext := range s.config.ExcludeBinaryExtensions {
-			if strings.HasSuffix(relPath, ext) {
-				return nil
-			}
-		}
+		secrets = append(secrets, Secret{
+			File:             data.file,
+			Key:              data.key,
+			Type:             data.keyType,
+			Severity:         cfg.SeverityForType(data.keyType),
+			ChangeCount:      len(history),
+			TotalOccurrences: totalOccurrences,
+			Authors:          authors,
+			History:          history,
+		})
+	}
 
-		if info.Size() > 1024*1024 {
-			return nil
+	// Most severe first, then by change count
+	sort.Slice(secrets, func(i, j int) bool {
+		ri := config.SeverityRank(SecretSeverity(cfg, secrets[i]))
+		rj := config.SeverityRank(SecretSeverity(cfg, secrets[j]))
+		if ri != rj {
+			return ri < rj
 		}
-
-		// Search file and write matches to JSONL
-		c := s.streamFileMatches(relPath, path, keyword, outFile, seen)
-		count += c
-		return nil
+		return secrets[i].ChangeCount > secrets[j].ChangeCount
 	})
 
-	return count
+	return secrets
 }
 
-func (s *Scanner) streamFileMatches(relPath, fullPath, keyword string, outFile *os.File, seen map[string]bool) int {
-	file, err := os.Open(fullPath)
-	if err != nil {
-		return 0
+func maskSecret(value string) string {
+	// Key blocks are shown by fingerprint: their first and last characters
+	// are the BEGIN and END lines
+	if isPEMBlock(value) {
+		return pemFingerprint(value)
 	}
-	defer file.Close()
-
-	fileScanner := bufio.NewScanner(file)
-	keywordLower := strings.ToLower(keyword)
-	var count int
-
-	for fileScanner.Scan() {
-		line := fileScanner.Text()
+	if len(value) <= 4 {
+		return "****"
+	}
+	maskLen := len(value) - 4
+	if maskLen > 16 {
+		maskLen = 16
+	}
+	return value[:2] + strings.Repeat("*", maskLen) + value[len(value)-2:]
+}
 
-		if !s.config.Settings.CaseSensitive {
-			if !strings.Contains(strings.ToLower(line), keywordLower) {
-				continue
-			}
-		} else {
-			if !strings.Contains(line, keyword) {
-				continue
-			}
-		}
+func countTotalValues(secrets []Secret) int {
+	total := 0
+	for _, s := range secrets {
+		total += s.ChangeCount
+	}
+	return total
+}
 
-		// Extract key=value using configured patterns
-		key, value, found := s.extractKeyValue(line)
-		if !found {
-			continue
-		}
+// streamWriter writes deduplicated findings to a JSONL file
+type streamWriter struct {
+	file    *os.File
+	cfg     *config.Config  // Severities of the entries
+	seen    map[string]bool // Tracks seen (file|key|value) combinations
+	count   int
+	onWrite func(entry StreamEntry) // Called for each new entry (optional)
+}
 
-		if s.config.ShouldIgnoreValue(value) {
-			continue
-		}
+func newStreamWriter(outputPath string, cfg *config.Config) (*streamWriter, error) {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	return &streamWriter{file: file, cfg: cfg, seen: make(map[string]bool)}, nil
+}
 
-		// Deduplication: check if we've seen this (file|key|value) combination
-		dedupeKey := fmt.Sprintf("%s|%s|%s", relPath, key, value)
-		if seen[dedupeKey] {
-			continue
-		}
-		seen[dedupeKey] = true
+// write appends a finding unless the same (file|key|value) was already
+// written. A deletion of the value is written separately from its addition.
+func (w *streamWriter) write(f finding) {
+	dedupeKey := fmt.Sprintf("%s|%s|%s|%t", f.file, f.key, f.value, f.removed)
+	if w.seen[dedupeKey] {
+		return
+	}
+	w.seen[dedupeKey] = true
 
-		entry := StreamEntry{
-			File:        relPath,
-			Key:         key,
-			Value:       value,
-			MaskedValue: maskSecret(value),
-			Type:        keyword,
-			Commit:      "current",
-			Author:      "current",
-			Date:        time.Now().Format(time.RFC3339),
-		}
+	entry := newEntry(f, w.cfg)
+	data, _ := json.Marshal(entry)
+	w.file.WriteString(string(data) + "\n")
+	w.count++
 
-		data, _ := json.Marshal(entry)
-		outFile.WriteString(string(data) + "\n")
-		count++
+	if w.onWrite != nil {
+		w.onWrite(entry)
 	}
-
-	return count
 }
 
-// ScanCurrent scans only current files (no history) - fast mode
-func (s *Scanner) ScanCurrent(repoPath string) (*ScanResult, error) {
-	keywords := s.config.GetAllKeywords()
-	secretsIndex := make(map[string]*secretData)
-
-	for _, keyword := range keywords {
-		s.grepCurrentFiles(repoPath, keyword, secretsIndex)
+// newEntry describes a finding as a stream entry, with the severity of its
+// type in cfg
+func newEntry(f finding, cfg *config.Config) StreamEntry {
+	date := f.commit.date
+	if date == "" {
+		date = time.Now().Format(time.RFC3339)
 	}
 
-	secrets := s.buildSecrets(secretsIndex)
+	entry := StreamEntry{
+		File:        f.file,
+		Key:         f.key,
+		Value:       f.value,
+		MaskedValue: maskSecret(f.value),
+		Type:        f.keyword,
+		Severity:    cfg.SeverityForType(f.keyword),
+		Commit:      f.commit.hash,
+		Author:      f.commit.author,
+		Date:        date,
+		Line:        f.line,
+		Offset:      f.offset,
+		Context:     f.context,
+	}
+	switch {
+	case f.reintroduced:
+		entry.Status = StatusReintroduced
+	case f.removed:
+		entry.Status = StatusRemovedInHistory
+	}
+	entry.JWT = decodeJWT(f.value, time.Now())
+	return entry
+}
 
-	return &ScanResult{
-		Repository:   repoPath,
-		Branch:       "HEAD (current files)",
-		SecretsFound: len(secrets),
-		TotalValues:  countTotalValues(secrets),
-		Secrets:      secrets,
-		ScanDate:     time.Now(),
-	}, nil
+func (w *streamWriter) Close() error {
+	return w.file.Close()
 }
 
-func (s *Scanner) grepCurrentFiles(repoPath, keyword string, index map[string]*secretData) {
-	// Walk all files in the repository (including untracked files)
-	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip files we can't access
-		}
+// ScanStream performs streaming scan to file
+func (s *Scanner) ScanStream(repoPath, outputPath string, opts ScanOptions) (int, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return 0, err
+	}
+	if opts.Branch == "" {
+		opts.Branch = "--all"
+	}
 
-		// Skip directories
-		if info.IsDir() {
-			// Skip .git directory
-			if info.Name() == ".git" {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+	w, err := newStreamWriter(outputPath, s.config)
+	if err != nil {
+		return 0, err
+	}
+	defer w.Close()
 
-		// Get relative path
-		relPath, err := filepath.Rel(repoPath, path)
-		if err != nil {
-			relPath = path
-		}
+	found, err := s.walkHistory(repoPath, opts, 0, w.write)
+	if err == nil && opts.Submodules {
+		_, err = s.walkSubmodules(repoPath, opts, found, w.write)
+	}
+	return w.count, w.finish(err)
+}
 
-		// Check if file should be ignored
-		if s.config.ShouldIgnoreFile(relPath) {
-			return nil
-		}
+// GetAllValues extracts all unique secret values from scan result
+func GetAllValues(scanResult *ScanResult) []string {
+	values := make(map[string]bool)
 
-		// Check if should exclude binary extensions
-		for _, ext := range s.config.ExcludeBinaryExtensions {
-			if strings.HasSuffix(relPath, ext) {
-				return nil
+	for _, secret := range scanResult.Secrets {
+		for _, h := range secret.History {
+			if h.Value != "" && !strings.Contains(h.Value, "REMOVED") {
+				values[h.Value] = true
 			}
 		}
+	}
 
-		// Skip large files (> 1MB)
-		if info.Size() > 1024*1024 {
-			return nil
-		}
+	valueList := make([]string, 0, len(values))
+	for v := range values {
+		valueList = append(valueList, v)
+	}
 
-		// Read and search file
-		s.searchFileForKeyword(relPath, path, keyword, index)
-		return nil
+	// Sort by length (longest first) for cleaning
+	sort.Slice(valueList, func(i, j int) bool {
+		return len(valueList[i]) > len(valueList[j])
 	})
+
+	return valueList
 }
 
-func (s *Scanner) searchFileForKeyword(relPath, fullPath, keyword string, index map[string]*secretData) {
-	file, err := os.Open(fullPath)
+// ScanCurrentStream scans current files and writes to JSONL file as it goes
+func (s *Scanner) ScanCurrentStream(repoPath, outputPath string, opts ScanOptions) (int, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return 0, err
+	}
+	w, err := newStreamWriter(outputPath, s.config)
 	if err != nil {
-		return
+		return 0, err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	keywordLower := strings.ToLower(keyword)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Check if line contains keyword (case insensitive)
-		if !s.config.Settings.CaseSensitive {
-			if !strings.Contains(strings.ToLower(line), keywordLower) {
-				continue
-			}
-		} else {
-			if !strings.Contains(line, keyword) {
-				continue
-			}
-		}
-
-		// Extract key=value using configured patterns
-		key, value, found := s.extractKeyValue(line)
-		if !found {
-			continue
-		}
-
-		if s.config.ShouldIgnoreValue(value) {
-			continue
-		}
-
-		secretKey := fmt.Sprintf("%s|%s", relPath, key)
+	defer w.Close()
 
-		if _, exists := index[secretKey]; !exists {
-			index[secretKey] = &secretData{
-				file:    relPath,
-				key:     key,
-				keyType: keyword,
-				authors: make(map[string]bool),
-				values:  make(map[string]*valueData),
-			}
-		}
-
-		entry := index[secretKey]
-		entry.authors["current"] = true
+	_, err = s.walkCurrent(repoPath, opts, 0, w.write)
+	return w.count, w.finish(err)
+}
 
-		if _, exists := entry.values[value]; !exists {
-			entry.values[value] = &valueData{
-				commits:   []string{"current"},
-				authors:   map[string]bool{"current": true},
-				firstSeen: time.Now(),
-				lastSeen:  time.Now(),
-			}
-		}
+// ScanCurrent scans only current files (no history) - fast mode
+func (s *Scanner) ScanCurrent(repoPath string, opts ScanOptions) (*ScanResult, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return nil, err
 	}
+	opts, stats := opts.collectStats()
+	index := newSecretIndex()
+	_, err := s.walkCurrent(repoPath, opts, 0, index.add)
+	return s.walkResult(index, repoPath, "HEAD (current files)", stats, err)
 }
 
 // ScanBoth scans both current files and git history, combining results
 func (s *Scanner) ScanBoth(repoPath string, opts ScanOptions) (*ScanResult, error) {
-	// First scan current files
-	currentResult, err := s.ScanCurrent(repoPath)
-	if err != nil {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
 		return nil, err
 	}
-
-	// Then scan git history
-	historyResult, err := s.Scan(repoPath, opts)
-	if err != nil {
-		return nil, err
-	}
-
-	// Merge results: combine secrets from both sources
-	secretsMap := make(map[string]*Secret)
-
-	// Add secrets from current files
-	for i := range currentResult.Secrets {
-		secret := &currentResult.Secrets[i]
-		key := fmt.Sprintf("%s|%s", secret.File, secret.Key)
-		secretsMap[key] = secret
+	if opts.Branch == "" {
+		opts.Branch = "--all"
 	}
 
-	// Add/merge secrets from history
-	for i := range historyResult.Secrets {
-		secret := &historyResult.Secrets[i]
-		key := fmt.Sprintf("%s|%s", secret.File, secret.Key)
-
-		if existing, ok := secretsMap[key]; ok {
-			// Merge: add history values and authors
-			existingValues := make(map[string]bool)
-			for _, h := range existing.History {
-				existingValues[h.Value] = true
-			}
-
-			for _, h := range secret.History {
-				if !existingValues[h.Value] {
-					existing.History = append(existing.History, h)
-					existing.ChangeCount++
-				}
-			}
-
-			for _, author := range secret.Authors {
-				found := false
-				for _, a := range existing.Authors {
-					if a == author {
-						found = true
-						break
-					}
-				}
-				if !found {
-					existing.Authors = append(existing.Authors, author)
-				}
-			}
-			existing.TotalOccurrences += secret.TotalOccurrences
-		} else {
-			secretsMap[key] = secret
+	// Both sources feed the same index so values seen in history and in the
+	// working tree are merged into a single secret
+	opts, stats := opts.collectStats()
+	index := newSecretIndex()
+	found, err := s.walkCurrent(repoPath, opts, 0, index.add)
+	if err == nil {
+		var n int
+		n, err = s.walkHistory(repoPath, opts, found, index.add)
+		if err == nil && opts.Submodules {
+			_, err = s.walkSubmodules(repoPath, opts, found+n, index.add)
 		}
 	}
-
-	// Build result slice
-	secrets := make([]Secret, 0, len(secretsMap))
-	for _, secret := range secretsMap {
-		secrets = append(secrets, *secret)
-	}
-
-	// Sort by change count
-	sort.Slice(secrets, func(i, j int) bool {
-		return secrets[i].ChangeCount > secrets[j].ChangeCount
-	})
-
-	return &ScanResult{
-		Repository:   repoPath,
-		Branch:       fmt.Sprintf("%s + current files", opts.Branch),
-		SecretsFound: len(secrets),
-		TotalValues:  countTotalValues(secrets),
-		Secrets:      secrets,
-		ScanDate:     time.Now(),
-	}, nil
+	return s.walkResult(index, repoPath, fmt.Sprintf("%s + current files", opts.revisions()), stats, err)
 }
 
 // ScanBothStream scans both current files and git history to JSONL
 func (s *Scanner) ScanBothStream(repoPath, outputPath string, opts ScanOptions) (int, error) {
-	file, err := os.Create(outputPath)
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return 0, err
+	}
+	w, err := newStreamWriter(outputPath, s.config)
 	if err != nil {
 		return 0, err
 	}
-	defer file.Close()
-
-	// Deduplication set: tracks seen (file|key|value) combinations
-	seen := make(map[string]bool)
-
-	var count int
+	defer w.Close()
 
 	// First scan current files
-	keywords := s.config.GetAllKeywords()
-	for _, keyword := range keywords {
-		c := s.streamCurrentFiles(repoPath, keyword, file, seen)
-		count += c
+	found, err := s.walkCurrent(repoPath, opts, 0, w.write)
+	if err != nil {
+		return w.count, w.finish(err)
 	}
 
 	// Then scan git history
@@ -909,10 +1273,9 @@ func (s *Scanner) ScanBothStream(repoPath, outputPath string, opts ScanOptions)
 		opts.Branch = "--all"
 	}
 
-	for _, keyword := range keywords {
-		c := s.streamKeyword(repoPath, keyword, opts.Branch, file, seen)
-		count += c
+	n, err := s.walkHistory(repoPath, opts, found, w.write)
+	if err == nil && opts.Submodules {
+		_, err = s.walkSubmodules(repoPath, opts, found+n, w.write)
 	}
-
-	return count, nil
+	return w.count, w.finish(err)
 }
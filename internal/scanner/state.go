@@ -0,0 +1,311 @@
+package scanner
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// ScanState records which commits an output file already covers, so repeated
+// scans only walk commits added since the last run
+type ScanState struct {
+	Repository string              `json:"repository"`
+	ConfigHash string              `json:"configHash"` // Findings depend on the configuration
+	Branches   map[string][]string `json:"branches"`   // Branch spec -> tips at the last scan
+	LastScan   time.Time           `json:"lastScan"`
+}
+
+// StatePath returns the state file kept next to an output file
+func StatePath(outputPath string) string {
+	return outputPath + ".state"
+}
+
+// LoadState reads a state file; a missing file yields an empty state
+func LoadState(path string) (*ScanState, error) {
+	state := &ScanState{Branches: make(map[string][]string)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	if state.Branches == nil {
+		state.Branches = make(map[string][]string)
+	}
+	return state, nil
+}
+
+// Save writes the state file
+func (st *ScanState) Save(path string) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// configHash fingerprints the configuration so a changed config forces a full rescan
+func (s *Scanner) configHash() string {
+	data, _ := json.Marshal(s.config)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// branchTips resolves a branch spec (a ref or --all) to commit hashes
+func branchTips(repoPath, branch string) ([]string, error) {
+	cmd := exec.Command("git", append([]string{"rev-parse"}, revisionArgs(branch)...)...)
+	cmd.Dir = repoPath
+	done := debugbundle.Track(cmd)
+	out, err := cmd.Output()
+	done(err)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", branch, err)
+	}
+
+	seen := make(map[string]bool)
+	var tips []string
+	for _, hash := range strings.Fields(string(out)) {
+		if !seen[hash] {
+			seen[hash] = true
+			tips = append(tips, hash)
+		}
+	}
+	return tips, nil
+}
+
+// reachableCommits keeps the hashes that still exist in the repository
+// (history may have been rewritten since the last scan)
+func reachableCommits(repoPath string, hashes []string) []string {
+	var kept []string
+	for _, hash := range hashes {
+		cmd := exec.Command("git", "cat-file", "-e", hash+"^{commit}")
+		cmd.Dir = repoPath
+		done := debugbundle.Track(cmd)
+		err := cmd.Run()
+		done(err)
+		if err == nil {
+			kept = append(kept, hash)
+		}
+	}
+	return kept
+}
+
+// prepareIncremental loads the state for outputPath and returns it along with
+// the commits to exclude and the current tips of branch. The tips are resolved
+// before walking so commits made during the scan are left for the next run.
+// resume is false when the previous output cannot be reused (first run, other
+// repository or changed configuration).
+func (s *Scanner) prepareIncremental(repoPath, outputPath, branch string) (state *ScanState, exclude, tips []string, resume bool, err error) {
+	state, err = LoadState(StatePath(outputPath))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	tips, err = branchTips(repoPath, branch)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	// Compare absolute paths so "." and the full path are the same repository
+	absRepo, err := filepath.Abs(repoPath)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	if _, err := os.Stat(outputPath); err != nil || state.Repository != absRepo || state.ConfigHash != s.configHash() {
+		// Start over: the existing output (if any) is replaced
+		state = &ScanState{Repository: absRepo, ConfigHash: s.configHash(), Branches: make(map[string][]string)}
+		return state, nil, tips, false, nil
+	}
+
+	return state, reachableCommits(repoPath, state.Branches[branch]), tips, true, nil
+}
+
+// finishIncremental records the tips scanned for branch in the state file
+func finishIncremental(outputPath, branch string, tips []string, state *ScanState) error {
+	state.Branches[branch] = tips
+	state.LastScan = time.Now()
+	return state.Save(StatePath(outputPath))
+}
+
+// LoadResult reads a full scan result written by SaveResult
+func LoadResult(path string) (*ScanResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var result ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid result file %s: %w", path, err)
+	}
+	return &result, nil
+}
+
+// SaveResult writes a scan result as indented JSON
+func SaveResult(result *ScanResult, path string) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ScanIncremental scans only the commits added since the last scan recorded
+// for outputPath, merges the new findings into the result already there and
+// writes it back. With withCurrent, the working tree is rescanned as well and
+// replaces the previously recorded current files.
+func (s *Scanner) ScanIncremental(repoPath, outputPath string, withCurrent bool, opts ScanOptions) (*ScanResult, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return nil, err
+	}
+	if opts.Branch == "" {
+		opts.Branch = "--all"
+	}
+	if opts.Range != "" {
+		return nil, ErrRangeNotIncremental
+	}
+
+	state, exclude, tips, resume, err := s.prepareIncremental(repoPath, outputPath, opts.Branch)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, stats := opts.collectStats()
+	index := newSecretIndex()
+	if resume {
+		previous, err := LoadResult(outputPath)
+		if err != nil {
+			return nil, err
+		}
+		index.load(previous, withCurrent)
+	}
+
+	// An interrupted scan leaves the output and its state as they were
+	var found int
+	if withCurrent {
+		if found, err = s.walkCurrent(repoPath, opts, 0, index.add); err != nil {
+			return nil, err
+		}
+	}
+
+	branch := opts.Branch
+	walkOpts := opts
+	walkOpts.Branch = strings.Join(tips, " ")
+	walkOpts.Exclude = exclude
+	if len(tips) > 0 {
+		if _, err := s.walkHistory(repoPath, walkOpts, found, index.add); err != nil {
+			return nil, err
+		}
+	}
+
+	if withCurrent {
+		branch = fmt.Sprintf("%s + current files", branch)
+	}
+	result := index.result(repoPath, branch, s.config)
+	result.Stats = stats()
+
+	if err := SaveResult(result, outputPath); err != nil {
+		return nil, err
+	}
+	if err := finishIncremental(outputPath, opts.Branch, tips, state); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ScanStreamIncremental appends the findings of commits added since the last
+// scan recorded for outputPath. Entries already in the file are not repeated.
+// Returns the number of new entries.
+func (s *Scanner) ScanStreamIncremental(repoPath, outputPath string, withCurrent bool, opts ScanOptions) (int, error) {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return 0, err
+	}
+	if opts.Branch == "" {
+		opts.Branch = "--all"
+	}
+	if opts.Range != "" {
+		return 0, ErrRangeNotIncremental
+	}
+
+	state, exclude, tips, resume, err := s.prepareIncremental(repoPath, outputPath, opts.Branch)
+	if err != nil {
+		return 0, err
+	}
+
+	var w *streamWriter
+	if resume {
+		w, err = appendStreamWriter(outputPath, s.config)
+	} else {
+		w, err = newStreamWriter(outputPath, s.config)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer w.Close()
+
+	// An interrupted scan keeps its entries but not the state: the next
+	// run scans the same commits again
+	var found int
+	if withCurrent {
+		if found, err = s.walkCurrent(repoPath, opts, 0, w.write); err != nil {
+			return w.count, w.finish(err)
+		}
+	}
+
+	walkOpts := opts
+	walkOpts.Branch = strings.Join(tips, " ")
+	walkOpts.Exclude = exclude
+	if len(tips) > 0 {
+		if _, err := s.walkHistory(repoPath, walkOpts, found, w.write); err != nil {
+			return w.count, w.finish(err)
+		}
+	}
+
+	if err := finishIncremental(outputPath, opts.Branch, tips, state); err != nil {
+		return w.count, err
+	}
+	return w.count, nil
+}
+
+// appendStreamWriter opens an existing JSONL file for appending, remembering
+// the entries it already holds
+func appendStreamWriter(outputPath string, cfg *config.Config) (*streamWriter, error) {
+	file, err := os.OpenFile(outputPath, os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &streamWriter{file: file, cfg: cfg, seen: make(map[string]bool)}
+	// Entries have no length limit: a minified file or PEM block makes long lines
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		var entry StreamEntry
+		if json.Unmarshal(line, &entry) == nil && !entry.Interrupted {
+			w.seen[fmt.Sprintf("%s|%s|%s", entry.File, entry.Key, entry.Value)] = true
+		}
+		if err == io.EOF {
+			return w, nil
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("%s: %w", outputPath, err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"io"
+
+	"github.com/Drilmo/git-secret-scanner/internal/model"
+)
+
+// ScanStats measures the data a scan went through: how much of the
+// repository it covered, and a base to compare the speed of versions
+type ScanStats = model.ScanStats
+
+// StatsFrom adds up the last progress report of each phase
+func StatsFrom(last map[string]Progress) ScanStats {
+	var stats ScanStats
+	for _, p := range last {
+		stats.Commits += p.Commits
+		stats.Files += p.Files
+		stats.Lines += p.Lines
+		stats.Bytes += p.Bytes
+	}
+	return stats
+}
+
+// FormatBytes writes a size in B, KB, MB or GB
+func FormatBytes(n int64) string {
+	return model.FormatBytes(n)
+}
+
+// collectStats returns opts reporting its progress to stats too, and a
+// function returning the statistics of the scan so far
+func (o ScanOptions) collectStats() (ScanOptions, func() *ScanStats) {
+	last := make(map[string]Progress)
+	report := o.OnProgress
+	o.OnProgress = func(p Progress) {
+		last[p.Phase] = p
+		if report != nil {
+			report(p)
+		}
+	}
+	return o, func() *ScanStats {
+		stats := StatsFrom(last)
+		return &stats
+	}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// structuredFormat returns the format a working-tree file is parsed with,
+// "" when settings.structuredFiles is off or the file has its own parser
+func (s *Scanner) structuredFormat(file string) string {
+	if !s.config.Settings.StructuredFiles || config.CredentialFileKind(file) != "" ||
+		config.IsTerraformFile(file) || config.IsEnvFile(file) {
+		return ""
+	}
+	return config.StructuredFormat(file)
+}
+
+// structuredLines parses a structured file and returns its values by line.
+// A line is left out, and matched line by line, when one of its values is
+// not written as is (escapes, folded strings): findings must match the file
+// text to be cleaned. nil when the file is not valid.
+func structuredLines(format string, data []byte) map[int][]config.StructuredValue {
+	values, err := config.ParseStructured(format, data)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	byLine := make(map[int][]config.StructuredValue)
+	verbatim := make(map[int]bool)
+	for _, v := range values {
+		if v.Line < 1 || v.Line > len(lines) {
+			continue
+		}
+		ok, seen := verbatim[v.Line]
+		verbatim[v.Line] = (ok || !seen) && strings.Contains(lines[v.Line-1], v.Value)
+		byLine[v.Line] = append(byLine[v.Line], v)
+	}
+	for n, ok := range verbatim {
+		if !ok {
+			delete(byLine, n)
+		}
+	}
+	return byLine
+}
+
+// matchStructured matches a value of a structured file. As for dotenv
+// files, the keyword must be in its key; the finding is reported under the
+// whole key path.
+func (s *Scanner) matchStructured(v config.StructuredValue) (keyword, key, value string, found bool) {
+	if strings.Contains(v.Value, "\n") || s.config.ShouldIgnoreKey(v.Key) || s.config.ShouldIgnoreKey(v.Path) {
+		return "", "", "", false // Block scalars go through the PEM matcher
+	}
+	if key, password, ok := config.ExtractURLCredential(v.Value); ok && !s.config.ShouldIgnoreValue(password) {
+		return config.URLCredentialType, key, password, true
+	}
+	if keyword, ok := s.matchKeyword(v.Key, v.Value); ok && !s.config.ShouldIgnoreValue(v.Value) {
+		return keyword, v.Path, v.Value, true
+	}
+	if token, ok := findJWT(v.Value); ok {
+		return JWTKeyword, v.Path, token, true
+	}
+	return "", "", "", false
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+)
+
+// SubmodulePhase prefixes the progress phase of a submodule history walk
+const SubmodulePhase = "submodule:"
+
+// listSubmodules returns the paths of the initialized submodules, nested
+// ones included, relative to the top-level repository
+func listSubmodules(repoPath string) ([]string, error) {
+	cmd := exec.Command("git", "-c", "core.quotepath=off", "submodule", "status", "--recursive")
+	cmd.Dir = repoPath
+	done := debugbundle.Track(cmd)
+	out, err := cmd.Output()
+	done(err)
+	if err != nil {
+		return nil, fmt.Errorf("git submodule status failed: %w", err)
+	}
+
+	var paths []string
+	for _, line := range strings.Split(string(out), "\n") {
+		// "-" marks a submodule that is not initialized (nothing to scan)
+		if line == "" || line[0] == '-' {
+			continue
+		}
+		fields := strings.Fields(line[1:])
+		if len(fields) >= 2 {
+			paths = append(paths, fields[1])
+		}
+	}
+	return paths, nil
+}
+
+// walkSubmodules walks the history of every initialized submodule with the
+// same configuration. Files are reported under the submodule path. A range
+// or branch of the parent means nothing in a submodule: its checked-out
+// HEAD is walked instead, unless the whole history (--all) was requested.
+func (s *Scanner) walkSubmodules(repoPath string, opts ScanOptions, baseFound int, emit func(f finding)) (int, error) {
+	subs, err := listSubmodules(repoPath)
+	if err != nil {
+		return 0, err
+	}
+
+	found := 0
+	for _, sub := range subs {
+		subOpts := opts
+		subOpts.Range = ""
+		subOpts.Exclude = nil
+		if opts.Branch != "--all" {
+			subOpts.Branch = "HEAD"
+		}
+		subOpts.OnProgress = func(p Progress) {
+			p.Phase = SubmodulePhase + sub
+			opts.report(p)
+		}
+		if opts.OnFinding != nil {
+			subOpts.OnFinding = func(entry StreamEntry) {
+				entry.File = path.Join(sub, entry.File)
+				opts.OnFinding(entry)
+			}
+		}
+
+		prefixed := func(f finding) {
+			f.file = path.Join(sub, f.file)
+			emit(f)
+		}
+		n, err := s.walkHistory(filepath.Join(repoPath, sub), subOpts, baseFound+found, prefixed)
+		if err != nil {
+			return found, fmt.Errorf("submodule %s: %w", sub, err)
+		}
+		found += n
+	}
+	return found, nil
+}
@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// Summary is a compact, machine-readable overview of a scan
+type Summary struct {
+	Output       string         `json:"output"`
+	Mode         string         `json:"mode"`
+	Secrets      int            `json:"secrets"`      // Distinct file/key pairs
+	Values       int            `json:"values"`       // Distinct values
+	Reintroduced int            `json:"reintroduced"` // Values found again after a cleanup
+	BySeverity   map[string]int `json:"bySeverity"`
+	ByType       map[string]int `json:"byType"`
+	Commits      int            `json:"commits"`
+	Files        int            `json:"files"` // Working-tree files read
+	Lines        int            `json:"lines"` // Lines matched against the keywords
+	Bytes        int64          `json:"bytes"` // Bytes of diff and file content read
+	SkippedLines int            `json:"skippedLines"`
+	Truncated    bool           `json:"truncated"` // Some lines were too long to scan
+	DurationMs   int64          `json:"durationMs"`
+	Error        string         `json:"error,omitempty"` // Why the scan failed: the counts are then incomplete
+}
+
+// newSummary creates a summary with every severity present
+func newSummary(output, mode string) *Summary {
+	s := &Summary{Output: output, Mode: mode, BySeverity: make(map[string]int), ByType: make(map[string]int)}
+	for _, severity := range config.Severities {
+		s.BySeverity[severity] = 0
+	}
+	return s
+}
+
+// FailedSummary is the summary of a scan that stopped on err, before its
+// findings could be counted
+func FailedSummary(output, mode string, err error) *Summary {
+	s := newSummary(output, mode)
+	s.Error = err.Error()
+	return s
+}
+
+// Finish records the run statistics from the last progress report of each phase
+func (s *Summary) Finish(last map[string]Progress, duration time.Duration) {
+	stats := StatsFrom(last)
+	s.Commits, s.Files, s.Lines, s.Bytes = stats.Commits, stats.Files, stats.Lines, stats.Bytes
+	for _, p := range last {
+		s.SkippedLines += p.Skipped
+	}
+	s.Truncated = s.SkippedLines > 0
+	s.DurationMs = duration.Milliseconds()
+}
+
+// JSON returns the summary as a single line
+func (s *Summary) JSON() string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// Summarize counts the secrets of a full scan result by severity and type
+func Summarize(result *ScanResult, cfg *config.Config, output string) *Summary {
+	summary := newSummary(output, "full")
+	for _, secret := range result.Secrets {
+		for _, h := range secret.History {
+			if h.Status == StatusReintroduced {
+				summary.Reintroduced++
+			}
+		}
+		summary.Secrets++
+		summary.Values += secret.ChangeCount
+		summary.BySeverity[SecretSeverity(cfg, secret)]++
+		summary.ByType[secret.Type]++
+	}
+	return summary
+}
+
+// SummarizeStream counts the entries of a JSONL output file by severity and type
+func SummarizeStream(path string, cfg *config.Config) (*Summary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	summary := newSummary(path, "stream")
+	secrets := make(map[string]string) // Secret -> severity counted
+	reader := bufio.NewReaderSize(file, maxLineLength)
+	for {
+		line, tooLong, err := readLine(reader)
+		if err != nil {
+			break
+		}
+		var entry StreamEntry
+		if tooLong || json.Unmarshal([]byte(line), &entry) != nil || entry.Interrupted {
+			continue
+		}
+		secretKey := fmt.Sprintf("%s|%s", entry.File, entry.Key)
+		severity := EntrySeverity(cfg, entry)
+		switch previous, seen := secrets[secretKey]; {
+		case !seen:
+			secrets[secretKey] = severity
+			summary.Secrets++
+			summary.BySeverity[severity]++
+			summary.ByType[entry.Type]++
+		case severity == config.SeverityCritical && previous != severity:
+			// A reintroduced value raises the whole secret
+			secrets[secretKey] = severity
+			summary.BySeverity[previous]--
+			summary.BySeverity[severity]++
+		}
+		if entry.Status == StatusReintroduced {
+			summary.Reintroduced++
+		}
+		summary.Values++
+	}
+	return summary, nil
+}
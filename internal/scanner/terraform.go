@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// terraformWalker follows the nesting of a Terraform state or variables
+// file, so an output value is reported under the output name
+// ("db_password": {"value": "..."}) rather than as "value"
+type terraformWalker struct {
+	stack []string // Names of the enclosing blocks, "" for list items
+}
+
+// feed passes the next line of the file. For a string assignment it returns
+// the name the value goes by and the value.
+func (w *terraformWalker) feed(line string) (name, value string, assigned bool) {
+	tl := config.ParseTerraformLine(line)
+	switch {
+	case tl.Closes:
+		if len(w.stack) > 0 {
+			w.stack = w.stack[:len(w.stack)-1]
+		}
+	case tl.Opens:
+		w.stack = append(w.stack, tl.Key)
+	case tl.String:
+		name = tl.Key
+		if name == "value" {
+			for i := len(w.stack) - 1; i >= 0; i-- {
+				if w.stack[i] != "" {
+					name = w.stack[i]
+					break
+				}
+			}
+		}
+		return name, tl.Value, true
+	}
+	return "", "", false
+}
+
+// feedDiff follows the new side of a Terraform file in a diff: context lines
+// update the nesting here, added lines when they are matched. The nesting
+// restarts at each hunk and file.
+func (w *terraformWalker) feedDiff(p *diffParser, line string) {
+	if !p.inHunk || p.file == "" || line == "" || !config.IsTerraformFile(p.file) {
+		w.reset()
+		return
+	}
+	switch line[0] {
+	case ' ':
+		w.feed(line[1:])
+	case '+', '-':
+	default: // Next hunk header
+		w.reset()
+	}
+}
+
+// reset forgets the enclosing blocks
+func (w *terraformWalker) reset() {
+	w.stack = w.stack[:0]
+}
+
+// matchTerraformLine matches a line of a Terraform file: string attributes
+// are reported when their name denotes a credential, keyword or not
+func (s *Scanner) matchTerraformLine(tf *terraformWalker, line string) (keyword, key, value string, found bool) {
+	key, value, ok := tf.feed(line)
+	if !ok {
+		return s.matchLine(line) // Not an assignment: a heredoc line, a pasted token...
+	}
+	if key, password, ok := config.ExtractURLCredential(value); ok && !s.config.ShouldIgnoreValue(password) {
+		return config.URLCredentialType, key, password, true
+	}
+	if config.IsSensitiveTerraformAttribute(key) && !s.config.ShouldIgnoreValue(value) {
+		keyword, ok := s.matchKeyword(key, value)
+		if !ok {
+			keyword = config.TerraformType
+		}
+		return keyword, key, value, true
+	}
+	if token, ok := findJWT(value); ok {
+		return JWTKeyword, key, token, true
+	}
+	return "", "", "", false
+}
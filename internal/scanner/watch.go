@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WatchOptions configures continuous scanning
+type WatchOptions struct {
+	ScanOptions
+	Interval time.Duration         // Delay between two polls (default 30s)
+	OnPoll   func(newCommits bool) // Called after each poll (optional)
+}
+
+// Watch polls the repository until ctx is cancelled. New commits on the
+// watched branches and modified working-tree files are scanned as they
+// appear; new findings are appended to the JSONL file at outputPath and
+// passed to OnFinding (those already in the file are not repeated). The
+// incremental state is shared with ScanStreamIncremental, so a restarted
+// watcher resumes where it stopped.
+func (s *Scanner) Watch(ctx context.Context, repoPath, outputPath string, opts WatchOptions) error {
+	if err := s.useProfile(opts.Profile, opts.Groups); err != nil {
+		return err
+	}
+	if opts.Branch == "" {
+		opts.Branch = "--all"
+	}
+	if opts.Range != "" {
+		return ErrRangeNotIncremental
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+
+	state, exclude, _, resume, err := s.prepareIncremental(repoPath, outputPath, opts.Branch)
+	if err != nil {
+		return err
+	}
+
+	var w *streamWriter
+	if resume {
+		w, err = appendStreamWriter(outputPath, s.config)
+	} else {
+		w, err = newStreamWriter(outputPath, s.config)
+	}
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	// Findings are reported once written, the output holding the previous ones
+	w.onWrite = opts.OnFinding
+	opts.OnFinding = nil
+
+	modTimes := make(map[string]time.Time)
+
+	for {
+		// New commits since the previous poll
+		tips, err := branchTips(repoPath, opts.Branch)
+		if err != nil {
+			return err
+		}
+		newCommits := len(tips) > 0 && !sameCommits(tips, exclude)
+		if newCommits {
+			walkOpts := opts.ScanOptions
+			walkOpts.Branch = strings.Join(tips, " ")
+			walkOpts.Exclude = exclude
+			if _, err := s.walkHistory(repoPath, walkOpts, w.count, w.write); err != nil {
+				return err
+			}
+			if err := finishIncremental(outputPath, opts.Branch, tips, state); err != nil {
+				return err
+			}
+			exclude = tips
+		}
+
+		// Working-tree files created or modified since the previous poll
+		s.walkModified(repoPath, modTimes, opts.Context, w.write)
+
+		if opts.OnPoll != nil {
+			opts.OnPoll(newCommits)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// walkModified scans the working-tree files whose modification time changed
+// since they were last seen, and forgets deleted files
+func (s *Scanner) walkModified(repoPath string, modTimes map[string]time.Time, context int, emit func(f finding)) {
+	emit = flagReintroduced(repoPath, emit)
+	s.useRepoIgnores(repoPath) // A broken file keeps the rules in force
+	present := make(map[string]bool)
+
+	for _, relPath := range s.listCurrentFiles(repoPath) {
+		present[relPath] = true
+		fullPath := filepath.Join(repoPath, relPath)
+		info, err := os.Stat(fullPath)
+		if err != nil {
+			continue
+		}
+		if last, ok := modTimes[relPath]; ok && last.Equal(info.ModTime()) {
+			continue
+		}
+		modTimes[relPath] = info.ModTime()
+		s.matchFile(relPath, fullPath, context, &Progress{}, emit)
+	}
+
+	for relPath := range modTimes {
+		if !present[relPath] {
+			delete(modTimes, relPath)
+		}
+	}
+}
+
+// sameCommits reports whether both lists hold the same hashes
+func sameCommits(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(b))
+	for _, hash := range b {
+		set[hash] = true
+	}
+	for _, hash := range a {
+		if !set[hash] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Thursday 2024-03-14 10:30
+	from := time.Date(2024, 3, 14, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 2 * * 0", time.Date(2024, 3, 17, 2, 0, 0, 0, time.UTC)},       // Sunday 02:00
+		{"0 2 * * 7", time.Date(2024, 3, 17, 2, 0, 0, 0, time.UTC)},       // 7 is Sunday too
+		{"*/15 * * * *", time.Date(2024, 3, 14, 10, 45, 0, 0, time.UTC)},  // Step
+		{"0 9-17 * * 1-5", time.Date(2024, 3, 14, 11, 0, 0, 0, time.UTC)}, // Office hours
+		{"0 0 1,15 * *", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},    // List
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},      // Next leap day
+		{"@weekly", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := c.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := ParseCron(bad); err == nil {
+			t.Errorf("ParseCron(%q) accepted an invalid expression", bad)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package schedule
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// DefaultRetention is how many results are kept per job when not configured
+const DefaultRetention = 8
+
+// Job is one repository scanned on a schedule
+type Job struct {
+	Name    string `json:"name"`
+	Repo    string `json:"repo"`
+	Cron    string `json:"cron"`              // e.g. "0 2 * * 0" for Sundays at 02:00
+	Branch  string `json:"branch,omitempty"`  // Default: --all
+	Source  string `json:"source,omitempty"`  // both (default), current or history
+	Config  string `json:"config,omitempty"`  // Pattern configuration (default: GITSECRET_CONFIG, else the repository's, else auto-detect)
+	Profile string `json:"profile,omitempty"` // Profile of the configuration to apply (see config.Profile)
+
+	cron *Cron
+}
+
+// Config is a schedule file
+type Config struct {
+	Jobs               []Job  `json:"jobs"`
+	OutputDir          string `json:"outputDir,omitempty"`          // Default: ~/.config/git-secret-scanner/scheduled
+	Retention          int    `json:"retention,omitempty"`          // Results kept per job
+	Notify             string `json:"notify,omitempty"`             // Shell command run for each new finding
+	NotifyReintroduced string `json:"notifyReintroduced,omitempty"` // Replaces Notify for secrets reintroduced after a cleanup
+}
+
+// Run is the outcome of one scheduled scan
+type Run struct {
+	Job      *Job
+	Output   string
+	Findings int
+	New      []scanner.StreamEntry // Not present in the previous result
+	Resolved int                   // In the previous result but gone now
+	First    bool                  // No previous result to compare with
+	Config   *config.Config        // Pattern configuration used (for severities)
+}
+
+// Load reads and validates a schedule file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid schedule %s: %w", path, err)
+	}
+
+	if len(cfg.Jobs) == 0 {
+		return nil, fmt.Errorf("schedule %s has no jobs", path)
+	}
+	if cfg.OutputDir == "" {
+		cfg.OutputDir = filepath.Join(config.UserConfigDir(), "scheduled")
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+
+	names := make(map[string]bool)
+	for i := range cfg.Jobs {
+		job := &cfg.Jobs[i]
+		if job.Name == "" || strings.ContainsAny(job.Name, `/\`) {
+			return nil, fmt.Errorf("job %d: a name without slashes is required", i+1)
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("job %s: duplicate name", job.Name)
+		}
+		names[job.Name] = true
+		if job.Repo == "" {
+			return nil, fmt.Errorf("job %s: repo is required", job.Name)
+		}
+		switch job.Source {
+		case "":
+			job.Source = "both"
+		case "both", "current", "history":
+		default:
+			return nil, fmt.Errorf("job %s: invalid source %q", job.Name, job.Source)
+		}
+		if job.Branch == "" {
+			job.Branch = "--all"
+		}
+		if job.cron, err = ParseCron(job.Cron); err != nil {
+			return nil, fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+	return &cfg, nil
+}
+
+// Next returns the jobs due at the earliest upcoming run time after now
+func (c *Config) Next(now time.Time) ([]*Job, time.Time) {
+	var due []*Job
+	var at time.Time
+	for i := range c.Jobs {
+		job := &c.Jobs[i]
+		next := job.cron.Next(now)
+		if next.IsZero() {
+			continue
+		}
+		switch {
+		case at.IsZero() || next.Before(at):
+			due, at = []*Job{job}, next
+		case next.Equal(at):
+			due = append(due, job)
+		}
+	}
+	return due, at
+}
+
+// RunJob scans the job repository to a new timestamped JSONL file, compares
+// it with the previous result and prunes results beyond the retention
+func (c *Config) RunJob(job *Job, now time.Time) (*Run, error) {
+	cfg, err := loadPatterns(job.Config, job.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dir := filepath.Join(c.OutputDir, job.Name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	previous := results(dir)
+	output := filepath.Join(dir, now.Format("20060102-150405")+".jsonl")
+
+	s := scanner.New(cfg)
+	opts := scanner.ScanOptions{Branch: job.Branch, ConfigPath: job.Config, Profile: job.Profile}
+	run := &Run{Job: job, Output: output, Config: cfg, First: len(previous) == 0}
+	switch job.Source {
+	case "current":
+		run.Findings, err = s.ScanCurrentStream(job.Repo, output, opts)
+	case "history":
+		run.Findings, err = s.ScanStream(job.Repo, output, opts)
+	default:
+		run.Findings, err = s.ScanBothStream(job.Repo, output, opts)
+	}
+	if err != nil {
+		os.Remove(output)
+		return nil, err
+	}
+
+	if !run.First {
+		if err := run.compare(previous[len(previous)-1]); err != nil {
+			return run, err
+		}
+	}
+
+	// Retention: the new result plus the most recent ones
+	all := results(dir)
+	for len(all) > c.Retention {
+		os.Remove(all[0])
+		all = all[1:]
+	}
+	return run, nil
+}
+
+// compare fills New and Resolved against a previous result
+func (r *Run) compare(previousPath string) error {
+	before, err := readEntries(previousPath)
+	if err != nil {
+		return err
+	}
+	now, err := readEntries(r.Output)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(now))
+	for k := range now {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := before[k]; !ok {
+			r.New = append(r.New, now[k])
+		}
+	}
+	for k := range before {
+		if _, ok := now[k]; !ok {
+			r.Resolved++
+		}
+	}
+	return nil
+}
+
+// results returns the result files of a job, oldest first
+func results(dir string) []string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	sort.Strings(files)
+	return files
+}
+
+// readEntries loads a JSONL result keyed by file, key and value hash
+func readEntries(path string) (map[string]scanner.StreamEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := make(map[string]scanner.StreamEntry)
+	lines := bufio.NewScanner(file)
+	lines.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lines.Scan() {
+		var entry scanner.StreamEntry
+		if json.Unmarshal(lines.Bytes(), &entry) != nil || entry.Interrupted {
+			continue
+		}
+		sum := sha256.Sum256([]byte(entry.Value))
+		entries[entry.File+"|"+entry.Key+"|"+hex.EncodeToString(sum[:])] = entry
+	}
+	return entries, lines.Err()
+}
+
+// loadPatterns loads a job configuration, else GITSECRET_CONFIG, else the
+// one of its repository, else the auto-detected one
+func loadPatterns(path, repo string) (*config.Config, error) {
+	if path == "" {
+		path = os.Getenv(config.ConfigEnv)
+	}
+	if path == "" {
+		if repoFile := config.RepoConfigPath(repo); repoFile != "" {
+			return config.Load(repoFile)
+		}
+		return config.LoadAuto()
+	}
+	return config.Load(path)
+}
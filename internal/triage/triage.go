@@ -0,0 +1,227 @@
+// Package triage stores review decisions about findings. Decisions are kept
+// in a baseline file at the repository root, keyed by file, key and a hash
+// of the value so the store never contains the secrets themselves.
+package triage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Triage statuses
+const (
+	StatusConfirmed     = "confirmed"      // Real secret, not rotated yet
+	StatusRotated       = "rotated"        // Real secret, already rotated: safe to clean
+	StatusFalsePositive = "false_positive" // Not a secret
+	StatusAccepted      = "accepted"       // Real secret, risk accepted
+)
+
+// Statuses lists all statuses in display order
+var Statuses = []string{StatusConfirmed, StatusRotated, StatusFalsePositive, StatusAccepted}
+
+// Decision is the review outcome for one value of a secret
+type Decision struct {
+	File      string    `json:"file"`
+	Key       string    `json:"key"`
+	ValueHash string    `json:"valueHash"`
+	Status    string    `json:"status"`
+	Note      string    `json:"note,omitempty"`
+	DecidedBy string    `json:"decidedBy,omitempty"`
+	Date      time.Time `json:"date"`
+}
+
+// CleanedValue records a value removed by a cleanup, so scans can flag it
+// if it comes back. Only the hash is kept: the value may be found again in
+// any file, under any key.
+type CleanedValue struct {
+	ValueHash string    `json:"valueHash"`
+	Date      time.Time `json:"date"`
+}
+
+// Store holds the decisions of a baseline file
+type Store struct {
+	Version   int            `json:"version"`
+	Decisions []Decision     `json:"decisions"`
+	Cleaned   []CleanedValue `json:"cleaned,omitempty"`
+
+	path    string
+	index   map[string]int       // Fingerprint -> position in Decisions
+	cleaned map[string]time.Time // Hash -> date of Cleaned
+}
+
+// Fingerprint identifies a value of a secret without revealing it
+func Fingerprint(file, key, value string) string {
+	return file + "|" + key + "|" + HashValue(value)
+}
+
+// HashValue returns the hash under which a value is recorded
+func HashValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// Load reads a baseline file; a missing file yields an empty store
+func Load(path string) (*Store, error) {
+	store := &Store{Version: 1, path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, store); err != nil {
+			return nil, fmt.Errorf("invalid baseline %s: %w", path, err)
+		}
+	}
+
+	store.reindex()
+	return store, nil
+}
+
+func (s *Store) reindex() {
+	s.index = make(map[string]int, len(s.Decisions))
+	for i, d := range s.Decisions {
+		s.index[d.File+"|"+d.Key+"|"+d.ValueHash] = i
+	}
+	s.cleaned = make(map[string]time.Time, len(s.Cleaned))
+	for _, c := range s.Cleaned {
+		s.cleaned[c.ValueHash] = c.Date
+	}
+}
+
+// Path returns the file the store is saved to
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Get returns the decision recorded for a value, if any
+func (s *Store) Get(file, key, value string) (Decision, bool) {
+	i, ok := s.index[Fingerprint(file, key, value)]
+	if !ok {
+		return Decision{}, false
+	}
+	return s.Decisions[i], true
+}
+
+// Status returns the recorded status of a value ("" when not reviewed)
+func (s *Store) Status(file, key, value string) string {
+	d, _ := s.Get(file, key, value)
+	return d.Status
+}
+
+// Dismissed reports whether a value was triaged as needing no action, a
+// false positive or an accepted risk: reports and cleanups leave it out
+func (s *Store) Dismissed(file, key, value string) bool {
+	status := s.Status(file, key, value)
+	return status == StatusFalsePositive || status == StatusAccepted
+}
+
+// Set records (or replaces) the decision for a value
+func (s *Store) Set(file, key, value, status, note string) {
+	d := Decision{
+		File:      file,
+		Key:       key,
+		ValueHash: HashValue(value),
+		Status:    status,
+		Note:      note,
+		DecidedBy: currentUser(),
+		Date:      time.Now().UTC(),
+	}
+
+	if i, ok := s.index[Fingerprint(file, key, value)]; ok {
+		s.Decisions[i] = d
+		return
+	}
+	s.index[Fingerprint(file, key, value)] = len(s.Decisions)
+	s.Decisions = append(s.Decisions, d)
+}
+
+// Remove forgets the decision for a value
+func (s *Store) Remove(file, key, value string) {
+	i, ok := s.index[Fingerprint(file, key, value)]
+	if !ok {
+		return
+	}
+	s.Decisions = append(s.Decisions[:i], s.Decisions[i+1:]...)
+	s.reindex()
+}
+
+// MarkCleaned records that a value was removed by a cleanup. A value
+// cleaned again keeps the date of its latest cleanup.
+func (s *Store) MarkCleaned(value string) {
+	hash := HashValue(value)
+	now := time.Now().UTC()
+	if _, ok := s.cleaned[hash]; ok {
+		for i := range s.Cleaned {
+			if s.Cleaned[i].ValueHash == hash {
+				s.Cleaned[i].Date = now
+			}
+		}
+	} else {
+		s.Cleaned = append(s.Cleaned, CleanedValue{ValueHash: hash, Date: now})
+	}
+	s.cleaned[hash] = now
+}
+
+// CleanedAt returns when a value was last removed by a cleanup
+func (s *Store) CleanedAt(value string) (time.Time, bool) {
+	if len(s.cleaned) == 0 {
+		return time.Time{}, false
+	}
+	date, ok := s.cleaned[HashValue(value)]
+	return date, ok
+}
+
+// Counts returns the number of decisions per status
+func (s *Store) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, d := range s.Decisions {
+		counts[d.Status]++
+	}
+	return counts
+}
+
+// Save writes the store, sorted so the file diffs well under version control
+func (s *Store) Save() error {
+	sort.SliceStable(s.Decisions, func(i, j int) bool {
+		a, b := s.Decisions[i], s.Decisions[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.ValueHash < b.ValueHash
+	})
+	sort.Slice(s.Cleaned, func(i, j int) bool {
+		return s.Cleaned[i].ValueHash < s.Cleaned[j].ValueHash
+	})
+	s.reindex()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(data, '\n'), 0644)
+}
+
+// currentUser names who took a decision (git user, then OS user)
+func currentUser() string {
+	if out, err := exec.Command("git", "config", "user.name").Output(); err == nil {
+		if name := strings.TrimSpace(string(out)); name != "" {
+			return name
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package triage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitsecret-baseline.json")
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load missing file: %v", err)
+	}
+	store.Set("app.conf", "db_password", "Sup3rS3cret!", StatusConfirmed, "")
+	store.Set("app.conf", "token", "tok_123456", StatusFalsePositive, "test fixture")
+	store.Set("app.conf", "db_password", "Sup3rS3cret!", StatusRotated, "")
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "Sup3rS3cret!") {
+		t.Fatal("raw value written to the baseline")
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(reloaded.Decisions) != 2 {
+		t.Fatalf("got %d decisions, want 2", len(reloaded.Decisions))
+	}
+	if got := reloaded.Status("app.conf", "db_password", "Sup3rS3cret!"); got != StatusRotated {
+		t.Errorf("status = %q, want %q", got, StatusRotated)
+	}
+	if got := reloaded.Status("app.conf", "db_password", "other"); got != "" {
+		t.Errorf("unknown value has status %q", got)
+	}
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	cleanLogSize = 500 // Lines of tool output kept
+	cleanLogRows = 10  // Lines of the pane
+)
+
+// cleanLog collects the output of the running clean (CleanOptions.Output)
+// and its steps (CleanOptions.OnProgress) for the progress view. The tools
+// write from the goroutine of the clean: the view reads a copy.
+type cleanLog struct {
+	mu       sync.Mutex
+	lines    []string
+	partial  string // Line written without its newline yet
+	returned bool   // A carriage return ended the partial line: the next text replaces it
+	dropped  int    // Oldest lines dropped
+	step     int    // Current step, 0 for a stage out of the count
+	total    int
+	status   string
+}
+
+func newCleanLog() *cleanLog {
+	return &cleanLog{}
+}
+
+// Write adds tool output. Progress counters rewritten in place with a
+// carriage return keep a single line.
+func (l *cleanLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := string(p)
+	for s != "" {
+		if l.returned && s[0] != '\n' {
+			l.partial, l.returned = "", false
+		}
+		i := strings.IndexAny(s, "\r\n")
+		if i < 0 {
+			l.partial += s
+			break
+		}
+		l.partial += s[:i]
+		if s[i] == '\n' {
+			l.endLine()
+		} else {
+			l.returned = true
+		}
+		s = s[i+1:]
+	}
+	return len(p), nil
+}
+
+// endLine moves the partial line to the log
+func (l *cleanLog) endLine() {
+	l.lines = append(l.lines, l.partial)
+	l.partial, l.returned = "", false
+	if len(l.lines) > cleanLogSize {
+		l.dropped += len(l.lines) - cleanLogSize
+		l.lines = l.lines[len(l.lines)-cleanLogSize:]
+	}
+}
+
+// progress starts a step (CleanOptions.OnProgress); a step of 0 is a stage
+// after the counted ones, like the verification scan
+func (l *cleanLog) progress(step, total int, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.partial != "" {
+		l.endLine()
+	}
+	l.step, l.total, l.status = step, total, message
+	l.partial = "── " + l.stepLabel()
+	l.endLine()
+}
+
+// stepLabel is the step and its message, e.g. "Step 2/3: Running git gc..."
+func (l *cleanLog) stepLabel() string {
+	if l.step == 0 {
+		return l.status
+	}
+	return fmt.Sprintf("Step %d/%d: %s", l.step, l.total, l.status)
+}
+
+// snapshot returns the output lines, the partial one last, the current
+// step ("" before the first) and how many lines were dropped
+func (l *cleanLog) snapshot() (lines []string, step string, dropped int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lines = append([]string(nil), l.lines...)
+	if l.partial != "" {
+		lines = append(lines, l.partial)
+	}
+	if l.status != "" {
+		step = l.stepLabel()
+	}
+	return lines, step, l.dropped
+}
+
+// scrollCleanLog moves the output pane by delta lines, up when positive;
+// at 0 it follows the latest output
+func (m *Model) scrollCleanLog(delta int) {
+	if m.cleanLog == nil {
+		return
+	}
+	lines, _, _ := m.cleanLog.snapshot()
+	m.cleanLogScroll = max(0, min(m.cleanLogScroll+delta, len(lines)-cleanLogRows))
+}
+
+// viewCleanLog shows the latest output of the cleaning tools, or the part
+// scrolled to
+func (m Model) viewCleanLog() string {
+	if m.cleanLog == nil {
+		return ""
+	}
+	lines, _, dropped := m.cleanLog.snapshot()
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	if len(lines) == 0 {
+		return "\n" + muted.Render("The output of the cleaning tools will be shown here.") + "\n"
+	}
+
+	width := maxFormWidth
+	if m.width > 0 {
+		width = min(m.width-formChromeWidth, maxFormWidth+20)
+	}
+	end := len(lines) - min(m.cleanLogScroll, max(len(lines)-cleanLogRows, 0))
+	start := max(end-cleanLogRows, 0)
+
+	var sb strings.Builder
+	sb.WriteString("\n" + keyStyle.Render("Output:"))
+	if len(lines) > cleanLogRows {
+		sb.WriteString(muted.Render(fmt.Sprintf(" lines %d-%d of %d", dropped+start+1, dropped+end, dropped+len(lines))))
+	}
+	sb.WriteString("\n")
+	for _, line := range lines[start:end] {
+		// Colors of the tools would break the pane
+		line = strings.ReplaceAll(ansi.Strip(line), "\t", "    ")
+		sb.WriteString(muted.Render(truncateString(line, width)) + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
+)
+
+// mirrorPush is the push of a verified mirror clone, offered on the clean
+// results
+type mirrorPush struct {
+	mirror     string
+	remote     string            // Origin of the cleaned repository ("" for none)
+	refs       map[string]string // Branches and tags when cloned, leased by the push
+	creds      *cleaner.Credentials
+	confirming bool // Waiting for y/n
+	pushing    bool
+	pushed     bool
+	err        error
+}
+
+// mirrorPushedMsg ends the push of a mirror clone
+type mirrorPushedMsg struct {
+	err error
+}
+
+// newMirrorPush prepares the push of a clean's mirror, nil when there is
+// nothing to push: no mirror, values still in it (left by the rewrite or
+// found by the verification scan), or a verification scan that did not
+// complete. creds are those the mirror was cloned with (nil for none).
+func newMirrorPush(result *cleaner.CleanResult, remote string, creds *cleaner.Credentials) *mirrorPush {
+	if result == nil || !result.Success || result.Mirror == "" || len(result.Remaining) > 0 || len(result.Survivors) > 0 || result.VerifyError != "" {
+		return nil
+	}
+	return &mirrorPush{mirror: result.Mirror, remote: remote, refs: result.MirrorRefs, creds: creds}
+}
+
+// canPush reports whether the mirror can be pushed to the origin
+func (p *mirrorPush) canPush() bool {
+	return p != nil && p.remote != "" && !p.pushing && !p.pushed
+}
+
+// start runs the push
+func (p *mirrorPush) start(m *Model) tea.Cmd {
+	p.confirming, p.pushing, p.err = false, true, nil
+	mirror, remote, refs, creds := p.mirror, p.remote, p.refs, p.creds
+	return tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return mirrorPushedMsg{err: cleaner.PushMirror(mirror, remote, refs, creds)}
+	})
+}
+
+// updateMirrorPush handles the keys and messages of the push; handled is
+// false for the ones it leaves to the results screen
+func (m Model) updateMirrorPush(msg tea.Msg) (Model, tea.Cmd, bool) {
+	p := m.cleanPush
+	if p == nil {
+		return m, nil, false
+	}
+	switch msg := msg.(type) {
+	case mirrorPushedMsg:
+		p.pushing, p.err = false, msg.err
+		if msg.err == nil {
+			p.pushed = true
+			cleaner.RemoveMirror(p.mirror)
+		}
+		// The remote refused the push: ask for its credentials
+		if auth := authError(msg.err); auth != nil && !auth.SSH {
+			model, cmd := m.openCredentials(auth, true)
+			return model.(Model), cmd, true
+		}
+		return m, nil, true
+	case tea.KeyMsg:
+		switch {
+		case p.confirming && msg.String() == "y":
+			return m, p.start(&m), true
+		case p.confirming:
+			p.confirming = false
+			return m, nil, true
+		case msg.String() == "p" && p.canPush():
+			p.confirming = true
+			return m, nil, true
+		}
+	default:
+		if p.pushing {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd, true
+		}
+	}
+	return m, nil, false
+}
+
+// writeMirror tells where the mirror clone is, whether it passed the
+// verification and how far its push went
+func (m Model) writeMirror(sb *strings.Builder, result *cleaner.CleanResult) {
+	if result.Mirror == "" {
+		return
+	}
+	p := m.cleanPush
+	if p != nil && p.pushed {
+		sb.WriteString(fmt.Sprintf("%s pushed to %s and removed\n", keyStyle.Render("Mirror clone:"), p.remote))
+	} else {
+		sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Mirror clone:"), result.Mirror))
+	}
+
+	if len(result.Remaining) > 0 {
+		sb.WriteString("\n" + errorStyle.Render(fmt.Sprintf("✗ Verification failed: %d files still hold values or were not deleted", len(result.Remaining))) + "\n")
+		for i, f := range result.Remaining {
+			if i >= 10 {
+				sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(result.Remaining)-i))
+				break
+			}
+			sb.WriteString("  • " + f + "\n")
+		}
+		sb.WriteString("  The mirror is not offered for push: the repository is unchanged\n")
+		return
+	}
+	sb.WriteString(successStyle.Render("✓ Verified: no value or deleted file left in the mirror") + "\n")
+	if len(result.Survivors) > 0 {
+		sb.WriteString("  The mirror is not offered for push: the verification scan found values\n")
+		return
+	}
+	if result.VerifyError != "" {
+		sb.WriteString("  The mirror is not offered for push: verification incomplete\n")
+		return
+	}
+
+	switch {
+	case p == nil:
+	case p.pushing:
+		sb.WriteString("\n" + m.spinner.View() + " Pushing the branches and tags to " + p.remote + "...\n")
+	case p.confirming:
+		sb.WriteString("\n" + warningStyle.Render("Force-push the rewritten branches and tags to "+p.remote+"? (y/n)") + "\n")
+		sb.WriteString(fmt.Sprintf("  The %d branches and tags cloned from it are replaced by their rewrite;\n", len(p.refs)))
+		sb.WriteString("  any pushed to since the clone is refused, not overwritten\n")
+	case p.err != nil:
+		sb.WriteString("\n" + errorStyle.Render("Push failed: "+p.err.Error()) + "\n")
+		writeAuthHelp(sb, authError(p.err))
+	case p.remote == "":
+		sb.WriteString("  No origin remote: the mirror was cloned from this repository; push it with\n")
+		sb.WriteString("  " + cleaner.PushCommand(result.Mirror, "", result.MirrorRefs) + "\n")
+	}
+}
+
+// writeMirrorSteps lists what follows a mirror clean
+func (m Model) writeMirrorSteps(sb *strings.Builder, result *cleaner.CleanResult) {
+	if len(result.Remaining)+len(result.Survivors) > 0 {
+		sb.WriteString("  1. Check the files left: a value may span lines or be encoded\n")
+		sb.WriteString("  2. Clean again, with another tool or Anchor on Keys: No\n")
+		sb.WriteString("  3. Rotate all exposed credentials\n")
+		return
+	}
+	if result.VerifyError != "" {
+		sb.WriteString("  1. Scan the mirror: gitsecret scan --repo " + result.Mirror + "\n")
+		sb.WriteString("  2. Push it yourself once clean, each branch and tag leased on its state when cloned:\n")
+		sb.WriteString("     " + cleaner.PushCommand(result.Mirror, m.cleanRemote, result.MirrorRefs) + "\n")
+		sb.WriteString("  3. Rotate all exposed credentials\n")
+		return
+	}
+	sb.WriteString("  1. Push the mirror: p (force-pushes the branches and tags cloned from origin)\n")
+	sb.WriteString("  2. Re-clone this repository: it still holds the old history\n")
+	sb.WriteString("  3. Notify collaborators to re-clone\n")
+	sb.WriteString("  4. Rotate all exposed credentials\n")
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
+)
+
+// backupRestore is the restore of the backup a clean made, offered on the
+// clean results to undo it
+type backupRestore struct {
+	repoPath   string
+	backup     cleaner.Backup
+	confirming bool // Waiting for y/n
+	restoring  bool
+	restored   bool
+	err        error
+}
+
+// backupRestoredMsg ends the restore of a backup
+type backupRestoredMsg struct {
+	err error
+}
+
+// newBackupRestore prepares the restore of a clean's backup, nil when there
+// is nothing to undo: a dry run, a mirror clean or no backup
+func newBackupRestore(result *cleaner.CleanResult, repoPath string) *backupRestore {
+	if result == nil || result.DryRun || result.Mirror != "" || result.Backup().Empty() {
+		return nil
+	}
+	return &backupRestore{repoPath: repoPath, backup: result.Backup()}
+}
+
+// canRestore reports whether the backup can be restored
+func (r *backupRestore) canRestore() bool {
+	return r != nil && !r.restoring && !r.restored
+}
+
+// updateBackupRestore handles the keys and messages of the restore;
+// handled is false for the ones it leaves to the results screen
+func (m Model) updateBackupRestore(msg tea.Msg) (Model, tea.Cmd, bool) {
+	r := m.cleanRestore
+	if r == nil {
+		return m, nil, false
+	}
+	switch msg := msg.(type) {
+	case backupRestoredMsg:
+		r.restoring, r.err = false, msg.err
+		r.restored = msg.err == nil
+		return m, nil, true
+	case tea.KeyMsg:
+		switch {
+		case r.confirming && msg.String() == "y":
+			r.confirming, r.restoring, r.err = false, true, nil
+			repoPath, backup := r.repoPath, r.backup
+			return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+				return backupRestoredMsg{err: cleaner.Restore(repoPath, backup)}
+			}), true
+		case r.confirming:
+			r.confirming = false
+			return m, nil, true
+		case msg.String() == "u" && r.canRestore():
+			r.confirming = true
+			return m, nil, true
+		}
+	default:
+		if r.restoring {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd, true
+		}
+	}
+	return m, nil, false
+}
+
+// writeRestore tells how far the restore of the backup went
+func (m Model) writeRestore(sb *strings.Builder) {
+	r := m.cleanRestore
+	switch {
+	case r == nil:
+	case r.restoring:
+		sb.WriteString("\n\n" + m.spinner.View() + " Restoring the " + r.backup.String() + "...")
+	case r.confirming:
+		what := "the branch checked out"
+		if r.backup.Bundle != "" {
+			what = "every branch and tag"
+		}
+		sb.WriteString("\n\n" + warningStyle.Render("Reset "+what+" to the "+r.backup.String()+"? (y/n)"))
+		sb.WriteString("\n  Changes not committed in the working tree are lost")
+	case r.restored:
+		sb.WriteString("\n\n" + successStyle.Render("✓ Restored from the "+r.backup.String()+": the clean is undone"))
+	case r.err != nil:
+		sb.WriteString("\n\n" + errorStyle.Render("Restore failed: "+r.err.Error()))
+	}
+}
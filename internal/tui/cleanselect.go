@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
+)
+
+// cleanSelectHelp lists the keys changing the selection of the values to clean
+const cleanSelectHelp = "↑/↓: move • space: exclude/include • x: file/key • a: all • n: none"
+
+// cleanCandidatesMsg carries the values of the results file to select from
+type cleanCandidatesMsg struct {
+	candidates []cleaner.Candidate
+	err        error
+}
+
+// openCleanSelect loads the values the clean would remove, for the user to
+// pick the ones actually cleaned before the history is rewritten
+func (m Model) openCleanSelect() (tea.Model, tea.Cmd) {
+	m.view = ViewCleanSelect
+	m.cleanCandidates = nil
+	m.cleanSelectErr = nil
+	inputPath, repoPath := m.cleanPaths()
+	filter := m.cleanFilter()
+	onlyRotated := m.cleanRotated != nil && *m.cleanRotated
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		loadResult, secrets, _, err := loadCleanSecrets(inputPath, repoPath, filter, onlyRotated)
+		if err != nil {
+			return cleanCandidatesMsg{err: err}
+		}
+		return cleanCandidatesMsg{candidates: cleaner.Candidates(loadResult, secrets)}
+	})
+}
+
+// loadingCleanSelect reports whether the values to select are being loaded
+func (m Model) loadingCleanSelect() bool {
+	return m.view == ViewCleanSelect && m.cleanCandidates == nil && m.cleanSelectErr == nil
+}
+
+func (m Model) updateCleanSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case cleanCandidatesMsg:
+		m.cleanCandidates, m.cleanSelectErr = msg.candidates, msg.err
+		m.cleanCursor = 0
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "enter" {
+			if !m.cleanSelection() && !m.deletingFiles() {
+				return m, nil // Nothing to clean
+			}
+			return m.openCleanConfirm()
+		}
+		m.selectCandidate(msg.String())
+	default:
+		if m.loadingCleanSelect() {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewCleanSelect() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("🧹 " + tr.T("Select Secrets to Clean")))
+	sb.WriteString("\n\n")
+
+	switch {
+	case m.cleanSelectErr != nil:
+		sb.WriteString(errorStyle.Render("Error: " + m.cleanSelectErr.Error()))
+		sb.WriteString("\n\n" + helpStyle.Render("esc: back to menu"))
+		return errorBoxStyle.Render(sb.String())
+	case m.loadingCleanSelect():
+		sb.WriteString(m.spinner.View() + " Loading the secrets of the results file...")
+		return boxStyle.Render(sb.String())
+	case len(m.cleanCandidates) == 0:
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("No secrets to clean in the results file"))
+		help := "esc: back to menu"
+		if m.deletingFiles() {
+			sb.WriteString("\nOnly the files of Delete Files are removed.")
+			help = "enter: continue • " + help
+		}
+		sb.WriteString("\n\n" + helpStyle.Render(help))
+		return boxStyle.Render(sb.String())
+	}
+
+	sb.WriteString("Only the selected values are replaced; excluded values stay in the history.\n")
+	m.writeCleanCandidates(&sb)
+	if !m.cleanSelection() && !m.deletingFiles() {
+		sb.WriteString("\n" + warningStyle.Render("Select at least one value to clean."))
+	}
+
+	help := helpStyle.Render(cleanSelectHelp + " • enter: continue • esc: back to menu")
+	sb.WriteString("\n\n" + help)
+
+	return boxStyle.Render(sb.String())
+}
+
+// selectCandidate moves the cursor of the values to clean or changes their
+// selection
+func (m *Model) selectCandidate(key string) {
+	if len(m.cleanCandidates) == 0 {
+		return
+	}
+	if m.cleanExcluded == nil {
+		m.cleanExcluded = make(map[string]bool)
+	}
+	switch key {
+	case "up", "k":
+		if m.cleanCursor > 0 {
+			m.cleanCursor--
+		}
+	case "down", "j":
+		if m.cleanCursor < len(m.cleanCandidates)-1 {
+			m.cleanCursor++
+		}
+	case " ", "space":
+		value := m.cleanCandidates[m.cleanCursor].Value
+		if m.cleanExcluded[value] {
+			delete(m.cleanExcluded, value)
+		} else {
+			m.cleanExcluded[value] = true
+		}
+	case "x":
+		// Every value of the cursor's file/key: excluded unless all are
+		current := m.cleanCandidates[m.cleanCursor]
+		exclude := false
+		for _, c := range m.cleanCandidates {
+			if c.File == current.File && c.Key == current.Key && !m.cleanExcluded[c.Value] {
+				exclude = true
+			}
+		}
+		for _, c := range m.cleanCandidates {
+			if c.File != current.File || c.Key != current.Key {
+				continue
+			}
+			if exclude {
+				m.cleanExcluded[c.Value] = true
+			} else {
+				delete(m.cleanExcluded, c.Value)
+			}
+		}
+	case "a":
+		m.cleanExcluded = make(map[string]bool)
+	case "n":
+		for _, c := range m.cleanCandidates {
+			m.cleanExcluded[c.Value] = true
+		}
+	}
+}
+
+// deletingFiles reports whether the clean form lists files to delete
+func (m Model) deletingFiles() bool {
+	return m.cleanDelete != nil && len(cleaner.ParsePatterns(*m.cleanDelete)) > 0
+}
+
+// cleanSelection reports whether some values to clean are still selected
+func (m Model) cleanSelection() bool {
+	for _, c := range m.cleanCandidates {
+		if !m.cleanExcluded[c.Value] {
+			return true
+		}
+	}
+	return false
+}
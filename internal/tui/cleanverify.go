@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// verifyScan is the configuration of the scan run after a clean: the one
+// of the last scan, else the configuration selected
+type verifyScan struct {
+	configPath string
+	profile    string
+	groups     []string
+}
+
+func (m Model) verifyScan() verifyScan {
+	if m.lastScan != nil {
+		return verifyScan{configPath: m.lastScan.configPath, profile: m.lastScan.profile, groups: m.lastScan.groups}
+	}
+	return verifyScan{configPath: m.configPath}
+}
+
+// run rescans the rewritten history of a successful clean (the mirror
+// clone when there is one) and records the cleaned values it still finds
+func (v verifyScan) run(ctx context.Context, result *cleaner.CleanResult, repoPath string, cleaned []string, paths cleaner.PathFilter) {
+	if result.Mirror != "" {
+		repoPath = result.Mirror
+	}
+	result.Verified = true
+	cfg, err := config.Load(v.configPath)
+	if err != nil {
+		result.VerifyError = fmt.Sprintf("failed to load config: %v", err)
+		return
+	}
+	scan, err := scanner.New(cfg).Scan(repoPath, scanner.ScanOptions{
+		Branch:     "--all",
+		ConfigPath: v.configPath,
+		Profile:    v.profile,
+		Groups:     v.groups,
+		Interrupt:  ctx,
+	})
+	if errors.Is(err, scanner.ErrInterrupted) {
+		// A partial scan would pass values it did not reach
+		result.VerifyError = "interrupted, the history was partly scanned"
+		return
+	}
+	if err != nil {
+		result.VerifyError = err.Error()
+		return
+	}
+	result.Survivors = cleaner.Survivors(scan, cleaned, paths)
+}
+
+// writeVerification gives the verdict of the scan run after the clean
+func writeVerification(sb *strings.Builder, result *cleaner.CleanResult) {
+	if !result.Verified {
+		return
+	}
+	switch {
+	case len(result.Survivors) > 0:
+		sb.WriteString("\n" + errorStyle.Render(fmt.Sprintf("✗ Verification scan failed: %d values survived the clean", len(result.Survivors))) + "\n")
+		for i, s := range result.Survivors {
+			if i >= 10 {
+				sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(result.Survivors)-i))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("  • %s/%s %s (%d commits)\n", s.File, s.Key, maskedValueStyle.Render(s.MaskedValue), s.Commits))
+		}
+		sb.WriteString("  Values split across lines or encoded are not replaced: delete the files or edit them\n")
+	case result.VerifyError != "":
+		sb.WriteString("\n" + warningStyle.Render("⚠ Verification scan incomplete: "+result.VerifyError) + "\n")
+	default:
+		sb.WriteString("\n" + successStyle.Render("✓ Verification scan passed: no cleaned value left in the history") + "\n")
+	}
+}
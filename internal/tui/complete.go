@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/huh"
+)
+
+// maxPathSuggestions bounds the entries offered for a path (large directories)
+const maxPathSuggestions = 200
+
+// pathInput is an input that completes filesystem paths: directories, and
+// files with one of extensions (directories only without)
+func pathInput(value *string, extensions ...string) *huh.Input {
+	return huh.NewInput().
+		Value(value).
+		SuggestionsFunc(func() []string { return pathSuggestions(*value, extensions) }, value)
+}
+
+// withPathCompletion binds tab to completion in the path inputs of form,
+// enter moving to the next field. huh.NewForm gives every field the form
+// keymap, so this runs on the form built.
+func withPathCompletion(form *huh.Form, inputs ...*huh.Input) *huh.Form {
+	keymap := huh.NewDefaultKeyMap()
+	keymap.Input.AcceptSuggestion = key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "complete"))
+	keymap.Input.Next = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "next"))
+	for _, input := range inputs {
+		input.WithKeyMap(keymap)
+	}
+	return form
+}
+
+// pathSuggestions lists the completions of a partly typed path: the entries
+// of its directory starting with the last element, directories ending with
+// a separator so the next tab goes into them. Hidden entries are offered
+// once a dot is typed.
+func pathSuggestions(value string, extensions []string) []string {
+	sep := strings.LastIndexAny(value, `/`+string(filepath.Separator))
+	dir, partial := value[:sep+1], value[sep+1:]
+
+	readDir := expandHome(dir)
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+
+	var suggestions []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(partial)) ||
+			(strings.HasPrefix(name, ".") && !strings.HasPrefix(partial, ".")) {
+			continue
+		}
+		if isDir(filepath.Join(readDir, name), entry) {
+			suggestions = append(suggestions, dir+name+"/")
+		} else if hasExtension(name, extensions) {
+			suggestions = append(suggestions, dir+name)
+		}
+		if len(suggestions) == maxPathSuggestions {
+			break
+		}
+	}
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// isDir reports whether a directory entry is a directory, or a link to one
+func isDir(path string, entry os.DirEntry) bool {
+	if entry.Type()&os.ModeSymlink == 0 {
+		return entry.IsDir()
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// hasExtension reports whether a file name ends with one of extensions
+func hasExtension(name string, extensions []string) bool {
+	for _, ext := range extensions {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandHome replaces a leading ~/ with the home directory
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	expanded := filepath.Join(home, rest)
+	if rest == "" || strings.HasSuffix(rest, "/") {
+		expanded += "/" // Keep a directory completed as one
+	}
+	return expanded
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+
+	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
+)
+
+// credentialPrompt asks for the username and password of an HTTPS remote
+// that no credential helper answered for (ViewCredentials). Git never
+// prompts under the TUI: the clone or push is run again with them.
+type credentialPrompt struct {
+	auth     *cleaner.AuthError
+	push     bool // For the push of the mirror, else the clone of the clean
+	username *string
+	password *string
+}
+
+// authError returns the AuthError of a failed clone or push, nil for
+// other failures
+func authError(err error) *cleaner.AuthError {
+	var auth *cleaner.AuthError
+	if errors.As(err, &auth) {
+		return auth
+	}
+	return nil
+}
+
+// openCredentials asks for the credentials a clone (push false) or push of
+// the mirror needed. Over SSH only the agent can answer: the results screen
+// tells how to load the key instead.
+func (m Model) openCredentials(auth *cleaner.AuthError, push bool) (tea.Model, tea.Cmd) {
+	if auth == nil || auth.SSH {
+		m.view = ViewCleanResults
+		return m, nil
+	}
+	username, password := "", ""
+	m.credentials = &credentialPrompt{auth: auth, push: push, username: &username, password: &password}
+	m.form = m.fitForm(huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Credentials Needed").
+				Description(auth.Remote+"\n\n"+strings.Join(auth.Help(), "\n")),
+			huh.NewInput().
+				Title("Username").
+				Value(&username).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("enter the username of the remote")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Password or Token").
+				Description("Given to git for this operation only, never stored").
+				EchoMode(huh.EchoModePassword).
+				Value(&password),
+		),
+	).WithTheme(formTheme()))
+	m.view = ViewCredentials
+	return m, m.form.Init()
+}
+
+func (m Model) updateCredentials(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "esc" {
+		m.credentials = nil
+		m.view = ViewCleanResults
+		return m, nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		c := m.credentials
+		m.credentials = nil
+		creds := &cleaner.Credentials{Username: strings.TrimSpace(*c.username), Password: *c.password}
+		if c.push && m.cleanPush != nil {
+			m.view = ViewCleanResults
+			m.cleanPush.creds = creds
+			return m, m.cleanPush.start(&m)
+		}
+		// The clone failed before anything was changed: the clean starts over
+		m.cleanCreds = creds
+		m.view = ViewCleanProgress
+		return m, tea.Batch(m.spinner.Tick, m.startClean())
+	case huh.StateAborted:
+		m.credentials = nil
+		m.view = ViewCleanResults
+		return m, nil
+	}
+	return m, cmd
+}
+
+func (m Model) viewCredentials() string {
+	return boxStyle.Render(
+		titleStyle.Render("🔑 "+tr.T("Git Credentials")) + "\n\n" +
+			m.form.View(),
+	)
+}
+
+// canRetryClone reports whether the last clean failed on the clone of its
+// mirror for credentials the user can enter
+func (m Model) canRetryClone() bool {
+	result, ok := m.cleanResult.(*cleaner.CleanResult)
+	return ok && result != nil && result.Auth != nil && !result.Auth.SSH
+}
+
+// writeAuthHelp tells how to give git the credentials a clone or push of
+// the mirror needed
+func writeAuthHelp(sb *strings.Builder, auth *cleaner.AuthError) {
+	if auth == nil {
+		return
+	}
+	for _, line := range auth.Help() {
+		sb.WriteString("  " + line + "\n")
+	}
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/analyzer"
+	"github.com/Drilmo/git-secret-scanner/internal/plain"
+)
+
+// Rows of each dashboard chart: the largest types and authors, the latest
+// months
+const dashboardRows = 10
+
+// dashboardBar is one bar of a dashboard chart
+type dashboardBar struct {
+	label string
+	count int
+}
+
+// barEighths draws the fractions of a bar cell, in eighths
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if keyMsg.String() == "d" {
+			m.view = ViewAnalyzeResults
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewDashboard() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("📊 " + tr.T("Dashboard")))
+	sb.WriteString("\n\n")
+
+	result, ok := m.analyzeResult.(*analyzer.Analysis)
+	if !ok {
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render(tr.T("No analysis to chart")))
+		return boxStyle.Render(sb.String())
+	}
+
+	var types, authors, months []dashboardBar
+	for _, t := range result.Stats.TypeBreakdown {
+		types = append(types, dashboardBar{t.Type, t.Count})
+	}
+	for _, a := range result.Stats.TopAuthors {
+		authors = append(authors, dashboardBar{a.Author, a.Count})
+	}
+	for _, month := range analyzer.ValuesByMonth(result) {
+		months = append(months, dashboardBar{month.Month, month.Count})
+	}
+	if len(months) > dashboardRows {
+		months = months[len(months)-dashboardRows:]
+	}
+
+	sb.WriteString(m.renderChart(tr.T("Findings by type"), types, len(result.Stats.TypeBreakdown)))
+	sb.WriteString(m.renderChart(tr.T("Findings by author"), authors, len(result.Stats.TopAuthors)))
+	sb.WriteString(m.renderChart(tr.T("New values by month"), months, len(months)))
+
+	help := helpStyle.Render(tr.T("d/esc: back to the results"))
+	sb.WriteString("\n" + help)
+
+	return boxStyle.Render(sb.String())
+}
+
+// renderChart renders a titled horizontal bar chart of the first rows of
+// bars, total being the number of bars before the cut
+func (m Model) renderChart(title string, bars []dashboardBar, total int) string {
+	var sb strings.Builder
+	sb.WriteString(keyStyle.Render(title) + "\n")
+	if len(bars) == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  "+tr.T("No data")) + "\n\n")
+		return sb.String()
+	}
+	if len(bars) > dashboardRows {
+		bars = bars[:dashboardRows]
+	}
+
+	labelWidth, largest := 0, 1
+	for _, b := range bars {
+		labelWidth = max(labelWidth, len([]rune(b.label)))
+		largest = max(largest, b.count)
+	}
+	labelWidth = min(labelWidth, 24)
+	// The bars take what the box leaves of the terminal
+	width := 40
+	if m.width > 0 {
+		width = max(10, min(60, m.width-labelWidth-20))
+	}
+
+	for _, b := range bars {
+		sb.WriteString(fmt.Sprintf("  %-*s %s %d\n", labelWidth, truncateString(b.label, labelWidth),
+			lipgloss.NewStyle().Foreground(primaryColor).Render(drawBar(b.count, largest, width)), b.count))
+	}
+	if total > len(bars) {
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render(fmt.Sprintf("  ... %d/%d", len(bars), total)) + "\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// drawBar draws count on a scale where largest fills width cells, in
+// eighths of a cell (whole cells in plain mode)
+func drawBar(count, largest, width int) string {
+	eighths := count * width * 8 / max(largest, 1)
+	if count > 0 && eighths == 0 {
+		eighths = 1
+	}
+	if plain.Enabled() {
+		return strings.Repeat("█", (eighths+7)/8)
+	}
+	return strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+}
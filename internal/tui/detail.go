@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/analyzer"
+	"github.com/Drilmo/git-secret-scanner/internal/auditlog"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// detailViewChrome is the height of the detail view around its scrolled
+// content (title, status, help, borders)
+const detailViewChrome = 11
+
+// openSecretDetail shows the secret selected in the results table
+func (m Model) openSecretDetail() (tea.Model, tea.Cmd) {
+	cursor := m.resultsTable.Cursor()
+	start, _ := m.resultsPages.GetSliceBounds(len(m.resultsRows))
+	if cursor < 0 || start+cursor >= len(m.resultsRows) {
+		return m, nil
+	}
+	m.detail = m.resultsRows[start+cursor]
+	m.detailRevealed = false
+	m.detailErr = nil
+	m.detailView = viewport.New(0, 0)
+	m.view = ViewSecretDetail
+	m.refreshDetail()
+	return m, nil
+}
+
+// refreshDetail fits the detail viewport to the terminal and renders the
+// secret into it, masked or revealed
+func (m *Model) refreshDetail() {
+	width, height := maxFormWidth, 20
+	if m.width > 0 {
+		width, height = m.width-formChromeWidth, max(m.height-detailViewChrome, 5)
+	}
+	m.detailView.Width, m.detailView.Height = width, height
+	m.detailView.SetContent(m.renderSecretDetail(width))
+}
+
+func (m Model) updateSecretDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "v" {
+		if !m.detailRevealed {
+			// No entry, no value on screen
+			err := auditlog.Record(auditlog.Entry{
+				Action: auditlog.ActionRawValueShown,
+				Input:  m.scanOutputFile,
+				Output: "screen: " + m.detail.secret.File + "/" + m.detail.secret.Key,
+				Values: len(m.detail.secret.History),
+			})
+			if err != nil {
+				m.detailErr = fmt.Errorf("cannot write the audit log, values stay masked: %w", err)
+				return m, nil
+			}
+		}
+		m.detailRevealed = !m.detailRevealed
+		m.detailErr = nil
+		m.refreshDetail()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.detailView, cmd = m.detailView.Update(msg)
+	return m, cmd
+}
+
+// renderSecretDetail describes a secret and each of its values, oldest first
+func (m Model) renderSecretDetail(width int) string {
+	var sb strings.Builder
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	field := func(indent, label, value string) {
+		sb.WriteString(fmt.Sprintf("%s%s %s\n", indent, keyStyle.Render(label), value))
+	}
+	cfg := m.severityConfig()
+	locale, _ := analyzer.LookupLocale(cfg.Settings.Locale)
+	secret, severity := m.detail.secret, m.detail.severity
+
+	field("", "File:", secret.File)
+	field("", "Key:", secret.Key)
+	field("", "Type:", secret.Type)
+	field("", "Severity:", severityStyle(severity).Render(severityShape(severity)+" "+severity))
+	field("", "Changes:", fmt.Sprintf("%d (%d occurrences)", secret.ChangeCount, secret.TotalOccurrences))
+	field("", "Authors:", strings.Join(secret.Authors, ", "))
+
+	sb.WriteString("\n" + keyStyle.Render(fmt.Sprintf("Values (%d, oldest first):", len(secret.History))) + "\n")
+	for i, h := range secret.History {
+		value := h.MaskedValue
+		switch {
+		case m.detailRevealed && h.Value != "":
+			value = warningStyle.Render(h.Value)
+		case m.detailRevealed:
+			value += muted.Render(" (raw value not in the results file)")
+		}
+		switch h.Status {
+		case scanner.StatusReintroduced:
+			value += " " + errorStyle.Render("[reintroduced after a cleanup]")
+		case scanner.StatusRemovedInHistory:
+			value += " " + muted.Render("[removed in history]")
+		}
+		sb.WriteString(fmt.Sprintf("\n%d. %s\n", i+1, value))
+
+		field("   ", "First seen:", locale.Date(h.FirstSeen))
+		field("   ", "Last seen:", locale.Date(h.LastSeen))
+		field("   ", "Authors:", strings.Join(h.Authors, ", "))
+		commits := make([]string, len(h.Commits))
+		for j, commit := range h.Commits {
+			commits[j] = shortCommit(commit)
+		}
+		field("   ", fmt.Sprintf("Commits (%d):", len(commits)),
+			lipgloss.NewStyle().Width(max(width-17, 20)).Render(strings.Join(commits, " ")))
+		if h.Line > 0 {
+			field("   ", "Line:", fmt.Sprintf("%d", h.Line))
+		}
+		if m.triageStore != nil {
+			if decision, ok := m.triageStore.Get(secret.File, secret.Key, h.Value); ok {
+				field("   ", "Triage:", fmt.Sprintf("%s, by %s on %s", triageLabels[decision.Status], decision.DecidedBy, locale.Date(decision.Date.Format(time.RFC3339))))
+			}
+		}
+		if h.JWT != nil {
+			claims := fmt.Sprintf("%s, issued by %s", h.JWT.Algorithm, h.JWT.Issuer)
+			if h.JWT.Expired {
+				claims += ", " + muted.Render("expired")
+			}
+			field("   ", "JWT:", claims)
+		}
+		if h.Context != nil {
+			for j, line := range h.Context.Lines {
+				sb.WriteString(muted.Render(truncateString(fmt.Sprintf("   %5d │ %s", h.Context.Start+j, line), width)) + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// shortCommit abbreviates a commit hash the way git log --oneline does
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+func (m Model) viewSecretDetail() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("🔑 Secret Detail"))
+	sb.WriteString("\n\n")
+	sb.WriteString(m.detailView.View())
+	sb.WriteString("\n")
+
+	switch {
+	case m.detailErr != nil:
+		sb.WriteString(errorStyle.Render("Error: " + m.detailErr.Error()))
+	case m.detailRevealed:
+		sb.WriteString(warningStyle.Render("⚠ Raw values shown (recorded in " + auditlog.Path() + ")"))
+	default:
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render(fmt.Sprintf("%3.f%%", m.detailView.ScrollPercent()*100)))
+	}
+
+	toggle := "v: reveal values"
+	if m.detailRevealed {
+		toggle = "v: mask values"
+	}
+	sb.WriteString("\n\n" + helpStyle.Render("↑/↓: scroll • "+toggle+" • esc: back to results"))
+
+	return boxStyle.Render(sb.String())
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// feedSize is how many of the latest findings the scan progress lists
+const feedSize = 8
+
+// scanFeed collects the findings of the running scan (ScanOptions.OnFinding)
+// for the progress view. The scan adds them from its goroutine: the view
+// reads a copy, so none is dropped however fast they come.
+type scanFeed struct {
+	mu     sync.Mutex
+	seen   map[string]bool       // file|key|value already listed (by another walk)
+	recent []scanner.StreamEntry // Latest findings, oldest first
+	count  int
+}
+
+func newScanFeed() *scanFeed {
+	return &scanFeed{seen: make(map[string]bool)}
+}
+
+// add lists a finding unless another walk already reported its value. Its
+// Severity is the one to show (scanner.EntrySeverity).
+func (f *scanFeed) add(entry scanner.StreamEntry) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := entry.File + "|" + entry.Key + "|" + entry.Value
+	if f.seen[key] {
+		return
+	}
+	f.seen[key] = true
+	f.count++
+	// Values are not kept: the feed shows them masked
+	entry.Value = ""
+	f.recent = append(f.recent, entry)
+	if len(f.recent) > feedSize {
+		f.recent = f.recent[len(f.recent)-feedSize:]
+	}
+}
+
+// latest returns the latest findings, oldest first, and how many were found
+func (f *scanFeed) latest() ([]scanner.StreamEntry, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]scanner.StreamEntry(nil), f.recent...), f.count
+}
+
+// viewScanFeed lists the latest findings of the running scan, one line each
+func (m Model) viewScanFeed() string {
+	if m.scanFeed == nil {
+		return ""
+	}
+	recent, count := m.scanFeed.latest()
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	if count == 0 {
+		return "\n" + muted.Render("Findings will be listed here as they are found.") + "\n"
+	}
+
+	width := maxFormWidth
+	if m.width > 0 {
+		width = min(m.width-formChromeWidth, maxFormWidth+20)
+	}
+	var sb strings.Builder
+	sb.WriteString("\n" + keyStyle.Render("Latest findings:"))
+	if count > len(recent) {
+		sb.WriteString(muted.Render(fmt.Sprintf(" %d of %d", len(recent), count)))
+	}
+	sb.WriteString("\n")
+	for i := len(recent) - 1; i >= 0; i-- {
+		entry := recent[i]
+		severity := entry.Severity
+		location := entry.File + "/" + entry.Key
+		if entry.Line > 0 {
+			location += fmt.Sprintf(":%d", entry.Line)
+		}
+		detail := " " + entry.MaskedValue
+		switch entry.Commit {
+		case "":
+		case "current": // Working tree findings
+			detail += " • working tree"
+		default:
+			detail += " • " + shortCommit(entry.Commit) + " " + entry.Author
+		}
+		// Badge, location, then what the width leaves of the value and commit
+		room := max(width-lipgloss.Width(severityBadge(severity))-1, 20)
+		location = truncateString(location, room)
+		detail = truncateString(detail, max(room-len([]rune(location)), 1))
+		sb.WriteString(severityBadge(severity) + " " + severityStyle(severity).Render(location) + muted.Render(detail) + "\n")
+	}
+	return sb.String()
+}
@@ -1,9 +1,16 @@
 package tui
 
 import (
+	"fmt"
 	"os/exec"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/huh"
+
+	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/workspace"
 )
 
 func (m *Model) createScanForm() *huh.Form {
@@ -18,7 +25,7 @@ func (m *Model) createScanForm() *huh.Form {
 		m.scanMode = &mode
 	}
 	if m.scanBranch == nil {
-		branch := "--all"
+		branch := config.EnvDefault(config.BranchEnv, "--all")
 		m.scanBranch = &branch
 	}
 	if m.scanSource == nil {
@@ -26,9 +33,29 @@ func (m *Model) createScanForm() *huh.Form {
 		m.scanSource = &source
 	}
 	if m.scanOutputPath == nil {
-		outputPath := "secrets.json"
+		outputPath := config.EnvDefault(config.OutputEnv, "")
 		m.scanOutputPath = &outputPath
 	}
+	if m.scanIncremental == nil {
+		incremental := false
+		m.scanIncremental = &incremental
+	}
+	if m.scanRemoved == nil {
+		removed := false
+		m.scanRemoved = &removed
+	}
+	if m.scanSubmodules == nil {
+		submodules := false
+		m.scanSubmodules = &submodules
+	}
+	if m.scanProfile == nil {
+		profile := ""
+		m.scanProfile = &profile
+	}
+	if m.scanGroups == nil {
+		var groups, all []string
+		m.scanGroups, m.scanAllGroups = &groups, &all
+	}
 	// Use the selected config path
 	m.scanConfigPath = m.configPath
 
@@ -37,78 +64,200 @@ func (m *Model) createScanForm() *huh.Form {
 	confirm := false
 	m.scanConfirm = &confirm
 
-	return huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("Repository Path").
-				Description("Path to the git repository to scan").
-				Value(m.scanRepoPath),
+	repoInput := pathInput(m.scanRepoPath).
+		Title("Repository Path").
+		Description("Path to the git repository to scan (tab: complete, ctrl+o: browse)").
+		Validate(validateRepo)
+	outputInput := pathInput(m.scanOutputPath, ".json", ".jsonl").
+		Title("Output File").
+		Description("Where to save the results (empty: a new file in the repository's workspace)").
+		Validate(validateOptionalOutput)
 
-			huh.NewSelect[string]().
-				Title("Scan Mode").
-				Description("Choose the scanning method").
-				Options(
-					huh.NewOption("Full (all history, aggregated)", "full"),
-					huh.NewOption("Stream (large repos, writes to file)", "stream"),
-					huh.NewOption("Fast (quick check)", "fast"),
-				).
-				Value(m.scanMode),
+	// The profiles and keyword groups are those of the configuration the
+	// scan will use: the selected one, else the repository's
+	configPath, configChosen, repoPath, profile := m.configPath, m.configChosen, m.scanRepoPath, m.scanProfile
+	scanConfig := func() (*config.Config, error) {
+		path := configPath
+		if repoConfig := config.RepoConfigPath(expandHome(*repoPath)); !configChosen && repoConfig != "" {
+			path = repoConfig
+		}
+		return config.Load(path)
+	}
+	profileOptions := func() []huh.Option[string] {
+		options := []huh.Option[string]{huh.NewOption("None (whole configuration)", "")}
+		cfg, err := scanConfig()
+		if err != nil {
+			return options
+		}
+		for _, name := range cfg.ProfileNames() {
+			label := name
+			if description := cfg.Profiles[name].Description; description != "" {
+				label += " — " + description
+			}
+			options = append(options, huh.NewOption(label, name))
+		}
+		return options
+	}
+	// Every group of the profile starts selected; all of them selected
+	// scans with the whole profile
+	allGroups := m.scanAllGroups
+	groupOptions := func() []huh.Option[string] {
+		cfg, err := scanConfig()
+		if err == nil && *profile != "" {
+			cfg, err = cfg.WithProfile(*profile)
+		}
+		if err != nil {
+			*allGroups = nil
+			return nil
+		}
+		var options []huh.Option[string]
+		names := make([]string, 0, len(cfg.KeywordGroups()))
+		for _, group := range cfg.KeywordGroups() {
+			label := fmt.Sprintf("%s (%d keywords)", group.Name, len(group.Patterns))
+			options = append(options, huh.NewOption(label, group.Name).Selected(true))
+			names = append(names, group.Name)
+		}
+		*allGroups = names
+		return options
+	}
 
-			huh.NewSelect[string]().
-				Title("Source").
-				Description("What to scan").
-				Options(
-					huh.NewOption("Both (current files + git history)", "both"),
-					huh.NewOption("Current files (HEAD + untracked)", "current"),
-					huh.NewOption("Git history only", "history"),
-				).
-				Value(m.scanSource),
+	fields := append(m.recentRepoFields(repoInput),
+		repoInput,
 
-			huh.NewInput().
-				Title("Branch").
-				Description("Branch to scan (for git history)").
-				Value(m.scanBranch),
+		huh.NewSelect[string]().
+			Title("Scan Mode").
+			Description("Choose the scanning method").
+			Options(
+				huh.NewOption("Full (all history, aggregated)", "full"),
+				huh.NewOption("Stream (large repos, writes to file)", "stream"),
+				huh.NewOption("Fast (quick check)", "fast"),
+			).
+			Value(m.scanMode),
 
-			huh.NewInput().
-				Title("Output File").
-				Description("Where to save the results").
-				Value(m.scanOutputPath),
+		huh.NewSelect[string]().
+			Title("Source").
+			Description("What to scan").
+			Options(
+				huh.NewOption("Both (current files + git history)", "both"),
+				huh.NewOption("Current files (HEAD + untracked)", "current"),
+				huh.NewOption("Git history only", "history"),
+			).
+			Value(m.scanSource),
 
-			huh.NewConfirm().
-				Title("Start Scan?").
-				Affirmative("Start").
-				Negative("Cancel").
-				Value(m.scanConfirm),
-		),
-	).WithTheme(huh.ThemeDracula())
+		huh.NewSelect[string]().
+			Title("Profile").
+			Description("Profile of the configuration to apply (its profiles section)").
+			OptionsFunc(profileOptions, m.scanRepoPath).
+			Value(m.scanProfile),
+	)
+	// Under the signature policy the scan looks for every group of the
+	// signed configuration: none can be left out
+	if !config.SignedConfigRequired() {
+		fields = append(fields,
+			huh.NewMultiSelect[string]().
+				Title("Keyword Groups").
+				Description("Groups to look for in this scan (space: toggle, ctrl+a: all)").
+				OptionsFunc(groupOptions, []*string{m.scanRepoPath, m.scanProfile}).
+				Value(m.scanGroups).
+				Validate(validateGroups),
+		)
+	}
+	fields = append(fields,
+		huh.NewInput().
+			Title("Branch").
+			Description("Branch to scan, --remotes, a glob like origin/feature/*, or a range like v1.0..HEAD (for git history)").
+			Value(m.scanBranch).
+			Validate(validateBranch(m.scanRepoPath, m.scanSource)),
+
+		outputInput,
+
+		huh.NewConfirm().
+			Title("Incremental").
+			Description("Only scan commits added since the last scan to this output file").
+			Affirmative("Yes").
+			Negative("No").
+			Value(m.scanIncremental).
+			Validate(validateIncremental(m.scanBranch)),
+
+		huh.NewConfirm().
+			Title("Deleted Lines").
+			Description("Also report secrets found in deleted lines (removed-in-history)").
+			Affirmative("Yes").
+			Negative("No").
+			Value(m.scanRemoved),
+
+		huh.NewConfirm().
+			Title("Submodules").
+			Description("Also scan the history of initialized submodules (not with incremental scans)").
+			Affirmative("Yes").
+			Negative("No").
+			Value(m.scanSubmodules),
+
+		huh.NewConfirm().
+			Title("Start Scan?").
+			Affirmative("Start").
+			Negative("Cancel").
+			Value(m.scanConfirm),
+	)
+
+	return m.fitForm(withPathCompletion(huh.NewForm(
+		huh.NewGroup(fields...),
+	).WithTheme(formTheme()), repoInput, outputInput))
+}
+
+// latestResults returns the results the analyze and clean forms start
+// with: those of the last scan, else the latest scan in the workspace of
+// the current directory, else secrets.json
+func (m Model) latestResults() string {
+	if m.scanOutputFile != "" {
+		return m.scanOutputFile
+	}
+	if ws, err := workspace.Lookup("."); err == nil && ws.LatestScan() != "" {
+		return ws.LatestScan()
+	}
+	return "secrets.json"
 }
 
 func (m *Model) createAnalyzeForm() *huh.Form {
 	// Allocate pointers for values (shared across Model copies)
 	if m.analyzeInputPath == nil {
-		inputPath := "secrets.json"
+		inputPath := m.latestResults()
 		m.analyzeInputPath = &inputPath
 	}
 	if m.analyzeOutputPath == nil {
-		outputPath := "secrets_analysis.csv"
+		outputPath := ""
 		m.analyzeOutputPath = &outputPath
 	}
 	// Allocate pointer for confirm (shared across Model copies)
 	// Default to false (Cancel) - user must explicitly choose to start
 	confirm := false
 	m.analyzeConfirm = &confirm
+	if m.analyzeAnonymize == nil {
+		anonymize := false
+		m.analyzeAnonymize = &anonymize
+	}
+
+	resultsInput := pathInput(m.analyzeInputPath, ".json", ".jsonl").
+		Title("Input File").
+		Description("JSONL file from scan-stream or JSON from scan (tab: complete)").
+		Validate(validateResults)
+	outputInput := pathInput(m.analyzeOutputPath, ".csv", ".html").
+		Title("Report Output File").
+		Description("Where to save the report (.csv for spreadsheets, .html for a standalone report; empty: a .csv in the workspace)").
+		Validate(validateOptionalOutput)
 
-	return huh.NewForm(
+	return m.fitForm(withPathCompletion(huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Title("Input File").
-				Description("JSONL file from scan-stream or JSON from scan").
-				Value(m.analyzeInputPath),
+			resultsInput,
 
-			huh.NewInput().
-				Title("CSV Output File").
-				Description("Where to save the CSV report for statistics").
-				Value(m.analyzeOutputPath),
+			outputInput,
+
+			huh.NewConfirm().
+				Title("Anonymize?").
+				Description("Author pseudonyms, no values (for sharing outside)").
+				Affirmative("Yes").
+				Negative("No").
+				Value(m.analyzeAnonymize),
 
 			huh.NewConfirm().
 				Title("Start Analysis?").
@@ -116,13 +265,13 @@ func (m *Model) createAnalyzeForm() *huh.Form {
 				Negative("Cancel").
 				Value(m.analyzeConfirm),
 		),
-	).WithTheme(huh.ThemeDracula())
+	).WithTheme(formTheme()), resultsInput, outputInput))
 }
 
 func (m *Model) createCleanForm() *huh.Form {
 	// Allocate pointers for values (shared across Model copies)
 	if m.cleanInputPath == nil {
-		inputPath := "secrets.json"
+		inputPath := m.latestResults()
 		m.cleanInputPath = &inputPath
 	}
 	if m.cleanRepoPath == nil {
@@ -133,24 +282,57 @@ func (m *Model) createCleanForm() *huh.Form {
 		tool := "auto"
 		m.cleanTool = &tool
 	}
+	if m.cleanInclude == nil {
+		include, exclude := "", ""
+		m.cleanInclude, m.cleanExclude = &include, &exclude
+	}
+	if m.cleanReplace == nil {
+		replace := ""
+		m.cleanReplace = &replace
+	}
+	if m.cleanDelete == nil {
+		deleteFiles := ""
+		m.cleanDelete = &deleteFiles
+	}
+	if m.cleanTypes == nil {
+		types, files, minLength, severity := "", "", "", ""
+		m.cleanTypes, m.cleanFiles, m.cleanMinLength, m.cleanSeverity = &types, &files, &minLength, &severity
+	}
 	// Allocate pointers for confirm values (shared across Model copies)
 	// Default dryRun to true for safety, but confirm to false (Cancel)
 	dryRun := true
 	m.cleanDryRun = &dryRun
 	confirm := false
 	m.cleanConfirm = &confirm
+	rotated := false
+	m.cleanRotated = &rotated
+	anchored := false
+	m.cleanAnchored = &anchored
+	mirror := false
+	m.cleanMirror = &mirror
+	if m.cleanBundle == nil {
+		bundle := ""
+		m.cleanBundle = &bundle
+	}
 
-	return huh.NewForm(
+	resultsInput := pathInput(m.cleanInputPath, ".json", ".jsonl").
+		Title("Scan Results File").
+		Description("JSON or JSONL file with secrets to remove (tab: complete)").
+		Validate(validateResults)
+	repoInput := pathInput(m.cleanRepoPath).
+		Title("Repository Path").
+		Description("Path to the git repository to clean (tab: complete, ctrl+o: browse)").
+		Validate(validateRepo)
+	bundleInput := pathInput(m.cleanBundle, ".bundle").
+		Title("Backup Bundle").
+		Description("Git bundle of every branch and tag written before the rewrite,\ne.g. ~/backups/repo.bundle (empty: backup branch only)").
+		Validate(validateBundle)
+
+	return m.fitForm(withPathCompletion(huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Title("Scan Results File").
-				Description("JSON or JSONL file with secrets to remove").
-				Value(m.cleanInputPath),
+			resultsInput,
 
-			huh.NewInput().
-				Title("Repository Path").
-				Description("Path to the git repository to clean").
-				Value(m.cleanRepoPath),
+			repoInput,
 
 			huh.NewSelect[string]().
 				Title("History Tool").
@@ -159,31 +341,117 @@ func (m *Model) createCleanForm() *huh.Form {
 					huh.NewOption("Auto (best available)", "auto"),
 					huh.NewOption("git-filter-repo (recommended)", "filter-repo"),
 					huh.NewOption("BFG Repo Cleaner", "bfg"),
+					huh.NewOption("Built-in Go engine (no dependencies)", cleaner.ToolNative),
 					huh.NewOption("git-filter-branch (slow)", "filter-branch"),
 				).
 				Value(m.cleanTool),
 
+			huh.NewInput().
+				Title("Only Files").
+				Description("Globs of the files to rewrite, e.g. *.env, *.properties, *.yaml (empty: all)").
+				Value(m.cleanInclude),
+
+			huh.NewInput().
+				Title("Skip Files").
+				Description("Globs of the files never rewritten, e.g. *fixtures*, *.min.js").
+				Value(m.cleanExclude),
+
+			huh.NewInput().
+				Title("Delete Files").
+				Description("Globs of the files removed whole from the history, e.g. *.pem, id_rsa, *.sql.gz\n(names match in any directory)").
+				Value(m.cleanDelete),
+
+			huh.NewConfirm().
+				Title("Only Rotated Secrets?").
+				Description("Clean only values triaged as rotated in .gitsecret-baseline.json;\nvalues still in use or not reviewed are left in place").
+				Affirmative("Yes, rotated only").
+				Negative("No, all secrets").
+				Value(m.cleanRotated),
+
+			huh.NewConfirm().
+				Title("Anchor on Keys?").
+				Description("Replace values only after their keys (key = value) where the scan\nfound them, so short or common values elsewhere are kept").
+				Affirmative("Yes, key = value").
+				Negative("No, values everywhere").
+				Value(m.cleanAnchored),
+
+			huh.NewInput().
+				Title("Replacement Text").
+				Description("Written instead of the values (empty: ***REMOVED***), with ${KEY}, ${TYPE}\nand ${ENV:NAME}, e.g. ${ENV:TICKET}-${KEY}").
+				Value(m.cleanReplace).
+				Validate(cleaner.ValidateReplacement),
+
+			huh.NewConfirm().
+				Title("Clean a Mirror Clone?").
+				Description("Rewrite a temporary mirror clone, verify it, then offer to push it;\nthis repository is left as it is (not with git-filter-branch)").
+				Affirmative("Yes, mirror clone").
+				Negative("No, in place").
+				Value(m.cleanMirror),
+
+			bundleInput,
+
 			huh.NewConfirm().
 				Title("Dry Run?").
 				Description("Simulate without making changes").
 				Affirmative("Yes, dry run first").
 				Negative("No, clean directly").
 				Value(m.cleanDryRun),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Types").
+				Description("Finding types to clean, e.g. password, api_key (empty: all)").
+				Value(m.cleanTypes),
+
+			huh.NewInput().
+				Title("Found In").
+				Description("Globs of the files whose findings are cleaned, e.g. config/*, *.env (empty: all);\nthe values are still replaced wherever they appear").
+				Value(m.cleanFiles),
+
+			huh.NewInput().
+				Title("Minimum Length").
+				Description("Leave values shorter than this (empty: all)").
+				Value(m.cleanMinLength).
+				Validate(validateMinLength),
+
+			huh.NewSelect[string]().
+				Title("Minimum Severity").
+				Description("Leave findings below this severity").
+				Options(
+					huh.NewOption("All severities", ""),
+					huh.NewOption("Critical", config.SeverityCritical),
+					huh.NewOption("High and above", config.SeverityHigh),
+					huh.NewOption("Medium and above", config.SeverityMedium),
+				).
+				Value(m.cleanSeverity),
 
 			huh.NewConfirm().
 				Title("Proceed?").
 				Affirmative("Continue").
 				Negative("Cancel").
 				Value(m.cleanConfirm),
-		),
-	).WithTheme(huh.ThemeDracula())
+		).Title("Filter Findings").Description("Restrict the secrets loaded from the results file"),
+	).WithTheme(formTheme()), resultsInput, repoInput, bundleInput))
+}
+
+// cleanFilter reads the filter step of the clean form
+func (m Model) cleanFilter() cleaner.LoadFilter {
+	filter := cleaner.LoadFilter{Config: m.severityConfig()}
+	if m.cleanTypes == nil {
+		return filter
+	}
+	filter.Types = cleaner.ParsePatterns(*m.cleanTypes)
+	filter.Files.Include = cleaner.ParsePatterns(*m.cleanFiles)
+	filter.MinLength, _ = strconv.Atoi(strings.TrimSpace(*m.cleanMinLength))
+	filter.MinSeverity = *m.cleanSeverity
+	return filter
 }
 
 func (m *Model) createCleanConfirmForm() *huh.Form {
 	// Allocate pointer for confirm (shared across Model copies)
 	confirm := false // Default to false for safety
 	m.cleanConfirm = &confirm
-	return huh.NewForm(
+	return m.fitForm(huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
 				Title("⚠️  WARNING: This will rewrite git history!").
@@ -192,7 +460,7 @@ func (m *Model) createCleanConfirmForm() *huh.Form {
 				Negative("Cancel").
 				Value(m.cleanConfirm),
 		),
-	).WithTheme(huh.ThemeDracula())
+	).WithTheme(formTheme()))
 }
 
 // Helper functions
@@ -0,0 +1,324 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/httpclient"
+	"github.com/Drilmo/git-secret-scanner/internal/integrations"
+)
+
+// integrationTokensMsg reports where the token of each integration comes
+// from ("" if none), read from the keyring in the background (it may ask
+// to unlock it)
+type integrationTokensMsg struct {
+	sources   map[string]string
+	errs      map[string]error
+	noKeyring bool // Tokens can only come from the environment
+}
+
+// integrationStatusMsg reports the outcome of a test, save or removal
+type integrationStatusMsg struct {
+	name   string
+	status string
+	err    error
+	reload bool // The tokens changed: read them again
+}
+
+// openIntegrations shows the integrations settings screen
+func (m Model) openIntegrations() (tea.Model, tea.Cmd) {
+	settings, err := integrations.LoadSettings()
+	if err != nil {
+		settings = integrations.Settings{}
+	}
+	m.integrationSettings, m.integrationsErr = settings, err
+	if m.integrationStatus == nil {
+		m.integrationStatus = make(map[string]string)
+	}
+	m.view = ViewIntegrations
+	return m, loadIntegrationTokens
+}
+
+// loadIntegrationTokens reads the token source of every integration
+func loadIntegrationTokens() tea.Msg {
+	msg := integrationTokensMsg{sources: make(map[string]string), errs: make(map[string]error)}
+	keyring := integrations.SystemKeyring()
+	for _, i := range integrations.All {
+		_, source, err := i.Token(keyring)
+		switch {
+		case errors.Is(err, integrations.ErrNoKeyring):
+			msg.noKeyring = true
+		case err != nil && !errors.Is(err, integrations.ErrNotFound):
+			msg.errs[i.Name] = err
+		}
+		msg.sources[i.Name] = source
+	}
+	return msg
+}
+
+func (m Model) updateIntegrations(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case integrationTokensMsg:
+		m.integrationTokens = msg.sources
+		m.integrationNoKeyring = msg.noKeyring
+		for name, err := range msg.errs {
+			m.integrationStatus[name] = errorStyle.Render("✗ " + err.Error())
+		}
+		return m, nil
+
+	case integrationStatusMsg:
+		m.integrationTesting = ""
+		if msg.err != nil {
+			m.integrationStatus[msg.name] = errorStyle.Render("✗ " + msg.err.Error())
+		} else {
+			m.integrationStatus[msg.name] = msg.status
+		}
+		if msg.reload {
+			return m, loadIntegrationTokens
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		selected := integrations.All[m.integrationIndex]
+		switch msg.String() {
+		case "up", "k":
+			if m.integrationIndex > 0 {
+				m.integrationIndex--
+			}
+		case "down", "j":
+			if m.integrationIndex < len(integrations.All)-1 {
+				m.integrationIndex++
+			}
+		case "enter", "e":
+			m.view = ViewIntegrationEdit
+			m.form = m.createIntegrationForm(selected)
+			return m, m.form.Init()
+		case "t":
+			if m.integrationTesting != "" {
+				return m, nil
+			}
+			m.integrationTesting = selected.Name
+			return m, tea.Batch(m.spinner.Tick, m.testIntegration(selected))
+		case "d":
+			if m.integrationTokens[selected.Name] == integrations.SourceEnv {
+				m.integrationStatus[selected.Name] = warningStyle.Render(
+					fmt.Sprintf("⚠ The token comes from %s: unset it to remove it", selected.TokenEnv()))
+				return m, nil
+			}
+			return m, removeIntegrationToken(selected)
+		}
+
+	default:
+		if m.integrationTesting != "" {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+// testIntegration calls the API of an integration with its token, through
+// the network settings of the configuration (proxy, CA bundle)
+func (m Model) testIntegration(i integrations.Integration) tea.Cmd {
+	account := m.integrationSettings[i.Name]
+	network := m.severityConfig().Settings.Network
+	return func() tea.Msg {
+		status := integrationStatusMsg{name: i.Name}
+		token, _, err := i.Token(integrations.SystemKeyring())
+		if err != nil {
+			status.err = err
+			return status
+		}
+		client, err := httpclient.New(network)
+		if err != nil {
+			status.err = err
+			return status
+		}
+		result, err := i.Test(client, account, token)
+		if err != nil {
+			status.err = err
+			return status
+		}
+		switch {
+		case !result.ScopesKnown:
+			status.status = successStyle.Render("✓ Connected as "+result.User) +
+				warningStyle.Render(" (scopes not reported: check them)")
+		case len(result.Missing) > 0:
+			status.status = warningStyle.Render(fmt.Sprintf("⚠ Connected as %s, missing scopes: %s",
+				result.User, strings.Join(result.Missing, ", ")))
+		default:
+			status.status = successStyle.Render("✓ Connected as " + result.User + ", scopes OK")
+		}
+		return status
+	}
+}
+
+// removeIntegrationToken deletes the token of an integration from the keyring
+func removeIntegrationToken(i integrations.Integration) tea.Cmd {
+	return func() tea.Msg {
+		err := integrations.SystemKeyring().Delete(i.Name)
+		return integrationStatusMsg{name: i.Name, status: successStyle.Render("✓ Token removed"), err: err, reload: true}
+	}
+}
+
+func (m *Model) createIntegrationForm(i integrations.Integration) *huh.Form {
+	// Allocate pointers (shared across Model copies)
+	account := m.integrationSettings[i.Name]
+	url, user, token := account.URL, account.User, ""
+	m.integrationURL, m.integrationUser, m.integrationToken = &url, &user, &token
+
+	urlInput := huh.NewInput().
+		Title(i.Title + " URL").
+		Value(m.integrationURL)
+	if i.DefaultURL != "" {
+		urlInput.Description("API base, for self-hosted instances").Placeholder(i.DefaultURL)
+	} else {
+		urlInput.Description("Site of the instance, e.g. https://example.atlassian.net").
+			Validate(func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return fmt.Errorf("%s needs the URL of its instance", i.Title)
+				}
+				return nil
+			})
+	}
+	fields := []huh.Field{urlInput}
+	if i.NeedsUser {
+		fields = append(fields, huh.NewInput().
+			Title("Account Email").
+			Description("The token authenticates this account").
+			Value(m.integrationUser))
+	}
+	fields = append(fields, huh.NewInput().
+		Title("Token").
+		Description(fmt.Sprintf("Stored in the OS keyring (empty: keep the current one). Scopes: %s\nCreate it at %s",
+			strings.Join(i.Scopes, ", "), i.TokenHelp)).
+		EchoMode(huh.EchoModePassword).
+		Value(m.integrationToken))
+
+	return m.fitForm(huh.NewForm(huh.NewGroup(fields...)).WithTheme(formTheme()))
+}
+
+func (m Model) updateIntegrationEdit(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// A test started before the form was opened still reports to the list
+	switch msg.(type) {
+	case integrationTokensMsg, integrationStatusMsg:
+		return m.updateIntegrations(msg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "esc" {
+		m.view = ViewIntegrations
+		return m, nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	// The settings file keeps the URL and user, the keyring the token
+	i := integrations.All[m.integrationIndex]
+	m.integrationSettings[i.Name] = integrations.Account{
+		URL:  strings.TrimSpace(*m.integrationURL),
+		User: strings.TrimSpace(*m.integrationUser),
+	}
+	m.view = ViewIntegrations
+	if err := m.integrationSettings.Save(); err != nil {
+		m.integrationStatus[i.Name] = errorStyle.Render("✗ " + err.Error())
+		return m, nil
+	}
+	token := strings.TrimSpace(*m.integrationToken)
+	if token == "" {
+		m.integrationStatus[i.Name] = successStyle.Render("✓ Settings saved")
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		err := integrations.SystemKeyring().Set(i.Name, token)
+		if errors.Is(err, integrations.ErrNoKeyring) {
+			err = fmt.Errorf("%w: set %s instead", err, i.TokenEnv())
+		}
+		return integrationStatusMsg{name: i.Name, status: successStyle.Render("✓ Token stored in the keyring"), err: err, reload: true}
+	}
+}
+
+func (m Model) viewIntegrationEdit() string {
+	i := integrations.All[m.integrationIndex]
+	return boxStyle.Render(
+		titleStyle.Render("🔗 "+i.Title+" Integration") + "\n\n" +
+			m.form.View(),
+	)
+}
+
+func (m Model) viewIntegrations() string {
+	var sb strings.Builder
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	sb.WriteString(titleStyle.Render("🔗 Integrations"))
+	sb.WriteString("\n")
+	sb.WriteString(subtitleStyle.Render("Tokens for notifications and tickets, kept in the OS keyring"))
+	sb.WriteString("\n\n")
+	if m.integrationsErr != nil {
+		sb.WriteString(errorStyle.Render("Error: "+m.integrationsErr.Error()) + "\n\n")
+	}
+	if m.integrationNoKeyring {
+		sb.WriteString(warningStyle.Render("⚠ No OS keyring: tokens are read from GITSECRET_<NAME>_TOKEN") + "\n\n")
+	}
+
+	for idx, i := range integrations.All {
+		cursor := "  "
+		style := menuItemStyle
+		if idx == m.integrationIndex {
+			cursor = "▸ "
+			style = selectedMenuItemStyle
+		}
+		sb.WriteString(style.Render(cursor+i.Title) + "\n")
+
+		url := i.URL(m.integrationSettings[i.Name])
+		if url == "" {
+			url = warningStyle.Render("not set")
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s\n", keyStyle.Render("URL:"), url))
+		if i.NeedsUser {
+			user := m.integrationSettings[i.Name].User
+			if user == "" {
+				user = warningStyle.Render("not set")
+			}
+			sb.WriteString(fmt.Sprintf("    %s %s\n", keyStyle.Render("User:"), user))
+		}
+
+		var token string
+		switch source, loaded := m.integrationTokens[i.Name]; {
+		case !loaded:
+			token = muted.Render("…")
+		case source == integrations.SourceEnv:
+			token = successStyle.Render("set") + muted.Render(" (from "+i.TokenEnv()+")")
+		case source == integrations.SourceKeyring:
+			token = successStyle.Render("set") + muted.Render(" (keyring)")
+		default:
+			token = warningStyle.Render("not set")
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s\n", keyStyle.Render("Token:"), token))
+		sb.WriteString(fmt.Sprintf("    %s %s %s\n", keyStyle.Render("Scopes:"),
+			strings.Join(i.Scopes, ", "), muted.Render("("+i.Purpose+")")))
+
+		switch {
+		case m.integrationTesting == i.Name:
+			sb.WriteString("    " + m.spinner.View() + " Testing...\n")
+		case m.integrationStatus[i.Name] != "":
+			sb.WriteString("    " + m.integrationStatus[i.Name] + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	help := helpStyle.Render("↑/↓: navigate • enter: edit • t: test connection • d: remove token • esc: back")
+	sb.WriteString(help)
+
+	return boxStyle.Render(sb.String())
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fileOpenedMsg reports how opening a file outside the TUI went
+type fileOpenedMsg struct {
+	path string
+	err  error
+}
+
+// openFile opens path in $VISUAL or $EDITOR, the TUI suspended until the
+// editor exits, else in the default application of the OS. HTML reports
+// always go to the default application (a browser).
+func openFile(path string) tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if args := strings.Fields(editor); len(args) > 0 && !strings.HasSuffix(strings.ToLower(path), ".html") {
+		cmd := exec.Command(args[0], append(args[1:], path)...)
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return fileOpenedMsg{path: path, err: err}
+		})
+	}
+
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", path)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+		default:
+			cmd = exec.Command("xdg-open", path)
+		}
+		// The application outlives the command on most systems: only
+		// failing to start it is reported
+		if err := cmd.Start(); err != nil {
+			return fileOpenedMsg{path: path, err: fmt.Errorf("%w (or set $EDITOR)", err)}
+		}
+		go cmd.Wait()
+		return fileOpenedMsg{path: path}
+	}
+}
+
+// viewOpenErr renders the failure of the last file opened, if any
+func (m Model) viewOpenErr() string {
+	if m.openErr == nil {
+		return ""
+	}
+	return "\n" + errorStyle.Render("Cannot open the file: "+m.openErr.Error())
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// exitWait bounds how long quitting waits for an interrupted scan or clean
+// to stop its processes and save its partial output
+const exitWait = 30 * time.Second
+
+// operation is a scan or clean running in the background. The copies of
+// the Model share it, so Run can stop it when the TUI quits midway and
+// tell what was done.
+type operation struct {
+	kind     string // "Scan" or "Clean"
+	cancel   context.CancelFunc
+	done     chan struct{} // Closed once summary is set
+	summary  string        // What the operation did, in plain text
+	stopping bool          // Cancelled with esc, still saving what it did
+}
+
+// newOperation starts tracking an operation; ctx is cancelled to stop it
+func newOperation(kind string) (*operation, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &operation{kind: kind, cancel: cancel, done: make(chan struct{})}, ctx
+}
+
+// finish records what the operation did; it is then over
+func (op *operation) finish(summary string) {
+	op.summary = summary
+	op.cancel()
+	close(op.done)
+}
+
+// stop cancels the operation from the TUI (esc): it stops its processes and
+// ends as when interrupted, its result showing what it did
+func (op *operation) stop() {
+	op.stopping = true
+	op.cancel()
+}
+
+// interrupt stops the operation, waits for it to save what it has done and
+// writes its summary to w
+func (op *operation) interrupt(w io.Writer) {
+	op.cancel()
+	select {
+	case <-op.done:
+		fmt.Fprintln(w, op.summary)
+	case <-time.After(exitWait):
+		fmt.Fprintf(w, "%s still running after %s: its output may be incomplete\n", op.kind, exitWait)
+	}
+}
+
+// scanSummary tells what a scan did, for the exit summary
+func scanSummary(req scanRequest, msg scanDoneMsg) string {
+	interrupted := errors.Is(msg.err, scanner.ErrInterrupted)
+	switch {
+	case interrupted && msg.outputPath == "":
+		// Incremental scans save nothing of an interrupted run
+		return fmt.Sprintf("Scan of %s interrupted: the previous results were left unchanged", req.repoPath)
+	case msg.err != nil && !interrupted:
+		return fmt.Sprintf("Scan of %s failed: %v", req.repoPath, msg.err)
+	}
+
+	var found string
+	switch result := msg.result.(type) {
+	case *scanner.ScanResult:
+		found = fmt.Sprintf("%d secrets (%d values)", result.SecretsFound, result.TotalValues)
+	case map[string]interface{}:
+		found = fmt.Sprintf("%v entries", result["count"])
+	}
+	if !interrupted {
+		return fmt.Sprintf("Scan of %s completed: %s written to %s", req.repoPath, found, msg.outputPath)
+	}
+	return fmt.Sprintf("Scan of %s interrupted after %d commits and %d files: %s written to %s, marked as interrupted",
+		req.repoPath, msg.stats.Commits, msg.stats.Files, found, msg.outputPath)
+}
+
+// cleanSummary tells what a clean did, for the exit summary
+func cleanSummary(repoPath string, msg cleanDoneMsg) string {
+	if msg.err != nil {
+		return fmt.Sprintf("Clean of %s failed: %v", repoPath, msg.err)
+	}
+	if msg.result == nil {
+		return fmt.Sprintf("Clean of %s did not run", repoPath)
+	}
+	summary := fmt.Sprintf("Clean of %s: %s", repoPath, msg.result.Message)
+	if msg.result.BackupBranch != "" && !msg.result.Interrupted {
+		summary += fmt.Sprintf(" (backup branch %s)", msg.result.BackupBranch)
+	}
+	if msg.result.BackupBundle != "" && !msg.result.Interrupted {
+		summary += fmt.Sprintf(" (backup bundle %s)", msg.result.BackupBundle)
+	}
+	if n := len(msg.result.Survivors); n > 0 {
+		summary += fmt.Sprintf("; verification scan failed, %d values survived", n)
+	} else if msg.result.VerifyError != "" {
+		summary += "; verification scan incomplete: " + msg.result.VerifyError
+	}
+	return summary
+}
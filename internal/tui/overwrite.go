@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+
+	"github.com/Drilmo/git-secret-scanner/internal/workspace"
+)
+
+// Choices of the overwrite form
+const (
+	overwriteReplace = "overwrite"
+	overwriteStamped = "stamped"
+	overwriteCancel  = "cancel"
+)
+
+// scanTarget returns the file a scan will write: the output path with the
+// extension of its mode, as runScan gives it ("" for the workspace, and for
+// incremental scans, which update their file)
+func (m Model) scanTarget() string {
+	if m.scanOutputPath == nil || *m.scanOutputPath == "" ||
+		(m.scanIncremental != nil && *m.scanIncremental && (m.scanSource == nil || *m.scanSource != "current")) {
+		return ""
+	}
+	path := expandHome(*m.scanOutputPath)
+	if m.scanMode != nil && *m.scanMode == "stream" {
+		if strings.HasSuffix(path, ".json") {
+			return strings.TrimSuffix(path, ".json") + ".jsonl"
+		} else if !strings.HasSuffix(path, ".jsonl") {
+			return path + ".jsonl"
+		}
+		return path
+	}
+	if strings.HasSuffix(path, ".jsonl") {
+		return strings.TrimSuffix(path, ".jsonl") + ".json"
+	} else if !strings.HasSuffix(path, ".json") {
+		return path + ".json"
+	}
+	return path
+}
+
+// outputExists reports whether path names a file a scan or an analysis
+// would overwrite
+func outputExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// openOverwrite asks what to do with the existing output file of the scan
+// or analyze form (from): overwrite it, write a timestamped file beside it,
+// or go back to the form
+func (m Model) openOverwrite(from View, path string) (tea.Model, tea.Cmd) {
+	m.overwriteReturn = from
+	m.overwritePath = path
+	m.overwriteStamped = workspace.Stamped(path, time.Now())
+	choice := overwriteStamped
+	m.overwriteChoice = &choice
+	m.form = m.fitForm(huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("The output file already exists").
+				Description(path).
+				Options(
+					huh.NewOption("Write "+m.overwriteStamped+" instead", overwriteStamped),
+					huh.NewOption("Overwrite it", overwriteReplace),
+					huh.NewOption("Cancel (back to the form)", overwriteCancel),
+				).
+				Value(m.overwriteChoice),
+		),
+	).WithTheme(formTheme()))
+	m.view = ViewOverwrite
+	return m, m.form.Init()
+}
+
+func (m Model) updateOverwrite(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "esc" {
+		return m.leaveOverwrite()
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		switch *m.overwriteChoice {
+		case overwriteStamped:
+			if m.overwriteReturn == ViewAnalyze {
+				*m.analyzeOutputPath = m.overwriteStamped
+			} else {
+				*m.scanOutputPath = m.overwriteStamped
+			}
+		case overwriteCancel:
+			return m.leaveOverwrite()
+		}
+		if m.overwriteReturn == ViewAnalyze {
+			m.view = ViewAnalyzeProgress
+			return m, tea.Batch(m.spinner.Tick, m.startAnalyze())
+		}
+		m.view = ViewScanProgress
+		return m, tea.Batch(m.spinner.Tick, m.startScan())
+	case huh.StateAborted:
+		return m.leaveOverwrite()
+	}
+	return m, cmd
+}
+
+// leaveOverwrite goes back to the form, its values kept
+func (m Model) leaveOverwrite() (tea.Model, tea.Cmd) {
+	m.view = m.overwriteReturn
+	if m.view == ViewAnalyze {
+		m.form = m.createAnalyzeForm()
+	} else {
+		m.form = m.createScanForm()
+	}
+	return m, m.form.Init()
+}
+
+func (m Model) viewOverwrite() string {
+	return boxStyle.Render(
+		titleStyle.Render("⚠️  File Exists") + "\n\n" +
+			m.form.View(),
+	)
+}
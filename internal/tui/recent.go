@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// recentReposMax is how many scanned repositories the scan form offers
+const recentReposMax = 8
+
+// addRecentRepo puts a scanned repository first in the recent ones
+func (m *Model) addRecentRepo(repoPath string) {
+	repo := absPath(repoPath)
+	recent := slices.DeleteFunc(slices.Clone(m.recentRepos), func(r string) bool { return r == repo })
+	m.recentRepos = append([]string{repo}, recent...)
+	if len(m.recentRepos) > recentReposMax {
+		m.recentRepos = m.recentRepos[:recentReposMax]
+	}
+}
+
+// recentRepoAccessor holds the choice of the recent repositories select:
+// picking a repository fills the Repository Path field with it, "" leaves
+// what is typed there
+type recentRepoAccessor struct {
+	value string
+	path  *string
+	input *huh.Input
+}
+
+func (a *recentRepoAccessor) Get() string {
+	return a.value
+}
+
+func (a *recentRepoAccessor) Set(value string) {
+	a.value = value
+	if value != "" && value != *a.path {
+		*a.path = value
+		// The input only reads its value when given one
+		a.input.Value(a.path)
+	}
+}
+
+// recentRepoFields returns the select of the recently scanned repositories
+// that still exist, to put before the Repository Path field (none without
+// any)
+func (m Model) recentRepoFields(repoInput *huh.Input) []huh.Field {
+	home, _ := os.UserHomeDir()
+	var options []huh.Option[string]
+	for _, repo := range m.recentRepos {
+		if _, err := os.Stat(repo); err != nil {
+			continue
+		}
+		dir := filepath.Dir(repo)
+		if home != "" && (dir == home || strings.HasPrefix(dir, home+string(filepath.Separator))) {
+			dir = "~" + strings.TrimPrefix(dir, home)
+		}
+		options = append(options, huh.NewOption(filepath.Base(repo)+"  "+dir, repo))
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	options = append(options, huh.NewOption("Another repository (type its path below)", ""))
+
+	accessor := &recentRepoAccessor{path: m.scanRepoPath, input: repoInput}
+	if slices.Contains(m.recentRepos, absPath(*m.scanRepoPath)) {
+		accessor.value = absPath(*m.scanRepoPath)
+	}
+	return []huh.Field{
+		huh.NewSelect[string]().
+			Title("Recent Repositories").
+			Description("←/→: pick one to fill the path below").
+			Inline(true).
+			Accessor(accessor).
+			Options(options...),
+	}
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/plain"
+)
+
+// openRepoBrowse opens the directory browser of the Repository Path field
+// of the scan or clean form (from), in the directory of the path typed
+func (m Model) openRepoBrowse(from View, repoPath *string) (tea.Model, tea.Cmd) {
+	dir := "."
+	if repoPath != nil && *repoPath != "" {
+		dir = absPath(*repoPath)
+	}
+	// A path being typed starts from its nearest existing directory
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			dir, _ = os.Getwd()
+			break
+		}
+		dir = parent
+	}
+	m.browseDir = absPath(dir)
+	m.browseIndex = 0
+	m.loadRepoBrowseEntries()
+	m.repoBrowseReturn = from
+	m.view = ViewRepoBrowse
+	return m, nil
+}
+
+// loadRepoBrowseEntries lists the directories of browseDir, marking the
+// git repositories (a .git directory, or file for worktrees and submodules)
+func (m *Model) loadRepoBrowseEntries() {
+	m.browseEntries = []browserEntry{}
+
+	if m.browseDir != "/" {
+		m.browseEntries = append(m.browseEntries, browserEntry{
+			name:  "..",
+			isDir: true,
+			path:  filepath.Dir(m.browseDir),
+		})
+	}
+
+	entries, err := os.ReadDir(m.browseDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		path := filepath.Join(m.browseDir, e.Name())
+		if strings.HasPrefix(e.Name(), ".") || !isDir(path, e) {
+			continue
+		}
+		_, err := os.Stat(filepath.Join(path, ".git"))
+		m.browseEntries = append(m.browseEntries, browserEntry{
+			name:   e.Name(),
+			isDir:  true,
+			isRepo: err == nil,
+			path:   path,
+		})
+	}
+}
+
+// chooseRepo fills the Repository Path field with path and returns to the
+// form, rebuilt to show it
+func (m Model) chooseRepo(path string) (tea.Model, tea.Cmd) {
+	m.view = m.repoBrowseReturn
+	if m.view == ViewClean {
+		*m.cleanRepoPath = path
+		m.form = m.createCleanForm()
+	} else {
+		*m.scanRepoPath = path
+		m.form = m.createScanForm()
+	}
+	return m, m.form.Init()
+}
+
+func (m Model) updateRepoBrowse(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	var entry browserEntry
+	if m.browseIndex < len(m.browseEntries) {
+		entry = m.browseEntries[m.browseIndex]
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.browseIndex > 0 {
+			m.browseIndex--
+		}
+	case "down", "j":
+		if m.browseIndex < len(m.browseEntries)-1 {
+			m.browseIndex++
+		}
+	case "enter":
+		// A repository is picked, another directory opened
+		if entry.isRepo {
+			return m.chooseRepo(entry.path)
+		}
+		if entry.path != "" {
+			m.browseDir, m.browseIndex = entry.path, 0
+			m.loadRepoBrowseEntries()
+		}
+	case "right", "l":
+		// Into a repository too, for the nested ones
+		if entry.path != "" {
+			m.browseDir, m.browseIndex = entry.path, 0
+			m.loadRepoBrowseEntries()
+		}
+	case " ":
+		if entry.path != "" && entry.name != ".." {
+			return m.chooseRepo(entry.path)
+		}
+	case ".":
+		return m.chooseRepo(m.browseDir)
+	case "backspace", "left", "h":
+		if m.browseDir != "/" {
+			m.browseDir, m.browseIndex = filepath.Dir(m.browseDir), 0
+			m.loadRepoBrowseEntries()
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewRepoBrowse() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("📂 Choose Repository"))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("Path: "))
+	sb.WriteString(m.browseDir)
+	sb.WriteString("\n\n")
+
+	maxVisible := 15
+	startIdx := 0
+	if m.browseIndex >= maxVisible {
+		startIdx = m.browseIndex - maxVisible + 1
+	}
+
+	for i := startIdx; i < len(m.browseEntries) && i < startIdx+maxVisible; i++ {
+		entry := m.browseEntries[i]
+		cursor := "  "
+		style := menuItemStyle
+		if i == m.browseIndex {
+			cursor = "▸ "
+			style = selectedMenuItemStyle
+		}
+		line := fmt.Sprintf("%s%s %s", cursor, repoIcon(entry.isRepo), entry.name)
+		if entry.isRepo {
+			line += "  " + successStyle.Render("git")
+		}
+		sb.WriteString(style.Render(line) + "\n")
+	}
+
+	if len(m.browseEntries) == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  (no directories)") + "\n")
+	}
+
+	if len(m.browseEntries) > maxVisible {
+		sb.WriteString(fmt.Sprintf("\n  ... %d/%d items", m.browseIndex+1, len(m.browseEntries)))
+	}
+
+	help := helpStyle.Render("↑/↓: navigate • enter: pick repo/open • space: pick • .: this folder • →/←: in/up • esc: back")
+	sb.WriteString("\n\n" + help)
+
+	return boxStyle.Render(sb.String())
+}
+
+// repoIcon returns the icon of a directory of the repository browser
+func repoIcon(isRepo bool) string {
+	switch {
+	case plain.Enabled() && isRepo:
+		return "repo"
+	case plain.Enabled():
+		return "dir "
+	case isRepo:
+		return "📦"
+	}
+	return "📁"
+}
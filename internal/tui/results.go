@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/analyzer"
+)
+
+// Results browser messages
+type pagerOpenedMsg struct {
+	pager *analyzer.ResultPager
+	err   error
+}
+type pagerPageMsg struct {
+	page int
+	rows []analyzer.ScanSecret
+	err  error
+}
+
+// openResultsBrowser indexes a result file in the background and switches to the browser
+func (m Model) openResultsBrowser(path string) (tea.Model, tea.Cmd) {
+	m.browseReturn = m.view
+	m.view = ViewResultsBrowse
+	m.pager = nil
+	m.pagerPath = path
+	m.pagerPage = 0
+	m.pagerRows = nil
+	m.pagerLoading = true
+	m.err = nil
+
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		pager, err := analyzer.OpenResults(path)
+		return pagerOpenedMsg{pager: pager, err: err}
+	})
+}
+
+// pageSize returns how many rows fit on screen
+func (m Model) pageSize() int {
+	if m.height > 0 {
+		// Leave room for title, header, status and help lines
+		if size := (m.height - 14) / 2; size > 5 {
+			return size
+		}
+		return 5
+	}
+	return 10
+}
+
+// loadPage reads a page of the indexed file in the background
+func (m Model) loadPage(page int) tea.Cmd {
+	pager := m.pager
+	size := m.pageSize()
+	return func() tea.Msg {
+		rows, err := pager.Page(page, size)
+		return pagerPageMsg{page: page, rows: rows, err: err}
+	}
+}
+
+func (m Model) updateResultsBrowse(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case pagerOpenedMsg:
+		if msg.err != nil {
+			m.pagerLoading = false
+			m.err = msg.err
+			return m, nil
+		}
+		m.pager = msg.pager
+		return m, m.loadPage(0)
+
+	case pagerPageMsg:
+		m.pagerLoading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.pagerPage = msg.page
+		m.pagerRows = msg.rows
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.pager == nil || m.pagerLoading {
+			return m, nil
+		}
+		pages := m.pager.PageCount(m.pageSize())
+		target := m.pagerPage
+		switch msg.String() {
+		case "right", "l", "n", "pgdown", " ":
+			target++
+		case "left", "h", "p", "pgup":
+			target--
+		case "home", "g":
+			target = 0
+		case "end", "G":
+			target = pages - 1
+		case "q":
+			m.view = m.browseReturn
+			return m, nil
+		default:
+			m.toggleSeverityFilter(msg.String())
+			return m, nil
+		}
+		if target < 0 || target >= pages || target == m.pagerPage {
+			return m, nil
+		}
+		m.pagerLoading = true
+		return m, tea.Batch(m.spinner.Tick, m.loadPage(target))
+
+	default:
+		if m.pagerLoading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewResultsBrowse() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("📄 Browse Results"))
+	sb.WriteString("\n\n")
+	sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("File:"), m.pagerPath))
+
+	if m.err != nil {
+		sb.WriteString("\n" + errorStyle.Render("Error: "+m.err.Error()))
+		sb.WriteString("\n\n" + helpStyle.Render("esc: back to menu"))
+		return errorBoxStyle.Render(sb.String())
+	}
+
+	if m.pager == nil {
+		sb.WriteString("\n" + m.spinner.View() + " Indexing result file...\n")
+		return boxStyle.Render(sb.String())
+	}
+
+	if m.pager.Repository != "" {
+		sb.WriteString(fmt.Sprintf("%s %s (%s)\n", keyStyle.Render("Repository:"), m.pager.Repository, m.pager.Branch))
+	}
+	unit := "secrets"
+	if m.pager.IsJSONL {
+		unit = "entries"
+	}
+	pages := m.pager.PageCount(m.pageSize())
+	sb.WriteString(fmt.Sprintf("%s %d %s • page %d/%d\n\n", keyStyle.Render("Total:"), m.pager.Len(), unit, m.pagerPage+1, pages))
+
+	cfg := m.severityConfig()
+	for _, row := range m.pagerRows {
+		severity := findingSeverity(cfg, row.Severity, row.Type)
+		if m.severityHidden[severity] {
+			continue
+		}
+		values := make([]string, 0, len(row.History))
+		location := ""
+		for _, h := range row.History {
+			values = append(values, h.MaskedValue)
+			if h.Line > 0 && h.Offset > 0 {
+				location = fmt.Sprintf(" (line %d, offset %d)", h.Line, h.Offset) // History is oldest first
+			} else if h.Line > 0 {
+				location = fmt.Sprintf(" (line %d)", h.Line)
+			}
+		}
+		sb.WriteString(fmt.Sprintf("%s %s%s\n", severityBadge(severity), severityStyle(severity).Render(row.File+"/"+row.Key),
+			lipgloss.NewStyle().Foreground(mutedColor).Render(location)))
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render(
+			fmt.Sprintf("       %s • %d changes • %s", row.Type, row.ChangeCount, truncateString(strings.Join(values, ", "), 50))) + "\n")
+	}
+	if m.pager.Len() == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  (no results)") + "\n")
+	}
+
+	if m.pagerLoading {
+		sb.WriteString("\n" + m.spinner.View() + " Loading page...")
+	}
+
+	help := helpStyle.Render("←/→: page • g/G: first/last • 1-4: toggle severity • q: back • esc: menu")
+	sb.WriteString("\n" + help)
+
+	return boxStyle.Render(sb.String())
+}
+
+// truncateString shortens s to maxLen characters with an ellipsis
+func truncateString(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen-1]) + "…"
+}
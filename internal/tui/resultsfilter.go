@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// resultsFilterFields are the quick filters of the results search, as
+// field:value terms
+var resultsFilterFields = []string{"file", "key", "type", "author"}
+
+// resultsFilter is a parsed results search. Terms of the same field are
+// alternatives, the fields and free words must all match.
+type resultsFilter struct {
+	fields map[string][]string // Lowercased values by field
+	words  []string            // Lowercased free words: file, key, type or an author
+}
+
+// parseResultsFilter reads a search such as "type:password file:*.env prod".
+// An unknown field is searched as a free word.
+func parseResultsFilter(query string) resultsFilter {
+	f := resultsFilter{fields: make(map[string][]string)}
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		field, value, ok := strings.Cut(term, ":")
+		if ok && value != "" && isResultsFilterField(field) {
+			f.fields[field] = append(f.fields[field], value)
+			continue
+		}
+		f.words = append(f.words, term)
+	}
+	return f
+}
+
+func isResultsFilterField(field string) bool {
+	for _, known := range resultsFilterFields {
+		if field == known {
+			return true
+		}
+	}
+	return false
+}
+
+// empty reports whether the filter lets every secret through
+func (f resultsFilter) empty() bool {
+	return len(f.fields) == 0 && len(f.words) == 0
+}
+
+// match reports whether a secret passes the filter. File values with a
+// wildcard are globs ("*" also matches "/", as for clean paths), the others
+// and every other field match a part of the text, case-insensitively.
+func (f resultsFilter) match(s scanner.Secret) bool {
+	file, key, typ := strings.ToLower(s.File), strings.ToLower(s.Key), strings.ToLower(s.Type)
+	authors := strings.ToLower(strings.Join(s.Authors, "\n"))
+
+	for field, values := range f.fields {
+		matched := false
+		for _, value := range values {
+			switch field {
+			case "file":
+				if strings.ContainsAny(value, "*?[") {
+					matched = cleaner.PathFilter{Include: []string{value}}.Match(file)
+				} else {
+					matched = strings.Contains(file, value)
+				}
+			case "key":
+				matched = strings.Contains(key, value)
+			case "type":
+				matched = strings.Contains(typ, value)
+			case "author":
+				matched = strings.Contains(authors, value)
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, word := range f.words {
+		if !strings.Contains(file, word) && !strings.Contains(key, word) &&
+			!strings.Contains(typ, word) && !strings.Contains(authors, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// newResultsSearch creates the search input of the results table (/)
+func newResultsSearch() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "/ "
+	input.Placeholder = "words or file:GLOB key:TEXT type:NAME author:NAME"
+	input.CharLimit = 0
+	return input
+}
+
+// openResultsSearch focuses the search input, keeping the current search
+func (m Model) openResultsSearch() (Model, tea.Cmd) {
+	width := maxFormWidth
+	if m.width > 0 {
+		width = m.width - formChromeWidth
+	}
+	m.resultsSearch.Width = width - lipgloss.Width(m.resultsSearch.Prompt) - 1
+	m.resultsSearch.CursorEnd()
+	return m, m.resultsSearch.Focus()
+}
+
+// clearResultsSearch closes the search input and lists every secret again
+func (m *Model) clearResultsSearch() {
+	m.resultsSearch.Blur()
+	m.resultsSearch.SetValue("")
+	m.resultsFilter = resultsFilter{}
+	m.resultsPages.Page = 0
+	m.refreshResults()
+	m.resultsTable.GotoTop()
+}
+
+// updateResultsSearch edits the search, filtering the table as it is typed:
+// enter keeps the filter and returns to the table, ctrl+u clears the input
+func (m Model) updateResultsSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
+	query := m.resultsSearch.Value()
+	switch msg.String() {
+	case "enter", "down", "up":
+		m.resultsSearch.Blur()
+		return m, nil
+	case "ctrl+u":
+		m.resultsSearch.SetValue("")
+	}
+
+	var cmd tea.Cmd
+	m.resultsSearch, cmd = m.resultsSearch.Update(msg)
+	if m.resultsSearch.Value() != query {
+		m.resultsFilter = parseResultsFilter(m.resultsSearch.Value())
+		m.resultsPages.Page = 0
+		m.refreshResults()
+		m.resultsTable.GotoTop()
+	}
+	return m, cmd
+}
+
+// searchingResults reports whether a search is typed or applied, which esc
+// clears before leaving the results
+func (m Model) searchingResults() bool {
+	return m.resultsSearch.Focused() || m.resultsSearch.Value() != ""
+}
+
+// viewResultsSearch renders the search line: the input while it is typed,
+// the applied search and how many secrets it keeps otherwise
+func (m Model) viewResultsSearch(total int) string {
+	if m.resultsSearch.Focused() {
+		return m.resultsSearch.View() + "\n"
+	}
+	if m.resultsSearch.Value() == "" {
+		return ""
+	}
+	count := lipgloss.NewStyle().Foreground(mutedColor).Render(fmt.Sprintf(" (%d of %d)", len(m.resultsRows), total))
+	return keyStyle.Render("Search:") + " " + m.resultsSearch.Value() + count + "\n"
+}
@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// Columns of the scan results table, in the order s cycles through them
+const (
+	columnSeverity = iota
+	columnFile
+	columnKey
+	columnType
+	columnChanges
+	columnTriage
+)
+
+var resultColumnTitles = []string{"Severity", "File", "Key", "Type", "Changes", "Triage"}
+
+// resultsViewChrome is the height of the scan results view around the table
+// rows (summary, filters, header, page line, help, borders)
+const resultsViewChrome = 27
+
+// resultRow is a secret listed in the results table
+type resultRow struct {
+	secret   scanner.Secret
+	severity string
+	triage   string // Decision about its values (secretTriage)
+}
+
+// resultsTableStyles returns the styles of the results table in the colors
+// of the theme in use
+func resultsTableStyles() table.Styles {
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(mutedColor).
+		BorderBottom(true)
+	styles.Selected = styles.Selected.Foreground(primaryColor)
+	return styles
+}
+
+// newResultsTable creates the table of the scan results view. The view
+// turns the pages (resultsPages): the table only moves the cursor.
+func newResultsTable() table.Model {
+	return table.New(
+		table.WithFocused(true),
+		table.WithStyles(resultsTableStyles()),
+		table.WithKeyMap(table.KeyMap{
+			LineUp:     key.NewBinding(key.WithKeys("up", "k")),
+			LineDown:   key.NewBinding(key.WithKeys("down", "j")),
+			GotoTop:    key.NewBinding(key.WithKeys("home", "g")),
+			GotoBottom: key.NewBinding(key.WithKeys("end", "G")),
+		}),
+	)
+}
+
+// newResultsPages creates the paginator of the results table (←/→, pgup/pgdown)
+func newResultsPages() paginator.Model {
+	pages := paginator.New()
+	pages.Type = paginator.Arabic
+	pages.ArabicFormat = "Page %d/%d"
+	return pages
+}
+
+// resultsPageSize returns how many rows of the results table fit on screen
+func (m Model) resultsPageSize() int {
+	if m.height > 0 {
+		chrome := resultsViewChrome
+		if m.searchingResults() {
+			chrome++ // Search line
+		}
+		return max(m.height-chrome, 5)
+	}
+	return 10
+}
+
+// refreshResults lists the visible secrets of the last full scan in the
+// results table: filtered by the severity toggles and the search, sorted by
+// the selected column, cut to the current page
+func (m *Model) refreshResults() {
+	// In the colors of the configuration scanned with
+	m.resultsTable.SetStyles(resultsTableStyles())
+	m.resultsRows = nil
+	if result, ok := m.scanResult.(*scanner.ScanResult); ok {
+		cfg := m.severityConfig()
+		for _, secret := range result.Secrets {
+			severity := scanner.SecretSeverity(cfg, secret)
+			if !m.severityHidden[severity] && m.resultsFilter.match(secret) {
+				row := resultRow{secret: secret, severity: severity}
+				row.triage = m.secretTriage(row)
+				m.resultsRows = append(m.resultsRows, row)
+			}
+		}
+	}
+	// Stable: ties keep the scanner's order (most severe, then most changed)
+	slices.SortStableFunc(m.resultsRows, func(a, b resultRow) int {
+		order := compareResults(a, b, m.resultsSort)
+		if m.resultsSortDesc {
+			return -order
+		}
+		return order
+	})
+
+	m.resultsPages.PerPage = m.resultsPageSize()
+	m.resultsPages.SetTotalPages(len(m.resultsRows))
+	m.resultsPages.Page = min(m.resultsPages.Page, max(m.resultsPages.TotalPages-1, 0))
+
+	width := maxFormWidth
+	if m.width > 0 {
+		width = m.width - formChromeWidth
+	}
+	m.resultsTable.SetColumns(m.resultColumns(width))
+	m.resultsTable.SetWidth(width)
+
+	start, end := m.resultsPages.GetSliceBounds(len(m.resultsRows))
+	rows := make([]table.Row, 0, end-start)
+	for _, row := range m.resultsRows[start:end] {
+		rows = append(rows, table.Row{
+			severityShape(row.severity) + " " + row.severity,
+			row.secret.File,
+			row.secret.Key,
+			row.secret.Type,
+			strconv.Itoa(row.secret.ChangeCount),
+			row.triage,
+		})
+	}
+	m.resultsTable.SetRows(rows)
+	// Header and its border, then the rows of a full page
+	m.resultsTable.SetHeight(min(len(m.resultsRows), m.resultsPages.PerPage) + 2)
+}
+
+// compareResults orders two secrets by a column
+func compareResults(a, b resultRow, column int) int {
+	switch column {
+	case columnSeverity:
+		return cmp.Compare(config.SeverityRank(a.severity), config.SeverityRank(b.severity))
+	case columnFile:
+		return cmp.Compare(a.secret.File, b.secret.File)
+	case columnKey:
+		return cmp.Compare(a.secret.Key, b.secret.Key)
+	case columnType:
+		return cmp.Compare(a.secret.Type, b.secret.Type)
+	case columnTriage:
+		return cmp.Compare(a.triage, b.triage)
+	default:
+		return cmp.Compare(a.secret.ChangeCount, b.secret.ChangeCount)
+	}
+}
+
+// resultColumns sizes the columns to the width, file and key sharing what
+// the others leave; the sorted column is marked with its direction
+func (m Model) resultColumns(width int) []table.Column {
+	widths := []int{10, 0, 0, 14, 7, 10}
+	// Each cell is padded by one space on both sides
+	rest := max(width-widths[columnSeverity]-widths[columnType]-widths[columnChanges]-widths[columnTriage]-2*len(widths), 12)
+	widths[columnFile] = rest * 3 / 5
+	widths[columnKey] = rest - widths[columnFile]
+
+	columns := make([]table.Column, len(resultColumnTitles))
+	for i, title := range resultColumnTitles {
+		if i == m.resultsSort {
+			if m.resultsSortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		columns[i] = table.Column{Title: title, Width: widths[i]}
+	}
+	return columns
+}
+
+// updateResultsTable handles the keys of the results table: / searches,
+// s sorts by the next column, S reverses the order, ←/→ turn the pages, ↑/↓
+// move the cursor, crossing into the previous or next page, and f/a/t/u
+// record a decision about the selected secret
+func (m Model) updateResultsTable(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if status, ok := triageKeys[msg.String()]; ok {
+		return m.triageSelected(status), nil
+	}
+	switch msg.String() {
+	case "/":
+		return m.openResultsSearch()
+	case "s":
+		m.resultsSort = (m.resultsSort + 1) % len(resultColumnTitles)
+		// Most changed first, the other columns from the top
+		m.resultsSortDesc = m.resultsSort == columnChanges
+		m.resultsPages.Page = 0
+		m.refreshResults()
+		m.resultsTable.GotoTop()
+		return m, nil
+	case "S":
+		m.resultsSortDesc = !m.resultsSortDesc
+		m.resultsPages.Page = 0
+		m.refreshResults()
+		m.resultsTable.GotoTop()
+		return m, nil
+	case "down", "j":
+		if m.resultsTable.Cursor() == len(m.resultsTable.Rows())-1 && !m.resultsPages.OnLastPage() {
+			m.resultsPages.NextPage()
+			m.refreshResults()
+			m.resultsTable.GotoTop()
+			return m, nil
+		}
+	case "up", "k":
+		if m.resultsTable.Cursor() == 0 && m.resultsPages.Page > 0 {
+			m.resultsPages.PrevPage()
+			m.refreshResults()
+			m.resultsTable.GotoBottom()
+			return m, nil
+		}
+	}
+
+	page := m.resultsPages.Page
+	var cmd tea.Cmd
+	if m.resultsPages, cmd = m.resultsPages.Update(msg); m.resultsPages.Page != page {
+		m.refreshResults()
+		m.resultsTable.GotoTop()
+		return m, cmd
+	}
+	m.resultsTable, cmd = m.resultsTable.Update(msg)
+	return m, cmd
+}
+
+// viewResultsTable renders the results table and its page line
+func (m Model) viewResultsTable() string {
+	if len(m.resultsRows) == 0 {
+		empty := "  No secret at the selected severities"
+		if !m.resultsFilter.empty() {
+			empty = "  No secret matches the search"
+		}
+		return lipgloss.NewStyle().Foreground(mutedColor).Render(empty) + "\n"
+	}
+	status := m.resultsPages.View()
+	if cursor := m.resultsTable.Cursor(); cursor >= 0 {
+		start, _ := m.resultsPages.GetSliceBounds(len(m.resultsRows))
+		status += " • " + strconv.Itoa(start+cursor+1) + "/" + strconv.Itoa(len(m.resultsRows))
+	}
+	if m.triageMsg != "" {
+		status = helpStyle.Render(status+" • ") + m.triageMsg
+	} else {
+		status = helpStyle.Render(status)
+	}
+	return m.resultsTable.View() + "\n" + status + "\n"
+}
@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// resumeResults is what "Resume Last Session" reopens: the last scan and
+// analysis, by their files and options. The values stay in the results
+// files, never in the session state.
+type resumeResults struct {
+	Scan     *resumeScan     `json:"scan,omitempty"`
+	Analysis *resumeAnalysis `json:"analysis,omitempty"`
+	Analyzed bool            `json:"analyzed,omitempty"` // The analysis came last: resuming shows it
+	Saved    time.Time       `json:"saved"`
+}
+
+// resumeScan is the last scan: its results file and its options, so that
+// r still rescans after resuming
+type resumeScan struct {
+	Results     string            `json:"results"`          // File written
+	Output      string            `json:"output,omitempty"` // File asked ("": the workspace)
+	Repo        string            `json:"repo"`
+	Config      string            `json:"config,omitempty"`
+	ConfigAuto  bool              `json:"configAuto,omitempty"`
+	Profile     string            `json:"profile,omitempty"`
+	Groups      []string          `json:"groups,omitempty"`
+	Mode        string            `json:"mode"`
+	Source      string            `json:"source"`
+	Branch      string            `json:"branch"`
+	Range       string            `json:"range,omitempty"`
+	Incremental bool              `json:"incremental,omitempty"`
+	Submodules  bool              `json:"submodules,omitempty"`
+	Removed     bool              `json:"removed,omitempty"`
+	Count       int               `json:"count,omitempty"` // Entries of a stream scan
+	Stats       scanner.ScanStats `json:"stats"`
+}
+
+// resumeAnalysis is the last analysis, run again on its results file
+type resumeAnalysis struct {
+	Input      string `json:"input"`
+	Repo       string `json:"repo"`             // Baseline of results outside a workspace
+	Report     string `json:"report,omitempty"` // Report exported
+	Anonymized bool   `json:"anonymized,omitempty"`
+}
+
+// rememberScan records the scan whose results were just written
+func (m *Model) rememberScan(results string, result interface{}) {
+	if m.lastScan == nil || results == "" {
+		return
+	}
+	req := m.lastScan
+	scan := &resumeScan{
+		Results:     absPath(results),
+		Output:      absPath(req.outputPath),
+		Repo:        absPath(req.repoPath),
+		Config:      m.scanConfigPath,
+		ConfigAuto:  m.scanConfigAuto,
+		Profile:     req.profile,
+		Groups:      req.groups,
+		Mode:        req.mode,
+		Source:      req.source,
+		Branch:      req.branch,
+		Range:       req.revRange,
+		Incremental: req.incremental,
+		Submodules:  req.submodules,
+		Removed:     req.removed,
+		Stats:       m.scanStats,
+	}
+	if stream, ok := result.(map[string]interface{}); ok {
+		scan.Count, _ = stream["count"].(int)
+	}
+	m.results.Scan, m.results.Analyzed, m.results.Saved = scan, false, time.Now()
+}
+
+// rememberAnalysis records the analysis just shown
+func (m *Model) rememberAnalysis(analysis *resumeAnalysis) {
+	m.results.Analysis, m.results.Analyzed, m.results.Saved = analysis, true, time.Now()
+}
+
+// restoreResults keeps the results of the previous session that can still
+// be reopened (nil without any)
+func restoreResults(results *resumeResults) *resumeResults {
+	if results == nil {
+		return nil
+	}
+	restored := *results
+	if restored.Scan != nil && !fileExists(restored.Scan.Results) {
+		restored.Scan = nil
+	}
+	if restored.Analysis != nil && !fileExists(restored.Analysis.Input) {
+		restored.Analysis = nil
+	}
+	if restored.Scan == nil && restored.Analysis == nil {
+		return nil
+	}
+	return &restored
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// resumeMenuItem is the menu entry shown first when the previous session
+// left results to reopen
+func (m Model) resumeMenuItem() menuItem {
+	file := ""
+	if m.resume.Analyzed || m.resume.Scan == nil {
+		file = m.resume.Analysis.Input
+	} else {
+		file = m.resume.Scan.Results
+	}
+	return menuItem{
+		"Resume Last Session",
+		tr.Sprintf("Reopen %s from %s without rescanning", filepath.Base(file), m.resume.Saved.Local().Format("2006-01-02 15:04")),
+	}
+}
+
+// resumeSession reopens the scan results of the previous session, or its
+// analysis when it came last (analyzed again, the results file being read
+// anew)
+func (m Model) resumeSession() (tea.Model, tea.Cmd) {
+	r := m.resume
+	m.err = nil
+	if scan := r.Scan; scan != nil {
+		m.lastScan = &scanRequest{
+			repoPath:    scan.Repo,
+			outputPath:  scan.Output,
+			configPath:  scan.Config,
+			profile:     scan.Profile,
+			groups:      scan.Groups,
+			mode:        scan.Mode,
+			source:      scan.Source,
+			branch:      scan.Branch,
+			revRange:    scan.Range,
+			incremental: scan.Incremental,
+			submodules:  scan.Submodules,
+			removed:     scan.Removed,
+		}
+		m.scanConfigPath, m.scanConfigAuto = scan.Config, scan.ConfigAuto
+		m.scanCancelled = ""
+		m.scanStats = scan.Stats
+		if scan.Mode == "stream" {
+			m.scanResult = map[string]interface{}{"mode": "stream", "source": scan.Source, "count": scan.Count}
+		} else {
+			result, err := scanner.LoadResult(scan.Results)
+			m.scanResult, m.err = result, err
+		}
+		m.openScanResults(scan.Results)
+	}
+
+	if analysis := r.Analysis; analysis != nil && (r.Analyzed || r.Scan == nil) {
+		input := analysis.Input
+		m.analyzeInputPath = &input
+		m.err = nil
+		m.view = ViewAnalyzeProgress
+		return m, tea.Batch(m.spinner.Tick, m.reanalyze(*analysis))
+	}
+	return m, nil
+}
+
+// reanalyze runs a remembered analysis again, its report left as it is
+func (m Model) reanalyze(analysis resumeAnalysis) tea.Cmd {
+	cfg := m.severityConfig()
+	return func() tea.Msg {
+		result, err := analyzeResults(analysis.Input, analysis.Repo, cfg, analysis.Anonymized)
+		return analyzeDoneMsg{
+			result:      result,
+			err:         err,
+			csvPath:     analysis.Report,
+			csvExported: analysis.Report != "" && fileExists(analysis.Report),
+			resume:      &analysis,
+		}
+	}
+}
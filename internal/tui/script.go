@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Pace of a script: delay between steps (also lets forms settle) and
+// between checks while a scan, analysis or clean runs
+const (
+	scriptPace = 100 * time.Millisecond
+	scriptPoll = 100 * time.Millisecond
+)
+
+// scriptStep is a line of a --script file:
+//
+//	key NAME [N]   press a key (enter, esc, tab, down, space, ctrl+u, q...), N times
+//	type TEXT      type text into the focused field
+//	sleep 2s       pause, for recordings
+//	expect TEXT    stop with an error unless TEXT is on screen
+//
+// Each step waits for the running scan, analysis or clean to finish.
+type scriptStep struct {
+	line   int
+	action string
+	arg    string
+	key    tea.KeyMsg
+	repeat int
+	delay  time.Duration
+}
+
+// scriptMsg runs the next step of the script
+type scriptMsg struct{}
+
+// namedKeys are the key names a script can press, besides single characters
+// and ctrl+a to ctrl+z
+var namedKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"backspace": tea.KeyBackspace,
+	"delete":    tea.KeyDelete,
+	"space":     tea.KeySpace,
+}
+
+// loadScript reads a script of TUI steps ("-" reads standard input)
+func loadScript(path string) ([]scriptStep, error) {
+	if path == "-" {
+		return parseScript(os.Stdin, "stdin")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseScript(f, path)
+}
+
+// parseScript reads script steps; name locates errors
+func parseScript(r io.Reader, name string) ([]scriptStep, error) {
+	var steps []scriptStep
+	lines := bufio.NewScanner(r)
+	n := 0
+	for lines.Scan() {
+		n++
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		action, arg, _ := strings.Cut(line, " ")
+		step := scriptStep{line: n, action: action, arg: strings.TrimSpace(arg)}
+
+		switch action {
+		case "key":
+			keyName, count, _ := strings.Cut(step.arg, " ")
+			key, ok := parseKey(keyName)
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: unknown key %q", name, n, keyName)
+			}
+			step.key, step.repeat = key, 1
+			if count != "" {
+				repeat, err := strconv.Atoi(strings.TrimSpace(count))
+				if err != nil || repeat < 1 {
+					return nil, fmt.Errorf("%s:%d: invalid repeat count %q", name, n, count)
+				}
+				step.repeat = repeat
+			}
+		case "type":
+			// Keep the text as written, spaces included
+			step.arg = strings.TrimPrefix(strings.TrimLeft(lines.Text(), " \t"), "type ")
+			step.key = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(step.arg)}
+			step.repeat = 1
+		case "sleep":
+			delay, err := time.ParseDuration(step.arg)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", name, n, err)
+			}
+			step.delay = delay
+		case "expect":
+			if step.arg == "" {
+				return nil, fmt.Errorf("%s:%d: expect needs a text", name, n)
+			}
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown step %q (key, type, sleep or expect)", name, n, action)
+		}
+		steps = append(steps, step)
+	}
+	return steps, lines.Err()
+}
+
+// parseKey translates a key name of a script
+func parseKey(name string) (tea.KeyMsg, bool) {
+	if t, ok := namedKeys[name]; ok {
+		msg := tea.KeyMsg{Type: t}
+		if t == tea.KeySpace {
+			msg.Runes = []rune{' '}
+		}
+		return msg, true
+	}
+	if letter, ok := strings.CutPrefix(name, "ctrl+"); ok && len(letter) == 1 && letter[0] >= 'a' && letter[0] <= 'z' {
+		return tea.KeyMsg{Type: tea.KeyCtrlA + tea.KeyType(letter[0]-'a')}, true
+	}
+	if runes := []rune(name); len(runes) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: runes}, true
+	}
+	return tea.KeyMsg{}, false
+}
+
+// nextScriptStep schedules the next step of the script, if any
+func (m Model) nextScriptStep(delay time.Duration) tea.Cmd {
+	if len(m.script) == 0 {
+		return nil
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg { return scriptMsg{} })
+}
+
+// busy reports whether a task runs that the script must wait for
+func (m Model) busy() bool {
+	switch m.view {
+	case ViewScanProgress, ViewAnalyzeProgress, ViewCleanProgress:
+		return true
+	}
+	return m.pagerLoading || m.installing || m.loadingCleanSelect()
+}
+
+// runScriptStep runs the next step of the script, as if typed by the user
+func (m Model) runScriptStep() (tea.Model, tea.Cmd) {
+	if len(m.script) == 0 {
+		return m, nil
+	}
+	if m.busy() {
+		return m, m.nextScriptStep(scriptPoll)
+	}
+	step := m.script[0]
+	m.script = m.script[1:]
+
+	switch step.action {
+	case "sleep":
+		if len(m.script) == 0 {
+			return m, nil
+		}
+		return m, tea.Tick(step.delay, func(time.Time) tea.Msg { return scriptMsg{} })
+	case "expect":
+		if !strings.Contains(ansi.Strip(m.View()), step.arg) {
+			m.scriptErr = fmt.Errorf("script line %d: %q not on screen", step.line, step.arg)
+			return m, tea.Quit
+		}
+		return m, m.nextScriptStep(scriptPace)
+	}
+
+	var cmds []tea.Cmd
+	var model tea.Model = m
+	for i := 0; i < step.repeat; i++ {
+		var cmd tea.Cmd
+		model, cmd = model.Update(step.key)
+		cmds = append(cmds, cmd)
+	}
+	m = model.(Model)
+	return m, tea.Batch(append(cmds, m.nextScriptStep(scriptPace))...)
+}
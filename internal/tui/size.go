@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Smallest terminal the views are laid out for; below it a notice replaces them
+const (
+	minWidth  = 64
+	minHeight = 20
+)
+
+// Room the boxed views take around a form: border, padding, title and the
+// header lines of the scan form
+const (
+	formChromeWidth  = 6
+	formChromeHeight = 11
+	maxFormWidth     = 80
+)
+
+// cleanPanelHeight is the terminal height from which the clean form shows
+// its information panel
+const cleanPanelHeight = 60
+
+// menuFullHeight is the height of the menu with the logo and descriptions
+const menuFullHeight = 41
+
+// tooSmall reports whether the terminal is smaller than the views need
+// (false until its size is known)
+func (m Model) tooSmall() bool {
+	return m.width > 0 && (m.width < minWidth || m.height < minHeight)
+}
+
+// viewTooSmall asks to enlarge the terminal. Keys still reach the current
+// view, and it comes back as soon as the terminal is large enough.
+func (m Model) viewTooSmall() string {
+	msg := warningStyle.Render("Terminal too small") + "\n\n" +
+		fmt.Sprintf("Current size: %dx%d\n", m.width, m.height) +
+		fmt.Sprintf("Please enlarge to at least %dx%d", minWidth, minHeight) + "\n\n" +
+		helpStyle.Render("ctrl+c: quit")
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().Width(min(m.width, minWidth)).Align(lipgloss.Center).Render(msg))
+}
+
+// fitView cuts a view larger than the terminal, ending it with a line that
+// says so, rather than let the terminal clip it silently
+func (m Model) fitView(view string) string {
+	if m.height == 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	width := lipgloss.Width(view)
+	if len(lines) <= m.height && width <= m.width {
+		return view
+	}
+	notice := fmt.Sprintf("enlarge to at least %dx%d to see everything", max(width, m.width), max(len(lines), m.height))
+	if len(lines) >= m.height {
+		notice = fmt.Sprintf("… %d more lines: %s", len(lines)-m.height+1, notice)
+		lines = lines[:m.height-1]
+	}
+	return strings.Join(append(lines, warningStyle.Render(notice)), "\n")
+}
+
+// fitForm sizes a form to the terminal: at most maxFormWidth wide, and
+// scrolling when its fields are taller than the screen
+func (m Model) fitForm(form *huh.Form) *huh.Form {
+	if m.width == 0 || form == nil {
+		return form
+	}
+	form.Update(tea.WindowSizeMsg{
+		Width:  min(m.width-formChromeWidth, maxFormWidth),
+		Height: max(m.height-formChromeHeight, 1),
+	})
+	return form
+}
+
+// resize applies a new terminal size to the current view: the form is
+// refitted and the results browser reloads the page holding its first row
+func (m Model) resize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	oldSize := m.pageSize()
+	m.width = msg.Width
+	m.height = msg.Height
+	m.form = m.fitForm(m.form)
+	if m.view == ViewScanResults || m.view == ViewSecretDetail {
+		m.refreshResults()
+	}
+	if m.view == ViewSecretDetail {
+		m.refreshDetail()
+	}
+
+	if m.view == ViewResultsBrowse && m.pager != nil && !m.pagerLoading {
+		if page := m.pagerPage * oldSize / m.pageSize(); page != m.pagerPage || oldSize != m.pageSize() {
+			m.pagerLoading = true
+			return m, tea.Batch(m.spinner.Tick, m.loadPage(page))
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// sessionState is what the TUI remembers between sessions: saved on exit,
+// restored on startup (not when a script drives it, so runbooks play the
+// same way every time)
+type sessionState struct {
+	ConfigPath    string   `json:"configPath,omitempty"`    // Configuration selected ("" for none)
+	BuiltinConfig bool     `json:"builtinConfig,omitempty"` // The built-in defaults were selected
+	ScanRepo      string   `json:"scanRepo,omitempty"`
+	ScanMode      string   `json:"scanMode,omitempty"`
+	ScanOutput    string   `json:"scanOutput,omitempty"`
+	AnalyzeOutput string   `json:"analyzeOutput,omitempty"`
+	CleanRepo     string   `json:"cleanRepo,omitempty"`
+	CleanReplace  string   `json:"cleanReplace,omitempty"` // Replacement text of the clean form
+	RecentRepos   []string `json:"recentRepos,omitempty"`  // Scanned repositories, most recent first
+
+	Results *resumeResults `json:"results,omitempty"` // Last scan and analysis, for "Resume Last Session"
+}
+
+// statePath returns the file holding the session state
+func statePath() string {
+	return filepath.Join(config.UserConfigDir(), "state.json")
+}
+
+// loadState reads the state of the previous session. A missing or
+// unreadable file starts afresh: the state only saves typing.
+func loadState() sessionState {
+	var state sessionState
+	if data, err := os.ReadFile(statePath()); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// save writes the session state
+func (s sessionState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.UserConfigDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(), append(data, '\n'), 0600)
+}
+
+// restoreState fills the forms and the configuration selection with the
+// values of the previous session. GITSECRET_CONFIG and GITSECRET_OUTPUT
+// still come first, and a configuration that no longer loads is dropped.
+func (m *Model) restoreState(state sessionState) {
+	if os.Getenv(config.ConfigEnv) == "" {
+		switch {
+		case state.BuiltinConfig:
+			m.configPath, m.configChosen = "", true
+			m.currentConfig = config.DefaultConfig()
+		case state.ConfigPath != "":
+			if cfg, err := config.Load(state.ConfigPath); err == nil {
+				m.configPath, m.configChosen = state.ConfigPath, true
+				m.currentConfig = cfg
+			}
+		}
+	}
+
+	restore := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	m.scanRepoPath = restore(state.ScanRepo)
+	m.scanMode = restore(state.ScanMode)
+	m.scanOutputPath = restore(config.EnvDefault(config.OutputEnv, state.ScanOutput))
+	m.analyzeOutputPath = restore(state.AnalyzeOutput)
+	m.cleanRepoPath = restore(state.CleanRepo)
+	m.cleanReplace = restore(state.CleanReplace)
+	m.recentRepos = state.RecentRepos
+	if m.resume = restoreResults(state.Results); m.resume != nil {
+		m.results = *m.resume
+	}
+}
+
+// sessionState returns the state to save on exit: the values of the forms
+// opened during the session, the previous ones otherwise
+func (m Model) sessionState(previous sessionState) sessionState {
+	state := previous
+	if os.Getenv(config.ConfigEnv) == "" {
+		state.ConfigPath, state.BuiltinConfig = "", false
+		if m.configChosen {
+			state.ConfigPath, state.BuiltinConfig = m.configPath, m.configPath == ""
+		}
+	}
+
+	if m.scanMode != nil {
+		state.ScanMode = *m.scanMode
+	}
+	// Paths are saved absolute: the next session may start elsewhere
+	savePath := func(target *string, value *string) {
+		if value != nil {
+			*target = absPath(*value)
+		}
+	}
+	savePath(&state.ScanRepo, m.scanRepoPath)
+	if os.Getenv(config.OutputEnv) == "" {
+		savePath(&state.ScanOutput, m.scanOutputPath)
+	}
+	savePath(&state.AnalyzeOutput, m.analyzeOutputPath)
+	savePath(&state.CleanRepo, m.cleanRepoPath)
+	if m.cleanReplace != nil {
+		state.CleanReplace = *m.cleanReplace
+	}
+	state.RecentRepos = m.recentRepos
+	state.Results = nil
+	if m.results.Scan != nil || m.results.Analysis != nil {
+		results := m.results
+		state.Results = &results
+	}
+	return state
+}
+
+// absPath returns the absolute form of a path typed in a form ("" stays
+// empty: the workspace default)
+func absPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(expandHome(path))
+	if err != nil {
+		return path
+	}
+	return abs
+}
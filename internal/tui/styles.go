@@ -1,108 +1,372 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/plain"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tuiTheme holds the colors of a theme (settings.theme), which the styles
+// and the form theme are built from
+type tuiTheme struct {
+	primary, secondary, danger, warning, muted, text lipgloss.Color
+	severity                                         map[string]lipgloss.Color
+}
+
+// themes are the TUI themes by name
+var themes = map[string]tuiTheme{
+	config.ThemeDark: {
+		primary:   lipgloss.Color("#7C3AED"), // Purple
+		secondary: lipgloss.Color("#10B981"), // Green
+		danger:    lipgloss.Color("#EF4444"), // Red
+		warning:   lipgloss.Color("#F59E0B"), // Orange
+		muted:     lipgloss.Color("#6B7280"), // Gray
+		text:      lipgloss.Color("#F9FAFB"), // White
+		severity: map[string]lipgloss.Color{
+			config.SeverityCritical: lipgloss.Color("#EF4444"), // Red
+			config.SeverityHigh:     lipgloss.Color("#F97316"), // Orange
+			config.SeverityMedium:   lipgloss.Color("#EAB308"), // Yellow
+			config.SeverityLow:      lipgloss.Color("#9CA3AF"), // Gray
+		},
+	},
+	// Darker tones, readable on a white background
+	config.ThemeLight: {
+		primary:   lipgloss.Color("#6D28D9"), // Purple
+		secondary: lipgloss.Color("#047857"), // Green
+		danger:    lipgloss.Color("#B91C1C"), // Red
+		warning:   lipgloss.Color("#B45309"), // Orange
+		muted:     lipgloss.Color("#4B5563"), // Gray
+		text:      lipgloss.Color("#111827"), // Black
+		severity: map[string]lipgloss.Color{
+			config.SeverityCritical: lipgloss.Color("#B91C1C"), // Red
+			config.SeverityHigh:     lipgloss.Color("#C2410C"), // Orange
+			config.SeverityMedium:   lipgloss.Color("#A16207"), // Yellow
+			config.SeverityLow:      lipgloss.Color("#4B5563"), // Gray
+		},
+	},
+	// Bright ANSI colors the terminal renders at full strength, white
+	// instead of gray
+	config.ThemeHighContrast: {
+		primary:   lipgloss.Color("14"), // Bright cyan
+		secondary: lipgloss.Color("10"), // Bright green
+		danger:    lipgloss.Color("9"),  // Bright red
+		warning:   lipgloss.Color("11"), // Bright yellow
+		muted:     lipgloss.Color("15"), // White
+		text:      lipgloss.Color("15"),
+		severity: map[string]lipgloss.Color{
+			config.SeverityCritical: lipgloss.Color("9"),   // Bright red
+			config.SeverityHigh:     lipgloss.Color("208"), // Orange
+			config.SeverityMedium:   lipgloss.Color("11"),  // Bright yellow
+			config.SeverityLow:      lipgloss.Color("15"),  // White
+		},
+	},
+}
 
 var (
-	// Colors
-	primaryColor   = lipgloss.Color("#7C3AED") // Purple
-	secondaryColor = lipgloss.Color("#10B981") // Green
-	dangerColor    = lipgloss.Color("#EF4444") // Red
-	warningColor   = lipgloss.Color("#F59E0B") // Orange
-	mutedColor     = lipgloss.Color("#6B7280") // Gray
-	textColor      = lipgloss.Color("#F9FAFB") // White
+	// Colors of the theme in use (useTheme)
+	primaryColor   lipgloss.Color
+	secondaryColor lipgloss.Color
+	dangerColor    lipgloss.Color
+	warningColor   lipgloss.Color
+	mutedColor     lipgloss.Color
+	textColor      lipgloss.Color
+
+	// Color-blind safe severity colors (Okabe-Ito), decreasing in lightness
+	colorBlindSeverityColors = map[string]lipgloss.Color{
+		config.SeverityCritical: lipgloss.Color("#D55E00"), // Vermillion
+		config.SeverityHigh:     lipgloss.Color("#E69F00"), // Orange
+		config.SeverityMedium:   lipgloss.Color("#56B4E9"), // Sky blue
+		config.SeverityLow:      lipgloss.Color("#999999"), // Gray
+	}
+
+	// Health score colors (good, fair, poor) of the color-blind palette
+	colorBlindHealthColors = [3]lipgloss.Color{
+		lipgloss.Color("#0072B2"), // Blue
+		lipgloss.Color("#E69F00"), // Orange
+		lipgloss.Color("#D55E00"), // Vermillion
+	}
+
+	// Shapes marking severities next to their colors, so they are not told
+	// apart by hue alone
+	severityShapes = map[string]string{
+		config.SeverityCritical: "▲",
+		config.SeverityHigh:     "◆",
+		config.SeverityMedium:   "●",
+		config.SeverityLow:      "○",
+	}
+)
+
+// Styles of the theme in use, built by useTheme
+var (
+	titleStyle, subtitleStyle                lipgloss.Style
+	boxStyle, successBoxStyle, errorBoxStyle lipgloss.Style
+	menuItemStyle, selectedMenuItemStyle     lipgloss.Style
+	statLabelStyle, statValueStyle           lipgloss.Style
+	progressBarStyle                         lipgloss.Style
+	tableHeaderStyle, tableCellStyle         lipgloss.Style
+	keyStyle, valueStyle, maskedValueStyle   lipgloss.Style
+	successStyle, errorStyle, warningStyle   lipgloss.Style
+	helpStyle, logoStyle                     lipgloss.Style
+)
+
+// activeSeverityColors and activeHealthColors are the colors of the theme
+// and palette in use, appliedTheme what useTheme last applied and
+// draculaForms whether the forms keep the Dracula theme of the default look
+var (
+	activeSeverityColors map[string]lipgloss.Color
+	activeHealthColors   [3]lipgloss.Color
+	appliedTheme         string
+	draculaForms         bool
+)
+
+// useTheme switches to the theme, palette and colors of the settings
+// (settings.theme, settings.palette, settings.themeColors). Unknown theme
+// names and "" select the dark theme, unknown palettes the default one.
+func useTheme(settings config.Settings) {
+	applied := fmt.Sprintf("%s|%s|%+v", settings.Theme, settings.Palette, settings.ThemeColors)
+	if applied == appliedTheme {
+		return
+	}
+	appliedTheme = applied
+
+	theme, ok := themes[settings.Theme]
+	if !ok {
+		theme = themes[config.ThemeDark]
+	}
+	severity := maps.Clone(theme.severity)
+	health := [3]lipgloss.Color{theme.secondary, theme.warning, theme.danger}
+	if settings.Palette == config.PaletteColorBlind {
+		severity = maps.Clone(colorBlindSeverityColors)
+		health = colorBlindHealthColors
+	}
+
+	// Colors of the configuration come last, over the theme and palette
+	colors := settings.ThemeColors.Named()
+	for name, target := range map[string]*lipgloss.Color{
+		"primary": &theme.primary, "secondary": &theme.secondary, "danger": &theme.danger,
+		"warning": &theme.warning, "muted": &theme.muted, "text": &theme.text,
+	} {
+		if color, ok := colors[name]; ok {
+			*target = lipgloss.Color(color)
+		}
+	}
+	for _, level := range config.Severities {
+		if color, ok := colors[level]; ok {
+			severity[level] = lipgloss.Color(color)
+		}
+	}
 
+	primaryColor, secondaryColor, dangerColor = theme.primary, theme.secondary, theme.danger
+	warningColor, mutedColor, textColor = theme.warning, theme.muted, theme.text
+	activeSeverityColors, activeHealthColors = severity, health
+	draculaForms = (settings.Theme == "" || settings.Theme == config.ThemeDark) && len(colors) == 0
+	buildStyles()
+}
+
+// buildStyles builds the styles from the colors of the theme in use
+func buildStyles() {
 	// Title styles
 	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(primaryColor).
+		MarginBottom(1)
 
 	subtitleStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			MarginBottom(1)
+		Foreground(mutedColor).
+		MarginBottom(1)
 
 	// Box styles
 	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2)
+	if plain.Enabled() {
+		// Boxes drawn in ASCII would only be noise to a screen reader
+		boxStyle = lipgloss.NewStyle().Padding(1, 2)
+	}
 
-	successBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(secondaryColor).
-			Padding(1, 2)
+	successBoxStyle = boxStyle.BorderForeground(secondaryColor)
 
-	errorBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(dangerColor).
-			Padding(1, 2)
+	errorBoxStyle = boxStyle.BorderForeground(dangerColor)
 
 	// Menu styles
 	menuItemStyle = lipgloss.NewStyle().
-			PaddingLeft(2)
+		PaddingLeft(2)
 
 	selectedMenuItemStyle = lipgloss.NewStyle().
-				PaddingLeft(2).
-				Foreground(primaryColor).
-				Bold(true)
+		PaddingLeft(2).
+		Foreground(primaryColor).
+		Bold(true)
 
 	// Stats styles
 	statLabelStyle = lipgloss.NewStyle().
-			Foreground(mutedColor)
+		Foreground(mutedColor)
 
 	statValueStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(textColor)
+		Bold(true).
+		Foreground(textColor)
 
 	// Progress styles
 	progressBarStyle = lipgloss.NewStyle().
-				Foreground(primaryColor)
+		Foreground(primaryColor)
 
 	// Table styles
 	tableHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(primaryColor).
-				BorderStyle(lipgloss.NormalBorder()).
-				BorderBottom(true).
-				BorderForeground(mutedColor)
+		Bold(true).
+		Foreground(primaryColor).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true).
+		BorderForeground(mutedColor)
 
 	tableCellStyle = lipgloss.NewStyle().
-			Padding(0, 1)
+		Padding(0, 1)
 
 	// Key/value styles
 	keyStyle = lipgloss.NewStyle().
-			Foreground(primaryColor).
-			Bold(true)
+		Foreground(primaryColor).
+		Bold(true)
 
 	valueStyle = lipgloss.NewStyle().
-			Foreground(textColor)
+		Foreground(textColor)
 
 	maskedValueStyle = lipgloss.NewStyle().
-				Foreground(mutedColor)
+		Foreground(mutedColor)
 
 	// Status styles
 	successStyle = lipgloss.NewStyle().
-			Foreground(secondaryColor).
-			Bold(true)
+		Foreground(secondaryColor).
+		Bold(true)
 
 	errorStyle = lipgloss.NewStyle().
-			Foreground(dangerColor).
-			Bold(true)
+		Foreground(dangerColor).
+		Bold(true)
 
 	warningStyle = lipgloss.NewStyle().
-			Foreground(warningColor).
-			Bold(true)
+		Foreground(warningColor).
+		Bold(true)
 
 	// Help styles
 	helpStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			MarginTop(1)
+		Foreground(mutedColor).
+		MarginTop(1)
 
 	// Logo
 	logoStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor)
-)
+		Bold(true).
+		Foreground(primaryColor)
+}
+
+// formTheme returns the theme of the huh forms: Dracula for the default look,
+// else one built from the colors of the theme in use
+func formTheme() *huh.Theme {
+	if draculaForms && !plain.Enabled() {
+		return huh.ThemeDracula()
+	}
+	t := huh.ThemeBase()
+	t.Focused.Base = t.Focused.Base.BorderForeground(mutedColor)
+	t.Focused.Card = t.Focused.Base
+	t.Focused.Title = t.Focused.Title.Foreground(primaryColor).Bold(true)
+	t.Focused.NoteTitle = t.Focused.NoteTitle.Foreground(primaryColor).Bold(true).MarginBottom(1)
+	t.Focused.Directory = t.Focused.Directory.Foreground(primaryColor)
+	t.Focused.Description = t.Focused.Description.Foreground(mutedColor)
+	t.Focused.ErrorIndicator = t.Focused.ErrorIndicator.Foreground(dangerColor)
+	t.Focused.ErrorMessage = t.Focused.ErrorMessage.Foreground(dangerColor)
+	t.Focused.SelectSelector = t.Focused.SelectSelector.Foreground(primaryColor)
+	t.Focused.NextIndicator = t.Focused.NextIndicator.Foreground(primaryColor)
+	t.Focused.PrevIndicator = t.Focused.PrevIndicator.Foreground(primaryColor)
+	t.Focused.Option = t.Focused.Option.Foreground(textColor)
+	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(primaryColor)
+	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(secondaryColor)
+	t.Focused.SelectedPrefix = lipgloss.NewStyle().Foreground(secondaryColor).SetString("✓ ")
+	t.Focused.UnselectedPrefix = lipgloss.NewStyle().Foreground(mutedColor).SetString("• ")
+	t.Focused.UnselectedOption = t.Focused.UnselectedOption.Foreground(textColor)
+	// Buttons leave the background to the terminal: the focused one is
+	// reversed, readable whatever the theme
+	t.Focused.FocusedButton = t.Focused.FocusedButton.UnsetBackground().Foreground(primaryColor).Reverse(true).Bold(true)
+	t.Focused.Next = t.Focused.FocusedButton
+	t.Focused.BlurredButton = t.Focused.BlurredButton.UnsetBackground().Foreground(mutedColor)
+	t.Focused.TextInput.Cursor = t.Focused.TextInput.Cursor.Foreground(primaryColor)
+	t.Focused.TextInput.Placeholder = t.Focused.TextInput.Placeholder.Foreground(mutedColor)
+	t.Focused.TextInput.Prompt = t.Focused.TextInput.Prompt.Foreground(primaryColor)
+	t.Focused.TextInput.Text = t.Focused.TextInput.Text.Foreground(textColor)
+	if plain.Enabled() {
+		t.Focused.SelectedPrefix = lipgloss.NewStyle().SetString("[x] ")
+		t.Focused.UnselectedPrefix = lipgloss.NewStyle().SetString("[ ] ")
+	}
+
+	t.Blurred = t.Focused
+	t.Blurred.Base = t.Focused.Base.BorderStyle(lipgloss.HiddenBorder())
+	t.Blurred.Card = t.Blurred.Base
+	t.Blurred.NextIndicator = lipgloss.NewStyle()
+	t.Blurred.PrevIndicator = lipgloss.NewStyle()
+
+	t.Group.Title = t.Focused.Title
+	t.Group.Description = t.Focused.Description
+	return t
+}
+
+// browseIcon marks the entries of the file browsers, spelled out in plain
+// mode
+func browseIcon(isDir bool) string {
+	switch {
+	case plain.Enabled() && isDir:
+		return "dir "
+	case plain.Enabled():
+		return "file"
+	case isDir:
+		return "📁"
+	}
+	return "📄"
+}
+
+// severityStyle returns the text style for a severity level
+func severityStyle(severity string) lipgloss.Style {
+	color, ok := activeSeverityColors[severity]
+	if !ok {
+		color = mutedColor
+	}
+	return lipgloss.NewStyle().Foreground(color)
+}
+
+// severityShape returns the shape marking a severity level
+func severityShape(severity string) string {
+	if shape, ok := severityShapes[severity]; ok {
+		return shape
+	}
+	return "·"
+}
+
+// healthStyle colors a health score: green when good, red when poor
+func healthStyle(score int) lipgloss.Style {
+	color := activeHealthColors[2]
+	switch {
+	case score >= 75:
+		color = activeHealthColors[0]
+	case score >= 40:
+		color = activeHealthColors[1]
+	}
+	return lipgloss.NewStyle().Foreground(color).Bold(true)
+}
+
+// severityBadge renders a fixed-width colored badge with its shape, e.g.
+// "[▲ CRIT]"; the labels start with C, H, M and L
+func severityBadge(severity string) string {
+	label := map[string]string{
+		config.SeverityCritical: "CRIT",
+		config.SeverityHigh:     "HIGH",
+		config.SeverityMedium:   "MED ",
+		config.SeverityLow:      "LOW ",
+	}[severity]
+	if label == "" {
+		label = strings.ToUpper(severity)
+	}
+	return severityStyle(severity).Bold(true).Render("[" + severityShape(severity) + " " + label + "]")
+}
 
 // Logo ASCII art
 const logo = `
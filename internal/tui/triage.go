@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+)
+
+// triageKeys are the decisions the results table records ("" forgets them)
+var triageKeys = map[string]string{
+	"f": triage.StatusFalsePositive,
+	"a": triage.StatusAccepted,
+	"t": triage.StatusConfirmed,
+	"u": "",
+}
+
+// triageLabels names the statuses in the results table and detail
+var triageLabels = map[string]string{
+	triage.StatusConfirmed:     "to rotate",
+	triage.StatusRotated:       "rotated",
+	triage.StatusFalsePositive: "false pos.",
+	triage.StatusAccepted:      "accepted",
+}
+
+// loadTriage reads the baseline of the scanned repository, where the
+// results table records its decisions
+func (m *Model) loadTriage() {
+	m.triageStore, m.triageMsg = nil, ""
+	if m.lastScan == nil {
+		return
+	}
+	store, err := triage.Load(filepath.Join(m.lastScan.repoPath, config.BaselineFile))
+	if err != nil {
+		m.triageMsg = errorStyle.Render("✗ " + err.Error())
+		return
+	}
+	m.triageStore = store
+}
+
+// secretTriage labels the decisions about the values of a secret: the
+// status they share, "mixed" when they differ, "" when none is reviewed
+func (m Model) secretTriage(row resultRow) string {
+	if m.triageStore == nil {
+		return ""
+	}
+	label := ""
+	for i, h := range row.secret.History {
+		status := triageLabels[m.triageStore.Status(row.secret.File, row.secret.Key, h.Value)]
+		if i > 0 && status != label {
+			return "mixed"
+		}
+		label = status
+	}
+	return label
+}
+
+// triageSelected records a decision for every value of the selected secret
+// in the baseline, which reports and cleanups then follow
+func (m Model) triageSelected(status string) Model {
+	if m.triageStore == nil {
+		return m
+	}
+	cursor := m.resultsTable.Cursor()
+	start, _ := m.resultsPages.GetSliceBounds(len(m.resultsRows))
+	if cursor < 0 || start+cursor >= len(m.resultsRows) {
+		return m
+	}
+	secret := m.resultsRows[start+cursor].secret
+
+	marked := 0
+	for _, h := range secret.History {
+		// Decisions are keyed by the value: none without it
+		if h.Value == "" {
+			continue
+		}
+		if status == "" {
+			m.triageStore.Remove(secret.File, secret.Key, h.Value)
+		} else {
+			m.triageStore.Set(secret.File, secret.Key, h.Value, status, "")
+		}
+		marked++
+	}
+	switch {
+	case marked == 0:
+		m.triageMsg = warningStyle.Render("⚠ No raw value in the results file: nothing to record")
+		return m
+	case status == "":
+		m.triageMsg = successStyle.Render(fmt.Sprintf("✓ %s/%s: decision cleared", secret.File, secret.Key))
+	default:
+		values := "values"
+		if marked == 1 {
+			values = "value"
+		}
+		m.triageMsg = successStyle.Render(fmt.Sprintf("✓ %s/%s: %s (%d %s)", secret.File, secret.Key, triageLabels[status], marked, values))
+	}
+	if err := m.triageStore.Save(); err != nil {
+		m.triageMsg = errorStyle.Render("✗ " + err.Error())
+	}
+
+	// The rows keep their place: the label only changes
+	cursor = m.resultsTable.Cursor()
+	m.refreshResults()
+	m.resultsTable.SetCursor(cursor)
+	return m
+}
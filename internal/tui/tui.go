@@ -8,11 +8,23 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/Drilmo/git-secret-scanner/internal/analyzer"
+	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
 	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/i18n"
+	"github.com/Drilmo/git-secret-scanner/internal/integrations"
+	"github.com/Drilmo/git-secret-scanner/internal/plain"
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+	"github.com/charmbracelet/bubbles/paginator"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 // View represents different screens
@@ -40,6 +52,16 @@ const (
 	ViewScanConfigSelect  // Config select accessed from Scan form
 	ViewScanConfigBrowse  // Config browse accessed from Scan form
 	ViewAnalyzeProgress   // Analyze progress screen
+	ViewResultsBrowse     // Paged browser for result files
+	ViewConfigPlayground  // Sample line matched against the patterns
+	ViewIntegrations      // GitHub, GitLab and Jira accounts
+	ViewIntegrationEdit   // Form of an integration's URL, user and token
+	ViewSecretDetail      // Values, commits and authors of a scan result
+	ViewRepoBrowse        // Directory browser of the Repository Path fields
+	ViewOverwrite         // Choice for an output file that already exists
+	ViewDashboard         // Charts of the analysis results
+	ViewCleanSelect       // Values picked for the clean before the rewrite
+	ViewCredentials       // Username and password of a remote no credential helper answered for
 )
 
 // Model represents the application state
@@ -58,46 +80,152 @@ type Model struct {
 	scanMode         *string
 	scanSource       *string // current, history, both
 	scanOutputPath   *string
+	scanIncremental  *bool // Only scan commits added since the last scan
+	scanSubmodules   *bool // Also scan the history of submodules
+	scanProfile      *string // Profile of the configuration ("" for none)
+	scanGroups       *[]string // Keyword groups selected in the scan form
+	scanAllGroups    *[]string // Keyword groups offered, all selected by default
+	scanRemoved      *bool // Also match deleted lines
+	recentRepos      []string // Scanned repositories, most recent first (scan form select)
 	scanConfigPath   string
+	scanConfigAuto   bool // scanConfigPath was found in the scanned repository
 	scanConfigAction string
 	scanConfirm      *bool
-	scanProgress     int
-	scanTotal        int
-	scanFound        int
+	scanProgress     scanner.Progress
+	scanStats        scanner.ScanStats // Data processed by the last scan
+	scanMsgs         chan tea.Msg // Progress/done messages from the running scan
+	scanFeed         *scanFeed    // Findings of the running scan, as found
 	scanResult       interface{}
+	scanOutputFile   string // Actual file written by the last scan
+	lastScan         *scanRequest // Options of the last scan, for the rescan key
+	running          *operation   // Scan or clean in progress, until its result is shown
+	scanCancelled    string       // What the last scan did before esc stopped it
+	resultsTable     table.Model     // Secrets of the last full scan, one page at a time
+	resultsPages     paginator.Model // Page of resultsTable
+	resultsRows      []resultRow     // Visible secrets, in table order
+	resultsSort      int             // Column the table is sorted by
+	resultsSortDesc  bool
+	resultsSearch    textinput.Model // Search of the table (/)
+	resultsFilter    resultsFilter   // Parsed resultsSearch
+	detail           resultRow       // Secret shown in the detail view
+	detailView       viewport.Model  // Scrolled description of detail
+	detailRevealed   bool            // Raw values shown (recorded in the audit log)
+	detailErr        error
+	triageStore      *triage.Store // Baseline of the scanned repository (results table decisions)
+	triageMsg        string        // Outcome of the last decision
 
 	// Analyze state (pointers for huh form compatibility)
 	analyzeInputPath   *string
 	analyzeOutputPath  *string
 	analyzeConfirm     *bool
+	analyzeAnonymize   *bool
 	analyzeResult      interface{}
 	analyzeCsvExported bool
+	analyzeReport      string // Where the report was exported
 
 	// Clean state (pointers for huh form compatibility)
 	cleanInputPath  *string
 	cleanRepoPath   *string
 	cleanTool       *string
 	cleanDryRun     *bool
+	cleanRotated    *bool
+	cleanAnchored   *bool
+	cleanInclude    *string // Globs of the files to rewrite
+	cleanExclude    *string // Globs of the files never rewritten
+	cleanReplace    *string // Text written instead of the values ("" for cleaner.DefaultReplacement)
+	cleanDelete     *string // Globs of the files removed from the history
+	cleanMirror     *bool   // Rewrite a mirror clone instead of the repository
+	cleanBundle     *string // Git bundle written before the rewrite ("" for none)
+	cleanTypes      *string // Filter step: finding types loaded from the results
+	cleanFiles      *string // Filter step: globs of the files whose findings are loaded
+	cleanMinLength  *string // Filter step: shortest value loaded
+	cleanSeverity   *string // Filter step: lowest severity loaded
 	cleanConfirm    *bool
 	cleanResult     interface{}
+	cleanCandidates []cleaner.Candidate // Values of the last dry run, for review
+	cleanExcluded   map[string]bool     // Values excluded from the clean in the review
+	cleanCursor     int
+	cleanSelectErr  error                // Loading the values of the selection step failed
+	cleanSize       *cleanSizeMsg        // Size of the repository, on the confirm screen (nil while measured)
+	cleanPush       *mirrorPush          // Push of the verified mirror clone of the last clean
+	cleanRemote     string               // Origin the mirror of the last clean was cloned from ("" for none)
+	cleanRestore    *backupRestore       // Restore of the backup of the last clean, to undo it
+	cleanLog        *cleanLog            // Output and steps of the running clean
+	cleanLogScroll  int                  // Lines the output pane is scrolled up (0 follows the output)
+	cleanCreds      *cleaner.Credentials // Credentials of the next clean's mirror clone, asked after a refusal
+	credentials     *credentialPrompt    // Credentials asked for a clone or push (ViewCredentials)
 
 	// Tools state
 	toolIndex     int
+	installIndex  int
 	installOutput string
 	installing    bool
 
 	// Config state
 	configIndex       int
+	configSelectIndex int
 	configPath        string
+	configChosen      bool // A configuration was selected: scans do not use the repository's
 	configCreatePath  string
 	configConfirm     *bool
+	configPacks       *[]string // Language packs selected when creating a config
+	configPalette     *string   // Palette selected when creating a config
+	configTheme       *string   // Theme selected when creating a config
+	configFormat      *string   // File format selected when creating a config
 	currentConfig     *config.Config
-	configFromScan    bool // Track if config was opened from scan form
+	configIssues      []config.Issue // Problems of the viewed configuration
+	configIssuesErr   error          // Why the viewed configuration could not be validated
+	configFromScan    bool           // Track if config was opened from scan form
+	playgroundInput   textinput.Model
+	playgroundScanner *scanner.Scanner // Built from the configuration when the playground opens
+
+	// Integrations state
+	integrationIndex     int
+	integrationSettings  integrations.Settings
+	integrationsErr      error             // Why the settings file could not be read
+	integrationTokens    map[string]string // Token source by integration (nil until read)
+	integrationStatus    map[string]string // Outcome of the last test, save or removal
+	integrationTesting   string            // Integration whose connection is tested
+	integrationNoKeyring bool              // No OS keyring: tokens come from the environment
+	integrationURL       *string
+	integrationUser      *string
+	integrationToken     *string
+
+	// Results filtering
+	severityHidden map[string]bool // Severities hidden in results views (toggled with 1-4)
+
+	// Results browser state (paged, lazily loaded)
+	browseReturn View
+	pager        *analyzer.ResultPager
+	pagerPath    string
+	pagerPage    int
+	pagerRows    []analyzer.ScanSecret
+	pagerLoading bool
 
 	// File browser state
 	browseDir     string
 	browseIndex   int
 	browseEntries []browserEntry
+
+	// Repository browser: the form it fills
+	repoBrowseReturn View
+
+	// Existing output file of the scan or analyze form (from overwriteReturn)
+	overwriteReturn  View
+	overwritePath    string
+	overwriteStamped string // Timestamped name offered instead
+	overwriteChoice  *string
+
+	openErr error // Failure of the last file opened in an editor or viewer
+
+	// Results of this session, saved for the next, and those of the
+	// previous session ("Resume Last Session", nil without any)
+	results resumeResults
+	resume  *resumeResults
+
+	// Script driving the TUI (--script)
+	script    []scriptStep
+	scriptErr error // Failed expect step
 }
 
 type menuItem struct {
@@ -105,37 +233,75 @@ type menuItem struct {
 	description string
 }
 
+// menuItems are in English, translated when rendered
 var menuItems = []menuItem{
 	{"Scan Repository", "Search for passwords and secrets in git history"},
 	{"Analyze Results", "View statistics, authors, and frequency of changes"},
 	{"Clean History", "Remove secrets from git history (rewrite commits)"},
 	{"Check Tools", "Verify and install cleaning tools (git-filter-repo, BFG)"},
+	{"Integrations", "GitHub, GitLab and Jira tokens for notifications and tickets"},
 	{"Quit", "Exit the application"},
 }
 
+// tr translates the text of the TUI into the language of the selected
+// configuration (settings.language, GITSECRET_LANG first). Menu, screen
+// titles and analysis results are translated, other text stays in English.
+var tr = i18n.For(config.LanguageEN)
+
 // New creates a new Model
 func New() Model {
+	// Until a configuration is selected, colors follow the one found by default
+	var settings config.Settings
+	if cfg, err := config.LoadAuto(); err == nil {
+		settings = cfg.Settings
+	}
+	useTheme(settings)
+	tr = i18n.For(i18n.Resolve(settings.Language))
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
+	if plain.Enabled() {
+		s.Spinner = spinner.Line
+	}
 	s.Style = lipgloss.NewStyle().Foreground(primaryColor)
 
+	// GITSECRET_CONFIG is selected from the start, over the repository's file
+	configPath := os.Getenv(config.ConfigEnv)
 	return Model{
-		view:    ViewMenu,
-		spinner: s,
+		view:           ViewMenu,
+		spinner:        s,
+		severityHidden: make(map[string]bool),
+		resultsTable:   newResultsTable(),
+		resultsPages:   newResultsPages(),
+		resultsSearch:  newResultsSearch(),
+		configPath:     configPath,
+		configChosen:   configPath != "",
 	}
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return m.spinner.Tick
+	return tea.Batch(m.spinner.Tick, m.nextScriptStep(scriptPace))
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// The theme and the language follow the selected configuration
+	if m.currentConfig != nil {
+		useTheme(m.currentConfig.Settings)
+		tr = i18n.For(i18n.Resolve(m.currentConfig.Settings.Language))
+		m.spinner.Style = m.spinner.Style.Foreground(primaryColor)
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+		return m.resize(msg)
+
+	case scriptMsg:
+		return m.runScriptStep()
+
+	case fileOpenedMsg:
+		m.openErr = msg.err
 		return m, nil
 
 	case tea.KeyMsg:
@@ -147,14 +313,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Don't intercept esc when in form views (let the form handle it)
 		isFormView := m.view == ViewScan || m.view == ViewAnalyze ||
 			m.view == ViewClean || m.view == ViewCleanConfirm ||
-			m.view == ViewConfigCreate
+			m.view == ViewConfigCreate || m.view == ViewIntegrationEdit ||
+			m.view == ViewOverwrite || m.view == ViewCredentials
 
 		if !isFormView && msg.String() == "esc" {
 			if m.view == ViewMenu {
 				return m, tea.Quit
 			}
+			if m.view == ViewSecretDetail {
+				m.view = ViewScanResults
+				return m, nil
+			}
+			if m.view == ViewDashboard {
+				m.view = ViewAnalyzeResults
+				return m, nil
+			}
+			// The repository browser returns to its form, the path unchanged
+			if m.view == ViewRepoBrowse {
+				m.view = m.repoBrowseReturn
+				return m, nil
+			}
+			// A running scan or clean is cancelled; its result follows
+			if m.view == ViewScanProgress || m.view == ViewCleanProgress {
+				if m.running != nil {
+					m.running.stop()
+				}
+				return m, nil
+			}
+			// A search of the results is cleared first
+			if m.view == ViewScanResults && m.searchingResults() {
+				m.clearResultsSearch()
+				return m, nil
+			}
 			// Special handling for config views accessed from scan
-			if m.view == ViewConfigView {
+			if m.view == ViewConfigView || m.view == ViewConfigPlayground {
 				if m.configFromScan {
 					m.view = ViewScanConfig
 					return m, nil
@@ -175,6 +367,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateScanForm(msg)
 	case ViewScanProgress:
 		return m.updateScanProgress(msg)
+	case ViewScanResults:
+		return m.updateScanResults(msg)
+	case ViewAnalyzeResults:
+		return m.updateAnalyzeResults(msg)
 	case ViewAnalyze:
 		return m.updateAnalyzeForm(msg)
 	case ViewClean:
@@ -183,6 +379,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateCleanConfirm(msg)
 	case ViewCleanProgress:
 		return m.updateCleanProgress(msg)
+	case ViewCleanResults:
+		return m.updateCleanResults(msg)
 	case ViewTools:
 		return m.updateTools(msg)
 	case ViewToolsInstall:
@@ -203,6 +401,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateScanConfigBrowse(msg)
 	case ViewAnalyzeProgress:
 		return m.updateAnalyzeProgress(msg)
+	case ViewResultsBrowse:
+		return m.updateResultsBrowse(msg)
+	case ViewConfigPlayground:
+		return m.updatePlayground(msg)
+	case ViewIntegrations:
+		return m.updateIntegrations(msg)
+	case ViewIntegrationEdit:
+		return m.updateIntegrationEdit(msg)
+	case ViewSecretDetail:
+		return m.updateSecretDetail(msg)
+	case ViewRepoBrowse:
+		return m.updateRepoBrowse(msg)
+	case ViewOverwrite:
+		return m.updateOverwrite(msg)
+	case ViewCredentials:
+		return m.updateCredentials(msg)
+	case ViewDashboard:
+		return m.updateDashboard(msg)
+	case ViewCleanSelect:
+		return m.updateCleanSelect(msg)
 	}
 
 	return m, nil
@@ -217,7 +435,7 @@ func (m Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.menuIndex--
 			}
 		case "down", "j":
-			if m.menuIndex < len(menuItems)-1 {
+			if m.menuIndex < len(m.menu())-1 {
 				m.menuIndex++
 			}
 		case "enter":
@@ -227,8 +445,24 @@ func (m Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// menu returns the main menu entries: "Resume Last Session" comes first
+// when the previous session left results
+func (m Model) menu() []menuItem {
+	if m.resume == nil {
+		return menuItems
+	}
+	return append([]menuItem{m.resumeMenuItem()}, menuItems...)
+}
+
 func (m Model) handleMenuSelect() (tea.Model, tea.Cmd) {
-	switch m.menuIndex {
+	index := m.menuIndex
+	if m.resume != nil {
+		if index == 0 {
+			return m.resumeSession()
+		}
+		index--
+	}
+	switch index {
 	case 0: // Scan
 		m.view = ViewScan
 		m.form = m.createScanForm()
@@ -244,7 +478,9 @@ func (m Model) handleMenuSelect() (tea.Model, tea.Cmd) {
 	case 3: // Tools
 		m.view = ViewTools
 		return m, nil
-	case 4: // Quit
+	case 4: // Integrations
+		return m.openIntegrations()
+	case 5: // Quit
 		return m, tea.Quit
 	}
 	return m, nil
@@ -252,6 +488,14 @@ func (m Model) handleMenuSelect() (tea.Model, tea.Cmd) {
 
 // View renders the UI
 func (m Model) View() string {
+	if m.tooSmall() {
+		return plain.Text(m.viewTooSmall())
+	}
+	return plain.Text(m.fitView(m.renderView()))
+}
+
+// renderView renders the current view at its natural size
+func (m Model) renderView() string {
 	switch m.view {
 	case ViewMenu:
 		return m.viewMenu()
@@ -283,6 +527,8 @@ func (m Model) View() string {
 		return m.viewConfig()
 	case ViewConfigView:
 		return m.viewConfigView()
+	case ViewConfigPlayground:
+		return m.viewPlayground()
 	case ViewConfigCreate:
 		return m.viewConfigCreate()
 	case ViewConfigSelect:
@@ -295,6 +541,24 @@ func (m Model) View() string {
 		return m.viewScanConfigSelect()
 	case ViewScanConfigBrowse:
 		return m.viewScanConfigBrowse()
+	case ViewResultsBrowse:
+		return m.viewResultsBrowse()
+	case ViewIntegrations:
+		return m.viewIntegrations()
+	case ViewIntegrationEdit:
+		return m.viewIntegrationEdit()
+	case ViewSecretDetail:
+		return m.viewSecretDetail()
+	case ViewRepoBrowse:
+		return m.viewRepoBrowse()
+	case ViewOverwrite:
+		return m.viewOverwrite()
+	case ViewCredentials:
+		return m.viewCredentials()
+	case ViewDashboard:
+		return m.viewDashboard()
+	case ViewCleanSelect:
+		return m.viewCleanSelect()
 	default:
 		return "Unknown view"
 	}
@@ -303,11 +567,18 @@ func (m Model) View() string {
 func (m Model) viewMenu() string {
 	var sb strings.Builder
 
-	sb.WriteString(renderLogo())
-	sb.WriteString("\n\n")
+	// Short terminals get the menu without the logo and descriptions
+	compact := m.height > 0 && m.height < menuFullHeight
+	if compact {
+		sb.WriteString(titleStyle.Render("Git Secret Scanner & Cleaner"))
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(renderLogo())
+		sb.WriteString("\n\n")
+	}
 
 	// Menu items
-	for i, item := range menuItems {
+	for i, item := range m.menu() {
 		cursor := "  "
 		style := menuItemStyle
 		if i == m.menuIndex {
@@ -315,13 +586,17 @@ func (m Model) viewMenu() string {
 			style = selectedMenuItemStyle
 		}
 
-		title := style.Render(cursor + item.title)
-		desc := subtitleStyle.Render("  " + item.description)
+		title := style.Render(cursor + tr.T(item.title))
+		if compact {
+			sb.WriteString(title + "\n")
+			continue
+		}
+		desc := subtitleStyle.Render("  " + tr.T(item.description))
 		sb.WriteString(title + "\n" + desc + "\n\n")
 	}
 
 	// Help
-	help := helpStyle.Render("↑/↓: navigate • enter: select • esc: quit")
+	help := helpStyle.Render(tr.T("↑/↓: navigate • enter: select • esc: quit"))
 	sb.WriteString("\n" + help)
 
 	return boxStyle.Render(sb.String())
@@ -360,6 +635,12 @@ var availableTools = []toolInfo{
 			{"Homebrew (macOS)", "brew", []string{"install", "bfg"}},
 		},
 	},
+	{
+		name:  "native",
+		check: func() bool { return true },
+		desc:  "Built-in Go engine - No dependencies",
+		installCmds: nil,
+	},
 	{
 		name:  "git-filter-branch",
 		check: func() bool { return true },
@@ -494,13 +775,12 @@ func (m Model) updateToolsInstall(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// Store install index in a simple way (reuse scanProgress as temp storage)
 func (m *Model) getInstallIndex() int {
-	return m.scanProgress
+	return m.installIndex
 }
 
 func (m *Model) setInstallIndex(idx int) {
-	m.scanProgress = idx
+	m.installIndex = idx
 }
 
 func (m *Model) runInstall(cmd installCmd) tea.Cmd {
@@ -592,6 +872,7 @@ var configMenuItems = []configMenuItem{
 	{"View Current", "See loaded configuration and patterns"},
 	{"Create New", "Create a new configuration file"},
 	{"Select Config", "Choose a configuration file to use"},
+	{"Pattern Playground", "Test a sample line against the patterns"},
 }
 
 func (m Model) updateConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -610,9 +891,7 @@ func (m Model) updateConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch m.configIndex {
 			case 0: // View
 				m.view = ViewConfigView
-				// Load current config
-				cfg, _ := config.Load(m.configPath)
-				m.currentConfig = cfg
+				m.loadConfigView()
 			case 1: // Create
 				m.view = ViewConfigCreate
 				if m.configCreatePath == "" {
@@ -622,6 +901,8 @@ func (m Model) updateConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.form.Init()
 			case 2: // Select
 				m.view = ViewConfigSelect
+			case 3: // Playground
+				return m.openPlayground()
 			}
 			return m, nil
 		}
@@ -637,7 +918,7 @@ func (m Model) viewConfig() string {
 
 	// Show current config
 	if m.configPath != "" {
-		sb.WriteString(fmt.Sprintf("Current: %s\n\n", successStyle.Render(m.configPath)))
+		sb.WriteString(fmt.Sprintf("Current: %s\n\n", successStyle.Render(configLayersLabel(m.configPath))))
 	} else {
 		sb.WriteString(fmt.Sprintf("Current: %s\n\n", lipgloss.NewStyle().Foreground(mutedColor).Render("Built-in defaults")))
 	}
@@ -661,6 +942,14 @@ func (m Model) viewConfig() string {
 	return boxStyle.Render(sb.String())
 }
 
+// loadConfigView loads the selected configuration and its problems for the
+// config view
+func (m *Model) loadConfigView() {
+	cfg, _ := config.Load(m.configPath)
+	m.currentConfig = cfg
+	m.configIssues, m.configIssuesErr = config.ValidateFile(m.configPath)
+}
+
 func (m Model) viewConfigView() string {
 	var sb strings.Builder
 
@@ -672,8 +961,15 @@ func (m Model) viewConfigView() string {
 	} else {
 		// Keywords
 		sb.WriteString(keyStyle.Render("Keywords Groups:") + "\n")
-		for _, kw := range m.currentConfig.Keywords {
-			sb.WriteString(fmt.Sprintf("  • %s (%d patterns)\n", kw.Name, len(kw.Patterns)))
+		for _, kw := range m.currentConfig.KeywordGroups() {
+			kind := "patterns"
+			if kw.Regex {
+				kind = "regex patterns"
+			}
+			sb.WriteString(fmt.Sprintf("  • %s (%d %s)\n", kw.Name, len(kw.Patterns), kind))
+		}
+		if len(m.currentConfig.LanguagePacks) > 0 {
+			sb.WriteString(fmt.Sprintf("  Language packs: %s\n", strings.Join(m.currentConfig.LanguagePacks, ", ")))
 		}
 		sb.WriteString("\n")
 
@@ -682,6 +978,19 @@ func (m Model) viewConfigView() string {
 		sb.WriteString(fmt.Sprintf("  Min length: %d\n", m.currentConfig.Settings.MinSecretLength))
 		sb.WriteString(fmt.Sprintf("  Max length: %d\n", m.currentConfig.Settings.MaxSecretLength))
 		sb.WriteString(fmt.Sprintf("  Case sensitive: %v\n", m.currentConfig.Settings.CaseSensitive))
+		palette := m.currentConfig.Settings.Palette
+		if palette == "" {
+			palette = config.PaletteDefault
+		}
+		theme := m.currentConfig.Settings.Theme
+		if theme == "" {
+			theme = config.ThemeDark
+		}
+		if len(m.currentConfig.Settings.ThemeColors.Named()) > 0 {
+			theme += " (custom colors)"
+		}
+		sb.WriteString(fmt.Sprintf("  Theme: %s\n", theme))
+		sb.WriteString(fmt.Sprintf("  Palette: %s\n", palette))
 		sb.WriteString("\n")
 
 		// Ignored values
@@ -693,6 +1002,24 @@ func (m Model) viewConfigView() string {
 			}
 			sb.WriteString(fmt.Sprintf("  • %s\n", v))
 		}
+		sb.WriteString("\n")
+	}
+
+	// Problems are shown even when the file cannot be loaded
+	sb.WriteString(keyStyle.Render("Validation:") + "\n")
+	switch {
+	case m.configIssuesErr != nil:
+		sb.WriteString("  " + errorStyle.Render(m.configIssuesErr.Error()) + "\n")
+	case len(m.configIssues) == 0:
+		sb.WriteString("  " + successStyle.Render("✓ No problems found") + "\n")
+	default:
+		for _, issue := range m.configIssues {
+			style := warningStyle
+			if issue.Level == config.IssueError {
+				style = errorStyle
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s: %s\n", style.Render(issue.Level), issue.Field, issue.Message))
+		}
 	}
 
 	help := helpStyle.Render("esc: back")
@@ -706,12 +1033,64 @@ func (m *Model) createConfigForm() *huh.Form {
 	// Default to false (Cancel) - user must explicitly choose to create
 	confirm := false
 	m.configConfirm = &confirm
-	return huh.NewForm(
+
+	// Allocate pointer for the selected language packs
+	packs := []string{}
+	m.configPacks = &packs
+	palette := config.PaletteDefault
+	m.configPalette = &palette
+	theme := config.ThemeDark
+	m.configTheme = &theme
+	format := config.ConfigFormat(m.configCreatePath)
+	m.configFormat = &format
+	packOptions := make([]huh.Option[string], 0)
+	for _, code := range config.LanguagePackCodes() {
+		pack, _ := config.GetLanguagePack(code)
+		packOptions = append(packOptions, huh.NewOption(fmt.Sprintf("%s (%s)", pack.Name, code), code))
+	}
+
+	pathField := pathInput(&m.configCreatePath, config.ConfigExtensions...).
+		Title("Configuration File Path").
+		Description("Where to save the new configuration (tab: complete)")
+
+	return m.fitForm(withPathCompletion(huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Title("Configuration File Path").
-				Description("Where to save the new configuration").
-				Value(&m.configCreatePath),
+			pathField,
+
+			huh.NewSelect[string]().
+				Title("File Format").
+				Description("The file extension follows the format").
+				Options(
+					huh.NewOption("JSON", config.FormatJSON),
+					huh.NewOption("YAML", config.FormatYAML),
+					huh.NewOption("TOML", config.FormatTOML),
+				).
+				Value(m.configFormat),
+
+			huh.NewMultiSelect[string]().
+				Title("Language Packs").
+				Description("Localized keywords to add (space to toggle)").
+				Options(packOptions...).
+				Value(m.configPacks),
+
+			huh.NewSelect[string]().
+				Title("Theme").
+				Description("Colors can be changed in settings.themeColors").
+				Options(
+					huh.NewOption("Dark", config.ThemeDark),
+					huh.NewOption("Light", config.ThemeLight),
+					huh.NewOption("High contrast", config.ThemeHighContrast),
+				).
+				Value(m.configTheme),
+
+			huh.NewSelect[string]().
+				Title("Color Palette").
+				Description("Severities are also marked ▲ ◆ ● ○ whatever the palette").
+				Options(
+					huh.NewOption("Default", config.PaletteDefault),
+					huh.NewOption("Color-blind safe", config.PaletteColorBlind),
+				).
+				Value(m.configPalette),
 
 			huh.NewConfirm().
 				Title("Create configuration file?").
@@ -719,7 +1098,7 @@ func (m *Model) createConfigForm() *huh.Form {
 				Negative("Cancel").
 				Value(m.configConfirm),
 		),
-	).WithTheme(huh.ThemeDracula())
+	).WithTheme(formTheme()), pathField))
 }
 
 func (m Model) updateConfigCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -742,10 +1121,23 @@ func (m Model) updateConfigCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.configConfirm != nil && *m.configConfirm {
 			// Create default config file
 			cfg := config.DefaultConfig()
+			if m.configPacks != nil {
+				cfg.LanguagePacks = *m.configPacks
+			}
+			if m.configPalette != nil && *m.configPalette != config.PaletteDefault {
+				cfg.Settings.Palette = *m.configPalette
+			}
+			if m.configTheme != nil && *m.configTheme != config.ThemeDark {
+				cfg.Settings.Theme = *m.configTheme
+			}
+			if m.configFormat != nil {
+				m.configCreatePath = config.WithFormatExtension(m.configCreatePath, *m.configFormat)
+			}
 			if err := cfg.Save(m.configCreatePath); err != nil {
 				m.err = err
 			} else {
 				m.configPath = m.configCreatePath
+				m.configChosen = true
 				m.currentConfig = cfg
 			}
 		}
@@ -807,6 +1199,7 @@ func (m Model) updateConfigSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			if idx < len(configs) {
 				selected := configs[idx]
+				m.configChosen = true
 				if selected == "(Built-in defaults)" {
 					m.configPath = ""
 					m.currentConfig = config.DefaultConfig()
@@ -818,6 +1211,14 @@ func (m Model) updateConfigSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.view = ViewConfig
 			}
 			return m, nil
+		case "a":
+			// Merge the file over the selected configuration
+			idx := m.getConfigSelectIndex()
+			if idx < len(configs) && configs[idx] != "(Built-in defaults)" {
+				m.addConfigLayer(configs[idx])
+				m.view = ViewConfig
+			}
+			return m, nil
 		case "esc":
 			m.view = ViewConfig
 			return m, nil
@@ -827,25 +1228,26 @@ func (m Model) updateConfigSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) getConfigSelectIndex() int {
-	return m.scanTotal // Reuse scanTotal as temp storage
+	return m.configSelectIndex
 }
 
 func (m *Model) setConfigSelectIndex(idx int) {
-	m.scanTotal = idx
+	m.configSelectIndex = idx
 }
 
 func (m Model) findConfigFiles() []string {
 	configs := []string{"(Built-in defaults)"}
 
 	// Check common locations
-	locations := []string{
-		"patterns.json",
-		"config/patterns.json",
-	}
-
-	// Add home config
+	var locations []string
+	dirs := []string{"", "config"}
 	if home, err := os.UserHomeDir(); err == nil {
-		locations = append(locations, filepath.Join(home, ".config", "git-secret-scanner", "patterns.json"))
+		dirs = append(dirs, filepath.Join(home, ".config", "git-secret-scanner"))
+	}
+	for _, dir := range dirs {
+		for _, name := range config.ConfigFileNames {
+			locations = append(locations, filepath.Join(dir, name))
+		}
 	}
 
 	// Find all .json files in current directory
@@ -866,6 +1268,40 @@ func (m Model) findConfigFiles() []string {
 	return configs
 }
 
+// addConfigLayer merges a file over the selected configuration (see
+// config.Layers); a file already in the layers is not added again
+func (m *Model) addConfigLayer(path string) {
+	if !contains(config.Layers(m.configPath), path) {
+		m.configPath = config.JoinLayers(m.configPath, path)
+	}
+	m.configChosen = true
+	cfg, _ := config.Load(m.configPath)
+	m.currentConfig = cfg
+}
+
+// configMark tells whether a file of the select list is the current
+// configuration or one of its layers
+func (m Model) configMark(cfg string) string {
+	layers := config.Layers(m.configPath)
+	switch {
+	case (cfg == "(Built-in defaults)" && m.configPath == "") || cfg == m.configPath:
+		return successStyle.Render(" (current)")
+	case cfg == "(Built-in defaults)" && len(layers) > 1 && layers[0] == "",
+		contains(layers, cfg):
+		return successStyle.Render(" (layer)")
+	}
+	return ""
+}
+
+// configLayersLabel shows a configuration path with its layers
+func configLayersLabel(path string) string {
+	layers := config.Layers(path)
+	if len(layers) > 0 && layers[0] == "" {
+		layers[0] = "Built-in defaults"
+	}
+	return strings.Join(layers, " + ")
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -892,14 +1328,7 @@ func (m Model) viewConfigSelect() string {
 			style = selectedMenuItemStyle
 		}
 
-		// Mark current
-		current := ""
-		if (cfg == "(Built-in defaults)" && m.configPath == "") ||
-			cfg == m.configPath {
-			current = successStyle.Render(" (current)")
-		}
-
-		sb.WriteString(style.Render(fmt.Sprintf("%s%s", cursor, cfg)) + current + "\n")
+		sb.WriteString(style.Render(fmt.Sprintf("%s%s", cursor, cfg)) + m.configMark(cfg) + "\n")
 	}
 
 	// Browse option
@@ -911,7 +1340,7 @@ func (m Model) viewConfigSelect() string {
 	}
 	sb.WriteString("\n" + style.Render(fmt.Sprintf("%s📁 Browse...", cursor)) + "\n")
 
-	help := helpStyle.Render("↑/↓: navigate • enter: select • esc: back")
+	help := helpStyle.Render("↑/↓: navigate • enter: select • a: add as layer • esc: back")
 	sb.WriteString("\n" + help)
 
 	return boxStyle.Render(sb.String())
@@ -919,9 +1348,10 @@ func (m Model) viewConfigSelect() string {
 
 // Browser entry types
 type browserEntry struct {
-	name  string
-	isDir bool
-	path  string
+	name   string
+	isDir  bool
+	isRepo bool // Git repository (repository browser)
+	path   string
 }
 
 func (m *Model) loadBrowseEntries() {
@@ -952,9 +1382,9 @@ func (m *Model) loadBrowseEntries() {
 		}
 	}
 
-	// Then JSON files
+	// Then configuration files
 	for _, e := range entries {
-		if !e.IsDir() && strings.HasSuffix(strings.ToLower(e.Name()), ".json") {
+		if !e.IsDir() && hasExtension(e.Name(), config.ConfigExtensions) {
 			m.browseEntries = append(m.browseEntries, browserEntry{
 				name:  e.Name(),
 				isDir: false,
@@ -987,12 +1417,20 @@ func (m Model) updateConfigBrowse(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					// Select file
 					m.configPath = entry.path
+					m.configChosen = true
 					cfg, _ := config.Load(entry.path)
 					m.currentConfig = cfg
 					m.view = ViewConfig
 				}
 			}
 			return m, nil
+		case "a":
+			// Merge the file over the selected configuration
+			if m.browseIndex < len(m.browseEntries) && !m.browseEntries[m.browseIndex].isDir {
+				m.addConfigLayer(m.browseEntries[m.browseIndex].path)
+				m.view = ViewConfig
+			}
+			return m, nil
 		case "backspace":
 			// Go up one directory
 			if m.browseDir != "/" {
@@ -1036,23 +1474,18 @@ func (m Model) viewConfigBrowse() string {
 			style = selectedMenuItemStyle
 		}
 
-		icon := "📄"
-		if entry.isDir {
-			icon = "📁"
-		}
-
-		sb.WriteString(style.Render(fmt.Sprintf("%s%s %s", cursor, icon, entry.name)) + "\n")
+		sb.WriteString(style.Render(fmt.Sprintf("%s%s %s", cursor, browseIcon(entry.isDir), entry.name)) + "\n")
 	}
 
 	if len(m.browseEntries) == 0 {
-		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  (no JSON files or directories)") + "\n")
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  (no configuration files or directories)") + "\n")
 	}
 
 	if len(m.browseEntries) > maxVisible {
 		sb.WriteString(fmt.Sprintf("\n  ... %d/%d items", m.browseIndex+1, len(m.browseEntries)))
 	}
 
-	help := helpStyle.Render("↑/↓: navigate • enter: open/select • backspace: up • esc: back")
+	help := helpStyle.Render("↑/↓: navigate • enter: open/select • a: add as layer • backspace: up • esc: back")
 	sb.WriteString("\n\n" + help)
 
 	return boxStyle.Render(sb.String())
@@ -1076,8 +1509,7 @@ func (m Model) updateScanConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case 0: // View
 				m.configFromScan = true
 				m.view = ViewConfigView
-				cfg, _ := config.Load(m.configPath)
-				m.currentConfig = cfg
+				m.loadConfigView()
 			case 1: // Create
 				m.configFromScan = true
 				m.view = ViewConfigCreate
@@ -1088,6 +1520,9 @@ func (m Model) updateScanConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.form.Init()
 			case 2: // Select
 				m.view = ViewScanConfigSelect
+			case 3: // Playground
+				m.configFromScan = true
+				return m.openPlayground()
 			}
 			return m, nil
 		case "esc":
@@ -1109,7 +1544,7 @@ func (m Model) viewScanConfig() string {
 
 	// Show current config
 	if m.configPath != "" {
-		sb.WriteString(fmt.Sprintf("Current: %s\n\n", successStyle.Render(m.configPath)))
+		sb.WriteString(fmt.Sprintf("Current: %s\n\n", successStyle.Render(configLayersLabel(m.configPath))))
 	} else {
 		sb.WriteString(fmt.Sprintf("Current: %s\n\n", lipgloss.NewStyle().Foreground(mutedColor).Render("Built-in defaults")))
 	}
@@ -1162,6 +1597,7 @@ func (m Model) updateScanConfigSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			if idx < len(configs) {
 				selected := configs[idx]
+				m.configChosen = true
 				if selected == "(Built-in defaults)" {
 					m.configPath = ""
 					m.currentConfig = config.DefaultConfig()
@@ -1176,6 +1612,16 @@ func (m Model) updateScanConfigSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.form.Init()
 			}
 			return m, nil
+		case "a":
+			// Merge the file over the selected configuration
+			idx := m.getConfigSelectIndex()
+			if idx < len(configs) && configs[idx] != "(Built-in defaults)" {
+				m.addConfigLayer(configs[idx])
+				m.view = ViewScan
+				m.form = m.createScanForm()
+				return m, m.form.Init()
+			}
+			return m, nil
 		case "esc":
 			m.view = ViewScanConfig
 			return m, nil
@@ -1201,14 +1647,7 @@ func (m Model) viewScanConfigSelect() string {
 			style = selectedMenuItemStyle
 		}
 
-		// Mark current
-		current := ""
-		if (cfg == "(Built-in defaults)" && m.configPath == "") ||
-			cfg == m.configPath {
-			current = successStyle.Render(" (current)")
-		}
-
-		sb.WriteString(style.Render(fmt.Sprintf("%s%s", cursor, cfg)) + current + "\n")
+		sb.WriteString(style.Render(fmt.Sprintf("%s%s", cursor, cfg)) + m.configMark(cfg) + "\n")
 	}
 
 	// Browse option
@@ -1220,7 +1659,7 @@ func (m Model) viewScanConfigSelect() string {
 	}
 	sb.WriteString("\n" + style.Render(fmt.Sprintf("%s📁 Browse...", cursor)) + "\n")
 
-	help := helpStyle.Render("↑/↓: navigate • enter: select • esc: back")
+	help := helpStyle.Render("↑/↓: navigate • enter: select • a: add as layer • esc: back")
 	sb.WriteString("\n" + help)
 
 	return boxStyle.Render(sb.String())
@@ -1249,6 +1688,7 @@ func (m Model) updateScanConfigBrowse(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					// Select file and return to scan form
 					m.configPath = entry.path
+					m.configChosen = true
 					cfg, _ := config.Load(entry.path)
 					m.currentConfig = cfg
 					m.view = ViewScan
@@ -1257,6 +1697,15 @@ func (m Model) updateScanConfigBrowse(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
+		case "a":
+			// Merge the file over the selected configuration
+			if m.browseIndex < len(m.browseEntries) && !m.browseEntries[m.browseIndex].isDir {
+				m.addConfigLayer(m.browseEntries[m.browseIndex].path)
+				m.view = ViewScan
+				m.form = m.createScanForm()
+				return m, m.form.Init()
+			}
+			return m, nil
 		case "backspace":
 			// Go up one directory
 			if m.browseDir != "/" {
@@ -1300,31 +1749,60 @@ func (m Model) viewScanConfigBrowse() string {
 			style = selectedMenuItemStyle
 		}
 
-		icon := "📄"
-		if entry.isDir {
-			icon = "📁"
-		}
-
-		sb.WriteString(style.Render(fmt.Sprintf("%s%s %s", cursor, icon, entry.name)) + "\n")
+		sb.WriteString(style.Render(fmt.Sprintf("%s%s %s", cursor, browseIcon(entry.isDir), entry.name)) + "\n")
 	}
 
 	if len(m.browseEntries) == 0 {
-		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  (no JSON files or directories)") + "\n")
+		sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  (no configuration files or directories)") + "\n")
 	}
 
 	if len(m.browseEntries) > maxVisible {
 		sb.WriteString(fmt.Sprintf("\n  ... %d/%d items", m.browseIndex+1, len(m.browseEntries)))
 	}
 
-	help := helpStyle.Render("↑/↓: navigate • enter: open/select • backspace: up • esc: back")
+	help := helpStyle.Render("↑/↓: navigate • enter: open/select • a: add as layer • backspace: up • esc: back")
 	sb.WriteString("\n\n" + help)
 
 	return boxStyle.Render(sb.String())
 }
 
 // Run starts the TUI
-func Run() error {
-	p := tea.NewProgram(New(), tea.WithAltScreen())
-	_, err := p.Run()
-	return err
+func Run(script string) error {
+	if plain.Enabled() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	m := New()
+	var state sessionState
+	if script == "" {
+		state = loadState()
+		m.restoreState(state)
+	}
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if script != "" {
+		steps, err := loadScript(script)
+		if err != nil {
+			return err
+		}
+		m.script = steps
+		if script == "-" {
+			// Standard input holds the script: keys come from the terminal
+			opts = append(opts, tea.WithInputTTY())
+		}
+	}
+
+	final, err := tea.NewProgram(m, opts...).Run()
+	if err != nil {
+		return err
+	}
+	if script == "" {
+		if err := final.(Model).sessionState(state).save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot save the session state: %v\n", err)
+		}
+	}
+	// Quit midway: the operation is stopped, what it did is printed once
+	// the terminal is back
+	if op := final.(Model).running; op != nil {
+		op.interrupt(os.Stdout)
+	}
+	return final.(Model).scriptErr
 }
@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+)
+
+// maxValidateBytes bounds the part of a results file checked by the forms,
+// so a large JSONL stream does not stall the UI
+const maxValidateBytes = 1 << 20
+
+// validateRepo is the validator of the repository path fields
+func validateRepo(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return errors.New("enter a repository path")
+	}
+	return scanner.CheckRepository(expandHome(path))
+}
+
+// validateBranch returns the validator of the scan branch field: the
+// revisions must resolve in the repository being entered. Nothing is
+// checked for working-tree scans, or while the repository is not valid
+// (its own field reports it).
+func validateBranch(repoPath, source *string) func(string) error {
+	return func(branch string) error {
+		if *source == "current" || validateRepo(*repoPath) != nil {
+			return nil
+		}
+		return scanner.CheckRevisions(expandHome(*repoPath), branch)
+	}
+}
+
+// validateIncremental returns the validator of the incremental field:
+// incremental scans track branches, not ranges
+func validateIncremental(branch *string) func(bool) error {
+	return func(incremental bool) error {
+		if incremental && strings.Contains(*branch, "..") {
+			return scanner.ErrRangeNotIncremental
+		}
+		return nil
+	}
+}
+
+// validateMinLength is the validator of the minimum length field: empty or
+// a positive number
+func validateMinLength(s string) error {
+	if s = strings.TrimSpace(s); s == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(s); err != nil || n < 0 {
+		return errors.New("enter a number of characters")
+	}
+	return nil
+}
+
+// validateGroups is the validator of the keyword groups field: a scan looks
+// for at least one group
+func validateGroups(groups []string) error {
+	if len(groups) == 0 {
+		return errors.New("select at least one keyword group")
+	}
+	return nil
+}
+
+// validateOutput is the validator of the output file fields: the file must
+// be creatable in an existing directory
+func validateOutput(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return errors.New("enter an output file")
+	}
+	path = expandHome(path)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	dir := filepath.Dir(path)
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("directory %s does not exist", dir)
+	}
+	probe, err := os.CreateTemp(dir, ".gitsecret-write-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable", dir)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return nil
+}
+
+// validateOptionalOutput is validateOutput for the output fields that
+// default to the workspace when left empty
+func validateOptionalOutput(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	return validateOutput(path)
+}
+
+// validateBundle is the validator of the backup bundle field: empty, or a
+// file that does not exist yet (backups are never overwritten)
+func validateBundle(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	path = expandHome(strings.TrimSpace(path))
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists: choose a new file", path)
+	}
+	return nil
+}
+
+// validateResults is the validator of the scan results fields: the file
+// must exist and hold JSON (a document or JSONL lines). Only the first
+// maxValidateBytes are read.
+func validateResults(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return errors.New("enter a results file")
+	}
+	path = expandHome(path)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s does not exist", path)
+		}
+		return err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+
+	dec := json.NewDecoder(f)
+	for tokens := 0; dec.InputOffset() < maxValidateBytes; tokens++ {
+		_, err := dec.Token()
+		if err == io.EOF {
+			if tokens == 0 {
+				return fmt.Errorf("%s is empty", path)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s is not a scan results file: %v", path, err)
+		}
+	}
+	return nil
+}
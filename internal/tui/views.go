@@ -1,10 +1,13 @@
 package tui
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
@@ -13,30 +16,42 @@ import (
 	"github.com/Drilmo/git-secret-scanner/internal/analyzer"
 	"github.com/Drilmo/git-secret-scanner/internal/cleaner"
 	"github.com/Drilmo/git-secret-scanner/internal/config"
+	"github.com/Drilmo/git-secret-scanner/internal/debugbundle"
+	"github.com/Drilmo/git-secret-scanner/internal/i18n"
 	"github.com/Drilmo/git-secret-scanner/internal/scanner"
+	"github.com/Drilmo/git-secret-scanner/internal/triage"
+	"github.com/Drilmo/git-secret-scanner/internal/workspace"
 )
 
 // Messages
 type scanStartMsg struct{}
 type scanProgressMsg struct {
-	current int
-	total   int
-	found   int
+	progress scanner.Progress
 }
 type scanDoneMsg struct {
 	result     interface{}
 	err        error
 	outputPath string
+	stats      scanner.ScanStats
 }
 type analyzeDoneMsg struct {
 	result     *analyzer.Analysis
 	err        error
 	csvPath    string
 	csvExported bool
+	resume     *resumeAnalysis // What reopens the analysis in a later session
+}
+type cleanSizeMsg struct {
+	size cleaner.RepoSize
+	tool string
+	err  error
 }
 type cleanDoneMsg struct {
-	result *cleaner.CleanResult
-	err    error
+	result     *cleaner.CleanResult
+	candidates []cleaner.Candidate  // Dry runs only
+	remote     string               // Origin of the repository, where a mirror clone is pushed
+	creds      *cleaner.Credentials // Credentials the mirror was cloned with, for its push
+	err        error
 }
 
 // Scan form handling
@@ -53,6 +68,8 @@ func (m Model) updateScanForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.configIndex = 0
 			m.configFromScan = true
 			return m, nil
+		case "ctrl+o":
+			return m.openRepoBrowse(ViewScan, m.scanRepoPath)
 		}
 	}
 
@@ -63,6 +80,9 @@ func (m Model) updateScanForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	if m.form.State == huh.StateCompleted {
 		if m.scanConfirm != nil && *m.scanConfirm {
+			if path := m.scanTarget(); outputExists(path) {
+				return m.openOverwrite(ViewScan, path)
+			}
 			// Start scan
 			m.view = ViewScanProgress
 			return m, tea.Batch(m.spinner.Tick, m.startScan())
@@ -82,27 +102,42 @@ func (m Model) updateScanForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) viewScanForm() string {
 	var sb strings.Builder
 
-	sb.WriteString(titleStyle.Render("🔍 Scan Repository"))
+	sb.WriteString(titleStyle.Render("🔍 " + tr.T("Scan Repository")))
 	sb.WriteString("\n\n")
 
 	// Show current configuration with details
-	configLabel := "Built-in defaults"
+	configPath, configLabel := m.configPath, "Built-in defaults"
 	if m.configPath != "" {
-		configLabel = m.configPath
+		configLabel = configLayersLabel(m.configPath)
+	}
+	repoPath := "."
+	if m.scanRepoPath != nil && *m.scanRepoPath != "" {
+		repoPath = *m.scanRepoPath
+	}
+	if repoConfig := m.repoConfig(repoPath); repoConfig != "" {
+		configPath = repoConfig
+		configLabel = repoConfig + lipgloss.NewStyle().Foreground(mutedColor).Render(" (found in the repository)")
 	}
-	sb.WriteString(keyStyle.Render("Configuration: "))
-	sb.WriteString(configLabel)
+	var header strings.Builder
+	header.WriteString(keyStyle.Render("Configuration: "))
+	header.WriteString(configLabel)
 
 	// Show pattern count
-	cfg, _ := config.Load(m.configPath)
+	cfg, _ := config.Load(configPath)
 	if cfg != nil {
 		patternCount := 0
-		for _, kw := range cfg.Keywords {
+		for _, kw := range cfg.KeywordGroups() {
 			patternCount += len(kw.Patterns)
 		}
-		sb.WriteString(fmt.Sprintf(" (%d patterns)", patternCount))
+		header.WriteString(fmt.Sprintf(" (%d patterns)", patternCount))
+	}
+	header.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  (Ctrl+E to change)"))
+	if m.width > 0 {
+		// Wrap on narrow terminals rather than widen the box
+		sb.WriteString(lipgloss.NewStyle().Width(min(m.width-formChromeWidth, maxFormWidth)).Render(header.String()))
+	} else {
+		sb.WriteString(header.String())
 	}
-	sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("  (Ctrl+E to change)"))
 	sb.WriteString("\n\n")
 
 	sb.WriteString(m.form.View())
@@ -114,12 +149,12 @@ func (m *Model) startScan() tea.Cmd {
 	// Capture values from pointers before the closure
 	repoPath := "."
 	if m.scanRepoPath != nil && *m.scanRepoPath != "" {
-		repoPath = *m.scanRepoPath
+		repoPath = expandHome(*m.scanRepoPath)
 	}
 
-	outputPath := "secrets.json"
+	outputPath := ""
 	if m.scanOutputPath != nil && *m.scanOutputPath != "" {
-		outputPath = *m.scanOutputPath
+		outputPath = expandHome(*m.scanOutputPath)
 	}
 
 	scanMode := "full"
@@ -137,18 +172,130 @@ func (m *Model) startScan() tea.Cmd {
 		branch = *m.scanBranch
 	}
 
-	configPath := m.scanConfigPath
+	incremental := m.scanIncremental != nil && *m.scanIncremental
+	submodules := m.scanSubmodules != nil && *m.scanSubmodules
+	removed := m.scanRemoved != nil && *m.scanRemoved
+	profile := ""
+	if m.scanProfile != nil {
+		profile = *m.scanProfile
+	}
+	// All the groups selected scan with the whole configuration
+	var groups []string
+	if m.scanGroups != nil && len(*m.scanGroups) < len(*m.scanAllGroups) {
+		groups = slices.Clone(*m.scanGroups)
+	}
 
-	return func() tea.Msg {
-		cfg, _ := config.Load(configPath)
+	// A revision range (v1.0..HEAD) scans only what it introduces
+	revRange := ""
+	if strings.Contains(branch, "..") {
+		revRange = branch
+	}
+
+	m.addRecentRepo(repoPath)
+
+	// Without a configuration selected, the repository's own is used
+	m.scanConfigAuto = false
+	if repoConfig := m.repoConfig(repoPath); repoConfig != "" {
+		m.scanConfigPath, m.scanConfigAuto = repoConfig, true
+	}
+
+	m.lastScan = &scanRequest{
+		repoPath:    repoPath,
+		outputPath:  outputPath,
+		configPath:  m.scanConfigPath,
+		profile:     profile,
+		groups:      groups,
+		mode:        scanMode,
+		source:      scanSource,
+		branch:      branch,
+		revRange:    revRange,
+		incremental: incremental,
+		submodules:  submodules,
+		removed:     removed,
+	}
+	return m.runScan(*m.lastScan)
+}
+
+// repoConfig returns the configuration file of a repository to scan when
+// no configuration was selected ("" otherwise)
+func (m Model) repoConfig(repoPath string) string {
+	if m.configChosen {
+		return ""
+	}
+	return config.RepoConfigPath(expandHome(repoPath))
+}
+
+// scanRequest holds the options of a scan, kept to run it again
+type scanRequest struct {
+	repoPath, outputPath, configPath string
+	profile                          string
+	groups                           []string // Keyword groups kept (nil: all)
+	mode, source, branch, revRange   string
+	incremental, submodules, removed bool
+}
+
+// runScan starts a scan in the background
+func (m *Model) runScan(req scanRequest) tea.Cmd {
+	repoPath, outputPath, configPath := req.repoPath, req.outputPath, req.configPath
+	scanMode, scanSource, branch, revRange := req.mode, req.source, req.branch, req.revRange
+	incremental, submodules, removed := req.incremental, req.submodules, req.removed
+
+	// Progress and completion messages are delivered through this channel
+	msgs := make(chan tea.Msg, 64)
+	m.scanMsgs = msgs
+	op, ctx := newOperation("Scan")
+	m.running = op
+	m.scanProgress = scanner.Progress{}
+	last := make(map[string]scanner.Progress) // Last report of each phase, for the statistics
+	feed := newScanFeed()
+	m.scanFeed = feed
+
+	run := func() tea.Msg {
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return scanDoneMsg{err: fmt.Errorf("failed to load config: %w", err)}
+		}
 		s := scanner.New(cfg)
 
+		// Without an output file, the results go to the repository's
+		// workspace, whose old scans are pruned once they are written
+		if outputPath == "" {
+			ws, err := workspace.Open(repoPath)
+			if err != nil {
+				return scanDoneMsg{err: err}
+			}
+			if incremental && scanSource != "current" {
+				outputPath, err = ws.IncrementalScan()
+			} else {
+				outputPath, err = ws.NewScan(time.Now())
+			}
+			if err != nil {
+				return scanDoneMsg{err: err}
+			}
+			defer ws.Prune(workspace.RetentionOf(cfg.Settings.Workspace), time.Now())
+		}
+
 		opts := scanner.ScanOptions{
 			Branch:     branch,
 			ConfigPath: configPath,
-			OnProgress: func(current, total, found int) {
-				// Progress updates would need channel communication
-				// For now, we'll show completion
+			Profile:    req.profile,
+			Groups:     req.groups,
+			Range:      revRange,
+			Submodules: submodules,
+			Removed:    removed,
+			Context:    scanner.DefaultContextLines,
+			Interrupt:  ctx,
+			OnProgress: func(p scanner.Progress) {
+				last[p.Phase] = p
+				// Never block the scan on a slow UI: drop updates if the buffer is full
+				select {
+				case msgs <- scanProgressMsg{progress: p}:
+				default:
+				}
+			},
+			OnFinding: func(entry scanner.StreamEntry) {
+				entry.Severity = scanner.EntrySeverity(cfg, entry)
+				feed.add(entry)
 			},
 		}
 
@@ -165,16 +312,19 @@ func (m *Model) startScan() tea.Cmd {
 			var count int
 			var err error
 
-			switch scanSource {
-			case "current":
-				count, err = s.ScanCurrentStream(repoPath, streamPath)
-			case "history":
+			switch {
+			case incremental && scanSource != "current":
+				count, err = s.ScanStreamIncremental(repoPath, streamPath, scanSource == "both", opts)
+			case scanSource == "current":
+				count, err = s.ScanCurrentStream(repoPath, streamPath, opts)
+			case scanSource == "history":
 				count, err = s.ScanStream(repoPath, streamPath, opts)
 			default: // both
 				count, err = s.ScanBothStream(repoPath, streamPath, opts)
 			}
 
-			if err != nil {
+			// An interrupted scan leaves its entries, the file marked
+			if err != nil && !errors.Is(err, scanner.ErrInterrupted) {
 				return scanDoneMsg{err: err}
 			}
 			return scanDoneMsg{
@@ -184,6 +334,7 @@ func (m *Model) startScan() tea.Cmd {
 					"count":  count,
 				},
 				outputPath: streamPath,
+				err:        err,
 			}
 
 		case "fast":
@@ -198,23 +349,30 @@ func (m *Model) startScan() tea.Cmd {
 			var result *scanner.ScanResult
 			var err error
 
-			switch scanSource {
-			case "current":
-				result, err = s.ScanCurrent(repoPath)
-			case "history":
+			switch {
+			case incremental && scanSource != "current":
+				// Merges into and saves the existing result
+				result, err = s.ScanIncremental(repoPath, jsonPath, scanSource == "both", opts)
+				if err != nil {
+					return scanDoneMsg{err: err}
+				}
+				return scanDoneMsg{result: result, outputPath: jsonPath}
+			case scanSource == "current":
+				result, err = s.ScanCurrent(repoPath, opts)
+			case scanSource == "history":
 				result, err = s.Scan(repoPath, opts)
 			default: // both
 				result, err = s.ScanBoth(repoPath, opts)
 			}
 
-			if err != nil {
+			if err != nil && !errors.Is(err, scanner.ErrInterrupted) {
 				return scanDoneMsg{err: err}
 			}
-			// Save results to file
-			if err := saveResultToFile(result, jsonPath); err != nil {
+			// Save results to file (partial ones are marked interrupted)
+			if err := scanner.SaveResult(result, jsonPath); err != nil {
 				return scanDoneMsg{err: err}
 			}
-			return scanDoneMsg{result: result, outputPath: jsonPath}
+			return scanDoneMsg{result: result, outputPath: jsonPath, err: err}
 
 		default: // full
 			// Full mode uses .json extension
@@ -228,41 +386,77 @@ func (m *Model) startScan() tea.Cmd {
 			var result *scanner.ScanResult
 			var err error
 
-			switch scanSource {
-			case "current":
-				result, err = s.ScanCurrent(repoPath)
-			case "history":
+			switch {
+			case incremental && scanSource != "current":
+				// Merges into and saves the existing result
+				result, err = s.ScanIncremental(repoPath, jsonPath, scanSource == "both", opts)
+				if err != nil {
+					return scanDoneMsg{err: err}
+				}
+				return scanDoneMsg{result: result, outputPath: jsonPath}
+			case scanSource == "current":
+				result, err = s.ScanCurrent(repoPath, opts)
+			case scanSource == "history":
 				result, err = s.Scan(repoPath, opts)
 			default: // both
 				result, err = s.ScanBoth(repoPath, opts)
 			}
 
-			if err != nil {
+			if err != nil && !errors.Is(err, scanner.ErrInterrupted) {
 				return scanDoneMsg{err: err}
 			}
-			// Save results to file
-			if err := saveResultToFile(result, jsonPath); err != nil {
+			// Save results to file (partial ones are marked interrupted)
+			if err := scanner.SaveResult(result, jsonPath); err != nil {
 				return scanDoneMsg{err: err}
 			}
-			return scanDoneMsg{result: result, outputPath: jsonPath}
+			return scanDoneMsg{result: result, outputPath: jsonPath, err: err}
 		}
 	}
+
+	return tea.Batch(
+		func() tea.Msg {
+			done := debugbundle.Step(fmt.Sprintf("scan (mode %s, source %s)", scanMode, scanSource))
+			msg := run().(scanDoneMsg)
+			msg.stats = scanner.StatsFrom(last)
+			done(msg.err)
+			op.finish(scanSummary(req, msg))
+			msgs <- msg
+			return nil
+		},
+		waitForScanMsg(msgs),
+	)
+}
+
+// waitForScanMsg blocks until the running scan sends its next message
+func waitForScanMsg(msgs <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-msgs
+	}
 }
 
 func (m Model) updateScanProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case scanProgressMsg:
-		m.scanProgress = msg.current
-		m.scanTotal = msg.total
-		m.scanFound = msg.found
-		return m, nil
+		m.scanProgress = msg.progress
+		return m, waitForScanMsg(m.scanMsgs)
 
 	case scanDoneMsg:
+		// A cancelled scan shows what it found before it stopped
+		m.scanCancelled = ""
+		if errors.Is(msg.err, scanner.ErrInterrupted) && m.running != nil {
+			m.scanCancelled = m.running.summary
+			msg.err = nil
+		}
+		m.running = nil
 		if msg.err != nil {
 			m.err = msg.err
 		}
 		m.scanResult = msg.result
-		m.view = ViewScanResults
+		m.scanStats = msg.stats
+		m.openScanResults(msg.outputPath)
+		if msg.err == nil {
+			m.rememberScan(msg.outputPath, msg.result)
+		}
 		return m, nil
 
 	default:
@@ -272,20 +466,65 @@ func (m Model) updateScanProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// openScanResults shows the scan results written to outputPath from their
+// first page, and starts the analyze and clean forms from them
+func (m *Model) openScanResults(outputPath string) {
+	m.scanOutputFile = outputPath
+	for _, input := range []*string{m.analyzeInputPath, m.cleanInputPath} {
+		if input != nil && outputPath != "" {
+			*input = outputPath
+		}
+	}
+	m.view = ViewScanResults
+	m.resultsPages.Page = 0
+	m.resultsSearch.SetValue("")
+	m.resultsFilter = resultsFilter{}
+	m.loadTriage()
+	m.refreshResults()
+	m.resultsTable.GotoTop()
+}
+
 func (m Model) viewScanProgress() string {
 	var sb strings.Builder
 
-	sb.WriteString(titleStyle.Render("🔍 Scanning Repository"))
+	sb.WriteString(titleStyle.Render("🔍 " + tr.T("Scanning Repository")))
 	sb.WriteString("\n\n")
 
+	p := m.scanProgress
+	phase := "Searching for secrets..."
+	switch p.Phase {
+	case "current":
+		phase = "Searching current files..."
+	case "history":
+		phase = "Searching git history..."
+	}
+	sub, inSubmodule := strings.CutPrefix(p.Phase, scanner.SubmodulePhase)
+	if inSubmodule {
+		phase = "Searching submodule " + sub + "..."
+	}
+	if m.running != nil && m.running.stopping {
+		phase = warningStyle.Render("Cancelling: saving the findings so far...")
+	}
 	sb.WriteString(m.spinner.View())
-	sb.WriteString(" Searching for secrets...\n\n")
+	sb.WriteString(" " + phase + "\n\n")
 
-	if m.scanTotal > 0 {
-		progress := float64(m.scanProgress) / float64(m.scanTotal) * 100
-		sb.WriteString(fmt.Sprintf("Progress: %d/%d keywords (%.0f%%)\n", m.scanProgress, m.scanTotal, progress))
-		sb.WriteString(fmt.Sprintf("Secrets found: %d\n", m.scanFound))
+	if p.Phase != "" {
+		unit := "files"
+		if p.Phase == "history" || inSubmodule {
+			unit = "commits"
+		}
+		if p.Total > 0 {
+			percent := float64(p.Current) / float64(p.Total) * 100
+			sb.WriteString(fmt.Sprintf("%s %s %d/%d %s (%.0f%%)\n",
+				keyStyle.Render("Progress:"), renderProgressBar(percent, 30), p.Current, p.Total, unit, percent))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s %d %s\n", keyStyle.Render("Progress:"), p.Current, unit))
+		}
+		sb.WriteString(fmt.Sprintf("%s %d\n", keyStyle.Render("Secrets found:"), p.Found))
+		sb.WriteString(fmt.Sprintf("%s %d lines, %s\n", keyStyle.Render("Processed:"), p.Lines, scanner.FormatBytes(p.Bytes)))
 	}
+	sb.WriteString(m.viewScanFeed())
+	sb.WriteString("\n" + helpStyle.Render("esc: cancel the scan • ctrl+c: quit"))
 
 	return boxStyle.Render(sb.String())
 }
@@ -293,15 +532,20 @@ func (m Model) viewScanProgress() string {
 func (m Model) viewScanResults() string {
 	var sb strings.Builder
 
-	sb.WriteString(titleStyle.Render("✅ Scan Complete"))
-	sb.WriteString("\n\n")
-
-	// Get output path from scanDoneMsg (stored in scanResult if available)
-	outputPath := "secrets.json"
-	if m.scanOutputPath != nil && *m.scanOutputPath != "" {
-		outputPath = *m.scanOutputPath
+	if m.scanCancelled != "" {
+		sb.WriteString(titleStyle.Render("⏹ " + tr.T("Scan Cancelled")))
+		width := maxFormWidth
+		if m.width > 0 {
+			width = m.width - formChromeWidth
+		}
+		sb.WriteString("\n\n" + warningStyle.Width(width).Render(m.scanCancelled) + "\n\n")
+	} else {
+		sb.WriteString(titleStyle.Render("✅ " + tr.T("Scan Complete")))
+		sb.WriteString("\n\n")
 	}
 
+	outputPath := m.scanOutputFile
+
 	if m.err != nil {
 		sb.WriteString(errorStyle.Render("Error: " + m.err.Error()))
 	} else if result, ok := m.scanResult.(*scanner.ScanResult); ok {
@@ -310,24 +554,36 @@ func (m Model) viewScanResults() string {
 		if m.scanConfigPath != "" {
 			configUsed = m.scanConfigPath
 		}
+		if m.scanConfigAuto {
+			configUsed += lipgloss.NewStyle().Foreground(mutedColor).Render(" (found in the repository)")
+		}
+		if m.lastScan != nil && m.lastScan.profile != "" {
+			configUsed += fmt.Sprintf(", profile %s", m.lastScan.profile)
+		}
+		if m.lastScan != nil && len(m.lastScan.groups) > 0 {
+			configUsed += fmt.Sprintf(", groups %s", strings.Join(m.lastScan.groups, ", "))
+		}
 		sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Config used:"), configUsed))
 		sb.WriteString(fmt.Sprintf("%s %d\n", keyStyle.Render("Secrets found:"), result.SecretsFound))
 		sb.WriteString(fmt.Sprintf("%s %d\n", keyStyle.Render("Total values:"), result.TotalValues))
 		sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Repository:"), result.Repository))
 		sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Branch:"), result.Branch))
 		sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Output file:"), successStyle.Render(outputPath)))
+		sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Processed:"), m.scanStats))
+		if summary := scanner.Summarize(result, m.severityConfig(), outputPath); summary.Reintroduced > 0 {
+			sb.WriteString("\n" + errorStyle.Render(fmt.Sprintf("⚠ %d cleaned value(s) reintroduced since the last cleanup: rotate them again", summary.Reintroduced)) + "\n")
+		}
 
 		if len(result.Secrets) > 0 {
-			sb.WriteString("\n" + keyStyle.Render("Top secrets by change frequency:") + "\n")
-			for i, secret := range result.Secrets {
-				if i >= 5 {
-					sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(result.Secrets)-5))
-					break
-				}
-				sb.WriteString(fmt.Sprintf("  • %s (%d changes)\n",
-					maskedValueStyle.Render(secret.File+"/"+secret.Key),
-					secret.ChangeCount))
+			cfg := m.severityConfig()
+			counts := make(map[string]int)
+			for _, secret := range result.Secrets {
+				counts[scanner.SecretSeverity(cfg, secret)]++
 			}
+			sb.WriteString("\n" + m.renderSeverityFilters(counts) + "\n")
+			sb.WriteString(m.viewResultsSearch(len(result.Secrets)))
+
+			sb.WriteString("\n" + m.viewResultsTable())
 		}
 	} else if streamResult, ok := m.scanResult.(map[string]interface{}); ok {
 		sb.WriteString(fmt.Sprintf("%s stream\n", keyStyle.Render("Mode:")))
@@ -336,14 +592,125 @@ func (m Model) viewScanResults() string {
 		}
 		sb.WriteString(fmt.Sprintf("%s %v\n", keyStyle.Render("Secrets found:"), streamResult["count"]))
 		sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Output file:"), successStyle.Render(outputPath)))
+		sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Processed:"), m.scanStats))
 	}
 
-	help := helpStyle.Render("esc: back to menu")
-	sb.WriteString("\n\n" + help)
+	help := "b: browse all • o: open file • 1-4: toggle severity • "
+	if len(m.resultsRows) > 0 {
+		help = "↑/↓: move • ←/→: page • enter: details • s: sort column • S: reverse order • /: search\n" +
+			"f: false positive • a: accept risk • t: to rotate • u: clear decision\n" + help
+	} else if m.searchingResults() {
+		help = "/: search • " + help
+	}
+	if m.lastScan != nil {
+		help += "r: rescan • "
+	}
+	switch {
+	case m.resultsSearch.Focused():
+		help = "type to filter • enter: back to the table • ctrl+u: clear • esc: clear search"
+	case m.searchingResults():
+		help += "esc: clear search"
+	default:
+		help += "esc: back to menu"
+	}
+	sb.WriteString(m.viewOpenErr())
+	sb.WriteString("\n\n" + helpStyle.Render(help))
 
 	return successBoxStyle.Render(sb.String())
 }
 
+func (m Model) updateScanResults(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if m.resultsSearch.Focused() {
+			return m.updateResultsSearch(keyMsg)
+		}
+		m.openErr = nil
+		if keyMsg.String() == "b" && m.err == nil && m.scanOutputFile != "" {
+			return m.openResultsBrowser(m.scanOutputFile)
+		}
+		if keyMsg.String() == "o" && m.scanOutputFile != "" {
+			return m, openFile(m.scanOutputFile)
+		}
+		if keyMsg.String() == "r" {
+			return m.rescan()
+		}
+		if keyMsg.String() == "enter" && len(m.resultsRows) > 0 {
+			return m.openSecretDetail()
+		}
+		if m.toggleSeverityFilter(keyMsg.String()) {
+			m.resultsPages.Page = 0
+			m.refreshResults()
+			m.resultsTable.GotoTop()
+			return m, nil
+		}
+		return m.updateResultsTable(keyMsg)
+	}
+	// Cursor blink of the search input
+	if m.resultsSearch.Focused() {
+		var cmd tea.Cmd
+		m.resultsSearch, cmd = m.resultsSearch.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// rescan runs the last scan again with the same options, e.g. to check a
+// clean; without a scan in this session the key does nothing
+func (m Model) rescan() (tea.Model, tea.Cmd) {
+	if m.lastScan == nil {
+		return m, nil
+	}
+	m.err = nil
+	m.scanResult = nil
+	m.view = ViewScanProgress
+	return m, tea.Batch(m.spinner.Tick, m.runScan(*m.lastScan))
+}
+
+// severityConfig returns the configuration used to derive finding severities
+func (m Model) severityConfig() *config.Config {
+	if m.currentConfig != nil {
+		return m.currentConfig
+	}
+	cfg, err := config.Load(m.configPath)
+	if err != nil || cfg == nil {
+		return config.DefaultConfig()
+	}
+	return cfg
+}
+
+// findingSeverity is the severity recorded on a finding, or the one of its
+// type in cfg for results written before groups carried one
+func findingSeverity(cfg *config.Config, recorded, findingType string) string {
+	if recorded != "" {
+		return recorded
+	}
+	return cfg.SeverityForType(findingType)
+}
+
+// toggleSeverityFilter shows/hides a severity when a number key 1-4 is pressed
+func (m *Model) toggleSeverityFilter(key string) bool {
+	if len(key) != 1 || key[0] < '1' || key[0] > '0'+byte(len(config.Severities)) {
+		return false
+	}
+	severity := config.Severities[key[0]-'1']
+	m.severityHidden[severity] = !m.severityHidden[severity]
+	return true
+}
+
+// renderSeverityFilters renders the per-severity counts with their filter state
+func (m Model) renderSeverityFilters(counts map[string]int) string {
+	parts := make([]string, 0, len(config.Severities))
+	for i, severity := range config.Severities {
+		label := fmt.Sprintf("%d:%s %d", i+1, severity, counts[severity])
+		if m.severityHidden[severity] {
+			parts = append(parts, lipgloss.NewStyle().Foreground(mutedColor).Strikethrough(true).Render(label))
+		} else {
+			parts = append(parts, severityStyle(severity).Render(severityShape(severity)+" "+label))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
 // Analyze form handling
 func (m Model) updateAnalyzeForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle ESC to go back to menu
@@ -359,6 +726,9 @@ func (m Model) updateAnalyzeForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	if m.form.State == huh.StateCompleted {
 		if m.analyzeConfirm != nil && *m.analyzeConfirm {
+			if path := expandHome(*m.analyzeOutputPath); outputExists(path) {
+				return m.openOverwrite(ViewAnalyze, path)
+			}
 			m.view = ViewAnalyzeProgress
 			return m, tea.Batch(m.spinner.Tick, m.startAnalyze())
 		}
@@ -376,7 +746,7 @@ func (m Model) updateAnalyzeForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) viewAnalyzeForm() string {
 	return boxStyle.Render(
-		titleStyle.Render("📊 Analyze Results") + "\n\n" +
+		titleStyle.Render("📊 "+tr.T("Analyze Results")) + "\n\n" +
 			m.form.View(),
 	)
 }
@@ -385,39 +755,68 @@ func (m *Model) startAnalyze() tea.Cmd {
 	// Capture values from pointers before closure
 	inputPath := "secrets.json"
 	if m.analyzeInputPath != nil {
-		inputPath = *m.analyzeInputPath
+		inputPath = expandHome(*m.analyzeInputPath)
 	}
-	outputPath := "secrets_analysis.csv"
+	outputPath := ""
 	if m.analyzeOutputPath != nil {
-		outputPath = *m.analyzeOutputPath
+		outputPath = expandHome(*m.analyzeOutputPath)
+	}
+	cfg := m.severityConfig()
+	anonymize := m.analyzeAnonymize != nil && *m.analyzeAnonymize
+	// Results outside a workspace: those of the last scan's repository
+	repoPath := "."
+	if m.lastScan != nil {
+		repoPath = m.lastScan.repoPath
 	}
 
 	return func() tea.Msg {
-		a := analyzer.New()
-		var result *analyzer.Analysis
-		var err error
-
-		// Use AnalyzeJSON for .json files, AnalyzeJSONL for .jsonl files
-		if strings.HasSuffix(inputPath, ".jsonl") {
-			result, err = a.AnalyzeJSONL(inputPath, analyzer.AnalyzeOptions{})
-		} else {
-			result, err = a.AnalyzeJSON(inputPath, analyzer.AnalyzeOptions{})
-		}
-
+		result, err := analyzeResults(inputPath, repoPath, cfg, anonymize)
 		if err != nil {
 			return analyzeDoneMsg{result: result, err: err}
 		}
 
-		// Export to CSV
+		// Without a report file, a CSV goes to the workspace of the results
+		if outputPath == "" {
+			if outputPath, err = workspaceReport(inputPath, result.Repository); err != nil {
+				return analyzeDoneMsg{result: result, err: err}
+			}
+		}
+
+		// Export to CSV, or to a standalone HTML report for .html paths
 		csvExported := false
 		if outputPath != "" && result != nil {
-			if csvErr := analyzer.ExportCSV(result, outputPath); csvErr == nil {
+			export := analyzer.ExportCSV
+			if strings.HasSuffix(strings.ToLower(outputPath), ".html") {
+				export = analyzer.ExportHTML
+			}
+			if csvErr := export(result, outputPath); csvErr == nil {
 				csvExported = true
 			}
 		}
 
-		return analyzeDoneMsg{result: result, err: err, csvPath: outputPath, csvExported: csvExported}
+		analysis := &resumeAnalysis{Input: absPath(inputPath), Repo: absPath(repoPath), Anonymized: anonymize}
+		if csvExported {
+			analysis.Report = absPath(outputPath)
+		}
+		return analyzeDoneMsg{result: result, err: err, csvPath: outputPath, csvExported: csvExported, resume: analysis}
+	}
+}
+
+// workspaceReport returns a new CSV report in the workspace of analyzed
+// results: the one holding the file, else their repository's
+func workspaceReport(inputPath, repo string) (string, error) {
+	ws, err := workspace.Containing(inputPath)
+	if err != nil && repo != "" {
+		ws, err = workspace.Open(repo)
+	}
+	if err != nil {
+		return "", fmt.Errorf("no workspace for %s: enter a report file", inputPath)
+	}
+	base, err := ws.NewReport(time.Now())
+	if err != nil {
+		return "", err
 	}
+	return base + ".csv", nil
 }
 
 func (m Model) updateAnalyzeProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -428,7 +827,11 @@ func (m Model) updateAnalyzeProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.analyzeResult = msg.result
 		m.analyzeCsvExported = msg.csvExported
+		m.analyzeReport = msg.csvPath
 		m.view = ViewAnalyzeResults
+		if msg.err == nil && msg.resume != nil {
+			m.rememberAnalysis(msg.resume)
+		}
 		return m, nil
 
 	default:
@@ -441,7 +844,7 @@ func (m Model) updateAnalyzeProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) viewAnalyzeProgress() string {
 	var sb strings.Builder
 
-	sb.WriteString(titleStyle.Render("📊 Analyzing Results"))
+	sb.WriteString(titleStyle.Render("📊 " + tr.T("Analyzing Results")))
 	sb.WriteString("\n\n")
 
 	sb.WriteString(m.spinner.View())
@@ -453,59 +856,167 @@ func (m Model) viewAnalyzeProgress() string {
 func (m Model) viewAnalyzeResults() string {
 	var sb strings.Builder
 
-	sb.WriteString(titleStyle.Render("📊 Analysis Results"))
+	sb.WriteString(titleStyle.Render("📊 " + tr.T("Analysis Results")))
 	sb.WriteString("\n\n")
 
 	if m.err != nil {
 		sb.WriteString(errorStyle.Render("Error: " + m.err.Error()))
 	} else if result, ok := m.analyzeResult.(*analyzer.Analysis); ok {
 		// Stats
-		sb.WriteString(keyStyle.Render("Statistics") + "\n")
-		sb.WriteString(fmt.Sprintf("  Total entries:     %d\n", result.Stats.TotalEntries))
-		sb.WriteString(fmt.Sprintf("  Unique secrets:    %d\n", result.Stats.UniqueSecrets))
-		sb.WriteString(fmt.Sprintf("  Unique values:     %d\n\n", result.Stats.UniqueValues))
+		stat := func(label string, value string) {
+			sb.WriteString(fmt.Sprintf("  %-22s %s\n", tr.T(label), value))
+		}
+		sb.WriteString(keyStyle.Render(tr.T("Statistics")) + "\n")
+		stat("Total entries:", fmt.Sprint(result.Stats.TotalEntries))
+		stat("Unique secrets:", fmt.Sprint(result.Stats.UniqueSecrets))
+		stat("Unique values:", fmt.Sprint(result.Stats.UniqueValues))
+		if result.Excluded > 0 {
+			stat("Left out:", tr.Sprintf("%d triaged as false positives or accepted risks", result.Excluded))
+		}
+		sb.WriteString("\n")
+
+		// Health score
+		if h := result.Health; h != nil {
+			sb.WriteString(keyStyle.Render(tr.T("Health Score")) + "\n")
+			sb.WriteString("  " + healthStyle(h.Score).Render(fmt.Sprintf("%d/100 (%s)", h.Score, h.Grade)) + "  " +
+				tr.Sprintf("%d active secrets, trend %s (%d new this quarter, %d before)",
+					h.ActiveSecrets, tr.T(h.Direction), h.RecentValues, h.PreviousValues) + "\n")
+			sb.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render(
+				"  "+tr.Sprintf("penalties: density -%d, severity -%d, active -%d, trend -%d", h.Density, h.Severity, h.Active, h.Trend)) + "\n\n")
+		}
 
 		// Top authors
 		if len(result.Stats.TopAuthors) > 0 {
-			sb.WriteString(keyStyle.Render("Top Authors") + "\n")
+			sb.WriteString(keyStyle.Render(tr.T("Top Authors")) + "\n")
 			for _, a := range result.Stats.TopAuthors[:min(5, len(result.Stats.TopAuthors))] {
 				sb.WriteString(fmt.Sprintf("  • %-20s %d\n", a.Author, a.Count))
 			}
 			sb.WriteString("\n")
 		}
 
+		// Hotspot files
+		if len(result.Hotspots) > 0 {
+			sb.WriteString(keyStyle.Render(tr.T("Hotspot Files")) + "\n")
+			for _, h := range result.Hotspots[:min(5, len(result.Hotspots))] {
+				density := lipgloss.NewStyle().Foreground(mutedColor).Render(tr.T("not in working tree"))
+				if h.Lines > 0 {
+					density = tr.Sprintf("%.1f per KLOC (%d lines)", h.PerKLOC, h.Lines)
+				}
+				sb.WriteString(fmt.Sprintf("  • %-30s %d secrets, %s\n", truncateString(h.File, 30), h.Secrets, density))
+			}
+			sb.WriteString("\n")
+		}
+
 		// Top secrets
 		if len(result.Secrets) > 0 {
-			sb.WriteString(keyStyle.Render("Most Changed Secrets") + "\n")
-			for i, s := range result.Secrets {
-				if i >= 5 {
+			cfg := m.severityConfig()
+			counts := make(map[string]int)
+			for _, s := range result.Secrets {
+				counts[findingSeverity(cfg, s.Severity, s.Type)]++
+			}
+			sb.WriteString(keyStyle.Render(tr.T("Severity")) + "\n")
+			sb.WriteString("  " + m.renderSeverityFilters(counts) + "\n\n")
+
+			sb.WriteString(keyStyle.Render(tr.T("Most Changed Secrets")) + "\n")
+			shown := 0
+			for _, s := range result.Secrets {
+				severity := findingSeverity(cfg, s.Severity, s.Type)
+				if m.severityHidden[severity] {
+					continue
+				}
+				if shown >= 5 {
 					break
 				}
-				sb.WriteString(fmt.Sprintf("  • %s\n", maskedValueStyle.Render(s.File+"/"+s.Key)))
-				sb.WriteString(fmt.Sprintf("    %d changes, %d occurrences, authors: %s\n",
-					s.ChangeCount, s.TotalOccurrences, strings.Join(s.Authors, ", ")))
+				shown++
+				sb.WriteString(fmt.Sprintf("  %s %s\n", severityBadge(severity), severityStyle(severity).Render(s.File+"/"+s.Key)))
+				sb.WriteString("    " + tr.Sprintf("%d changes, %d occurrences, authors: %s",
+					s.ChangeCount, s.TotalOccurrences, strings.Join(s.Authors, ", ")) + "\n")
 			}
 		}
 
 		// CSV export status
 		sb.WriteString("\n")
-		if m.analyzeCsvExported && m.analyzeOutputPath != nil && *m.analyzeOutputPath != "" {
-			sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("CSV exported:"), successStyle.Render(*m.analyzeOutputPath)))
+		if m.analyzeCsvExported {
+			sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render(tr.T("Report exported:")), successStyle.Render(m.analyzeReport)))
 		}
 	}
 
-	help := helpStyle.Render("esc: back to menu")
+	sb.WriteString(m.viewOpenErr())
+	help := helpStyle.Render(tr.T("b: browse all • d: dashboard • o: open file • 1-4: toggle severity • esc: back to menu"))
 	sb.WriteString("\n\n" + help)
 
 	return boxStyle.Render(sb.String())
 }
 
+func (m Model) updateAnalyzeResults(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		m.openErr = nil
+		if keyMsg.String() == "b" && m.err == nil && m.analyzeInputPath != nil {
+			return m.openResultsBrowser(*m.analyzeInputPath)
+		}
+		if keyMsg.String() == "d" && m.err == nil {
+			m.view = ViewDashboard
+			return m, nil
+		}
+		// The report, else the results analyzed
+		if keyMsg.String() == "o" {
+			if m.analyzeCsvExported {
+				return m, openFile(m.analyzeReport)
+			}
+			if m.analyzeInputPath != nil {
+				return m, openFile(expandHome(*m.analyzeInputPath))
+			}
+		}
+		m.toggleSeverityFilter(keyMsg.String())
+	}
+	return m, nil
+}
+
+// analyzeResults analyzes a results file for the analysis view: values
+// triaged as false positives or accepted risks left out, health, hotspots
+// and locale from cfg, anonymized on demand. Results outside a workspace
+// take their baseline from repoPath.
+func analyzeResults(inputPath, repoPath string, cfg *config.Config, anonymize bool) (*analyzer.Analysis, error) {
+	a := analyzer.New()
+	var result *analyzer.Analysis
+
+	store, err := triage.Load(filepath.Join(workspace.ResultsRepo(inputPath, repoPath), config.BaselineFile))
+	if err != nil {
+		return nil, err
+	}
+	opts := analyzer.AnalyzeOptions{Skip: store.Dismissed}
+
+	// Use AnalyzeJSON for .json files, AnalyzeJSONL for .jsonl files
+	if strings.HasSuffix(inputPath, ".jsonl") {
+		result, err = a.AnalyzeJSONL(inputPath, opts)
+	} else {
+		result, err = a.AnalyzeJSON(inputPath, opts)
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Health = analyzer.ComputeHealth(result, cfg.SeverityForType, time.Now())
+	result.Hotspots = analyzer.ComputeHotspots(result, result.Repository, 10)
+	// The configuration is validated on load: its locale is known
+	result.Locale, _ = analyzer.LookupLocale(cfg.Settings.Locale)
+	result.Language = i18n.Resolve(cfg.Settings.Language)
+	if anonymize {
+		analyzer.Anonymize(result, os.Getenv(analyzer.AnonymizeSaltEnv))
+	}
+	return result, nil
+}
+
 // Clean form handling
 func (m Model) updateCleanForm(msg tea.Msg) (tea.Model, tea.Cmd) {
-	// Handle ESC to go back to menu
-	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "esc" {
-		m.view = ViewMenu
-		return m, nil
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			// Go back to menu
+			m.view = ViewMenu
+			return m, nil
+		case "ctrl+o":
+			return m.openRepoBrowse(ViewClean, m.cleanRepoPath)
+		}
 	}
 
 	form, cmd := m.form.Update(msg)
@@ -519,13 +1030,15 @@ func (m Model) updateCleanForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.view = ViewMenu
 			return m, nil
 		}
+		// Exclusions only carry over from a dry run review to its clean
+		m.cleanExcluded = make(map[string]bool)
+		m.cleanCandidates = nil
+		m.cleanCursor = 0
 		if m.cleanDryRun != nil && *m.cleanDryRun {
 			m.view = ViewCleanProgress
 			return m, tea.Batch(m.spinner.Tick, m.startClean())
 		}
-		m.view = ViewCleanConfirm
-		m.form = m.createCleanConfirmForm()
-		return m, m.form.Init()
+		return m.openCleanSelect()
 	}
 
 	if m.form.State == huh.StateAborted {
@@ -538,9 +1051,17 @@ func (m Model) updateCleanForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) viewCleanForm() string {
 	var sb strings.Builder
 
-	sb.WriteString(titleStyle.Render("🧹 Clean History"))
+	sb.WriteString(titleStyle.Render("🧹 " + tr.T("Clean History")))
 	sb.WriteString("\n\n")
 
+	// The information panel only fits next to the form on tall terminals
+	if m.height > 0 && m.height < cleanPanelHeight {
+		sb.WriteString(errorStyle.Render("⚠️  Rewrites history: collaborators re-clone, rotate secrets"))
+		sb.WriteString("\n\n")
+		sb.WriteString(m.form.View())
+		return boxStyle.Render(sb.String())
+	}
+
 	// Information panel
 	sb.WriteString(warningStyle.Render("ℹ️  What this operation does:"))
 	sb.WriteString("\n\n")
@@ -551,7 +1072,11 @@ func (m Model) viewCleanForm() string {
 	sb.WriteString("  ✓ File structure and non-secret content\n\n")
 	sb.WriteString(keyStyle.Render("Modified:"))
 	sb.WriteString("\n")
-	sb.WriteString("  ⚡ Secrets replaced with ***REMOVED***\n")
+	replacement := cleaner.DefaultReplacement
+	if m.cleanReplace != nil && strings.TrimSpace(*m.cleanReplace) != "" {
+		replacement = strings.TrimSpace(*m.cleanReplace)
+	}
+	sb.WriteString("  ⚡ Secrets replaced with " + replacement + "\n")
 	sb.WriteString("  ⚡ Commit SHA hashes will change (git consequence)\n\n")
 	sb.WriteString(errorStyle.Render("⚠️  Important:"))
 	sb.WriteString("\n")
@@ -565,7 +1090,28 @@ func (m Model) viewCleanForm() string {
 	return boxStyle.Render(sb.String())
 }
 
+// openCleanConfirm asks to confirm the rewrite, measuring the repository
+// meanwhile to warn about long rewrites
+func (m Model) openCleanConfirm() (tea.Model, tea.Cmd) {
+	m.view = ViewCleanConfirm
+	m.form = m.createCleanConfirmForm()
+	m.cleanSize = nil
+	_, repoPath := m.cleanPaths()
+	tool := "auto"
+	if m.cleanTool != nil {
+		tool = *m.cleanTool
+	}
+	return m, tea.Batch(m.form.Init(), func() tea.Msg {
+		size, err := cleaner.EstimateSize(repoPath)
+		return cleanSizeMsg{size: size, tool: tool, err: err}
+	})
+}
+
 func (m Model) updateCleanConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(cleanSizeMsg); ok {
+		m.cleanSize = &size
+		return m, nil
+	}
 	// Handle ESC to go back to menu
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "esc" {
 		m.view = ViewMenu
@@ -596,62 +1142,214 @@ func (m Model) updateCleanConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) viewCleanConfirm() string {
 	return errorBoxStyle.Render(
-		titleStyle.Render("⚠️  Confirm Clean") + "\n\n" +
+		titleStyle.Render("⚠️  "+tr.T("Confirm Clean")) + "\n\n" +
+			m.viewCleanSize() +
 			m.form.View(),
 	)
 }
 
-func (m *Model) startClean() tea.Cmd {
-	// Capture values from pointers before closure
-	inputPath := "secrets.json"
+// viewCleanSize tells the size of the history and how long rewriting it
+// may take, warning about large repositories
+func (m Model) viewCleanSize() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	switch {
+	case m.cleanSize == nil:
+		return muted.Render("Measuring the repository...") + "\n\n"
+	case m.cleanSize.err != nil:
+		return muted.Render("Repository size unknown: "+m.cleanSize.err.Error()) + "\n\n"
+	}
+	size, tool := m.cleanSize.size, m.cleanSize.tool
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("History: %d commits, %d objects, %s\n", size.Commits, size.Objects, scanner.FormatBytes(size.Bytes)))
+	sb.WriteString(fmt.Sprintf("Estimated rewrite (%s): %s\n", tool, cleaner.FormatDuration(size.Duration(tool))))
+	if size.Large(tool) {
+		sb.WriteString("\n" + warningStyle.Render("⚠ Large history: the rewrite may take hours and cannot be paused.") + "\n")
+		sb.WriteString("  Recommended: Clean a Mirror Clone (the temporary directory, TMPDIR,\n")
+		sb.WriteString("  on a fast disk) rather than this working copy, then push it back.\n")
+	}
+	return sb.String() + "\n"
+}
+
+// cleanPaths returns the results file and the repository of the clean form
+func (m Model) cleanPaths() (inputPath, repoPath string) {
+	inputPath, repoPath = "secrets.json", "."
 	if m.cleanInputPath != nil {
-		inputPath = *m.cleanInputPath
+		inputPath = expandHome(*m.cleanInputPath)
 	}
-	repoPath := "."
 	if m.cleanRepoPath != nil && *m.cleanRepoPath != "" {
-		repoPath = *m.cleanRepoPath
+		repoPath = expandHome(*m.cleanRepoPath)
 	}
+	return inputPath, repoPath
+}
+
+func (m *Model) startClean() tea.Cmd {
+	// Capture values from pointers before closure
+	inputPath, repoPath := m.cleanPaths()
 	tool := "auto"
 	if m.cleanTool != nil {
 		tool = *m.cleanTool
 	}
 	dryRun := m.cleanDryRun != nil && *m.cleanDryRun
+	onlyRotated := m.cleanRotated != nil && *m.cleanRotated
+	anchored := m.cleanAnchored != nil && *m.cleanAnchored
+	mirror := m.cleanMirror != nil && *m.cleanMirror
+	verify := m.verifyScan()
+	bundle := ""
+	if m.cleanBundle != nil && strings.TrimSpace(*m.cleanBundle) != "" {
+		bundle = expandHome(strings.TrimSpace(*m.cleanBundle))
+	}
+	replacement := ""
+	if m.cleanReplace != nil {
+		replacement = strings.TrimSpace(*m.cleanReplace)
+	}
+	var deleteFiles []string
+	if m.cleanDelete != nil {
+		deleteFiles = cleaner.ParsePatterns(*m.cleanDelete)
+	}
+	var paths cleaner.PathFilter
+	if m.cleanInclude != nil {
+		paths.Include = cleaner.ParsePatterns(*m.cleanInclude)
+		paths.Exclude = cleaner.ParsePatterns(*m.cleanExclude)
+	}
+	filter := m.cleanFilter()
+	excluded := make(map[string]bool, len(m.cleanExcluded))
+	for v, ex := range m.cleanExcluded {
+		excluded[v] = ex
+	}
+	// Files whose head was scanned hold findings: they are cleaned whole
+	cfg := m.severityConfig()
+	maxFileSize := cfg.MaxFileSize()
+	if cfg.LargeFileHead() > 0 {
+		maxFileSize = -1
+	}
 
-	return func() tea.Msg {
-		// Load secrets and detect source automatically
-		var loadResult *cleaner.LoadSecretsResult
-		var err error
-
-		if strings.HasSuffix(inputPath, ".jsonl") {
-			loadResult, err = cleaner.LoadSecretsFromJSONL(inputPath)
-		} else {
-			loadResult, err = cleaner.LoadSecretsFromJSON(inputPath)
-		}
+	op, ctx := newOperation("Clean")
+	m.running = op
+	log := newCleanLog()
+	m.cleanLog, m.cleanLogScroll = log, 0
+	// Credentials asked after a refused clone serve this clean only
+	creds := m.cleanCreds
+	m.cleanCreds = nil
 
+	run := func() tea.Msg {
+		loadResult, secrets, left, err := loadCleanSecrets(inputPath, repoPath, filter, onlyRotated)
 		if err != nil {
 			return cleanDoneMsg{err: err}
 		}
 
+		// A dry run lists every candidate, excluded ones included, so the
+		// review can bring them back
+		var candidates []cleaner.Candidate
+		if dryRun {
+			candidates = cleaner.Candidates(loadResult, secrets)
+		}
+		secrets = cleaner.Exclude(secrets, excluded)
+
 		c := cleaner.New()
-		result, err := c.Clean(repoPath, loadResult.Secrets, cleaner.CleanOptions{
-			Tool:      tool,
-			Source:    loadResult.Source,    // Auto-detected from scan file
-			FilePaths: loadResult.FileMap,   // Only clean files listed in scan results
-			DryRun:    dryRun,
+		result, err := c.Clean(repoPath, secrets, cleaner.CleanOptions{
+			Tool:        tool,
+			Source:      loadResult.Source,  // Auto-detected from scan file
+			FilePaths:   loadResult.FileMap, // Only clean files listed in scan results
+			DryRun:      dryRun,
+			Anchored:    anchored,
+			Entries:     loadResult.Entries,
+			Paths:       paths,
+			MaxFileSize: maxFileSize,
+			Replacement: replacement,
+			DeleteFiles: deleteFiles,
+			Mirror:      mirror,
+			Bundle:      bundle,
+			Interrupt:   ctx,
+			Credentials: creds,
+			OnProgress:  log.progress,
+			Output:      log,
 		})
+		// A rewritten history is rescanned for the values that survived
+		if err == nil && result.Success && !dryRun && result.Source != "current" && !result.Interrupted {
+			log.progress(0, 0, "Verifying: rescanning the rewritten history...")
+			verify.run(ctx, result, repoPath, secrets, paths)
+		}
+		var remote string
+		if result != nil {
+			result.Left = left
+			result.Filter, result.Filtered = filter.String(), loadResult.Filtered
+			if result.Mirror != "" {
+				remote = cleaner.MirrorRemote(repoPath)
+			}
+		}
 
-		return cleanDoneMsg{result: result, err: err}
+		return cleanDoneMsg{result: result, candidates: candidates, remote: remote, creds: creds, err: err}
+	}
+
+	return func() tea.Msg {
+		msg := run().(cleanDoneMsg)
+		op.finish(cleanSummary(repoPath, msg))
+		return msg
 	}
 }
 
+// loadCleanSecrets loads the secrets of a results file that pass the filter
+// step, detecting the source, then leaves the values differential cleaning
+// skips (still in use), else the false positives and accepted risks
+func loadCleanSecrets(inputPath, repoPath string, filter cleaner.LoadFilter, onlyRotated bool) (*cleaner.LoadSecretsResult, []string, []cleaner.LeftSecret, error) {
+	var loadResult *cleaner.LoadSecretsResult
+	var err error
+	if strings.HasSuffix(inputPath, ".jsonl") {
+		loadResult, err = cleaner.LoadSecretsFromJSONL(inputPath, filter)
+	} else {
+		loadResult, err = cleaner.LoadSecretsFromJSON(inputPath, filter)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	store, err := triage.Load(filepath.Join(repoPath, config.BaselineFile))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	secrets, left := cleaner.FilterDismissed(loadResult, store)
+	if onlyRotated {
+		secrets, left = cleaner.FilterRotated(loadResult, store)
+	}
+	return loadResult, secrets, left, nil
+}
+
 func (m Model) updateCleanProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case cleanDoneMsg:
+		m.running = nil
 		if msg.err != nil {
 			m.err = msg.err
 		}
 		m.cleanResult = msg.result
+		m.cleanPush = newMirrorPush(msg.result, msg.remote, msg.creds)
+		m.cleanRemote = msg.remote
+		_, repoPath := m.cleanPaths()
+		m.cleanRestore = newBackupRestore(msg.result, repoPath)
+		if msg.candidates != nil {
+			m.cleanCandidates = msg.candidates
+			m.cleanCursor = min(m.cleanCursor, max(len(msg.candidates)-1, 0))
+		}
 		m.view = ViewCleanResults
+		// The origin refused the clone: ask for its credentials
+		if msg.result != nil && msg.result.Auth != nil {
+			return m.openCredentials(msg.result.Auth, false)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.scrollCleanLog(1)
+		case "down", "j":
+			m.scrollCleanLog(-1)
+		case "pgup":
+			m.scrollCleanLog(cleanLogRows)
+		case "pgdown":
+			m.scrollCleanLog(-cleanLogRows)
+		case "end", "G":
+			m.cleanLogScroll = 0
+		}
 		return m, nil
 
 	default:
@@ -664,13 +1362,26 @@ func (m Model) updateCleanProgress(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) viewCleanProgress() string {
 	var sb strings.Builder
 
-	sb.WriteString(titleStyle.Render("🧹 Cleaning Repository"))
+	sb.WriteString(titleStyle.Render("🧹 " + tr.T("Cleaning Repository")))
 	sb.WriteString("\n\n")
 
 	sb.WriteString(m.spinner.View())
-	sb.WriteString(" Cleaning secrets...\n\n")
+	if m.running != nil && m.running.stopping {
+		sb.WriteString(" Cancelling: waiting for the cleaning tool to stop...\n\n")
+		sb.WriteString(warningStyle.Render("A history rewrite stopped midway may be partly done: the result tells how to restore it."))
+		return boxStyle.Render(sb.String())
+	}
+	step := "Cleaning secrets..."
+	if m.cleanLog != nil {
+		if _, current, _ := m.cleanLog.snapshot(); current != "" {
+			step = current
+		}
+	}
+	sb.WriteString(" " + step + "\n")
+	sb.WriteString(m.viewCleanLog())
 
-	sb.WriteString(warningStyle.Render("This may take a while for large repositories."))
+	sb.WriteString("\n" + warningStyle.Render("This may take a while for large repositories."))
+	sb.WriteString("\n\n" + helpStyle.Render("↑/↓ pgup/pgdn: scroll the output • end: follow • esc: cancel the clean • ctrl+c: quit"))
 
 	return boxStyle.Render(sb.String())
 }
@@ -679,14 +1390,14 @@ func (m Model) viewCleanResults() string {
 	var sb strings.Builder
 
 	if m.err != nil {
-		sb.WriteString(titleStyle.Render("❌ Clean Failed"))
+		sb.WriteString(titleStyle.Render("❌ " + tr.T("Clean Failed")))
 		sb.WriteString("\n\n")
 		sb.WriteString(errorStyle.Render("Error: " + m.err.Error()))
 	} else if result, ok := m.cleanResult.(*cleaner.CleanResult); ok {
 		if result.Success {
 			if result.DryRun {
 				// Dry run results
-				sb.WriteString(titleStyle.Render("🔍 Dry Run Results"))
+				sb.WriteString(titleStyle.Render("🔍 " + tr.T("Dry Run Results")))
 				sb.WriteString("\n\n")
 				sb.WriteString(warningStyle.Render("No changes were made. This is a preview."))
 				sb.WriteString("\n\n")
@@ -705,9 +1416,15 @@ func (m Model) viewCleanResults() string {
 				}
 				sb.WriteString(fmt.Sprintf("%s %d\n", keyStyle.Render("Secrets to remove:"), result.SecretsRemoved))
 				sb.WriteString(fmt.Sprintf("%s %d\n", keyStyle.Render("Patterns to use:"), result.PatternsUsed))
+				if result.AnchoredValues > 0 {
+					sb.WriteString(fmt.Sprintf("%s %d of %d (the others are replaced everywhere)\n", keyStyle.Render("Anchored on keys:"), result.AnchoredValues, result.SecretsRemoved))
+				}
+				sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Replaced with:"), result.Replacement))
 
-				// Show preview of secrets
-				if len(result.PreviewSecrets) > 0 {
+				// Review of the values to remove
+				if len(m.cleanCandidates) > 0 {
+					m.writeCleanCandidates(&sb)
+				} else if len(result.PreviewSecrets) > 0 {
 					sb.WriteString("\n" + keyStyle.Render("Preview of values to remove:") + "\n")
 					for _, s := range result.PreviewSecrets {
 						sb.WriteString(fmt.Sprintf("  • %s\n", maskedValueStyle.Render(s)))
@@ -717,11 +1434,20 @@ func (m Model) viewCleanResults() string {
 					}
 				}
 
+				writeDeletedFiles(&sb, result.DeletedFiles, "Files to delete")
+				writeLeftSecrets(&sb, result.Left)
+				writeFiltered(&sb, result)
+				writeRiskyValues(&sb, result.Risky)
+
 				sb.WriteString("\n" + keyStyle.Render("To apply changes:") + "\n")
-				sb.WriteString("  Run Clean again with 'Dry Run: No'\n")
+				if len(m.cleanCandidates) > 0 {
+					sb.WriteString("  Press enter to clean the selected values\n")
+				} else {
+					sb.WriteString("  Run Clean again with 'Dry Run: No'\n")
+				}
 			} else {
 				// Actual clean results
-				sb.WriteString(titleStyle.Render("✅ Clean Complete"))
+				sb.WriteString(titleStyle.Render("✅ " + tr.T("Clean Complete")))
 				sb.WriteString("\n\n")
 
 				// Show source
@@ -749,15 +1475,33 @@ func (m Model) viewCleanResults() string {
 				}
 				if result.Source != "current" {
 					sb.WriteString(fmt.Sprintf("%s %d\n", keyStyle.Render("Patterns used:"), result.PatternsUsed))
+					if result.AnchoredValues > 0 {
+						sb.WriteString(fmt.Sprintf("%s %d\n", keyStyle.Render("Anchored on keys:"), result.AnchoredValues))
+					}
 				}
 
+				sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Replaced with:"), result.Replacement))
+
 				if result.BackupBranch != "" {
 					sb.WriteString(fmt.Sprintf("%s %s\n", keyStyle.Render("Backup branch:"), result.BackupBranch))
 				}
+				if result.BackupBundle != "" {
+					sb.WriteString(fmt.Sprintf("%s %s (git clone it to restore)\n", keyStyle.Render("Backup bundle:"), result.BackupBundle))
+				}
+				m.writeMirror(&sb, result)
+				writeVerification(&sb, result)
+
+				writeDeletedFiles(&sb, result.DeletedFiles, "Files deleted")
+				writeLeftSecrets(&sb, result.Left)
+				writeFiltered(&sb, result)
+				writeRiskyValues(&sb, result.Risky)
+				writeTooLarge(&sb, result.TooLarge)
 
 				// Show appropriate next steps based on source and actual changes
 				sb.WriteString("\n" + warningStyle.Render("⚠️  Next steps:") + "\n")
-				if result.Source == "current" {
+				if result.Mirror != "" {
+					m.writeMirrorSteps(&sb, result)
+				} else if result.Source == "current" {
 					if result.FilesModified > 0 {
 						sb.WriteString("  1. Review changes: git diff\n")
 						sb.WriteString("  2. Commit: git add -A && git commit -m 'Remove secrets'\n")
@@ -787,14 +1531,42 @@ func (m Model) viewCleanResults() string {
 					sb.WriteString("  6. Rotate all exposed credentials\n")
 				}
 			}
+		} else if result.Interrupted {
+			sb.WriteString(titleStyle.Render("⏹ " + tr.T("Clean Cancelled")))
+			sb.WriteString("\n\n")
+			sb.WriteString(warningStyle.Render(result.Message))
 		} else {
-			sb.WriteString(titleStyle.Render("❌ Clean Failed"))
+			sb.WriteString(titleStyle.Render("❌ " + tr.T("Clean Failed")))
 			sb.WriteString("\n\n")
 			sb.WriteString(errorStyle.Render(result.Message))
+			if result.Auth != nil {
+				sb.WriteString("\n\n")
+				writeAuthHelp(&sb, result.Auth)
+			}
 		}
 	}
 
+	m.writeRestore(&sb)
+
 	help := helpStyle.Render("esc: back to menu")
+	if m.lastScan != nil {
+		help = helpStyle.Render("r: rescan to verify • esc: back to menu")
+	}
+	if m.reviewingClean() {
+		help = helpStyle.Render(cleanSelectHelp + " • enter: clean selected • esc: back to menu")
+	}
+	switch {
+	case m.cleanPush != nil && m.cleanPush.confirming:
+		help = helpStyle.Render("y: push • n: cancel")
+	case m.cleanPush.canPush():
+		help = helpStyle.Render("p: push the mirror to origin • esc: back to menu")
+	case m.cleanRestore != nil && m.cleanRestore.confirming:
+		help = helpStyle.Render("y: restore • n: cancel")
+	case m.cleanRestore.canRestore():
+		help = helpStyle.Render("u: undo, restore the backup • esc: back to menu")
+	case m.canRetryClone():
+		help = helpStyle.Render("c: enter the credentials of the remote • esc: back to menu")
+	}
 	sb.WriteString("\n\n" + help)
 
 	if m.err != nil || (m.cleanResult != nil && !m.cleanResult.(*cleaner.CleanResult).Success) {
@@ -803,18 +1575,178 @@ func (m Model) viewCleanResults() string {
 	return successBoxStyle.Render(sb.String())
 }
 
+// reviewingClean reports whether the clean results are a dry run whose
+// values can be reviewed before the actual clean
+func (m Model) reviewingClean() bool {
+	result, ok := m.cleanResult.(*cleaner.CleanResult)
+	return ok && m.err == nil && result.Success && result.DryRun && len(m.cleanCandidates) > 0
+}
+
+// cleanReviewRows is the number of candidates shown at once in the review
+const cleanReviewRows = 10
+
+// writeCleanCandidates lists the values to clean with their selection,
+// under a header per file/key, scrolled around the cursor
+func (m Model) writeCleanCandidates(sb *strings.Builder) {
+	selected := len(m.cleanCandidates)
+	for _, c := range m.cleanCandidates {
+		if m.cleanExcluded[c.Value] {
+			selected--
+		}
+	}
+	sb.WriteString("\n" + keyStyle.Render(fmt.Sprintf("Values to remove (%d of %d selected):", selected, len(m.cleanCandidates))) + "\n")
+
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	var lines []string
+	cursorLine := 0
+	for i, c := range m.cleanCandidates {
+		if i == 0 || c.File != m.cleanCandidates[i-1].File || c.Key != m.cleanCandidates[i-1].Key {
+			lines = append(lines, "  "+muted.Render(truncateString(c.File+"/"+c.Key, 60)))
+		}
+		cursor := "  "
+		if i == m.cleanCursor {
+			cursor, cursorLine = "> ", len(lines)
+		}
+		check := successStyle.Render("[x]")
+		value := maskedValueStyle.Render(c.MaskedValue)
+		if m.cleanExcluded[c.Value] {
+			check = "[ ]"
+			value = muted.Strikethrough(true).Render(c.MaskedValue)
+		}
+		more := ""
+		if c.Occurrences > 1 {
+			more = muted.Render(fmt.Sprintf("  (+%d elsewhere)", c.Occurrences-1))
+		}
+		lines = append(lines, fmt.Sprintf("  %s%s %s%s", cursor, check, value, more))
+	}
+
+	start := max(0, min(cursorLine-cleanReviewRows/2, len(lines)-cleanReviewRows))
+	end := min(start+cleanReviewRows, len(lines))
+	if start > 0 {
+		sb.WriteString(fmt.Sprintf("    ↑ %d more\n", start))
+	}
+	for _, line := range lines[start:end] {
+		sb.WriteString(line + "\n")
+	}
+	if end < len(lines) {
+		sb.WriteString(fmt.Sprintf("    ↓ %d more\n", len(lines)-end))
+	}
+}
+
+// updateCleanResults handles the review of a dry run: values can be
+// excluded one by one, then the clean runs on the selection
+func (m Model) updateCleanResults(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m, cmd, handled := m.updateMirrorPush(msg); handled {
+		return m, cmd
+	}
+	if m, cmd, handled := m.updateBackupRestore(msg); handled {
+		return m, cmd
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && keyMsg.String() == "r" && !m.reviewingClean() {
+		return m.rescan()
+	}
+	if ok && keyMsg.String() == "c" && m.canRetryClone() {
+		return m.openCredentials(m.cleanResult.(*cleaner.CleanResult).Auth, false)
+	}
+	if !ok || !m.reviewingClean() {
+		return m, nil
+	}
+
+	if keyMsg.String() == "enter" {
+		if !m.cleanSelection() && !m.deletingFiles() {
+			return m, nil // Nothing to clean
+		}
+		dryRun := false
+		m.cleanDryRun = &dryRun
+		return m.openCleanConfirm()
+	}
+	m.selectCandidate(keyMsg.String())
+	return m, nil
+}
+
+// writeLeftSecrets lists the values differential cleaning deliberately kept
+func writeLeftSecrets(sb *strings.Builder, left []cleaner.LeftSecret) {
+	if len(left) == 0 {
+		return
+	}
+	sb.WriteString("\n" + warningStyle.Render(fmt.Sprintf("Deliberately left (%d):", len(left))) + "\n")
+	for i, l := range left {
+		if i >= 10 {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(left)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("  • %s/%s %s — %s\n", l.File, l.Key, maskedValueStyle.Render(l.MaskedValue), l.Reason))
+	}
+}
+
+// writeFiltered tells how many findings the filter step left out
+func writeFiltered(sb *strings.Builder, result *cleaner.CleanResult) {
+	if result.Filter == "" {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("%s %d findings left out (%s)\n", keyStyle.Render("Filter:"), result.Filtered, result.Filter))
+}
+
+// writeRiskyValues warns about the replacements that may damage unrelated data
+func writeRiskyValues(sb *strings.Builder, risky []cleaner.RiskyValue) {
+	if len(risky) == 0 {
+		return
+	}
+	sb.WriteString("\n" + warningStyle.Render(fmt.Sprintf("⚠ Risky replacements (%d):", len(risky))) + "\n")
+	for i, r := range risky {
+		if i >= 10 {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(risky)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("  • %s — %s\n", maskedValueStyle.Render(r.MaskedValue), r.Reason))
+	}
+}
+
+// writeTooLarge lists the current files left unchanged for their size
+func writeTooLarge(sb *strings.Builder, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	sb.WriteString("\n" + warningStyle.Render(fmt.Sprintf("⚠ Files over the size limit, not cleaned (%d):", len(files))) + "\n")
+	for i, f := range files {
+		if i >= 10 {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(files)-i))
+			break
+		}
+		sb.WriteString("  • " + f + "\n")
+	}
+	sb.WriteString("  Raise settings.maxFileSizeKB or edit them by hand\n")
+}
+
+// writeDeletedFiles lists the files removed whole from the history
+func writeDeletedFiles(sb *strings.Builder, files []string, title string) {
+	if len(files) == 0 {
+		return
+	}
+	sb.WriteString("\n" + keyStyle.Render(fmt.Sprintf("%s (%d):", title, len(files))) + "\n")
+	for i, f := range files {
+		if i >= 10 {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(files)-i))
+			break
+		}
+		sb.WriteString("  • " + f + "\n")
+	}
+}
+
+// renderProgressBar renders a fixed-width bar for a 0-100 percentage
+func renderProgressBar(percent float64, width int) string {
+	filled := int(percent / 100 * float64(width))
+	if filled > width {
+		filled = width
+	}
+	return progressBarStyle.Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(mutedColor).Render(strings.Repeat("░", width-filled))
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
 	}
 	return b
 }
-
-// saveResultToFile saves scan result to JSON file
-func saveResultToFile(result *scanner.ScanResult, path string) error {
-	data, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0644)
-}
@@ -0,0 +1,337 @@
+// Package workspace keeps the files produced for each scanned repository
+// (scan results, reports) in a directory of the user data dir rather than in
+// the repository itself, and removes the old ones by retention policy.
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Drilmo/git-secret-scanner/internal/config"
+)
+
+// Env overrides the directory holding the workspaces
+const Env = "GITSECRET_WORKSPACES"
+
+const (
+	metaFile    = "workspace.json"
+	scansDir    = "scans"
+	reportsDir  = "reports"
+	stampLayout = "20060102-150405" // Name of the timestamped scans and reports
+
+	// incrementalName is the scan incremental scans merge into: it holds
+	// their state, so retention never removes it
+	incrementalName = "incremental"
+)
+
+// Workspace is the directory of a repository's scans and reports
+type Workspace struct {
+	Dir     string    `json:"-"`
+	Repo    string    `json:"repo"` // Absolute path of the repository
+	Created time.Time `json:"created"`
+}
+
+// Root returns the directory holding the workspaces: GITSECRET_WORKSPACES,
+// else git-secret-scanner/workspaces under $XDG_DATA_HOME or ~/.local/share
+func Root() string {
+	if dir := os.Getenv(Env); dir != "" {
+		return dir
+	}
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		data = filepath.Join(os.Getenv("HOME"), ".local", "share")
+	}
+	return filepath.Join(data, "git-secret-scanner", "workspaces")
+}
+
+// Open returns the workspace of a repository, creating it the first time.
+// Its directory is named after the repository and a hash of its path, so
+// two clones with the same name get their own.
+func Open(repo string) (*Workspace, error) {
+	w, err := Lookup(repo)
+	if !os.IsNotExist(err) {
+		return w, err
+	}
+
+	w.Created = time.Now().UTC()
+	if err := os.MkdirAll(w.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("workspace: %w", err)
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(w.Dir, metaFile), append(data, '\n'), 0600); err != nil {
+		return nil, fmt.Errorf("workspace: %w", err)
+	}
+	return w, nil
+}
+
+// Lookup returns the workspace of a repository without creating it: when
+// there is none, the error satisfies os.IsNotExist and the workspace
+// returned has its Dir and Repo set
+func Lookup(repo string) (*Workspace, error) {
+	abs, err := filepath.Abs(repo)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	dir := filepath.Join(Root(), filepath.Base(abs)+"-"+hex.EncodeToString(sum[:4]))
+	w, err := load(dir)
+	if os.IsNotExist(err) {
+		return &Workspace{Dir: dir, Repo: abs}, err
+	}
+	return w, err
+}
+
+// Containing returns the workspace holding a scan or report file
+func Containing(path string) (*Workspace, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return load(filepath.Dir(filepath.Dir(abs)))
+}
+
+// ResultsRepo returns the repository results belong to: that of the
+// workspace holding them, else repo
+func ResultsRepo(path, repo string) string {
+	if w, err := Containing(path); err == nil {
+		return w.Repo
+	}
+	return repo
+}
+
+// load reads the workspace of a directory
+func load(dir string) (*Workspace, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metaFile))
+	if err != nil {
+		return nil, err
+	}
+	var w Workspace
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Join(dir, metaFile), err)
+	}
+	w.Dir = dir
+	return &w, nil
+}
+
+// List returns every workspace, by repository path
+func List() ([]*Workspace, error) {
+	entries, err := os.ReadDir(Root())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var all []*Workspace
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		// Not a workspace (no workspace.json): left alone
+		if w, err := load(filepath.Join(Root(), entry.Name())); err == nil {
+			all = append(all, w)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Repo < all[j].Repo })
+	return all, nil
+}
+
+// Missing reports whether the repository of the workspace no longer exists
+func (w *Workspace) Missing() bool {
+	_, err := os.Stat(w.Repo)
+	return os.IsNotExist(err)
+}
+
+// NewScan returns the path, without extension, of a new scan started at now
+func (w *Workspace) NewScan(now time.Time) (string, error) {
+	return w.newFile(scansDir, now)
+}
+
+// IncrementalScan returns the path, without extension, of the scan that
+// incremental scans of the repository update
+func (w *Workspace) IncrementalScan() (string, error) {
+	dir := filepath.Join(w.Dir, scansDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("workspace: %w", err)
+	}
+	return filepath.Join(dir, incrementalName), nil
+}
+
+// NewReport returns the path, without extension, of a new report written at now
+func (w *Workspace) NewReport(now time.Time) (string, error) {
+	return w.newFile(reportsDir, now)
+}
+
+// newFile names a new file of a subdirectory after now, with a suffix when
+// another one was created in the same second
+func (w *Workspace) newFile(sub string, now time.Time) (string, error) {
+	dir := filepath.Join(w.Dir, sub)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("workspace: %w", err)
+	}
+	base := filepath.Join(dir, now.UTC().Format(stampLayout))
+	path := base
+	for n := 2; taken(path); n++ {
+		path = fmt.Sprintf("%s-%d", base, n)
+	}
+	return path, nil
+}
+
+// Stamped returns path with now before its extension
+// (secrets-20260102-150405.json), with a suffix when that name is taken:
+// the name offered instead of overwriting an output file
+func Stamped(path string, now time.Time) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext) + "-" + now.UTC().Format(stampLayout)
+	stamped := base + ext
+	for n := 2; ; n++ {
+		if _, err := os.Stat(stamped); err != nil {
+			return stamped
+		}
+		stamped = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+}
+
+// taken reports whether a scan or report with the base path exists
+func taken(base string) bool {
+	for _, ext := range []string{".json", ".jsonl", ".csv", ".html"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Scans returns the scan results of the workspace, oldest first
+func (w *Workspace) Scans() []string {
+	return w.files(scansDir)
+}
+
+// Reports returns the reports of the workspace, oldest first
+func (w *Workspace) Reports() []string {
+	return w.files(reportsDir)
+}
+
+// files returns the results and reports of a subdirectory, oldest first
+// (files of a scan, such as the .state of incremental scans, excluded)
+func (w *Workspace) files(sub string) []string {
+	entries, _ := os.ReadDir(filepath.Join(w.Dir, sub))
+	type file struct {
+		path string
+		mod  time.Time
+	}
+	var files []file
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || ext == ".state" {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files = append(files, file{filepath.Join(w.Dir, sub, entry.Name()), info.ModTime()})
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].mod.Before(files[j].mod) })
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths
+}
+
+// LatestScan returns the most recently written scan result ("" if none)
+func (w *Workspace) LatestScan() string {
+	scans := w.Scans()
+	if len(scans) == 0 {
+		return ""
+	}
+	return scans[len(scans)-1]
+}
+
+// Size returns the disk usage of the workspace in bytes
+func (w *Workspace) Size() int64 {
+	var size int64
+	filepath.WalkDir(w.Dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// Remove deletes the workspace and everything in it
+func (w *Workspace) Remove() error {
+	return os.RemoveAll(w.Dir)
+}
+
+// Retention is what Prune keeps
+type Retention struct {
+	Keep   int           // Most recent timestamped scans kept (0: all)
+	MaxAge time.Duration // Older scans and reports are removed (0: no age limit)
+}
+
+// RetentionOf returns the retention policy of settings.workspace
+func RetentionOf(s config.WorkspaceSettings) Retention {
+	r := Retention{Keep: s.KeepScans, MaxAge: time.Duration(s.MaxAgeDays) * 24 * time.Hour}
+	if r.Keep == 0 {
+		r.Keep = config.DefaultKeepScans
+	}
+	return r
+}
+
+// Prune removes the timestamped scans beyond the r.Keep most recent and the
+// scans and reports older than r.MaxAge, returning the files removed. The
+// most recent scan and the incremental scan are always kept.
+func (w *Workspace) Prune(r Retention, now time.Time) ([]string, error) {
+	var remove []string
+	var scans []string
+	for _, path := range w.Scans() {
+		if !strings.HasPrefix(filepath.Base(path), incrementalName+".") {
+			scans = append(scans, path)
+		}
+	}
+	for i, path := range scans {
+		if i == len(scans)-1 {
+			break
+		}
+		if (r.Keep > 0 && i < len(scans)-r.Keep) || w.expired(path, r, now) {
+			remove = append(remove, path)
+		}
+	}
+	for _, path := range w.Reports() {
+		if w.expired(path, r, now) {
+			remove = append(remove, path)
+		}
+	}
+
+	var removed []string
+	for _, path := range remove {
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// expired reports whether a file was last written more than r.MaxAge ago
+func (w *Workspace) expired(path string, r Retention, now time.Time) bool {
+	if r.MaxAge <= 0 {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && now.Sub(info.ModTime()) > r.MaxAge
+}
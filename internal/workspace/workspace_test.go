@@ -0,0 +1,108 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWorkspace(t *testing.T) {
+	t.Setenv(Env, t.TempDir())
+	repo := t.TempDir()
+
+	w, err := Open(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := Open(repo); err != nil || again.Dir != w.Dir || !again.Created.Equal(w.Created) {
+		t.Fatalf("reopened %+v (%v), want %+v", again, err, w)
+	}
+	if other, _ := Open(t.TempDir()); other.Dir == w.Dir {
+		t.Error("two repositories share a workspace")
+	}
+
+	// Five scans a day apart, the same second twice, and the incremental one
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	var scans []string
+	for i := 4; i >= 0; i-- {
+		base, err := w.NewScan(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		write(t, base+".json", now.AddDate(0, 0, -i))
+		scans = append(scans, base+".json")
+	}
+	if filepath.Base(scans[1]) != "20260310-120000-2.json" {
+		t.Errorf("second scan of the same second = %s", filepath.Base(scans[1]))
+	}
+	incremental, _ := w.IncrementalScan()
+	write(t, incremental+".json", now.AddDate(0, 0, -30))
+	write(t, incremental+".json.state", now.AddDate(0, 0, -30))
+	report, _ := w.NewReport(now)
+	write(t, report+".csv", now.AddDate(0, 0, -3))
+
+	if latest := w.LatestScan(); latest != scans[4] {
+		t.Errorf("latest scan = %s, want %s", latest, scans[4])
+	}
+	if len(w.Scans()) != 6 {
+		t.Errorf("scans = %v, want 5 timestamped and the incremental one", w.Scans())
+	}
+
+	// Keep 3: the two oldest go, the incremental scan stays
+	removed, err := w.Prune(Retention{Keep: 3}, now)
+	if err != nil || len(removed) != 2 || removed[0] != scans[0] || removed[1] != scans[1] {
+		t.Fatalf("removed %v (%v), want the two oldest scans", removed, err)
+	}
+	// Two days: the report and all scans but the latest go
+	removed, _ = w.Prune(Retention{MaxAge: 36 * time.Hour}, now.AddDate(0, 0, 2))
+	if len(removed) != 3 {
+		t.Errorf("removed %v, want two scans and the report", removed)
+	}
+	if w.LatestScan() == "" || len(w.Reports()) != 0 {
+		t.Errorf("scans %v, reports %v after pruning by age", w.Scans(), w.Reports())
+	}
+	if _, err := os.Stat(incremental + ".json.state"); err != nil {
+		t.Error("incremental scan state removed")
+	}
+
+	all, err := List()
+	if err != nil || len(all) != 2 {
+		t.Fatalf("listed %d workspaces (%v), want 2", len(all), err)
+	}
+	os.RemoveAll(repo)
+	if !w.Missing() {
+		t.Error("workspace of a deleted repository not reported missing")
+	}
+	if err := w.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := List(); len(all) != 1 {
+		t.Errorf("%d workspaces after removal, want 1", len(all))
+	}
+}
+
+// write creates a file modified at mod
+func write(t *testing.T, path string, mod time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStamped(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(dir, "secrets.json")
+	stamped := Stamped(path, now)
+	if want := filepath.Join(dir, "secrets-20260310-120000.json"); stamped != want {
+		t.Fatalf("Stamped = %s, want %s", stamped, want)
+	}
+	os.WriteFile(stamped, nil, 0600)
+	if again := Stamped(path, now); filepath.Base(again) != "secrets-20260310-120000-2.json" {
+		t.Errorf("Stamped of a taken name = %s", again)
+	}
+}